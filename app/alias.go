@@ -13,4 +13,6 @@ var (
 	DefaultCLIHome = protocol.DefaultCLIHome
 	// DefaultNodeHome is the directory for okchaind
 	DefaultNodeHome = protocol.DefaultNodeHome
+	// ValidateGenesis is the function alias for the validation of the genesis of all modules
+	ValidateGenesis = protocol.ValidateGenesis
 )
@@ -0,0 +1,33 @@
+package app
+
+import (
+	"github.com/okex/okchain/app/protocol"
+	abci "github.com/tendermint/tendermint/abci/types"
+)
+
+// InvariantResult is the outcome of a registered invariant asserted by an audit
+type InvariantResult struct {
+	Route   string
+	Message string
+	Broken  bool
+}
+
+// AuditInvariants asserts all the registered invariants on the loaded state of okchain, and returns the outcome of
+// each one instead of panicking on the first broken invariant
+func (app *OKChainApp) AuditInvariants() []InvariantResult {
+	ctx := app.NewContext(true, abci.Header{Height: app.LastBlockHeight()})
+
+	// get current protocol from engine
+	curProtocol := protocol.GetEngine().GetCurrentProtocol()
+
+	// the depth books and order id lists of order module are cached in memory, which is filled in BeginBlock
+	curProtocol.GetOrderKeeper().ResetCache(ctx)
+
+	invarRoutes := curProtocol.GetCrisisKeeper().Routes()
+	results := make([]InvariantResult, 0, len(invarRoutes))
+	for _, ir := range invarRoutes {
+		msg, broken := ir.Invar(ctx)
+		results = append(results, InvariantResult{Route: ir.FullRoute(), Message: msg, Broken: broken})
+	}
+	return results
+}
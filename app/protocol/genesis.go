@@ -0,0 +1,44 @@
+package protocol
+
+import (
+	"encoding/json"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+
+	"github.com/okex/okchain/x/dex"
+	"github.com/okex/okchain/x/order"
+	"github.com/okex/okchain/x/token"
+)
+
+// ValidateGenesis validates the genesis of every module, then checks the state of the modules depending on each
+// other, which no module is able to check with its own genesis
+func ValidateGenesis(cdc *codec.Codec, genesisState map[string]json.RawMessage) error {
+	if err := ModuleBasics.ValidateGenesis(genesisState); err != nil {
+		return err
+	}
+
+	var orderGenesis order.GenesisState
+	var dexGenesis dex.GenesisState
+	var tokenGenesis token.GenesisState
+	if err := unmarshalModuleGenesis(cdc, genesisState, order.ModuleName, &orderGenesis); err != nil {
+		return err
+	}
+	if err := unmarshalModuleGenesis(cdc, genesisState, dex.ModuleName, &dexGenesis); err != nil {
+		return err
+	}
+	if err := unmarshalModuleGenesis(cdc, genesisState, token.ModuleName, &tokenGenesis); err != nil {
+		return err
+	}
+	return order.ValidateGenesisWithDependencies(orderGenesis, dexGenesis.TokenPairs, tokenGenesis.LockedAssets)
+}
+
+// unmarshalModuleGenesis unmarshals the genesis of the module, which is left empty if the module is not in genesis
+func unmarshalModuleGenesis(cdc *codec.Codec, genesisState map[string]json.RawMessage, moduleName string,
+	data interface{}) error {
+
+	bz, ok := genesisState[moduleName]
+	if !ok {
+		return nil
+	}
+	return cdc.UnmarshalJSON(bz, data)
+}
@@ -3,6 +3,7 @@ package protocol
 import (
 	"encoding/json"
 
+	"github.com/okex/okchain/x/order"
 	"github.com/okex/okchain/x/token"
 
 	"github.com/tendermint/tendermint/libs/log"
@@ -76,3 +77,4 @@ func (*MockProtocol) GetStakingKeeper() staking.Keeper
 func (*MockProtocol) GetDistrKeeper() distr.Keeper                                { return distr.Keeper{} }
 func (*MockProtocol) GetSlashingKeeper() slashing.Keeper                          { return slashing.Keeper{} }
 func (*MockProtocol) GetTokenKeeper() token.Keeper                                { return token.Keeper{} }
+func (*MockProtocol) GetOrderKeeper() order.Keeper                                { return order.Keeper{} }
@@ -31,6 +31,7 @@ func TestMockProtocol(t *testing.T) {
 	require.NotNil(t, mockProtocol.GetDistrKeeper())
 	require.NotNil(t, mockProtocol.GetCrisisKeeper())
 	require.NotNil(t, mockProtocol.GetTokenKeeper())
+	require.NotNil(t, mockProtocol.GetOrderKeeper())
 	require.Panics(t, func() {
 		mockProtocol.GetParent()
 	})
@@ -63,7 +63,7 @@ var (
 		distr.AppModuleBasic{},
 		gov.NewAppModuleBasic(
 			upgradeClient.ProposalHandler, paramsclient.ProposalHandler,
-			dexClient.DelistProposalHandler, distr.ProposalHandler,
+			dexClient.DelistProposalHandler, dexClient.MatchModeProposalHandler, distr.ProposalHandler,
 		),
 		params.AppModuleBasic{},
 		crisis.AppModuleBasic{},
@@ -225,6 +225,11 @@ func (p *ProtocolV0) GetTokenKeeper() token.Keeper {
 	return p.tokenKeeper
 }
 
+// GetOrderKeeper gets order keeper
+func (p *ProtocolV0) GetOrderKeeper() order.Keeper {
+	return p.orderKeeper
+}
+
 // GetKVStoreKeysMap gets the map of kv store keys
 func (p *ProtocolV0) GetKVStoreKeysMap() map[string]*sdk.KVStoreKey {
 	return p.keys
@@ -250,6 +255,13 @@ func (p *ProtocolV0) produceKeepers() {
 		p.logger.Error(fmt.Sprintf("the config of OKChain was parsed error : %s", err.Error()))
 		panic(err)
 	}
+	streamConfig, err := stream.ParseConfig()
+	if err != nil {
+		p.logger.Error(fmt.Sprintf("the stream config of OKChain was parsed error : %s", err.Error()))
+		panic(err)
+	}
+	// the block data of order and token modules is kept in memory for backend and stream modules
+	keepBlockData := appConfig.BackendConfig.EnableBackend || streamConfig.Enable
 
 	// 1.init params keeper and subspaces
 	p.paramsKeeper = params.NewKeeper(
@@ -295,18 +307,18 @@ func (p *ProtocolV0) produceKeepers() {
 	p.tokenKeeper = token.NewKeeper(
 		p.bankKeeper, tokenSubspace, auth.FeeCollectorName, p.supplyKeeper,
 		p.keys[token.StoreKey], p.keys[token.KeyLock],
-		p.cdc, appConfig.BackendConfig.EnableBackend)
+		p.cdc, keepBlockData)
 
 	p.dexKeeper = dex.NewKeeper(auth.FeeCollectorName, p.supplyKeeper, dexSubspace, p.tokenKeeper, &stakingKeeper,
 		p.bankKeeper, p.keys[dex.StoreKey], p.keys[dex.TokenPairStoreKey], p.cdc)
 
 	p.orderKeeper = order.NewKeeper(
 		p.tokenKeeper, p.supplyKeeper, p.dexKeeper, orderSubspace, auth.FeeCollectorName,
-		p.keys[order.OrderStoreKey], p.cdc, appConfig.BackendConfig.EnableBackend, orderMetrics,
+		p.keys[order.OrderStoreKey], p.cdc, keepBlockData, orderMetrics,
 	)
+	p.paramsKeeper.RegisterParamsValidator(order.DefaultParamspace, order.ValidateParamsChange)
 
-	p.streamKeeper = stream.NewKeeper(p.orderKeeper, p.tokenKeeper, p.dexKeeper, p.accountKeeper, p.cdc, p.logger,
-		appConfig, streamMetrics)
+	p.streamKeeper = stream.NewKeeper(p.orderKeeper, p.tokenKeeper, p.logger, streamConfig, streamMetrics)
 
 	p.backendKeeper = backend.NewKeeper(p.orderKeeper, p.tokenKeeper, p.dexKeeper, p.streamKeeper.GetMarketKeeper(),
 		p.cdc, p.logger, appConfig.BackendConfig)
@@ -492,6 +504,8 @@ func validateMsgHook(orderKeeper order.Keeper) auth.ValidateMsgHandler {
 				return order.ValidateMsgNewOrders(newCtx, orderKeeper, assertedMsg)
 			case order.MsgCancelOrders:
 				return order.ValidateMsgCancelOrders(newCtx, orderKeeper, assertedMsg)
+			case order.MsgReplaceOrders:
+				return order.ValidateMsgReplaceOrders(newCtx, orderKeeper, assertedMsg)
 			}
 		}
 		return sdk.Result{}
@@ -505,7 +519,7 @@ func isSystemFreeHook(ctx sdk.Context, msgs []sdk.Msg) bool {
 
 	for _, msg := range msgs {
 		switch msg.(type) {
-		case order.MsgNewOrders, order.MsgCancelOrders:
+		case order.MsgNewOrders, order.MsgCancelOrders, order.MsgReplaceOrders:
 		default:
 			return false
 		}
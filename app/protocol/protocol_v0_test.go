@@ -80,6 +80,7 @@ func TestProtocolV0_InitChainer_BeginBlocker_EndBlocker_ExportGenesis_ExportAppS
 	require.NotNil(t, protocolV0.GetCrisisKeeper())
 	require.NotNil(t, protocolV0.GetStakingKeeper())
 	require.NotNil(t, protocolV0.GetSlashingKeeper())
+	require.NotNil(t, protocolV0.GetOrderKeeper())
 
 	/****************************** test InitChainer ******************************/
 
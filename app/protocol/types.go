@@ -3,6 +3,7 @@ package protocol
 import (
 	"encoding/json"
 
+	"github.com/okex/okchain/x/order"
 	"github.com/okex/okchain/x/token"
 
 	"github.com/tendermint/tendermint/libs/log"
@@ -49,6 +50,7 @@ type Protocol interface {
 	GetDistrKeeper() distr.Keeper
 	GetSlashingKeeper() slashing.Keeper
 	GetTokenKeeper() token.Keeper
+	GetOrderKeeper() order.Keeper
 
 	// fit cm36
 	GetKVStoreKeysMap() map[string]*sdk.KVStoreKey
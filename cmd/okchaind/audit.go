@@ -0,0 +1,70 @@
+package main
+
+// DONTCOVER
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/cosmos/cosmos-sdk/server"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/okex/okchain/app"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/tendermint/tendermint/libs/cli"
+)
+
+const flagAuditHeight = "height"
+
+// get cmd to assert the registered invariants against the state in the data directory of a stopped node
+func auditCmd(ctx *server.Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "audit",
+		Short: "Assert all the registered invariants against the state of a data directory",
+		Long: `audit loads the application state from the data directory under --home and asserts every
+invariant registered to the crisis module on it, e.g. the depth book, lock and order id list checks of
+the order module. The node must be stopped, since the application database is opened exclusively.
+
+Example:
+	okchaind audit --home ~/.okchaind
+	okchaind audit --home ~/.okchaind --height 1000
+	`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			config := ctx.Config
+			config.SetRoot(viper.GetString(cli.HomeFlag))
+
+			db, err := sdk.NewLevelDB("application", filepath.Join(config.RootDir, "data"))
+			if err != nil {
+				return fmt.Errorf("failed to open the application database: %v", err)
+			}
+			defer db.Close()
+
+			height := viper.GetInt64(flagAuditHeight)
+			gApp := app.NewOKChainApp(ctx.Logger, db, nil, height == -1, uint(1))
+			if height != -1 {
+				if err := gApp.LoadHeight(height); err != nil {
+					return err
+				}
+			}
+
+			var brokenNum int
+			for _, result := range gApp.AuditInvariants() {
+				if !result.Broken {
+					fmt.Printf("%s: ok\n", result.Route)
+					continue
+				}
+				brokenNum++
+				fmt.Printf("%s: broken\n%s\n", result.Route, result.Message)
+			}
+
+			if brokenNum != 0 {
+				return fmt.Errorf("%d invariants broken at height %d", brokenNum, gApp.LastBlockHeight())
+			}
+			fmt.Printf("all invariants hold at height %d\n", gApp.LastBlockHeight())
+			return nil
+		},
+	}
+
+	cmd.Flags().Int64(flagAuditHeight, -1, "Audit the state at a particular height (defaults to the latest height)")
+	return cmd
+}
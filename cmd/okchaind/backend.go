@@ -0,0 +1,358 @@
+package main
+
+// DONTCOVER
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/cosmos/cosmos-sdk/baseapp"
+	"github.com/cosmos/cosmos-sdk/server"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/okex/okchain/app"
+	"github.com/okex/okchain/app/protocol"
+	"github.com/okex/okchain/x/backend"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	abci "github.com/tendermint/tendermint/abci/types"
+	tmcfg "github.com/tendermint/tendermint/config"
+	"github.com/tendermint/tendermint/crypto/tmhash"
+	"github.com/tendermint/tendermint/libs/cli"
+	"github.com/tendermint/tendermint/libs/log"
+	sm "github.com/tendermint/tendermint/state"
+	"github.com/tendermint/tendermint/store"
+	tmtypes "github.com/tendermint/tendermint/types"
+	dbm "github.com/tendermint/tm-db"
+)
+
+const (
+	flagFromHeight = "from-height"
+	flagToHeight   = "to-height"
+	flagEngineType = "engine-type"
+	flagConnectStr = "connect-str"
+
+	reindexLogInterval = 1000
+)
+
+// get cmd to maintain the backend database of a stopped node
+func backendCmd(ctx *server.Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "backend",
+		Short: "Maintain the backend database of a stopped node",
+	}
+	cmd.AddCommand(reindexCmd(ctx))
+	return cmd
+}
+
+// get cmd to regenerate the backend data by replaying the blocks in the local block store
+func reindexCmd(ctx *server.Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "reindex",
+		Short: "Regenerate the backend data by replaying the blocks in the local block store",
+		Long: `reindex replays the blocks [from-height, to-height] of the local block store on a scratch copy of
+the application state without running consensus, and regenerates the orders, deals, match results, fee
+details, transactions and klines of backend module into the database given by --engine-type and
+--connect-str (defaults to the backend database in app.toml).
+
+A fresh database is filled from --from-height on. A database already indexed up to some height is resumed
+from the next block, so the blocks missed by a node whose backend database failed can be filled in with
+--from-height set to the first missing block. The state of height from-height - 1 must still be kept by the
+application database, and the results of the replayed blocks are checked against the ones in the state
+database. The node must be stopped, since its databases are opened exclusively.
+
+Example:
+	okchaind backend reindex --home ~/.okchaind --from-height 1 \
+		--engine-type sqlite3 --connect-str ~/.okchaind/data/sqlite3/backend_reindex.db
+	okchaind backend reindex --home ~/.okchaind --from-height 1 \
+		--engine-type postgres --connect-str "host=127.0.0.1 user=okdexer dbname=okdex sslmode=disable"
+	okchaind backend reindex --home ~/.okchaind --from-height 2001 --to-height 3000
+		`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			config := ctx.Config
+			config.SetRoot(viper.GetString(cli.HomeFlag))
+
+			engineType := viper.GetString(flagEngineType)
+			if engineType == "" {
+				engineType = viper.GetString("backend.orm_engine.engine_type")
+			}
+			connectStr := viper.GetString(flagConnectStr)
+			if connectStr == "" {
+				connectStr = viper.GetString("backend.orm_engine.connect_str")
+			}
+			if engineType == "" || connectStr == "" {
+				return fmt.Errorf("the backend database is neither given by flags nor configured in app.toml")
+			}
+
+			return reindexBackend(ctx.Logger, config, engineType, connectStr,
+				viper.GetInt64(flagFromHeight), viper.GetInt64(flagToHeight))
+		},
+	}
+
+	cmd.Flags().Int64(flagFromHeight, 0, "The first block height to reindex")
+	cmd.Flags().Int64(flagToHeight, 0, "The last block height to reindex (defaults to the latest height of the block store)")
+	cmd.Flags().String(flagEngineType, "", "The engine type of the backend database to fill, sqlite3, mysql or postgres (defaults to the one in app.toml)")
+	cmd.Flags().String(flagConnectStr, "", "The connect string of the backend database to fill (defaults to the one in app.toml)")
+	if err := cmd.MarkFlagRequired(flagFromHeight); err != nil {
+		panic(err)
+	}
+	return cmd
+}
+
+func reindexBackend(logger log.Logger, config *tmcfg.Config, engineType, connectStr string,
+	fromHeight, toHeight int64) error {
+	blockStoreDB := dbm.NewDB("blockstore", dbm.DBBackendType(config.DBBackend), config.DBDir())
+	defer blockStoreDB.Close()
+	blockStore := store.NewBlockStore(blockStoreDB)
+
+	if toHeight == 0 || toHeight > blockStore.Height() {
+		toHeight = blockStore.Height()
+	}
+	if fromHeight <= tmtypes.GetStartBlockHeight() || fromHeight > toHeight {
+		return fmt.Errorf("invalid height range [%d, %d], the block store holds the blocks [%d, %d]",
+			fromHeight, toHeight, tmtypes.GetStartBlockHeight()+1, blockStore.Height())
+	}
+
+	stateDB := dbm.NewDB("state", dbm.DBBackendType(config.DBBackend), config.DBDir())
+	defer stateDB.Close()
+
+	// blocks are replayed on a scratch copy, leaving the application state of the node untouched
+	appDir, err := copyApplicationDB(config.DBDir())
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(appDir)
+	appDB, err := sdk.NewLevelDB("application", appDir)
+	if err != nil {
+		return fmt.Errorf("failed to open the copy of application database: %v", err)
+	}
+	defer appDB.Close()
+
+	// the backend keeper of the protocol keeps the caches filled by the modules, while the blocks are indexed by
+	// this command instead of the kline workers, which follow the wall clock
+	viper.Set("backend.enable_backend", true)
+	viper.Set("backend.enable_mkt_compute", false)
+	viper.Set("backend.orm_engine.engine_type", engineType)
+	viper.Set("backend.orm_engine.connect_str", connectStr)
+
+	gApp := app.NewOKChainApp(logger, appDB, nil, false, 0)
+	if fromHeight-1 == tmtypes.GetStartBlockHeight() {
+		genDoc, err := tmtypes.GenesisDocFromFile(config.GenesisFile())
+		if err != nil {
+			return err
+		}
+		gApp.InitChain(initChainRequest(genDoc))
+	} else if err := gApp.LoadHeight(fromHeight - 1); err != nil {
+		return fmt.Errorf("failed to load the application state of height %d: %v", fromHeight-1, err)
+	}
+
+	backendKeeper := protocol.GetEngine().GetCurrentProtocol().GetBackendKeeper()
+	if backendKeeper.Orm == nil {
+		return fmt.Errorf("failed to open the backend database %s of %s", connectStr, engineType)
+	}
+	defer backendKeeper.Stop()
+	backendConfig := *backendKeeper.Config
+	backendConfig.EnableMktCompute = true
+	backendKeeper.Config = &backendConfig
+
+	if unindexed, err := backendKeeper.Orm.HasUnindexedData(); err != nil {
+		return err
+	} else if unindexed {
+		return fmt.Errorf("backend database %s of %s holds data without the indexed height, reindex into a new one",
+			connectStr, engineType)
+	}
+	indexedHeight, err := backendKeeper.Orm.GetIndexedHeight()
+	if err != nil {
+		return err
+	}
+	if indexedHeight < tmtypes.GetStartBlockHeight() {
+		indexedHeight = tmtypes.GetStartBlockHeight()
+	}
+	if indexedHeight+1 < fromHeight {
+		return fmt.Errorf("backend database is indexed up to height %d, reindexing from height %d leaves a gap",
+			indexedHeight, fromHeight)
+	}
+
+	cdc := protocol.GetEngine().GetCurrentProtocol().GetCodec()
+	var lastBlockTimestamp int64
+	for height := fromHeight; height <= toHeight; height++ {
+		block := blockStore.LoadBlock(height)
+		if block == nil {
+			return fmt.Errorf("block %d is not found in the block store", height)
+		}
+
+		commitInfo, byzVals, err := beginBlockValidatorInfo(block, stateDB)
+		if err != nil {
+			return err
+		}
+		gApp.BeginBlock(abci.RequestBeginBlock{
+			Hash:                block.Hash(),
+			Header:              tmtypes.TM2PB.Header(&block.Header),
+			LastCommitInfo:      commitInfo,
+			ByzantineValidators: byzVals,
+		})
+
+		// the results are only missing for the blocks committed while the node was interrupted
+		abciResponses, err := sm.LoadABCIResponses(stateDB, height)
+		if err != nil {
+			abciResponses = nil
+		}
+		for i, txBytes := range block.Txs {
+			res := gApp.DeliverTx(abci.RequestDeliverTx{Tx: txBytes})
+			if abciResponses != nil && res.Code != abciResponses.DeliverTx[i].Code {
+				return fmt.Errorf("tx %d of block %d results in code %d, while it's %d in the state database",
+					i, height, res.Code, abciResponses.DeliverTx[i].Code)
+			}
+			if !res.IsOK() {
+				continue
+			}
+			if tx, err := auth.DefaultTxDecoder(cdc)(txBytes); err == nil {
+				if stdTx, ok := tx.(auth.StdTx); ok {
+					ctx := gApp.GetState(baseapp.RunTxModeDeliver()).Context()
+					backendKeeper.SyncTx(ctx, &stdTx, fmt.Sprintf("%X", tmhash.Sum(txBytes)), block.Time.Unix())
+				}
+			}
+		}
+
+		gApp.EndBlock(abci.RequestEndBlock{Height: height})
+		backend.EndBlocker(gApp.GetState(baseapp.RunTxModeDeliver()).Context(), backendKeeper)
+		if indexedHeight, err = backendKeeper.Orm.GetIndexedHeight(); err != nil {
+			return err
+		} else if indexedHeight < height {
+			return fmt.Errorf("failed to index block %d, the backend database is indexed up to height %d",
+				height, indexedHeight)
+		}
+
+		res := gApp.Commit()
+		if nextMeta := blockStore.LoadBlockMeta(height + 1); nextMeta != nil &&
+			!bytes.Equal(nextMeta.Header.AppHash, res.Data) {
+			return fmt.Errorf("app hash of block %d is %X, while it's %X in the block store",
+				height, res.Data, nextMeta.Header.AppHash)
+		}
+
+		lastBlockTimestamp = block.Time.Unix()
+		if (height-fromHeight+1)%reindexLogInterval == 0 {
+			logger.Info(fmt.Sprintf("reindexed blocks [%d, %d] of [%d, %d]", fromHeight, height, fromHeight, toHeight))
+		}
+	}
+
+	if err := backendKeeper.Orm.GenerateKlines(lastBlockTimestamp); err != nil {
+		return fmt.Errorf("failed to generate klines: %v", err)
+	}
+
+	fmt.Printf("reindexed blocks [%d, %d] into %s of %s\n", fromHeight, toHeight, connectStr, engineType)
+	return nil
+}
+
+// copyApplicationDB copies the application database in dbDir into a temporary directory. The database is opened
+// first, so that a running node, which holds its lock, is detected
+func copyApplicationDB(dbDir string) (string, error) {
+	db, err := sdk.NewLevelDB("application", dbDir)
+	if err != nil {
+		return "", fmt.Errorf("failed to open the application database, the node must be stopped: %v", err)
+	}
+	db.Close()
+
+	tmpDir, err := ioutil.TempDir("", "okchaind-reindex")
+	if err != nil {
+		return "", err
+	}
+
+	srcDir := filepath.Join(dbDir, "application.db")
+	err = filepath.Walk(srcDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		relPath, err := filepath.Rel(srcDir, path)
+		if err != nil {
+			return err
+		}
+		destPath := filepath.Join(tmpDir, "application.db", relPath)
+		if info.IsDir() {
+			return os.MkdirAll(destPath, info.Mode())
+		}
+		return copyFile(path, destPath)
+	})
+	if err != nil {
+		os.RemoveAll(tmpDir)
+		return "", fmt.Errorf("failed to copy the application database: %v", err)
+	}
+	return tmpDir, nil
+}
+
+func copyFile(src, dest string) error {
+	srcFile, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer srcFile.Close()
+
+	destFile, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(destFile, srcFile); err != nil {
+		destFile.Close()
+		return err
+	}
+	return destFile.Close()
+}
+
+// initChainRequest builds the InitChain request from the genesis doc, the same way as tendermint does at genesis
+func initChainRequest(genDoc *tmtypes.GenesisDoc) abci.RequestInitChain {
+	validators := make([]*tmtypes.Validator, len(genDoc.Validators))
+	for i, val := range genDoc.Validators {
+		validators[i] = tmtypes.NewValidator(val.PubKey, val.Power)
+	}
+
+	return abci.RequestInitChain{
+		Time:            genDoc.GenesisTime,
+		ChainId:         genDoc.ChainID,
+		ConsensusParams: tmtypes.TM2PB.ConsensusParams(genDoc.ConsensusParams),
+		Validators:      tmtypes.TM2PB.ValidatorUpdates(tmtypes.NewValidatorSet(validators)),
+		AppStateBytes:   genDoc.AppState,
+	}
+}
+
+// beginBlockValidatorInfo builds the last commit info and evidences of the BeginBlock request for a block, the same
+// way as tendermint does when the block is executed
+func beginBlockValidatorInfo(block *tmtypes.Block, stateDB dbm.DB) (abci.LastCommitInfo, []abci.Evidence, error) {
+	lastValSet := tmtypes.NewValidatorSet(nil)
+	if block.Height > tmtypes.GetStartBlockHeight()+1 {
+		var err error
+		if lastValSet, err = sm.LoadValidators(stateDB, block.Height-1); err != nil {
+			return abci.LastCommitInfo{}, nil, err
+		}
+		if block.LastCommit.Size() != len(lastValSet.Validators) {
+			return abci.LastCommitInfo{}, nil, fmt.Errorf("precommit length (%d) doesn't match valset length (%d) "+
+				"at height %d", block.LastCommit.Size(), len(lastValSet.Validators), block.Height)
+		}
+	}
+
+	voteInfos := make([]abci.VoteInfo, len(lastValSet.Validators))
+	for i, val := range lastValSet.Validators {
+		var vote *tmtypes.CommitSig
+		if i < len(block.LastCommit.Precommits) {
+			vote = block.LastCommit.Precommits[i]
+		}
+		voteInfos[i] = abci.VoteInfo{
+			Validator:       tmtypes.TM2PB.Validator(val),
+			SignedLastBlock: vote != nil,
+		}
+	}
+
+	byzVals := make([]abci.Evidence, len(block.Evidence.Evidence))
+	for i, ev := range block.Evidence.Evidence {
+		valSet, err := sm.LoadValidators(stateDB, ev.Height())
+		if err != nil {
+			return abci.LastCommitInfo{}, nil, err
+		}
+		byzVals[i] = tmtypes.TM2PB.Evidence(ev, valSet, block.Time)
+	}
+
+	return abci.LastCommitInfo{Round: int32(block.LastCommit.Round()), Votes: voteInfos}, byzVals, nil
+}
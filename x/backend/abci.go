@@ -15,98 +15,48 @@ import (
 func EndBlocker(ctx sdk.Context, keeper Keeper) {
 	if keeper.Config.EnableBackend && keeper.Config.EnableMktCompute {
 		keeper.Logger.Debug(fmt.Sprintf("begin backend endblocker: block---%d", ctx.BlockHeight()))
-		storeNewOrders(ctx, keeper)
-		updateOrders(ctx, keeper)
-		storeDealAndMatchResult(ctx, keeper)
-		storeFeeDetails(keeper)
-		storeTransactions(keeper)
+		storeBlock(ctx, keeper)
 		keeper.Flush()
 		keeper.Logger.Debug(fmt.Sprintf("end backend endblocker: block---%d", ctx.BlockHeight()))
 	}
 }
 
-func storeTransactions(keeper Keeper) {
+// storeBlock commits the orders, deals, match results, fee details and transactions of a block in one database
+// transaction together with the indexed height
+func storeBlock(ctx sdk.Context, keeper Keeper) {
 	defer types.PrintStackIfPanic()
 
-	txs := keeper.Cache.GetTransactions()
-	txsLen := len(txs)
+	blockHeight := ctx.BlockHeight()
+	timestamp := ctx.BlockHeader().Time.Unix()
 
-	cnt, err := keeper.Orm.AddTransactions(txs)
+	newOrders, err := GetNewOrdersAtEndBlock(ctx, keeper.OrderKeeper)
 	if err != nil {
-		keeper.Logger.Error(fmt.Sprintf("[backend] Expect to insert %d txs, inserted Count %d, err: %+v", txsLen, cnt, err))
-	} else {
-		keeper.Logger.Debug(fmt.Sprintf("[backend] Expect to insert %d txs, inserted Count %d", txsLen, cnt))
+		keeper.Logger.Error(fmt.Sprintf("[backend] failed to GetNewOrdersAtEndBlock, error: %s", err.Error()))
+		return
 	}
-}
-
-func storeDealAndMatchResult(ctx sdk.Context, keeper Keeper) {
-	timestamp := ctx.BlockHeader().Time.Unix()
-	keeper.Orm.MaxBlockTimestamp = timestamp
+	updatedOrders := GetUpdatedOrdersAtEndBlock(ctx, keeper.OrderKeeper)
 	deals, results, err := GetNewDealsAndMatchResultsAtEndBlock(ctx, keeper.OrderKeeper)
-
 	if err != nil {
 		keeper.Logger.Error(fmt.Sprintf("[backend] failed to GetNewDealsAndMatchResultsAtEndBlock, error: %s", err.Error()))
+		return
 	}
-
-	if len(results) > 0 {
-		cnt, err := keeper.Orm.AddMatchResults(results)
-		if err != nil {
-			keeper.Logger.Error(fmt.Sprintf("[backend] Expect to insert %d matchResults, inserted Count %d, err: %+v", len(results), cnt, err))
-		} else {
-			keeper.Logger.Debug(fmt.Sprintf("[backend] Expect to insert %d matchResults, inserted Count %d", len(results), cnt))
-		}
-	}
-	if len(deals) > 0 {
-		cnt, err := keeper.Orm.AddDeals(deals)
-		if err != nil {
-			keeper.Logger.Error(fmt.Sprintf("[backend] Expect to insert %d deals, inserted Count %d, err: %+v", len(deals), cnt, err))
-		} else {
-			keeper.Logger.Debug(fmt.Sprintf("[backend] Expect to insert %d deals, inserted Count %d", len(deals), cnt))
-		}
-	}
-
-	ts := keeper.Orm.MaxBlockTimestamp
-	keeper.UpdateTickersBuffer(ts-types.SecondsInADay, ts+1, keeper.Cache.ProductsBuf)
-}
-
-func storeFeeDetails(keeper Keeper) {
 	feeDetails := keeper.TokenKeeper.GetFeeDetailList()
-	if len(feeDetails) > 0 {
-		cnt, err := keeper.Orm.AddFeeDetails(feeDetails)
-		if err != nil {
-			keeper.Logger.Error(fmt.Sprintf("[backend] Expect to insert %d feeDetails, inserted Count %d, err: %+v", len(feeDetails), cnt, err))
-		} else {
-			keeper.Logger.Debug(fmt.Sprintf("[backend] Expect to insert %d feeDetails, inserted Count %d", len(feeDetails), cnt))
-		}
-	}
-}
+	txs := keeper.Cache.GetTransactions()
 
-func storeNewOrders(ctx sdk.Context, keeper Keeper) {
-	orders, err := GetNewOrdersAtEndBlock(ctx, keeper.OrderKeeper)
+	resultMap, err := keeper.Orm.IndexBlock(blockHeight, timestamp, newOrders, updatedOrders, deals, results, feeDetails, txs)
 	if err != nil {
-		keeper.Logger.Error(fmt.Sprintf("[backend] failed to GetNewOrdersAtEndBlock, error: %s", err.Error()))
+		keeper.Logger.Error(fmt.Sprintf("[backend] failed to index block %d, the backend stops serving until "+
+			"the missing blocks are reindexed by `okchaind backend reindex`, err: %+v", blockHeight, err))
+		return
 	}
-
-	if len(orders) > 0 {
-		cnt, err := keeper.Orm.AddOrders(orders)
-		if err != nil {
-			keeper.Logger.Error(fmt.Sprintf("[backend] Expect to insert %d orders, inserted Count %d, err: %+v", len(orders), cnt, err))
-		} else {
-			keeper.Logger.Debug(fmt.Sprintf("[backend] Expect to insert %d orders, inserted Count %d", len(orders), cnt))
-		}
+	if resultMap == nil {
+		keeper.Logger.Info(fmt.Sprintf("[backend] block %d has been indexed already, skipped", blockHeight))
+	} else {
+		keeper.Logger.Debug(fmt.Sprintf("[backend] block %d indexed: %v", blockHeight, resultMap))
 	}
-}
 
-func updateOrders(ctx sdk.Context, keeper Keeper) {
-	orders := GetUpdatedOrdersAtEndBlock(ctx, keeper.OrderKeeper)
-	if len(orders) > 0 {
-		cnt, err := keeper.Orm.UpdateOrders(orders)
-		if err != nil {
-			keeper.Logger.Error(fmt.Sprintf("[backend] Expect to update %d orders, updated Count %d, err: %+v", len(orders), cnt, err))
-		} else {
-			keeper.Logger.Debug(fmt.Sprintf("[backend] Expect to update %d orders, updated Count %d", len(orders), cnt))
-		}
-	}
+	keeper.Orm.MaxBlockTimestamp = timestamp
+	keeper.UpdateTickersBuffer(timestamp-types.SecondsInADay, timestamp+1, keeper.Cache.ProductsBuf)
 }
 
 // nolint
@@ -141,6 +91,10 @@ func GetNewDealsAndMatchResultsAtEndBlock(ctx sdk.Context, orderKeeper types.Ord
 
 		for _, record := range matchResult.Deals {
 			order := orderKeeper.GetOrder(ctx, record.OrderID)
+			dealPrice, err := strconv.ParseFloat(record.Price.String(), 64)
+			if err != nil {
+				return deals, results, err
+			}
 			if quantity, err := strconv.ParseFloat(record.Quantity.String(), 64); err == nil {
 
 				deal := &types.Deal{
@@ -149,7 +103,7 @@ func GetNewDealsAndMatchResultsAtEndBlock(ctx sdk.Context, orderKeeper types.Ord
 					Side:        record.Side,
 					Sender:      order.Sender.String(),
 					Product:     product,
-					Price:       price,
+					Price:       dealPrice,
 					Quantity:    quantity,
 					Fee:         record.Fee,
 					Timestamp:   ctx.BlockHeader().Time.Unix(),
@@ -185,6 +139,8 @@ func GetNewOrdersAtEndBlock(ctx sdk.Context, orderKeeper types.OrderKeeper) ([]*
 				RemainQuantity: order.RemainQuantity.String(),
 				Timestamp:      order.Timestamp,
 			}
+			orderDb.SetTrigger(order.Trigger)
+			orderDb.SetDisplayQuantity(order)
 			orders = append(orders, orderDb)
 		} else {
 			return nil, fmt.Errorf("failed to get order with orderID: %+v at blockHeight: %d", orderID, blockHeight)
@@ -213,6 +169,8 @@ func GetUpdatedOrdersAtEndBlock(ctx sdk.Context, orderKeeper types.OrderKeeper)
 				RemainQuantity: order.RemainQuantity.String(),
 				Timestamp:      order.Timestamp,
 			}
+			orderDb.SetTrigger(order.Trigger)
+			orderDb.SetDisplayQuantity(order)
 			orders = append(orders, orderDb)
 		}
 	}
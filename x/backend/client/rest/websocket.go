@@ -0,0 +1,372 @@
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/client/context"
+	"github.com/gorilla/websocket"
+)
+
+// The websocket endpoint pushes the backend data to the subscribers once a block is committed, instead of
+// having them poll the rest handlers.
+//
+// A client subscribes and unsubscribes by sending
+//
+//	{"op": "subscribe", "args": ["ticker:xxb_okt", "candle60:xxb_okt", "depth:xxb_okt", "order:okchain1..."]}
+//	{"op": "unsubscribe", "args": ["ticker:xxb_okt"]}
+//
+// and is acknowledged with {"event": "subscribe", "channel": "ticker:xxb_okt"}, or
+// {"event": "error", "message": "..."} when the request is refused.
+//
+// The data of a channel is pushed as
+//
+//	{"channel": "depth:xxb_okt", "action": "snapshot", "seq": 12, "height": 100, "data": ...}
+//
+// A subscriber gets a snapshot of the channel first, then an update with the changes of each block that changes
+// the channel. The seq of a channel is increased by one with each update, so a client missing one (its messages
+// are dropped when it doesn't keep up) notices the gap and subscribes again for a new snapshot. A block with more
+// changes than a channel is able to query at a time is pushed as a snapshot of the next seq instead of an update.
+const (
+	wsPath = "/ws"
+
+	wsOpSubscribe   = "subscribe"
+	wsOpUnsubscribe = "unsubscribe"
+	wsEventError    = "error"
+
+	wsActionSnapshot = "snapshot"
+	wsActionUpdate   = "update"
+
+	wsPollInterval   = time.Second
+	wsWriteTimeout   = 10 * time.Second
+	wsSendBufferSize = 256
+)
+
+type wsRequest struct {
+	Op   string   `json:"op"`
+	Args []string `json:"args"`
+}
+
+type wsEvent struct {
+	Event   string `json:"event"`
+	Channel string `json:"channel,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+type wsPush struct {
+	Channel string      `json:"channel"`
+	Action  string      `json:"action"`
+	Seq     int64       `json:"seq"`
+	Height  int64       `json:"height"`
+	Data    interface{} `json:"data"`
+}
+
+// wsQuerier queries the node for the hub
+type wsQuerier interface {
+	// LatestHeight returns the height of the latest block committed
+	LatestHeight() (int64, error)
+	// Query queries the custom path with the params in json
+	Query(path string, params interface{}) ([]byte, error)
+}
+
+type cliQuerier struct {
+	cliCtx context.CLIContext
+}
+
+func (q cliQuerier) LatestHeight() (int64, error) {
+	node, err := q.cliCtx.GetNode()
+	if err != nil {
+		return 0, err
+	}
+	status, err := node.Status()
+	if err != nil {
+		return 0, err
+	}
+	return status.SyncInfo.LatestBlockHeight, nil
+}
+
+func (q cliQuerier) Query(path string, params interface{}) ([]byte, error) {
+	bz, err := q.cliCtx.Codec.MarshalJSON(params)
+	if err != nil {
+		return nil, err
+	}
+	res, _, err := q.cliCtx.QueryWithData(path, bz)
+	return res, err
+}
+
+// wsClient is a websocket connection. Its messages are written by its own goroutine from sendCh
+type wsClient struct {
+	conn   *websocket.Conn
+	mtx    sync.Mutex
+	sendCh chan []byte
+	closed bool
+}
+
+func newWSClient(conn *websocket.Conn) *wsClient {
+	return &wsClient{
+		conn:   conn,
+		sendCh: make(chan []byte, wsSendBufferSize),
+	}
+}
+
+// send queues the message in json
+func (c *wsClient) send(v interface{}) {
+	if msg, err := json.Marshal(v); err == nil {
+		c.sendMsg(msg)
+	}
+}
+
+// sendMsg queues the message, which is dropped if the client is closed or too slow to keep up
+func (c *wsClient) sendMsg(msg []byte) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if c.closed {
+		return
+	}
+	select {
+	case c.sendCh <- msg:
+	default:
+	}
+}
+
+func (c *wsClient) sendError(format string, args ...interface{}) {
+	c.send(wsEvent{Event: wsEventError, Message: fmt.Sprintf(format, args...)})
+}
+
+func (c *wsClient) close() {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if !c.closed {
+		c.closed = true
+		close(c.sendCh)
+	}
+}
+
+func (c *wsClient) writeLoop() {
+	defer c.conn.Close()
+	for msg := range c.sendCh {
+		if err := c.conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout)); err != nil {
+			return
+		}
+		if err := c.conn.WriteMessage(websocket.TextMessage, msg); err != nil {
+			return
+		}
+	}
+	msg := websocket.FormatCloseMessage(websocket.CloseNormalClosure, "")
+	_ = c.conn.WriteControl(websocket.CloseMessage, msg, time.Now().Add(wsWriteTimeout))
+}
+
+// readLoop handles the requests of the client until it disconnects
+func (c *wsClient) readLoop(hub *wsHub) {
+	defer hub.unregister(c)
+	for {
+		_, bz, err := c.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		var req wsRequest
+		if err := json.Unmarshal(bz, &req); err != nil {
+			c.sendError("invalid request: %s", err.Error())
+			continue
+		}
+		if req.Op != wsOpSubscribe && req.Op != wsOpUnsubscribe {
+			c.sendError("unknown op: %s", req.Op)
+			continue
+		}
+		for _, channel := range req.Args {
+			if !hub.request(wsHubRequest{client: c, op: req.Op, channel: channel}) {
+				return
+			}
+		}
+	}
+}
+
+// wsChannel is a channel with at least one subscriber
+type wsChannel struct {
+	source  wsSource
+	seq     int64
+	height  int64 // the height of the latest block the source is refreshed at
+	clients map[*wsClient]struct{}
+}
+
+type wsHubRequest struct {
+	client  *wsClient
+	op      string
+	channel string
+}
+
+// wsHub keeps the channels subscribed, and refreshes them once a new block is committed. The channels are only
+// accessed by the goroutine of the hub
+type wsHub struct {
+	querier  wsQuerier
+	interval time.Duration
+	upgrader websocket.Upgrader
+	channels map[string]*wsChannel
+
+	startOnce sync.Once
+	stopOnce  sync.Once
+	requestCh chan wsHubRequest
+	quitCh    chan struct{}
+}
+
+func newWSHub(querier wsQuerier, interval time.Duration) *wsHub {
+	return &wsHub{
+		querier:  querier,
+		interval: interval,
+		upgrader: websocket.Upgrader{
+			// the same data is served by the rest handlers to any origin
+			CheckOrigin: func(r *http.Request) bool { return true },
+		},
+		channels:  make(map[string]*wsChannel),
+		requestCh: make(chan wsHubRequest),
+		quitCh:    make(chan struct{}),
+	}
+}
+
+// start runs the hub on the first connection, so nothing is polled while no client is around
+func (h *wsHub) start() {
+	h.startOnce.Do(func() {
+		go h.run()
+	})
+}
+
+func (h *wsHub) stop() {
+	h.stopOnce.Do(func() {
+		close(h.quitCh)
+	})
+}
+
+func (h *wsHub) request(req wsHubRequest) bool {
+	select {
+	case h.requestCh <- req:
+		return true
+	case <-h.quitCh:
+		return false
+	}
+}
+
+func (h *wsHub) unregister(client *wsClient) {
+	h.request(wsHubRequest{client: client})
+	client.close()
+}
+
+func (h *wsHub) run() {
+	ticker := time.NewTicker(h.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case req := <-h.requestCh:
+			switch req.op {
+			case wsOpSubscribe:
+				h.subscribe(req.client, req.channel)
+			case wsOpUnsubscribe:
+				h.unsubscribe(req.client, req.channel)
+				req.client.send(wsEvent{Event: wsOpUnsubscribe, Channel: req.channel})
+			default:
+				for name := range h.channels {
+					h.unsubscribe(req.client, name)
+				}
+			}
+		case <-ticker.C:
+			h.poll()
+		case <-h.quitCh:
+			return
+		}
+	}
+}
+
+func (h *wsHub) subscribe(client *wsClient, name string) {
+	ch, ok := h.channels[name]
+	if !ok {
+		source, err := newWSSource(name)
+		if err != nil {
+			client.sendError(err.Error())
+			return
+		}
+		height, err := h.querier.LatestHeight()
+		if err != nil {
+			client.sendError("failed to subscribe %s: %s", name, err.Error())
+			return
+		}
+		if _, err := source.refresh(h.querier); err != nil {
+			client.sendError("failed to subscribe %s: %s", name, err.Error())
+			return
+		}
+		ch = &wsChannel{source: source, height: height, clients: make(map[*wsClient]struct{})}
+		h.channels[name] = ch
+	}
+
+	ch.clients[client] = struct{}{}
+	client.send(wsEvent{Event: wsOpSubscribe, Channel: name})
+	client.send(wsPush{Channel: name, Action: wsActionSnapshot, Seq: ch.seq, Height: ch.height,
+		Data: ch.source.snapshot()})
+}
+
+func (h *wsHub) unsubscribe(client *wsClient, name string) {
+	ch, ok := h.channels[name]
+	if !ok {
+		return
+	}
+	delete(ch.clients, client)
+	if len(ch.clients) == 0 {
+		delete(h.channels, name)
+	}
+}
+
+// poll refreshes the channels once a new block is committed, and pushes the changes to the subscribers
+func (h *wsHub) poll() {
+	if len(h.channels) == 0 {
+		return
+	}
+	height, err := h.querier.LatestHeight()
+	if err != nil {
+		return
+	}
+
+	for name, ch := range h.channels {
+		if ch.height >= height {
+			continue
+		}
+		// the channel failing to be refreshed is tried again at the next poll, and nothing is missed
+		action := wsActionUpdate
+		update, err := ch.source.refresh(h.querier)
+		if err == errWSTruncated {
+			action, update = wsActionSnapshot, ch.source.snapshot()
+		} else if err != nil {
+			continue
+		}
+		ch.height = height
+		if update == nil {
+			continue
+		}
+
+		ch.seq++
+		msg, err := json.Marshal(wsPush{Channel: name, Action: action, Seq: ch.seq, Height: ch.height,
+			Data: update})
+		if err != nil {
+			continue
+		}
+		for client := range ch.clients {
+			client.sendMsg(msg)
+		}
+	}
+}
+
+func wsHandler(hub *wsHub) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		conn, err := hub.upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			// the upgrader has replied the error
+			return
+		}
+		hub.start()
+
+		client := newWSClient(conn)
+		go client.writeLoop()
+		client.readLoop(hub)
+	}
+}
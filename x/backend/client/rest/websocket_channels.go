@@ -0,0 +1,447 @@
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/okex/okchain/x/backend/types"
+	"github.com/okex/okchain/x/common"
+	orderkeeper "github.com/okex/okchain/x/order/keeper"
+	ordertypes "github.com/okex/okchain/x/order/types"
+)
+
+// the channels subscribable, as <channel>:<product> or <channel>:<address>.
+// candles are subscribed as candle<granularity>:<product>, with any granularity of types.GetAllKlineMap
+const (
+	wsChannelTicker = "ticker"
+	wsChannelCandle = "candle"
+	wsChannelDepth  = "depth"
+	wsChannelOrder  = "order"
+	wsChannelDeal   = "deal"
+
+	wsCandleSize = 100
+	wsDepthSize  = 200
+	wsListSize   = 100
+	// wsMaxListPages is the most pages of wsListSize a list channel queries at a refresh
+	wsMaxListPages = 10
+)
+
+// errWSTruncated is returned by the refresh of a source which has refreshed its snapshot, but left some changes out
+// of the update as they are beyond wsMaxListPages. The subscribers are pushed the snapshot instead
+var errWSTruncated = errors.New("too many changes in a block")
+
+// wsSource is the data of a channel
+type wsSource interface {
+	// refresh queries the latest data, and returns the changes since the last refresh, nil if nothing changed
+	refresh(q wsQuerier) (interface{}, error)
+	// snapshot returns the data of the last refresh
+	snapshot() interface{}
+}
+
+func newWSSource(channel string) (wsSource, error) {
+	parts := strings.SplitN(channel, ":", 2)
+	if len(parts) != 2 || parts[1] == "" {
+		return nil, fmt.Errorf("invalid channel: %s", channel)
+	}
+	name, arg := parts[0], parts[1]
+
+	switch name {
+	case wsChannelTicker:
+		return &tickerSource{product: arg}, nil
+	case wsChannelDepth:
+		return &depthSource{product: arg}, nil
+	case wsChannelOrder, wsChannelDeal:
+		if _, err := sdk.AccAddressFromBech32(arg); err != nil {
+			return nil, fmt.Errorf("invalid address of channel %s: %s", channel, err.Error())
+		}
+		if name == wsChannelOrder {
+			return &orderSource{address: arg}, nil
+		}
+		return &dealSource{address: arg}, nil
+	}
+
+	if strings.HasPrefix(name, wsChannelCandle) {
+		granularity, err := strconv.Atoi(strings.TrimPrefix(name, wsChannelCandle))
+		if err == nil {
+			if _, ok := types.GetAllKlineMap()[granularity]; ok {
+				return &candleSource{product: arg, granularity: granularity}, nil
+			}
+		}
+		return nil, fmt.Errorf("invalid granularity of channel %s", channel)
+	}
+	return nil, fmt.Errorf("unknown channel: %s", channel)
+}
+
+// parseBaseResponse unmarshals the data of a common.BaseResponse replied by the backend querier
+func parseBaseResponse(bz []byte, data interface{}) error {
+	var res struct {
+		Code      int             `json:"code"`
+		Msg       string          `json:"msg"`
+		DetailMsg string          `json:"detail_msg"`
+		Data      json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(bz, &res); err != nil {
+		return err
+	}
+	if res.Code != 0 {
+		return errors.New(res.Msg + res.DetailMsg)
+	}
+	return json.Unmarshal(res.Data, data)
+}
+
+// parseListResponse unmarshals the data of a common.ListResponse replied by the backend querier, and returns the
+// total number of the list
+func parseListResponse(bz []byte, data interface{}) (int, error) {
+	var list struct {
+		Data      json.RawMessage  `json:"data"`
+		ParamPage common.ParamPage `json:"param_page"`
+	}
+	if err := parseBaseResponse(bz, &list); err != nil {
+		return 0, err
+	}
+	return list.ParamPage.Total, json.Unmarshal(list.Data, data)
+}
+
+// queryPages queries the pages of a list from the first on, as long as query tells there are more to query, up to
+// maxPages pages. It returns false if some of the list wanted is left
+func queryPages(maxPages int, query func(page int) (more bool, err error)) (bool, error) {
+	for page := 1; page <= maxPages; page++ {
+		more, err := query(page)
+		if err != nil {
+			return false, err
+		}
+		if !more {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// tickerSource pushes the ticker of a product whenever it changes
+type tickerSource struct {
+	product string
+	ticker  *types.Ticker
+}
+
+func (s *tickerSource) refresh(q wsQuerier) (interface{}, error) {
+	params := types.QueryTickerParams{Product: s.product, Count: 1}
+	bz, err := q.Query(fmt.Sprintf("custom/backend/%s", types.QueryTickerList), params)
+	if err != nil {
+		return nil, err
+	}
+	var tickers []types.Ticker
+	if err := parseBaseResponse(bz, &tickers); err != nil {
+		return nil, err
+	}
+	if len(tickers) == 0 {
+		return nil, fmt.Errorf("no ticker of product %s", s.product)
+	}
+
+	ticker := tickers[0]
+	// the timestamp is left out of the comparison, as the one of a product never dealt is the time of the query
+	if s.ticker != nil {
+		last := *s.ticker
+		last.Timestamp = ticker.Timestamp
+		if last == ticker {
+			return nil, nil
+		}
+	}
+	s.ticker = &ticker
+	return ticker, nil
+}
+
+func (s *tickerSource) snapshot() interface{} {
+	return s.ticker
+}
+
+// candleSource pushes the candles of a product changed, which are the latest one and the one it just closed
+type candleSource struct {
+	product     string
+	granularity int
+	candles     [][]string // in the format of types.ToRestfulData, which starts with the time of the candle
+}
+
+func (s *candleSource) refresh(q wsQuerier) (interface{}, error) {
+	size := wsCandleSize
+	if s.candles != nil {
+		size = 2
+	}
+	params := types.NewQueryKlinesParams(s.product, s.granularity, size)
+	bz, err := q.Query(fmt.Sprintf("custom/backend/%s", types.QueryCandleList), params)
+	if err != nil {
+		return nil, err
+	}
+	var candles [][]string
+	if err := parseBaseResponse(bz, &candles); err != nil {
+		return nil, err
+	}
+
+	changed := [][]string{}
+	for _, candle := range candles {
+		if len(candle) == 0 {
+			continue
+		}
+		found := false
+		for i := len(s.candles) - 1; i >= 0; i-- {
+			if s.candles[i][0] == candle[0] {
+				found = true
+				if !equalStrings(s.candles[i], candle) {
+					s.candles[i] = candle
+					changed = append(changed, candle)
+				}
+				break
+			}
+		}
+		if !found {
+			s.candles = append(s.candles, candle)
+			changed = append(changed, candle)
+		}
+	}
+	if s.candles == nil {
+		s.candles = [][]string{}
+	}
+
+	// the times of candles are formatted in UTC, in which the order of strings is the order of time
+	sort.Slice(s.candles, func(i, j int) bool { return s.candles[i][0] < s.candles[j][0] })
+	if len(s.candles) > wsCandleSize {
+		s.candles = s.candles[len(s.candles)-wsCandleSize:]
+	}
+	if len(changed) == 0 {
+		return nil, nil
+	}
+	return changed, nil
+}
+
+func (s *candleSource) snapshot() interface{} {
+	return s.candles
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// depthSource pushes the price levels of a depth book changed, with a quantity of 0 for the levels removed
+type depthSource struct {
+	product string
+	book    orderkeeper.BookRes
+}
+
+func (s *depthSource) refresh(q wsQuerier) (interface{}, error) {
+	params := orderkeeper.NewQueryDepthBookParams(s.product, wsDepthSize)
+	bz, err := q.Query(fmt.Sprintf("custom/order/%s", ordertypes.QueryDepthBook), params)
+	if err != nil {
+		return nil, err
+	}
+	var book orderkeeper.BookRes
+	if err := json.Unmarshal(bz, &book); err != nil {
+		return nil, err
+	}
+	if book.Asks == nil {
+		book.Asks = []orderkeeper.BookResItem{}
+	}
+	if book.Bids == nil {
+		book.Bids = []orderkeeper.BookResItem{}
+	}
+
+	update := orderkeeper.BookRes{
+		Asks: diffBookItems(s.book.Asks, book.Asks),
+		Bids: diffBookItems(s.book.Bids, book.Bids),
+	}
+	s.book = book
+	if len(update.Asks) == 0 && len(update.Bids) == 0 {
+		return nil, nil
+	}
+	return update, nil
+}
+
+func (s *depthSource) snapshot() interface{} {
+	return s.book
+}
+
+// diffBookItems returns the levels of items changed from last, in the order of items then last
+func diffBookItems(last, items []orderkeeper.BookResItem) []orderkeeper.BookResItem {
+	lastQuantities := make(map[string]string, len(last))
+	for _, item := range last {
+		lastQuantities[item.Price] = item.Quantity
+	}
+
+	diff := []orderkeeper.BookResItem{}
+	for _, item := range items {
+		if quantity, ok := lastQuantities[item.Price]; !ok || quantity != item.Quantity {
+			diff = append(diff, item)
+		}
+		delete(lastQuantities, item.Price)
+	}
+	for _, item := range last {
+		if _, ok := lastQuantities[item.Price]; ok {
+			diff = append(diff, orderkeeper.BookResItem{Price: item.Price, Quantity: sdk.ZeroDec().String()})
+		}
+	}
+	return diff
+}
+
+// orderSource pushes the orders of an address changed, including the ones closed. Its snapshot is the open orders.
+// The orders changed since the last refresh are the open ones, the ones placed after the height of the last refresh,
+// and the ones which were open at the last refresh. The closed ones are paged through in the latest first until
+// these are passed
+type orderSource struct {
+	address string
+	height  int64 // the height of the latest order seen
+	open    []types.Order
+	orders  map[string]types.Order // the open orders and the closed ones queried at the last refresh
+}
+
+// queryOrders queries the orders in the latest first, until more tells the ones left after last are not wanted
+func (s *orderSource) queryOrders(q wsQuerier, openOrClosed string, maxPages int,
+	more func(last types.Order) bool) ([]types.Order, bool, error) {
+	var orders []types.Order
+	complete, err := queryPages(maxPages, func(page int) (bool, error) {
+		// the end is not limited, the orders of the latest block are all listed
+		params := types.NewQueryOrderListParams(s.address, "", "", page, wsListSize, 0, math.MaxInt64, false)
+		bz, err := q.Query(fmt.Sprintf("custom/backend/%s/%s", types.QueryOrderList, openOrClosed), params)
+		if err != nil {
+			return false, err
+		}
+		var pageOrders []types.Order
+		total, err := parseListResponse(bz, &pageOrders)
+		orders = append(orders, pageOrders...)
+		return err == nil && len(pageOrders) == wsListSize && len(orders) < total && more(orders[len(orders)-1]), err
+	})
+	return orders, complete, err
+}
+
+func (s *orderSource) refresh(q wsQuerier) (interface{}, error) {
+	open, openComplete, err := s.queryOrders(q, "open", wsMaxListPages, func(types.Order) bool { return true })
+	if err != nil {
+		return nil, err
+	}
+
+	// the latest closed orders are seen at the first refresh, they are not changes to come
+	firstRefresh := s.orders == nil
+	maxPages := wsMaxListPages
+	if firstRefresh {
+		maxPages = 1
+	}
+	stillOpen := make(map[string]struct{}, len(open))
+	for _, order := range open {
+		stillOpen[order.OrderID] = struct{}{}
+	}
+	var closedSince int64 = math.MaxInt64
+	for _, order := range s.open {
+		if _, ok := stillOpen[order.OrderID]; !ok && order.Timestamp < closedSince {
+			closedSince = order.Timestamp
+		}
+	}
+	closed, closedComplete, err := s.queryOrders(q, "closed", maxPages, func(last types.Order) bool {
+		return ordertypes.GetBlockHeightFromOrderID(last.OrderID) > s.height || last.Timestamp >= closedSince
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	changed := []types.Order{}
+	orders := make(map[string]types.Order, len(open)+len(closed))
+	for _, order := range append(append([]types.Order{}, open...), closed...) {
+		if last, ok := s.orders[order.OrderID]; !ok || last != order {
+			changed = append(changed, order)
+		}
+		orders[order.OrderID] = order
+		if height := ordertypes.GetBlockHeightFromOrderID(order.OrderID); height > s.height {
+			s.height = height
+		}
+	}
+	if open == nil {
+		open = []types.Order{}
+	}
+	s.open, s.orders = open, orders
+	if !firstRefresh && (!openComplete || !closedComplete) {
+		return nil, errWSTruncated
+	}
+	if len(changed) == 0 {
+		return nil, nil
+	}
+	return changed, nil
+}
+
+func (s *orderSource) snapshot() interface{} {
+	return s.open
+}
+
+// dealSource pushes the new deals of an address. Its snapshot is the latest deals. The deals of a block are indexed
+// at once, so the new deals are the ones after the height of the last refresh, which are paged through in the latest
+// first from the timestamp of the last refresh on
+type dealSource struct {
+	address string
+	height  int64 // the height of the latest deal seen
+	since   int64 // the timestamp of the latest deal seen
+	deals   []types.Deal
+}
+
+func (s *dealSource) refresh(q wsQuerier) (interface{}, error) {
+	// the latest deals are seen at the first refresh, they are not changes to come
+	firstRefresh := s.deals == nil
+	maxPages := wsMaxListPages
+	if firstRefresh {
+		maxPages = 1
+	}
+
+	var deals []types.Deal
+	complete, err := queryPages(maxPages, func(page int) (bool, error) {
+		params := types.NewQueryDealsParams(s.address, "", s.since, math.MaxInt64, page, wsListSize, "")
+		bz, err := q.Query(fmt.Sprintf("custom/backend/%s", types.QueryDealList), params)
+		if err != nil {
+			return false, err
+		}
+		var pageDeals []types.Deal
+		total, err := parseListResponse(bz, &pageDeals)
+		deals = append(deals, pageDeals...)
+		return err == nil && len(pageDeals) == wsListSize && len(deals) < total &&
+			deals[len(deals)-1].BlockHeight > s.height, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	newDeals := []types.Deal{}
+	for _, deal := range deals {
+		if deal.BlockHeight > s.height {
+			newDeals = append(newDeals, deal)
+		}
+	}
+	if len(newDeals) > 0 {
+		s.height, s.since = newDeals[0].BlockHeight, newDeals[0].Timestamp
+	}
+	if firstRefresh {
+		s.deals = newDeals
+	} else {
+		s.deals = append(append([]types.Deal{}, newDeals...), s.deals...)
+	}
+	if len(s.deals) > wsListSize {
+		s.deals = s.deals[:wsListSize]
+	}
+	if !firstRefresh && !complete {
+		return nil, errWSTruncated
+	}
+	if firstRefresh || len(newDeals) == 0 {
+		return nil, nil
+	}
+	return newDeals, nil
+}
+
+func (s *dealSource) snapshot() interface{} {
+	return s.deals
+}
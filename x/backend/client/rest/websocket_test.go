@@ -0,0 +1,400 @@
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/gorilla/websocket"
+	"github.com/okex/okchain/x/backend/types"
+	"github.com/okex/okchain/x/common"
+	orderkeeper "github.com/okex/okchain/x/order/keeper"
+	ordertypes "github.com/okex/okchain/x/order/types"
+	"github.com/stretchr/testify/require"
+)
+
+const testProduct = "xxb_okt"
+
+var testAddr = sdk.AccAddress([]byte("ws_test_addr________")).String()
+
+// mockQuerier replies the queries from the data set by the tests
+type mockQuerier struct {
+	mtx     sync.Mutex
+	height  int64
+	ticker  types.Ticker
+	candles [][]string
+	book    orderkeeper.BookRes
+	open    []types.Order
+	closed  []types.Order
+	deals   []types.Deal
+}
+
+func (q *mockQuerier) update(height int64, f func()) {
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+	f()
+	q.height = height
+}
+
+func (q *mockQuerier) LatestHeight() (int64, error) {
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+	return q.height, nil
+}
+
+func (q *mockQuerier) Query(path string, params interface{}) ([]byte, error) {
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+	switch path {
+	case "custom/backend/tickers":
+		if params.(types.QueryTickerParams).Product != testProduct {
+			return json.Marshal(common.GetErrorResponse(-1, "", "unknown product"))
+		}
+		return json.Marshal(common.GetBaseResponse([]types.Ticker{q.ticker}))
+	case "custom/backend/candles":
+		size := params.(types.QueryKlinesParams).Size
+		candles := q.candles
+		if len(candles) > size {
+			candles = candles[len(candles)-size:]
+		}
+		return json.Marshal(common.GetBaseResponse(candles))
+	case "custom/order/depthbook":
+		return json.Marshal(q.book)
+	case "custom/backend/orders/open", "custom/backend/orders/closed":
+		orders := q.open
+		if strings.HasSuffix(path, "closed") {
+			orders = q.closed
+		}
+		orderParams := params.(types.QueryOrderListParams)
+		var list []types.Order
+		for _, order := range orders {
+			if order.Timestamp >= orderParams.Start {
+				list = append(list, order)
+			}
+		}
+		from, to := pageRange(len(list), orderParams.Page, orderParams.PerPage)
+		return json.Marshal(common.GetListResponse(len(list), orderParams.Page, orderParams.PerPage, list[from:to]))
+	case "custom/backend/deals":
+		dealParams := params.(types.QueryDealsParams)
+		var list []types.Deal
+		for _, deal := range q.deals {
+			if deal.Timestamp >= dealParams.Start {
+				list = append(list, deal)
+			}
+		}
+		from, to := pageRange(len(list), dealParams.Page, dealParams.PerPage)
+		return json.Marshal(common.GetListResponse(len(list), dealParams.Page, dealParams.PerPage, list[from:to]))
+	}
+	return nil, errors.New("unknown path " + path)
+}
+
+func pageRange(total, page, perPage int) (int, int) {
+	from, to := (page-1)*perPage, page*perPage
+	if from > total {
+		from = total
+	}
+	if to > total {
+		to = total
+	}
+	return from, to
+}
+
+type testPush struct {
+	Event   string          `json:"event"`
+	Message string          `json:"message"`
+	Channel string          `json:"channel"`
+	Action  string          `json:"action"`
+	Seq     int64           `json:"seq"`
+	Height  int64           `json:"height"`
+	Data    json.RawMessage `json:"data"`
+}
+
+func readPush(t *testing.T, conn *websocket.Conn) testPush {
+	require.Nil(t, conn.SetReadDeadline(time.Now().Add(2*time.Second)))
+	_, msg, err := conn.ReadMessage()
+	require.Nil(t, err)
+	var push testPush
+	require.Nil(t, json.Unmarshal(msg, &push))
+	return push
+}
+
+func sendRequest(t *testing.T, conn *websocket.Conn, op string, args ...string) {
+	require.Nil(t, conn.WriteJSON(wsRequest{Op: op, Args: args}))
+}
+
+func TestWSHub(t *testing.T) {
+	q := &mockQuerier{
+		height: 10,
+		ticker: types.Ticker{Product: testProduct, Symbol: testProduct, Price: 10, Timestamp: 100},
+		book: orderkeeper.BookRes{
+			Asks: []orderkeeper.BookResItem{{Price: "11.00000000", Quantity: "1.00000000"}},
+			Bids: []orderkeeper.BookResItem{{Price: "9.00000000", Quantity: "1.00000000"}},
+		},
+		open: []types.Order{{OrderID: "ID0000000010-1", Sender: testAddr, Status: types.OrderStatusOpen}},
+	}
+	hub := newWSHub(q, 10*time.Millisecond)
+	defer hub.stop()
+	server := httptest.NewServer(wsHandler(hub))
+	defer server.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	require.Nil(t, err)
+	defer conn.Close()
+
+	// a subscriber gets the snapshot of a channel first
+	depthChannel := "depth:" + testProduct
+	orderChannel := "order:" + testAddr
+	sendRequest(t, conn, wsOpSubscribe, depthChannel, orderChannel)
+	require.Equal(t, testPush{Event: wsOpSubscribe, Channel: depthChannel}, readPush(t, conn))
+	push := readPush(t, conn)
+	require.Equal(t, depthChannel, push.Channel)
+	require.Equal(t, wsActionSnapshot, push.Action)
+	require.Equal(t, int64(0), push.Seq)
+	require.Equal(t, int64(10), push.Height)
+	require.JSONEq(t, `{"asks":[{"price":"11.00000000","quantity":"1.00000000"}],
+		"bids":[{"price":"9.00000000","quantity":"1.00000000"}]}`, string(push.Data))
+	require.Equal(t, testPush{Event: wsOpSubscribe, Channel: orderChannel}, readPush(t, conn))
+	push = readPush(t, conn)
+	require.Equal(t, wsActionSnapshot, push.Action)
+	var orders []types.Order
+	require.Nil(t, json.Unmarshal(push.Data, &orders))
+	require.Equal(t, q.open, orders)
+
+	// the changes of a new block are pushed as updates
+	q.update(11, func() {
+		q.book.Asks = nil
+		q.book.Bids = []orderkeeper.BookResItem{{Price: "9.00000000", Quantity: "3.00000000"}}
+	})
+	push = readPush(t, conn)
+	require.Equal(t, depthChannel, push.Channel)
+	require.Equal(t, wsActionUpdate, push.Action)
+	require.Equal(t, int64(1), push.Seq)
+	require.Equal(t, int64(11), push.Height)
+	require.JSONEq(t, `{"asks":[{"price":"11.00000000","quantity":"0.00000000"}],
+		"bids":[{"price":"9.00000000","quantity":"3.00000000"}]}`, string(push.Data))
+
+	// the order closed is pushed, the channels not changed are not
+	q.update(12, func() {
+		closed := q.open[0]
+		closed.Status = ordertypes.OrderStatusFilled
+		q.open, q.closed = nil, []types.Order{closed}
+	})
+	push = readPush(t, conn)
+	require.Equal(t, orderChannel, push.Channel)
+	require.Equal(t, int64(1), push.Seq)
+	require.Equal(t, int64(12), push.Height)
+	require.Nil(t, json.Unmarshal(push.Data, &orders))
+	require.Equal(t, 1, len(orders))
+	require.Equal(t, int64(ordertypes.OrderStatusFilled), orders[0].Status)
+
+	// a new subscriber of a channel gets the snapshot at its current seq
+	conn2, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	require.Nil(t, err)
+	defer conn2.Close()
+	sendRequest(t, conn2, wsOpSubscribe, depthChannel)
+	readPush(t, conn2)
+	push = readPush(t, conn2)
+	require.Equal(t, wsActionSnapshot, push.Action)
+	require.Equal(t, int64(1), push.Seq)
+	require.JSONEq(t, `{"asks":[],"bids":[{"price":"9.00000000","quantity":"3.00000000"}]}`, string(push.Data))
+
+	// no more updates after unsubscribed
+	sendRequest(t, conn, wsOpUnsubscribe, depthChannel)
+	require.Equal(t, testPush{Event: wsOpUnsubscribe, Channel: depthChannel}, readPush(t, conn))
+	q.update(13, func() {
+		q.book.Bids = nil
+	})
+	push = readPush(t, conn2)
+	require.Equal(t, int64(2), push.Seq)
+	sendRequest(t, conn, wsOpSubscribe, "ticker:"+testProduct)
+	readPush(t, conn)
+	push = readPush(t, conn)
+	require.Equal(t, "ticker:"+testProduct, push.Channel)
+
+	// the requests refused
+	for _, channel := range []string{"ticker", "kline:" + testProduct, "candle61:" + testProduct, "order:abc",
+		"ticker:abc_okt"} {
+		sendRequest(t, conn, wsOpSubscribe, channel)
+		push = readPush(t, conn)
+		require.Equal(t, wsEventError, push.Event, channel)
+	}
+	sendRequest(t, conn, "login")
+	require.Equal(t, testPush{Event: wsEventError, Message: "unknown op: login"}, readPush(t, conn))
+	require.Nil(t, conn.WriteMessage(websocket.TextMessage, []byte("{")))
+	require.Equal(t, wsEventError, readPush(t, conn).Event)
+}
+
+func TestCandleSource(t *testing.T) {
+	q := &mockQuerier{}
+	for i := 0; i < wsCandleSize+1; i++ {
+		q.candles = append(q.candles, []string{fmt.Sprintf("2020-01-01T00:%03d", i), "1", "1", "1", "1", "0"})
+	}
+	source, err := newWSSource("candle60:" + testProduct)
+	require.Nil(t, err)
+	_, err = source.refresh(q)
+	require.Nil(t, err)
+	candles := source.snapshot().([][]string)
+	require.Equal(t, wsCandleSize, len(candles))
+	require.Equal(t, q.candles[1:], candles)
+
+	update, err := source.refresh(q)
+	require.Nil(t, err)
+	require.Nil(t, update)
+
+	// the latest candle is updated, and a new one is opened
+	q.candles[wsCandleSize] = []string{q.candles[wsCandleSize][0], "1", "2", "1", "2", "5"}
+	q.candles = append(q.candles, []string{"2020-01-01T00:999", "2", "2", "2", "2", "0"})
+	update, err = source.refresh(q)
+	require.Nil(t, err)
+	require.Equal(t, q.candles[wsCandleSize:], update)
+	require.Equal(t, q.candles[2:], source.snapshot())
+}
+
+func TestDealSource(t *testing.T) {
+	q := &mockQuerier{deals: []types.Deal{{BlockHeight: 1, OrderID: "ID0000000001-1", Sender: testAddr}}}
+	source, err := newWSSource("deal:" + testAddr)
+	require.Nil(t, err)
+	_, err = source.refresh(q)
+	require.Nil(t, err)
+	require.Equal(t, q.deals, source.snapshot())
+
+	newDeal := types.Deal{BlockHeight: 2, OrderID: "ID0000000001-1", Sender: testAddr}
+	q.deals = append([]types.Deal{newDeal}, q.deals...)
+	update, err := source.refresh(q)
+	require.Nil(t, err)
+	require.Equal(t, []types.Deal{newDeal}, update)
+	update, err = source.refresh(q)
+	require.Nil(t, err)
+	require.Nil(t, update)
+
+	// the deals of one order in one block are all pushed
+	sameBlockDeals := []types.Deal{
+		{BlockHeight: 3, OrderID: "ID0000000003-1", Sender: testAddr, Side: ordertypes.BuyOrder, Price: 10,
+			Quantity: 1},
+		{BlockHeight: 3, OrderID: "ID0000000003-1", Sender: testAddr, Side: ordertypes.BuyOrder, Price: 10.1,
+			Quantity: 2},
+		{BlockHeight: 3, OrderID: "ID0000000003-1", Sender: testAddr, Side: ordertypes.BuyOrder, Price: 10.1,
+			Quantity: 2},
+	}
+	q.deals = append(append([]types.Deal{}, sameBlockDeals...), q.deals...)
+	update, err = source.refresh(q)
+	require.Nil(t, err)
+	require.Equal(t, sameBlockDeals, update)
+	update, err = source.refresh(q)
+	require.Nil(t, err)
+	require.Nil(t, update)
+}
+
+func TestOrderSourcePages(t *testing.T) {
+	q := &mockQuerier{}
+	for i := 0; i < wsListSize+50; i++ {
+		q.open = append([]types.Order{{OrderID: fmt.Sprintf("ID0000000001-%d", i+1), Sender: testAddr,
+			Status: types.OrderStatusOpen, Timestamp: int64(100 + i)}}, q.open...)
+	}
+	source, err := newWSSource("order:" + testAddr)
+	require.Nil(t, err)
+	_, err = source.refresh(q)
+	require.Nil(t, err)
+	require.Equal(t, q.open, source.snapshot())
+
+	// the orders closed in a block are all pushed, the old ones filled and the new ones placed and closed
+	for _, order := range q.open[wsListSize/2:] {
+		order.Status = ordertypes.OrderStatusFilled
+		q.closed = append(q.closed, order)
+	}
+	q.open = q.open[:wsListSize/2]
+	for i := 0; i < wsListSize; i++ {
+		q.closed = append([]types.Order{{OrderID: fmt.Sprintf("ID0000000002-%d", i+1), Sender: testAddr,
+			Status: ordertypes.OrderStatusCancelled, Timestamp: 1000}}, q.closed...)
+	}
+	update, err := source.refresh(q)
+	require.Nil(t, err)
+	require.Equal(t, wsListSize*2, len(update.([]types.Order)))
+	require.Equal(t, q.open, source.snapshot())
+	update, err = source.refresh(q)
+	require.Nil(t, err)
+	require.Nil(t, update)
+
+	// a block of more changes than the pages queried is told as truncated, the snapshot is refreshed still
+	for i := 0; i < wsListSize*wsMaxListPages; i++ {
+		q.closed = append([]types.Order{{OrderID: fmt.Sprintf("ID0000000003-%d", i+1), Sender: testAddr,
+			Status: ordertypes.OrderStatusCancelled, Timestamp: 2000}}, q.closed...)
+	}
+	q.open = nil
+	_, err = source.refresh(q)
+	require.Equal(t, errWSTruncated, err)
+	require.Equal(t, []types.Order{}, source.snapshot())
+	update, err = source.refresh(q)
+	require.Nil(t, err)
+	require.Nil(t, update)
+}
+
+func TestDealSourcePages(t *testing.T) {
+	q := &mockQuerier{}
+	addDeals := func(height int64, num int) {
+		for i := 0; i < num; i++ {
+			q.deals = append([]types.Deal{{Timestamp: height * 10, BlockHeight: height,
+				OrderID: fmt.Sprintf("ID%010d-%d", height, i+1), Sender: testAddr}}, q.deals...)
+		}
+	}
+	addDeals(1, wsListSize)
+	source, err := newWSSource("deal:" + testAddr)
+	require.Nil(t, err)
+	_, err = source.refresh(q)
+	require.Nil(t, err)
+	require.Equal(t, q.deals, source.snapshot())
+
+	// the deals of a block are all pushed, the snapshot keeps the latest ones
+	addDeals(2, wsListSize*2+1)
+	update, err := source.refresh(q)
+	require.Nil(t, err)
+	require.Equal(t, q.deals[:wsListSize*2+1], update)
+	require.Equal(t, q.deals[:wsListSize], source.snapshot())
+
+	// a block of more deals than the pages queried is told as truncated, the snapshot is refreshed still
+	addDeals(3, wsListSize*wsMaxListPages+1)
+	_, err = source.refresh(q)
+	require.Equal(t, errWSTruncated, err)
+	require.Equal(t, q.deals[:wsListSize], source.snapshot())
+	addDeals(4, 1)
+	update, err = source.refresh(q)
+	require.Nil(t, err)
+	require.Equal(t, q.deals[:1], update)
+}
+
+func TestWSHubTruncated(t *testing.T) {
+	q := &mockQuerier{height: 10}
+	hub := newWSHub(q, 10*time.Millisecond)
+	defer hub.stop()
+	server := httptest.NewServer(wsHandler(hub))
+	defer server.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	require.Nil(t, err)
+	defer conn.Close()
+	dealChannel := "deal:" + testAddr
+	sendRequest(t, conn, wsOpSubscribe, dealChannel)
+	readPush(t, conn)
+	require.Equal(t, wsActionSnapshot, readPush(t, conn).Action)
+
+	// the block of too many deals is pushed as a snapshot of the next seq
+	q.update(11, func() {
+		for i := 0; i < wsListSize*wsMaxListPages+1; i++ {
+			q.deals = append(q.deals, types.Deal{Timestamp: 110, BlockHeight: 11,
+				OrderID: fmt.Sprintf("ID0000000011-%d", i+1), Sender: testAddr})
+		}
+	})
+	push := readPush(t, conn)
+	require.Equal(t, wsActionSnapshot, push.Action)
+	require.Equal(t, int64(1), push.Seq)
+	require.Equal(t, int64(11), push.Height)
+	var deals []types.Deal
+	require.Nil(t, json.Unmarshal(push.Data, &deals))
+	require.Equal(t, q.deals[:wsListSize], deals)
+}
@@ -52,6 +52,7 @@ func NewKeeper(orderKeeper types.OrderKeeper, tokenKeeper types.TokenKeeper, dex
 		if err == nil {
 			k.Orm = orm
 			k.stopChan = make(chan struct{})
+			k.checkUnindexedData()
 
 			if k.Config.EnableMktCompute {
 				go generateKline1M(k.stopChan, k.Config, k.Orm, &k.Logger)
@@ -85,6 +86,33 @@ func (k Keeper) Flush() {
 	defer k.Cache.Flush()
 }
 
+// CheckIndexedHeight returns an error if the committed blocks are not all indexed into backend database, in which
+// case the backend data is incomplete until the missing blocks are reindexed
+func (k Keeper) CheckIndexedHeight(ctx sdk.Context) error {
+	indexedHeight, err := k.Orm.GetIndexedHeight()
+	if err != nil {
+		return fmt.Errorf("failed to get the indexed height of backend: %v", err)
+	}
+	if indexedHeight < ctx.BlockHeight() {
+		return fmt.Errorf("backend data is indexed up to height %d, behind the chain at height %d, "+
+			"blocks [%d, %d] need to be reindexed", indexedHeight, ctx.BlockHeight(), indexedHeight+1, ctx.BlockHeight())
+	}
+	return nil
+}
+
+// checkUnindexedData warns the operator of a backend database created before the indexed height was recorded, whose
+// blocks are refused until it's rebuilt by the reindex command
+func (k Keeper) checkUnindexedData() {
+	unindexed, err := k.Orm.HasUnindexedData()
+	if err != nil {
+		k.Logger.Error(fmt.Sprintf("[backend] failed to check the indexed height of backend database: %v", err))
+	} else if unindexed {
+		k.Logger.Error("[backend] backend database holds data without the indexed height, the blocks are not " +
+			"indexed and the backend stops serving until it is rebuilt by " +
+			"`okchaind backend reindex --from-height 1` into a new database")
+	}
+}
+
 // SyncTx generate transaction and add it to cache, called at DeliverTx
 func (k Keeper) SyncTx(ctx sdk.Context, tx *auth.StdTx, txHash string, timestamp int64) {
 	if k.Config.EnableBackend && k.Config.EnableMktCompute {
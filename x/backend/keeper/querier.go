@@ -25,6 +25,18 @@ func NewQuerier(keeper Keeper) sdk.Querier {
 			return res, nil
 		}
 
+		// refuse to serve incomplete data if blocks are missed by the indexing of backend
+		if keeper.Config.EnableMktCompute {
+			if err := keeper.CheckIndexedHeight(ctx); err != nil {
+				response := common.GetErrorResponse(-1, "", err.Error())
+				res, eJSON := json.Marshal(response)
+				if eJSON != nil {
+					return nil, sdk.ErrInternal(eJSON.Error())
+				}
+				return res, nil
+			}
+		}
+
 		defer func() {
 			if e := recover(); e != nil {
 				errMsg := fmt.Sprintf("%+v", e)
@@ -93,6 +93,7 @@ func TestKeeper_Tx(t *testing.T) {
 	mapp, addrKeysSlice := getMockApp(t, 2, true, "")
 	mapp.BeginBlock(abci.RequestBeginBlock{Header: abci.Header{Height: 2}})
 	ctx := mapp.BaseApp.NewContext(false, abci.Header{Time: time.Now()}).WithBlockHeight(2)
+	mapp.backendKeeper.Orm.MockIndexedHeight(1)
 	feeParams := orderTypes.DefaultParams()
 	mapp.orderKeeper.SetParams(ctx, &feeParams)
 
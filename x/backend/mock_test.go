@@ -298,6 +298,9 @@ func FireEndBlockerPeriodicMatch(t *testing.T, enableBackend bool) (mockDexApp *
 	}
 	orders[0].Sender = addrKeysSlice[0].Address
 	orders[1].Sender = addrKeysSlice[1].Address
+	if enableBackend {
+		mapp.backendKeeper.Orm.MockIndexedHeight(ctx.BlockHeight() - 1)
+	}
 	for i := 0; i < 2; i++ {
 		err := mapp.orderKeeper.PlaceOrder(ctx, orders[i])
 		require.NoError(t, err)
@@ -15,22 +15,48 @@ import (
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/jinzhu/gorm"
 	_ "github.com/jinzhu/gorm/dialects/mysql"
+	_ "github.com/jinzhu/gorm/dialects/postgres"
 	_ "github.com/jinzhu/gorm/dialects/sqlite"
 	"github.com/okex/okchain/x/backend/types"
 	"github.com/okex/okchain/x/token"
 	"github.com/pkg/errors"
 	"github.com/tendermint/tendermint/libs/log"
+	tmtypes "github.com/tendermint/tendermint/types"
 )
 
-// nolint
+// the engine types are the dialect names registered by the gorm dialects imported above. The sdk config names the ones
+// of sqlite3 and mysql, postgres is named after the dialect of gorm/dialects/postgres, which New checks for every
+// engine type
 const (
-	EngineTypeSqlite = okchaincfg.BackendOrmEngineTypeSqlite
-	EngineTypeMysql  = okchaincfg.BackendOrmEngineTypeMysql
+	EngineTypeSqlite   = okchaincfg.BackendOrmEngineTypeSqlite
+	EngineTypeMysql    = okchaincfg.BackendOrmEngineTypeMysql
+	EngineTypePostgres = postgresDialectName
+
+	// postgresDialectName is the name registered by gorm/dialects/postgres
+	postgresDialectName = "postgres"
 )
 
 // nolint
 type OrmEngineInfo = okchaincfg.BackendOrmEngineInfo
 
+const (
+	// indexedHeightID is the primary key of the single row of indexed height
+	indexedHeightID = 1
+	// maxBatchInsertVars keeps a batch INSERT statement below 999, the lowest limit of bound variables of sqlite
+	maxBatchInsertVars = 990
+)
+
+// ErrIndexGap is returned when a block is indexed while the blocks between it and the indexed height are missing
+type ErrIndexGap struct {
+	IndexedHeight int64
+	Height        int64
+}
+
+func (e ErrIndexGap) Error() string {
+	return fmt.Sprintf("backend data is indexed up to height %d, blocks [%d, %d) are missing",
+		e.IndexedHeight, e.IndexedHeight+1, e.Height)
+}
+
 // ORM is designed for deal with database by orm
 // http://gorm.io/docs/query.html
 type ORM struct {
@@ -62,9 +88,12 @@ func New(enableLog bool, engineInfo *OrmEngineInfo, logger *log.Logger) (m *ORM,
 				orm.Debug(fmt.Sprintf("%s created", dbDir))
 			}
 		}
-	case EngineTypeMysql:
+	case EngineTypeMysql, EngineTypePostgres:
 	default:
-
+		return nil, fmt.Errorf("unsupported engine type of backend database: %s", engineInfo.EngineType)
+	}
+	if _, ok := gorm.GetDialect(engineInfo.EngineType); !ok {
+		return nil, fmt.Errorf("no gorm dialect registered for engine type %s", engineInfo.EngineType)
 	}
 
 	if db, err = gorm.Open(engineInfo.EngineType, engineInfo.ConnectStr); err != nil {
@@ -84,6 +113,7 @@ func New(enableLog bool, engineInfo *OrmEngineInfo, logger *log.Logger) (m *ORM,
 	orm.db.AutoMigrate(&token.FeeDetail{})
 	orm.db.AutoMigrate(&types.Order{})
 	orm.db.AutoMigrate(&types.Transaction{})
+	orm.db.AutoMigrate(&types.IndexedHeight{})
 
 	allKlinesMap := types.GetAllKlineMap()
 	for _, v := range allKlinesMap {
@@ -93,6 +123,12 @@ func New(enableLog bool, engineInfo *OrmEngineInfo, logger *log.Logger) (m *ORM,
 	return &orm, nil
 }
 
+// quote quotes an identifier in the flavour of the database engine. The raw SQL statements quote their identifiers,
+// as timestamp is a keyword of mysql and postgres
+func (orm *ORM) quote(name string) string {
+	return orm.db.Dialect().Quote(name)
+}
+
 // Debug log  debug info when use orm
 func (orm *ORM) Debug(msg string) {
 	if orm.logger != nil {
@@ -333,7 +369,7 @@ func (orm *ORM) getOpenCloseKline(startTS, endTS int64, product string, firstK i
 
 func (orm *ORM) getMinTimestamp(tbName string) int64 {
 
-	sql := fmt.Sprintf("select min(Timestamp) as ts from %s", tbName)
+	sql := fmt.Sprintf("select min(%s) as ts from %s", orm.quote("timestamp"), orm.quote(tbName))
 	ts := int64(-1)
 
 	r := orm.db.Raw(sql).Row()
@@ -349,7 +385,7 @@ func (orm *ORM) getMinTimestamp(tbName string) int64 {
 
 func (orm *ORM) getMaxTimestamp(tbName string) int64 {
 
-	sql := fmt.Sprintf("select max(Timestamp) as ts from %s", tbName)
+	sql := fmt.Sprintf("select max(%s) as ts from %s", orm.quote("timestamp"), orm.quote(tbName))
 	ts := int64(-1)
 
 	r := orm.db.Raw(sql).Row()
@@ -405,9 +441,7 @@ func (dm *DealDataSource) getDataSourceMinTimestamp() int64 {
 }
 
 func (dm *DealDataSource) getMaxMinSumByGroupSQL(startTS, endTS int64) string {
-	sql := fmt.Sprintf("select product, sum(Quantity) as quantity, max(Price) as high, min(Price) as low, count(price) as cnt from deals "+
-		"where Timestamp >= %d and Timestamp < %d and Side = 'BUY' group by product", startTS, endTS)
-	return sql
+	return dm.orm.maxMinSumByGroupSQL("deals", startTS, endTS, fmt.Sprintf("%s = 'BUY'", dm.orm.quote("side")))
 }
 
 func (dm *DealDataSource) getOpenClosePrice(startTS, endTS int64, product string) (float64, float64) {
@@ -425,9 +459,7 @@ func (dm *MergeResultDataSource) getDataSourceMinTimestamp() int64 {
 }
 
 func (dm *MergeResultDataSource) getMaxMinSumByGroupSQL(startTS, endTS int64) string {
-	sql := fmt.Sprintf("select product, sum(Quantity) as quantity, max(Price) as high, min(Price) as low, count(price) as cnt from match_results "+
-		"where Timestamp >= %d and Timestamp < %d group by product", startTS, endTS)
-	return sql
+	return dm.Orm.maxMinSumByGroupSQL("match_results", startTS, endTS, "")
 }
 
 func (dm *MergeResultDataSource) getOpenClosePrice(startTS, endTS int64, product string) (float64, float64) {
@@ -435,6 +467,19 @@ func (dm *MergeResultDataSource) getOpenClosePrice(startTS, endTS int64, product
 	return openDeal.Price, closeDeal.Price
 }
 
+// maxMinSumByGroupSQL aggregates the quantities and prices of the rows of table in [startTS, endTS) by product,
+// with the extra condition if it's not empty
+func (orm *ORM) maxMinSumByGroupSQL(table string, startTS, endTS int64, condition string) string {
+	product, price, timestamp := orm.quote("product"), orm.quote("price"), orm.quote("timestamp")
+	sql := fmt.Sprintf("select %s, sum(%s) as quantity, max(%s) as high, min(%s) as low, count(%s) as cnt from %s "+
+		"where %s >= %d and %s < %d", product, orm.quote("quantity"), price, price, price, orm.quote(table),
+		timestamp, startTS, timestamp, endTS)
+	if condition != "" {
+		sql += " and " + condition
+	}
+	return sql + " group by " + product
+}
+
 // CreateKline1min batch insert into Kline1M
 func (orm *ORM) CreateKline1min(startTS, endTS int64, dataSource IKline1MDataSource) (anchorEndTS int64, newK int, err error) {
 	orm.singleEntryLock.Lock()
@@ -534,6 +579,34 @@ func (orm *ORM) CreateKline1min(startTS, endTS int64, dataSource IKline1MDataSou
 	return anchorEndTS, len(productKlines), nil
 }
 
+// GenerateKlines creates the 1 minute klines from match results up to endTS, then merges them into the klines of
+// all the other frequencies. It is used to build the klines in one pass when backend data is reindexed, instead of
+// the periodic workers following the latest block
+func (orm *ORM) GenerateKlines(endTS int64) error {
+	if orm.getMergeResultMinTimestamp() == -1 {
+		return nil
+	}
+
+	ds := MergeResultDataSource{Orm: orm}
+	if _, _, err := orm.CreateKline1min(0, endTS, &ds); err != nil {
+		return err
+	}
+
+	for freq, tableName := range types.GetAllKlineMap() {
+		if freq <= 60 {
+			continue
+		}
+		destK, err := types.NewKlineFactory(tableName, nil)
+		if err != nil {
+			return err
+		}
+		if _, _, err := orm.MergeKlineM1(0, endTS, destK.(types.IKline)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (orm *ORM) deleteKlinesBefore(unixTS int64, kline interface{}) (err error) {
 	orm.singleEntryLock.Lock()
 	defer orm.singleEntryLock.Unlock()
@@ -605,8 +678,9 @@ func (orm *ORM) getAllUpdatedProducts(anchorStartTS, anchorEndTS int64) ([]strin
 }
 
 func (orm *ORM) getAllUpdatedProductsFromTable(anchorStartTS, anchorEndTS int64, tb string) ([]string, error) {
-	sql := fmt.Sprintf("select distinct(Product) from %s where Timestamp >= %d and Timestamp < %d",
-		tb, anchorStartTS, anchorEndTS)
+	timestamp := orm.quote("timestamp")
+	sql := fmt.Sprintf("select distinct(%s) from %s where %s >= %d and %s < %d",
+		orm.quote("product"), orm.quote(tb), timestamp, anchorStartTS, timestamp, anchorEndTS)
 
 	rows, err := orm.db.Raw(sql).Rows()
 
@@ -714,8 +788,11 @@ func (orm *ORM) MergeKlineM1(startTS, endTS int64, destKline types.IKline) (anch
 	nextTimeStamp := nextTime.Unix()
 	for nextTimeStamp <= anchorEndTime {
 
-		sql := fmt.Sprintf("select %d, product, sum(volume) as volume, max(high) as high, min(low) as low, count(*) as cnt from %s "+
-			"where Timestamp >= %d and Timestamp < %d group by product", anchorStartTime.Unix(), klineM1.(types.IKline).GetTableName(), anchorStartTime.Unix(), nextTime.Unix())
+		product, timestamp := orm.quote("product"), orm.quote("timestamp")
+		sql := fmt.Sprintf("select %d, %s, sum(%s) as volume, max(%s) as high, min(%s) as low, count(*) as cnt from %s "+
+			"where %s >= %d and %s < %d group by %s", anchorStartTime.Unix(), product, orm.quote("volume"),
+			orm.quote("high"), orm.quote("low"), orm.quote(klineM1.(types.IKline).GetTableName()),
+			timestamp, anchorStartTime.Unix(), timestamp, nextTime.Unix(), product)
 
 		rows, err := orm.db.Raw(sql).Rows()
 
@@ -1066,13 +1143,14 @@ func (orm *ORM) GetOrderList(address, product, side string, open bool, offset, l
 	if product != "" {
 		query = query.Where("product = ?", product)
 	}
+	// untriggered orders are listed as open orders
 	if open {
-		query = query.Where("status = 0")
+		query = query.Where("status in (?)", []int64{types.OrderStatusOpen, types.OrderStatusUntriggered})
 	} else {
 		if hideNoFill {
-			query = query.Where("status in (1, 4, 5)")
+			query = query.Where("status in (1, 4, 5, 8, 12)")
 		} else {
-			query = query.Where("status > 0")
+			query = query.Where("status > 0 AND status <> ?", types.OrderStatusUntriggered)
 		}
 	}
 
@@ -1143,8 +1221,109 @@ func (orm *ORM) BatchInsertOrUpdate(newOrders []*types.Order, updatedOrders []*t
 	defer orm.singleEntryLock.Unlock()
 
 	trx := orm.db.Begin()
-	defer orm.deferRollbackTx(trx, err)
+	defer func() {
+		if e := recover(); e != nil {
+			orm.Error(fmt.Sprintf("ORM Panic : %+v", e))
+			debug.PrintStack()
+			err = fmt.Errorf("%+v", e)
+		}
+		if err != nil {
+			trx.Rollback()
+		}
+	}()
+
+	resultMap, err = batchInsertOrUpdate(trx, newOrders, updatedOrders, deals, mrs, feeDetails, trxs)
+	if err != nil {
+		return resultMap, err
+	}
+	return resultMap, trx.Commit().Error
+}
+
+// IndexBlock writes the backend data of a block and moves the indexed height to the block in one transaction, so
+// that the data of a block is either committed as a whole or not at all. A block at or below the indexed height has
+// been indexed already and is skipped with a nil result map, while a block beyond the next height is refused with
+// ErrIndexGap. A database without the indexed height is taken as indexed up to the start height of the chain, so the
+// backend enabled on a running node or opened on a database created before the indexed height was recorded refuses
+// the blocks until the missing ones are reindexed
+func (orm *ORM) IndexBlock(height, timestamp int64, newOrders []*types.Order, updatedOrders []*types.Order, deals []*types.Deal, mrs []*types.MatchResult, feeDetails []*token.FeeDetail, trxs []*types.Transaction) (resultMap map[string]int, err error) {
+
+	orm.singleEntryLock.Lock()
+	defer orm.singleEntryLock.Unlock()
+
+	trx := orm.db.Begin()
+	defer func() {
+		if e := recover(); e != nil {
+			orm.Error(fmt.Sprintf("ORM Panic : %+v", e))
+			debug.PrintStack()
+			err = fmt.Errorf("%+v", e)
+		}
+		if err != nil {
+			trx.Rollback()
+		}
+	}()
+
+	indexedHeight, found, err := getIndexedHeight(trx)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		indexedHeight = tmtypes.GetStartBlockHeight()
+	}
+	if height <= indexedHeight {
+		trx.Rollback()
+		return nil, nil
+	}
+	if height > indexedHeight+1 {
+		return nil, ErrIndexGap{IndexedHeight: indexedHeight, Height: height}
+	}
 
+	resultMap, err = batchInsertOrUpdate(trx, newOrders, updatedOrders, deals, mrs, feeDetails, trxs)
+	if err != nil {
+		return resultMap, err
+	}
+
+	record := types.IndexedHeight{ID: indexedHeightID, Height: height, Timestamp: timestamp}
+	if ret := trx.Save(&record); ret.Error != nil {
+		return resultMap, ret.Error
+	}
+	return resultMap, trx.Commit().Error
+}
+
+// GetIndexedHeight returns the height of the last block whose backend data has been committed, 0 if none
+func (orm *ORM) GetIndexedHeight() (int64, error) {
+	height, _, err := getIndexedHeight(orm.db)
+	return height, err
+}
+
+// HasUnindexedData returns true if the database holds backend data without the indexed height, which is the case of
+// a database created before the indexed height was recorded. Its blocks indexed are unknown, so it has to be
+// reindexed as a whole
+func (orm *ORM) HasUnindexedData() (bool, error) {
+	if _, found, err := getIndexedHeight(orm.db); err != nil || found {
+		return false, err
+	}
+	for _, table := range []interface{}{&types.Order{}, &types.Deal{}, &types.Transaction{}} {
+		count := 0
+		if err := orm.db.Model(table).Count(&count).Error; err != nil {
+			return false, err
+		}
+		if count > 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func getIndexedHeight(db *gorm.DB) (height int64, found bool, err error) {
+	var record types.IndexedHeight
+	ret := db.Where("id = ?", indexedHeightID).First(&record)
+	if ret.RecordNotFound() {
+		return 0, false, nil
+	}
+	return record.Height, ret.Error == nil, ret.Error
+}
+
+func batchInsertOrUpdate(trx *gorm.DB, newOrders []*types.Order, updatedOrders []*types.Order, deals []*types.Deal, mrs []*types.MatchResult, feeDetails []*token.FeeDetail, trxs []*types.Transaction) (resultMap map[string]int, err error) {
 	resultMap = map[string]int{}
 	resultMap["newOrders"] = 0
 	resultMap["updatedOrders"] = 0
@@ -1155,102 +1334,105 @@ func (orm *ORM) BatchInsertOrUpdate(newOrders []*types.Order, updatedOrders []*t
 
 	// FLT. 20190909.  BatchInsert is faster than insert one by one.
 	// 1. Batch Insert Orders.
-	orderVItems := []string{}
+	orderRows := make([][]interface{}, 0, len(newOrders))
 	for _, order := range newOrders {
-		vItem := fmt.Sprintf("('%s','%s','%s','%s','%s','%s','%s','%d','%s','%s','%d')",
-			order.TxHash, order.OrderID, order.Sender, order.Product, order.Side, order.Price, order.Quantity,
-			order.Status, order.FilledAvgPrice, order.RemainQuantity, order.Timestamp)
-		orderVItems = append(orderVItems, vItem)
-
+		orderRows = append(orderRows, []interface{}{order.TxHash, order.OrderID, order.Sender, order.Product,
+			order.Side, order.Price, order.Quantity, order.Status, order.FilledAvgPrice, order.RemainQuantity,
+			order.Timestamp, order.TriggerType, order.TriggerPrice, order.TriggeredHeight, order.DisplayQuantity})
 	}
-	if len(orderVItems) > 0 {
-		orderValueSQL := strings.Join(orderVItems, ", ")
-		orderSQL := fmt.Sprintf("INSERT INTO `orders` (`tx_hash`,`order_id`,`sender`,`product`,`side`,`price`,"+
-			"`quantity`,`status`,`filled_avg_price`,`remain_quantity`,`timestamp`) VALUES %s", orderValueSQL)
-		ret := trx.Exec(orderSQL)
-		if ret.Error != nil {
-			return resultMap, ret.Error
-		} else {
-			resultMap["newOrders"] += len(orderVItems)
-		}
+	if resultMap["newOrders"], err = batchInsert(trx, "orders", []string{"tx_hash", "order_id", "sender", "product",
+		"side", "price", "quantity", "status", "filled_avg_price", "remain_quantity", "timestamp", "trigger_type",
+		"trigger_price", "triggered_height", "display_quantity"}, orderRows); err != nil {
+		return resultMap, err
 	}
 
 	for _, order := range updatedOrders {
-		ret := trx.Save(&order)
+		ret := trx.Save(order)
 		if ret.Error != nil {
 			return resultMap, ret.Error
-		} else {
-			resultMap["updatedOrders"]++
 		}
+		resultMap["updatedOrders"]++
 	}
 
 	for _, mr := range mrs {
 		ret := trx.Create(mr)
 		if ret.Error != nil {
 			return resultMap, ret.Error
-		} else {
-			resultMap["matchResults"]++
 		}
+		resultMap["matchResults"]++
 	}
 
 	// 2. Batch Insert Deals
-	dealVItems := []string{}
+	dealRows := make([][]interface{}, 0, len(deals))
 	for _, d := range deals {
-		vItem := fmt.Sprintf("('%d','%d','%s','%s','%s','%s','%f','%f','%s')",
-			d.Timestamp, d.BlockHeight, d.OrderID, d.Sender, d.Product, d.Side, d.Price, d.Quantity, d.Fee)
-		dealVItems = append(dealVItems, vItem)
-	}
-	if len(dealVItems) > 0 {
-		dealsSQL := fmt.Sprintf("INSERT INTO `deals` (`timestamp`,`block_height`,`order_id`,`sender`,`product`,`side`,`price`,`quantity`,`fee`) "+
-			"VALUES %s", strings.Join(dealVItems, ","))
-		ret := trx.Exec(dealsSQL)
-		if ret.Error != nil {
-			return resultMap, ret.Error
-		} else {
-			resultMap["deals"] += len(dealVItems)
-		}
+		dealRows = append(dealRows, []interface{}{d.Timestamp, d.BlockHeight, d.OrderID, d.Sender, d.Product, d.Side,
+			d.Price, d.Quantity, d.Fee})
+	}
+	if resultMap["deals"], err = batchInsert(trx, "deals", []string{"timestamp", "block_height", "order_id", "sender",
+		"product", "side", "price", "quantity", "fee"}, dealRows); err != nil {
+		return resultMap, err
 	}
 
 	// 3. Batch Insert Transactions.
-	trxVItems := []string{}
+	trxRows := make([][]interface{}, 0, len(trxs))
 	for _, t := range trxs {
-		vItem := fmt.Sprintf("('%s','%d','%s','%s','%d','%s','%s','%d')",
-			t.TxHash, t.Type, t.Address, t.Symbol, t.Side, t.Quantity, t.Fee, t.Timestamp)
-		trxVItems = append(trxVItems, vItem)
-	}
-	if len(trxVItems) > 0 {
-		trxSQL := fmt.Sprintf("INSERT INTO `transactions` (`tx_hash`,`type`,`address`,`symbol`,`side`,`quantity`,`fee`,`timestamp`) "+
-			"VALUES %s", strings.Join(trxVItems, ", "))
-		ret := trx.Exec(trxSQL)
-		if ret.Error != nil {
-			return resultMap, ret.Error
-		} else {
-			resultMap["transactions"] += len(trxVItems)
-		}
-
+		trxRows = append(trxRows, []interface{}{t.TxHash, t.Type, t.Address, t.Symbol, t.Side, t.Quantity, t.Fee,
+			t.Timestamp})
+	}
+	if resultMap["transactions"], err = batchInsert(trx, "transactions", []string{"tx_hash", "type", "address",
+		"symbol", "side", "quantity", "fee", "timestamp"}, trxRows); err != nil {
+		return resultMap, err
 	}
 
 	// 4. Batch Insert Fee Details.
-	fdVItems := []string{}
+	fdRows := make([][]interface{}, 0, len(feeDetails))
 	for _, fd := range feeDetails {
-		vItem := fmt.Sprintf("('%s','%s','%s','%d')", fd.Address, fd.Fee, fd.FeeType, fd.Timestamp)
-		fdVItems = append(fdVItems, vItem)
+		fdRows = append(fdRows, []interface{}{fd.Address, fd.Fee, fd.FeeType, fd.Timestamp, fd.FeeRate})
 	}
-	if len(fdVItems) > 0 {
-		fdSQL := fmt.Sprintf("INSERT INTO `fee_details` (`address`,`fee`,`fee_type`,`timestamp`) VALUES %s", strings.Join(fdVItems, ","))
-		ret := trx.Exec(fdSQL)
-		if ret.Error != nil {
-			return resultMap, ret.Error
-		} else {
-			resultMap["feeDetails"] += len(fdVItems)
-		}
+	if resultMap["feeDetails"], err = batchInsert(trx, "fee_details", []string{"address", "fee", "fee_type",
+		"timestamp", "fee_rate"}, fdRows); err != nil {
+		return resultMap, err
 	}
 
-	trx.Commit()
-
 	return resultMap, nil
 }
 
+// batchInsert inserts rows into a table with multi-row INSERT statements. The values are bound rather than formatted
+// into the statement, and each statement is kept within the bound variable limit of the database engines
+func batchInsert(trx *gorm.DB, table string, columns []string, rows [][]interface{}) (int, error) {
+	rowPlaceholder := "(" + strings.TrimSuffix(strings.Repeat("?,", len(columns)), ",") + ")"
+	rowsPerStatement := maxBatchInsertVars / len(columns)
+	// the columns are quoted, as timestamp is a keyword of mysql and postgres
+	dialect := trx.Dialect()
+	quotedColumns := make([]string, 0, len(columns))
+	for _, column := range columns {
+		quotedColumns = append(quotedColumns, dialect.Quote(column))
+	}
+
+	cnt := 0
+	for start := 0; start < len(rows); start += rowsPerStatement {
+		end := start + rowsPerStatement
+		if end > len(rows) {
+			end = len(rows)
+		}
+
+		placeholders := make([]string, 0, end-start)
+		values := make([]interface{}, 0, (end-start)*len(columns))
+		for _, row := range rows[start:end] {
+			placeholders = append(placeholders, rowPlaceholder)
+			values = append(values, row...)
+		}
+
+		sql := fmt.Sprintf("INSERT INTO %s (%s) VALUES %s", dialect.Quote(table), strings.Join(quotedColumns, ","),
+			strings.Join(placeholders, ","))
+		if ret := trx.Exec(sql, values...); ret.Error != nil {
+			return cnt, ret.Error
+		}
+		cnt += end - start
+	}
+	return cnt, nil
+}
+
 // nolint
 func (orm *ORM) GetOrderListV2(instrumentID string, address string, side string, open bool, after string, before string, limit int) []types.Order {
 	var orders []types.Order
@@ -1278,9 +1460,9 @@ func (orm *ORM) GetOrderListV2(instrumentID string, address string, side string,
 	}
 
 	if open {
-		query = query.Where("status = 0")
+		query = query.Where("status in (?)", []int64{types.OrderStatusOpen, types.OrderStatusUntriggered})
 	} else {
-		query = query.Where("status > 0")
+		query = query.Where("status > 0 AND status <> ?", types.OrderStatusUntriggered)
 	}
 
 	query.Order("timestamp desc").Limit(limit).Find(&orders)
@@ -24,10 +24,17 @@ func (orm *DangrousORM) CleanupDataInTestEvn() (err error) {
 	feeDB := tx.Delete(&token.FeeDetail{})
 	txDB := tx.Delete(&types.Transaction{})
 	matchDB := tx.Delete(&types.MatchResult{})
+	heightDB := tx.Delete(&types.IndexedHeight{})
 
-	if err = types.NewErrorsMerged(dealDB.Error, orderDB.Error, feeDB.Error, txDB.Error, matchDB.Error); err != nil {
+	if err = types.NewErrorsMerged(dealDB.Error, orderDB.Error, feeDB.Error, txDB.Error, matchDB.Error,
+		heightDB.Error); err != nil {
 		return err
 	}
+	for _, tableName := range types.GetAllKlineMap() {
+		if klineDB := tx.Delete(types.MustNewKlineFactory(tableName, nil)); klineDB.Error != nil {
+			return klineDB.Error
+		}
+	}
 	tx.Commit()
 
 	return nil
@@ -77,3 +84,21 @@ func TestNewORM_BatchInsert(t *testing.T) {
 	orm, _ := NewMysqlORM()
 	testORMBatchInsert(t, orm)
 }
+
+func TestMysql_Matches(t *testing.T) {
+	common.SkipSysTestChecker(t)
+	orm, _ := NewMysqlORM()
+	testORMMatches(t, orm)
+}
+
+func TestMysql_IndexBlock(t *testing.T) {
+	common.SkipSysTestChecker(t)
+	orm, _ := NewMysqlORM()
+	testORMIndexBlock(t, orm)
+}
+
+func TestMysql_GenerateKlines(t *testing.T) {
+	common.SkipSysTestChecker(t)
+	orm, _ := NewMysqlORM()
+	testORMGenerateKlines(t, orm)
+}
@@ -0,0 +1,76 @@
+package orm
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/okex/okchain/x/common"
+)
+
+// NewPostgresORM connects the postgres of test environment, and skips the test if it fails to
+func NewPostgresORM(t *testing.T) *ORM {
+	engineInfo := OrmEngineInfo{
+		EngineType: EngineTypePostgres,
+		ConnectStr: "host=127.0.0.1 port=15432 user=okdexer password=okdex123! dbname=okdex sslmode=disable",
+	}
+	postgresOrm, err := newPostgresORM(&engineInfo)
+	if err != nil {
+		t.Skipf("failed to connect postgres: %v", err)
+	}
+
+	dorm := DangrousORM{postgresOrm}
+	if err := dorm.CleanupDataInTestEvn(); err != nil {
+		t.Skipf("failed to clean up postgres: %v", err)
+	}
+	return postgresOrm
+}
+
+// newPostgresORM returns the error of New instead of its panic on the connection failure
+func newPostgresORM(engineInfo *OrmEngineInfo) (orm *ORM, err error) {
+	defer func() {
+		if e := recover(); e != nil {
+			err = fmt.Errorf("%v", e)
+		}
+	}()
+	return New(false, engineInfo, nil)
+}
+
+func TestPostgres_ORMDeals(t *testing.T) {
+	common.SkipSysTestChecker(t)
+	testORMDeals(t, NewPostgresORM(t))
+}
+
+func TestPostgres_FeeDetails(t *testing.T) {
+	common.SkipSysTestChecker(t)
+	testORMFeeDetails(t, NewPostgresORM(t))
+}
+
+func TestPostgres_Orders(t *testing.T) {
+	common.SkipSysTestChecker(t)
+	testORMOrders(t, NewPostgresORM(t))
+}
+
+func TestPostgres_Transactions(t *testing.T) {
+	common.SkipSysTestChecker(t)
+	testORMTransactions(t, NewPostgresORM(t))
+}
+
+func TestPostgres_BatchInsert(t *testing.T) {
+	common.SkipSysTestChecker(t)
+	testORMBatchInsert(t, NewPostgresORM(t))
+}
+
+func TestPostgres_Matches(t *testing.T) {
+	common.SkipSysTestChecker(t)
+	testORMMatches(t, NewPostgresORM(t))
+}
+
+func TestPostgres_IndexBlock(t *testing.T) {
+	common.SkipSysTestChecker(t)
+	testORMIndexBlock(t, NewPostgresORM(t))
+}
+
+func TestPostgres_GenerateKlines(t *testing.T) {
+	common.SkipSysTestChecker(t)
+	testORMGenerateKlines(t, NewPostgresORM(t))
+}
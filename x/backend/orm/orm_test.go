@@ -102,6 +102,17 @@ func getTimestampRange() (int64, int64) {
 	return startTime.Unix(), endTime.Unix()
 }
 
+func TestORM_EngineTypes(t *testing.T) {
+	for _, engineType := range []string{EngineTypeSqlite, EngineTypeMysql, EngineTypePostgres} {
+		dialect, ok := gorm.GetDialect(engineType)
+		require.True(t, ok, engineType)
+		require.Equal(t, engineType, dialect.GetName())
+	}
+
+	_, err := New(false, &OrmEngineInfo{EngineType: "mssql", ConnectStr: ""}, nil)
+	require.NotNil(t, err)
+}
+
 func TestTimestamp(t *testing.T) {
 	now := time.Now()
 	unixTimestamp := now.Unix()
@@ -487,10 +498,7 @@ func testORMDeals(t *testing.T, orm *ORM) {
 }
 
 // Matches
-func TestORMMatches(t *testing.T) {
-	orm, dbPath := MockSqlite3ORM()
-	defer DeleteDB(dbPath)
-
+func testORMMatches(t *testing.T, orm *ORM) {
 	addMatches := []*types.MatchResult{
 		{Timestamp: 100, BlockHeight: 1, Product: types.TestTokenPair, Price: 10.0, Quantity: 1.0},
 		{Timestamp: 100, BlockHeight: 1, Product: "btc_" + common.NativeToken, Price: 11.0, Quantity: 2.0},
@@ -539,9 +547,20 @@ func TestORMMatches(t *testing.T) {
 	//
 	mrds := MergeResultDataSource{orm}
 	require.EqualValues(t, 100, mrds.getDataSourceMinTimestamp())
-	sql := `select product, sum(Quantity) as quantity, max(Price) as high, min(Price) as low, count(price) as cnt from match_results where Timestamp >= 0 and Timestamp < 1574406957 group by product`
-	require.EqualValues(t, sql, mrds.getMaxMinSumByGroupSQL(0, 1574406957))
+}
+
+func TestORMMatches(t *testing.T) {
+	orm, dbPath := MockSqlite3ORM()
+	defer DeleteDB(dbPath)
+	testORMMatches(t, orm)
 
+	// identifiers are quoted in the flavour of the engine
+	mrds := MergeResultDataSource{orm}
+	sql := `select "product", sum("quantity") as quantity, max("price") as high, min("price") as low, count("price") as cnt from "match_results" where "timestamp" >= 0 and "timestamp" < 1574406957 group by "product"`
+	require.EqualValues(t, sql, mrds.getMaxMinSumByGroupSQL(0, 1574406957))
+	dds := DealDataSource{orm}
+	sql = `select "product", sum("quantity") as quantity, max("price") as high, min("price") as low, count("price") as cnt from "deals" where "timestamp" >= 0 and "timestamp" < 1574406957 and "side" = 'BUY' group by "product"`
+	require.EqualValues(t, sql, dds.getMaxMinSumByGroupSQL(0, 1574406957))
 }
 
 func TestSqlite3_ORMDeals(t *testing.T) {
@@ -656,6 +675,18 @@ func testORMOrders(t *testing.T, orm *ORM) {
 	require.Equal(t, 1, len(otherOrdersV2))
 	require.Equal(t, updateOrders[2], &otherOrdersV2[0])
 
+	// untriggered orders are listed as open orders
+	untriggeredOrder := &types.Order{TxHash: "hash5", OrderID: "ID5", Sender: "addr3", Product: types.TestTokenPair, Side: types.SellOrder, Price: "9.0", Quantity: "1.1", Status: types.OrderStatusUntriggered, FilledAvgPrice: "0", RemainQuantity: "1.1", Timestamp: 400, TriggerType: "STOP_LOSS", TriggerPrice: "9.5"}
+	cnt, err = orm.AddOrders([]*types.Order{untriggeredOrder})
+	require.Nil(t, err)
+	require.EqualValues(t, 1, cnt)
+	getOrders, total = orm.GetOrderList("addr3", "", "", true, 0, 10, 0, 0, false)
+	require.EqualValues(t, 1, total)
+	require.EqualValues(t, "ID5", getOrders[0].OrderID)
+	require.EqualValues(t, "9.5", getOrders[0].TriggerPrice)
+	_, total = orm.GetOrderList("addr3", "", "", false, 0, 10, 0, 0, false)
+	require.EqualValues(t, 0, total)
+
 	// v2 GetOrderByID
 	ordersByExistID := orm.GetOrderByID("ID1")
 	require.EqualValues(t, updateOrders[0], ordersByExistID)
@@ -802,6 +833,138 @@ func TestORM_BatchInsert(t *testing.T) {
 	testORMBatchInsert(t, orm)
 }
 
+func testORMIndexBlock(t *testing.T, orm *ORM) {
+	height, err := orm.GetIndexedHeight()
+	require.Nil(t, err)
+	require.Equal(t, int64(0), height)
+
+	newOrders := []*types.Order{
+		{TxHash: "hash1", OrderID: "ID0000000001-1", Sender: "addr1", Product: types.TestTokenPair, Side: types.BuyOrder, Price: "10.0", Quantity: "1.1", Status: 0, FilledAvgPrice: "0", RemainQuantity: "1.1", Timestamp: 100},
+	}
+	deals := []*types.Deal{
+		{Timestamp: 100, BlockHeight: 1, OrderID: "ID0000000001-1", Sender: "addr1", Product: types.TestTokenPair, Side: types.BuyOrder, Price: 0.00000123, Quantity: 1.0, Fee: "0"},
+	}
+	resultMap, err := orm.IndexBlock(1, 100, newOrders, nil, deals, nil, nil, nil)
+	require.Nil(t, err)
+	require.Equal(t, 1, resultMap["newOrders"])
+	require.Equal(t, 1, resultMap["deals"])
+	height, err = orm.GetIndexedHeight()
+	require.Nil(t, err)
+	require.Equal(t, int64(1), height)
+
+	// bound values keep the precision of deal price
+	dbDeals, total := orm.GetDeals("addr1", "", "", 0, 0, 0, 10)
+	require.Equal(t, 1, total)
+	require.Equal(t, 0.00000123, dbDeals[0].Price)
+
+	// the replayed block is skipped instead of duplicated
+	resultMap, err = orm.IndexBlock(1, 100, newOrders, nil, deals, nil, nil, nil)
+	require.Nil(t, err)
+	require.Nil(t, resultMap)
+	_, total = orm.GetDeals("addr1", "", "", 0, 0, 0, 10)
+	require.Equal(t, 1, total)
+
+	// a failed write rolls back the whole block
+	deals2 := []*types.Deal{
+		{Timestamp: 200, BlockHeight: 2, OrderID: "ID0000000002-1", Sender: "addr1", Product: types.TestTokenPair, Side: types.BuyOrder, Price: 10.0, Quantity: 1.0, Fee: "0"},
+	}
+	_, err = orm.IndexBlock(2, 200, newOrders, nil, deals2, nil, nil, nil)
+	require.NotNil(t, err)
+	height, err = orm.GetIndexedHeight()
+	require.Nil(t, err)
+	require.Equal(t, int64(1), height)
+	_, total = orm.GetDeals("addr1", "", "", 0, 0, 0, 10)
+	require.Equal(t, 1, total)
+
+	// a block beyond the next height is refused
+	_, err = orm.IndexBlock(3, 300, nil, nil, deals2, nil, nil, nil)
+	require.Equal(t, ErrIndexGap{IndexedHeight: 1, Height: 3}, err)
+	_, total = orm.GetDeals("addr1", "", "", 0, 0, 0, 10)
+	require.Equal(t, 1, total)
+
+	resultMap, err = orm.IndexBlock(2, 200, nil, nil, deals2, nil, nil, nil)
+	require.Nil(t, err)
+	require.Equal(t, 1, resultMap["deals"])
+	height, err = orm.GetIndexedHeight()
+	require.Nil(t, err)
+	require.Equal(t, int64(2), height)
+}
+
+func TestORM_IndexBlock(t *testing.T) {
+	orm, dbPath := MockSqlite3ORM()
+	defer DeleteDB(dbPath)
+	testORMIndexBlock(t, orm)
+
+	unindexed, err := orm.HasUnindexedData()
+	require.Nil(t, err)
+	require.False(t, unindexed)
+
+	// the backend enabled on a running node refuses the blocks until the missing ones are reindexed
+	deals := []*types.Deal{
+		{Timestamp: 100, BlockHeight: 1, OrderID: "ID0000000001-1", Sender: "addr1", Product: types.TestTokenPair, Side: types.BuyOrder, Price: 0.00000123, Quantity: 1.0, Fee: "0"},
+	}
+	orm2, err := NewSqlite3ORM(false, "/tmp/", "test_index_block.db", nil)
+	require.Nil(t, err)
+	defer DeleteDB("/tmp/test_index_block.db")
+	_, err = orm2.IndexBlock(100, 1000, nil, nil, deals, nil, nil, nil)
+	require.Equal(t, ErrIndexGap{IndexedHeight: 0, Height: 100}, err)
+	height, err := orm2.GetIndexedHeight()
+	require.Nil(t, err)
+	require.Equal(t, int64(0), height)
+
+	// so does the database created before the indexed height was recorded
+	unindexed, err = orm2.HasUnindexedData()
+	require.Nil(t, err)
+	require.False(t, unindexed)
+	_, err = orm2.AddDeals(deals)
+	require.Nil(t, err)
+	unindexed, err = orm2.HasUnindexedData()
+	require.Nil(t, err)
+	require.True(t, unindexed)
+	_, err = orm2.IndexBlock(2, 200, nil, nil, nil, nil, nil, nil)
+	require.Equal(t, ErrIndexGap{IndexedHeight: 0, Height: 2}, err)
+}
+
+func testORMGenerateKlines(t *testing.T, orm *ORM) {
+	// nothing to generate without match results
+	require.Nil(t, orm.GenerateKlines(time.Now().Unix()))
+
+	results := []*types.MatchResult{
+		{Timestamp: 1577836800, BlockHeight: 1, Product: types.TestTokenPair, Price: 10.0, Quantity: 1.0},
+		{Timestamp: 1577836830, BlockHeight: 2, Product: types.TestTokenPair, Price: 12.0, Quantity: 2.0},
+		{Timestamp: 1577840460, BlockHeight: 3, Product: types.TestTokenPair, Price: 11.0, Quantity: 1.0},
+	}
+	_, err := orm.AddMatchResults(results)
+	require.Nil(t, err)
+	// open and close prices come from the deals of the match results
+	deals := make([]*types.Deal, 0, len(results))
+	for i, result := range results {
+		deals = append(deals, &types.Deal{Timestamp: result.Timestamp, BlockHeight: result.BlockHeight,
+			OrderID: fmt.Sprintf("ID%d", i), Sender: "addr1", Product: result.Product, Side: types.BuyOrder,
+			Price: result.Price, Quantity: result.Quantity, Fee: "0"})
+	}
+	_, err = orm.AddDeals(deals)
+	require.Nil(t, err)
+	require.Nil(t, orm.GenerateKlines(1577840520))
+
+	klineM1List := []types.KlineM1{}
+	require.Nil(t, orm.GetLatestKlinesByProduct(types.TestTokenPair, 100, -1, &klineM1List))
+	require.Equal(t, 2, len(klineM1List))
+
+	klineM60List := []types.KlineM60{}
+	require.Nil(t, orm.GetLatestKlinesByProduct(types.TestTokenPair, 100, -1, &klineM60List))
+	// only the complete hours are merged
+	require.Equal(t, 1, len(klineM60List))
+	require.Equal(t, 12.0, klineM60List[0].High)
+	require.Equal(t, 3.0, klineM60List[0].Volume)
+}
+
+func TestORM_GenerateKlines(t *testing.T) {
+	orm, dbPath := MockSqlite3ORM()
+	defer DeleteDB(dbPath)
+	testORMGenerateKlines(t, orm)
+}
+
 func TestORM_CloseDB(t *testing.T) {
 	closeORM, err := NewSqlite3ORM(false, "/tmp/", "test_close.db", nil)
 	require.Nil(t, err)
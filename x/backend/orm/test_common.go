@@ -5,6 +5,8 @@ import (
 	"log"
 	"os"
 	"time"
+
+	"github.com/okex/okchain/x/backend/types"
 )
 
 // MockSqlite3ORM create sqlite db for test, return orm
@@ -40,6 +42,11 @@ func (orm *ORM) CommitKlines(klines ...[]interface{}) {
 	tx.Commit()
 }
 
+// MockIndexedHeight sets the indexed height for test, so that the blocks indexed next start at height+1
+func (orm *ORM) MockIndexedHeight(height int64) {
+	orm.db.Save(&types.IndexedHeight{ID: indexedHeightID, Height: height})
+}
+
 // DeleteDB remove the sqlite db
 func DeleteDB(dbPath string) {
 	if err := os.Remove(dbPath); err != nil {
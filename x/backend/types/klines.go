@@ -80,11 +80,11 @@ func (klines IKlinesDsc) Less(i, j int) bool {
 type BaseKline struct {
 	Product   string  `gorm:"PRIMARY_KEY;type:varchar(20)" json:"product"`
 	Timestamp int64   `gorm:"PRIMARY_KEY;type:bigint;" json:"timestamp"`
-	Open      float64 `gorm:"type:DOUBLE" json:"open"`
-	Close     float64 `gorm:"type:DOUBLE" json:"close"`
-	High      float64 `gorm:"type:DOUBLE" json:"high"`
-	Low       float64 `gorm:"type:DOUBLE" json:"low"`
-	Volume    float64 `gorm:"type:DOUBLE" json:"volume"`
+	Open      float64 `gorm:"type:double precision" json:"open"`
+	Close     float64 `gorm:"type:double precision" json:"close"`
+	High      float64 `gorm:"type:double precision" json:"high"`
+	Low       float64 `gorm:"type:double precision" json:"low"`
+	Volume    float64 `gorm:"type:double precision" json:"volume"`
 	impl      IKline
 }
 
@@ -21,11 +21,19 @@ const (
 	SellOrder     = orderTypes.SellOrder
 	TestTokenPair = orderTypes.TestTokenPair
 
-	FeeTypeOrderNew     = orderTypes.FeeTypeOrderNew
-	FeeTypeOrderCancel  = orderTypes.FeeTypeOrderCancel
-	FeeTypeOrderExpire  = orderTypes.FeeTypeOrderExpire
-	FeeTypeOrderDeal    = orderTypes.FeeTypeOrderDeal
-	FeeTypeOrderReceive = orderTypes.FeeTypeOrderReceive
+	FeeTypeOrderNew            = orderTypes.FeeTypeOrderNew
+	FeeTypeOrderCancel         = orderTypes.FeeTypeOrderCancel
+	FeeTypeOrderExpire         = orderTypes.FeeTypeOrderExpire
+	FeeTypeOrderDeal           = orderTypes.FeeTypeOrderDeal
+	FeeTypeOrderReceive        = orderTypes.FeeTypeOrderReceive
+	FeeTypeOrderIOCCancel      = orderTypes.FeeTypeOrderIOCCancel
+	FeeTypeOrderFOKKill        = orderTypes.FeeTypeOrderFOKKill
+	FeeTypeOrderPostOnlyReject = orderTypes.FeeTypeOrderPostOnlyReject
+	FeeTypeOrderGTBExpire      = orderTypes.FeeTypeOrderGTBExpire
+	FeeTypeOrderSelfTrade      = orderTypes.FeeTypeOrderSelfTrade
+
+	OrderStatusOpen        = orderTypes.OrderStatusOpen
+	OrderStatusUntriggered = orderTypes.OrderStatusUntriggered
 )
 
 type Ticker struct {
@@ -63,17 +71,38 @@ func (tickers Tickers) Less(i, j int) bool {
 }
 
 type Order struct {
-	TxHash         string `gorm:"type:varchar(80)" json:"txhash" v2:"txhash"`
-	OrderID        string `gorm:"PRIMARY_KEY;type:varchar(30)" json:"order_id" v2:"order_id"`
-	Sender         string `gorm:"index;type:varchar(80)" json:"sender" v2:"sender"`
-	Product        string `gorm:"index;type:varchar(20)" json:"product" v2:"product"`
-	Side           string `gorm:"type:varchar(10)" json:"side" v2:"side"`
-	Price          string `gorm:"type:varchar(40)" json:"price" v2:"price"`
-	Quantity       string `gorm:"type:varchar(40)" json:"quantity" v2:"quantity"`
-	Status         int64  `gorm:"index;" json:"status" v2:"status"`
-	FilledAvgPrice string `gorm:"type:varchar(40)" json:"filled_avg_price" v2:"filled_avg_price"`
-	RemainQuantity string `gorm:"type:varchar(40)" json:"remain_quantity" v2:"remain_quantity"`
-	Timestamp      int64  `gorm:"index;" json:"timestamp" v2:"timestamp"`
+	TxHash          string `gorm:"type:varchar(80)" json:"txhash" v2:"txhash"`
+	OrderID         string `gorm:"PRIMARY_KEY;type:varchar(30)" json:"order_id" v2:"order_id"`
+	Sender          string `gorm:"index;type:varchar(80)" json:"sender" v2:"sender"`
+	Product         string `gorm:"index;type:varchar(20)" json:"product" v2:"product"`
+	Side            string `gorm:"type:varchar(10)" json:"side" v2:"side"`
+	Price           string `gorm:"type:varchar(40)" json:"price" v2:"price"`
+	Quantity        string `gorm:"type:varchar(40)" json:"quantity" v2:"quantity"`
+	Status          int64  `gorm:"index;" json:"status" v2:"status"`
+	FilledAvgPrice  string `gorm:"type:varchar(40)" json:"filled_avg_price" v2:"filled_avg_price"`
+	RemainQuantity  string `gorm:"type:varchar(40)" json:"remain_quantity" v2:"remain_quantity"`
+	Timestamp       int64  `gorm:"index;" json:"timestamp" v2:"timestamp"`
+	TriggerType     string `gorm:"type:varchar(20)" json:"trigger_type" v2:"trigger_type"`
+	TriggerPrice    string `gorm:"type:varchar(40)" json:"trigger_price" v2:"trigger_price"`
+	TriggeredHeight int64  `gorm:"" json:"triggered_height" v2:"triggered_height"`                 // 0 means untriggered
+	DisplayQuantity string `gorm:"type:varchar(40)" json:"display_quantity" v2:"display_quantity"` // empty means not iceberg
+}
+
+// SetTrigger records the trigger of stop-loss or take-profit order
+func (o *Order) SetTrigger(trigger *orderTypes.OrderTrigger) {
+	if trigger == nil {
+		return
+	}
+	o.TriggerType = trigger.Type
+	o.TriggerPrice = trigger.Price.String()
+	o.TriggeredHeight = trigger.TriggeredHeight
+}
+
+// SetDisplayQuantity records the visible slice of iceberg order
+func (o *Order) SetDisplayQuantity(order *orderTypes.Order) {
+	if order.IsIcebergOrder() {
+		o.DisplayQuantity = order.DisplayQuantity.String()
+	}
 }
 
 type Transaction struct {
@@ -86,3 +115,10 @@ type Transaction struct {
 	Fee       string `gorm:"type:varchar(40)" json:"fee" v2:"fee"`
 	Timestamp int64  `gorm:"index" json:"timestamp" v2:"timestamp"`
 }
+
+// IndexedHeight is the single row recording the height of the last block whose backend data has been committed
+type IndexedHeight struct {
+	ID        int64 `gorm:"PRIMARY_KEY" json:"-"`
+	Height    int64 `gorm:"type:bigint" json:"height"`
+	Timestamp int64 `gorm:"" json:"timestamp"`
+}
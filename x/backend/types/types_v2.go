@@ -17,8 +17,8 @@ type MatchResult struct {
 	Timestamp   int64   `gorm:"index;" json:"timestamp" v2:"timestamp"`
 	BlockHeight int64   `gorm:"PRIMARY_KEY;type:bigint" json:"block_height" v2:"block_height"`
 	Product     string  `gorm:"PRIMARY_KEY;type:varchar(20)" json:"product" v2:"product"`
-	Price       float64 `gorm:"type:DOUBLE" json:"price" v2:"price"`
-	Quantity    float64 `gorm:"type:DOUBLE" json:"volume" v2:"volume"`
+	Price       float64 `gorm:"type:double precision" json:"price" v2:"price"`
+	Quantity    float64 `gorm:"type:double precision" json:"volume" v2:"volume"`
 }
 
 type Deal struct {
@@ -28,8 +28,8 @@ type Deal struct {
 	Sender      string  `gorm:"index;type:varchar(80)" json:"sender" v2:"sender"`
 	Product     string  `gorm:"index;type:varchar(20)" json:"product" v2:"product"`
 	Side        string  `gorm:"type:varchar(10)" json:"side" v2:"side"`
-	Price       float64 `gorm:"type:DOUBLE" json:"price" v2:"price"`
-	Quantity    float64 `gorm:"type:DOUBLE" json:"volume" v2:"volume"`
+	Price       float64 `gorm:"type:double precision" json:"price" v2:"price"`
+	Quantity    float64 `gorm:"type:double precision" json:"volume" v2:"volume"`
 	Fee         string  `gorm:"type:varchar(20)" json:"fee" v2:"fee"`
 }
 
@@ -87,18 +87,22 @@ func ConvertTokenPairToInstrumentV2(tokenPair *dex.TokenPair) *InstrumentV2 {
 }
 
 type OrderV2 struct {
-	OrderID        string `json:"order_id"`
-	Price          string `json:"price"`
-	Size           string `json:"size"`
-	OrderType      string `json:"order_type"`
-	Notional       string `json:"notional"`
-	InstrumentID   string `json:"instrument_id"`
-	Side           string `json:"side"`
-	Type           string `json:"type"`
-	Timestamp      string `json:"timestamp"`
-	FilledSize     string `json:"filled_size"`
-	FilledNotional string `json:"filled_notional"`
-	State          string `json:"state"`
+	OrderID         string `json:"order_id"`
+	Price           string `json:"price"`
+	Size            string `json:"size"`
+	OrderType       string `json:"order_type"`
+	Notional        string `json:"notional"`
+	InstrumentID    string `json:"instrument_id"`
+	Side            string `json:"side"`
+	Type            string `json:"type"`
+	Timestamp       string `json:"timestamp"`
+	FilledSize      string `json:"filled_size"`
+	FilledNotional  string `json:"filled_notional"`
+	State           string `json:"state"`
+	TriggerType     string `json:"trigger_type,omitempty"`
+	TriggerPrice    string `json:"trigger_price,omitempty"`
+	TriggeredHeight int64  `json:"triggered_height,omitempty"`
+	DisplaySize     string `json:"display_size,omitempty"`
 }
 
 func ConvertOrderToOrderV2(order Order) OrderV2 {
@@ -113,6 +117,10 @@ func ConvertOrderToOrderV2(order Order) OrderV2 {
 	res.Type = "limit"
 	res.Timestamp = time.Unix(order.Timestamp, 0).UTC().Format("2006-01-02T15:04:05.000Z")
 	res.State = strconv.FormatInt(order.Status, 10)
+	res.TriggerType = order.TriggerType
+	res.TriggerPrice = order.TriggerPrice
+	res.TriggeredHeight = order.TriggeredHeight
+	res.DisplaySize = order.DisplayQuantity
 
 	filledSizeDec := sdk.MustNewDecFromStr(order.Quantity).Sub(sdk.MustNewDecFromStr(order.RemainQuantity))
 	filledNotionalDec := filledSizeDec.Mul(sdk.MustNewDecFromStr(order.FilledAvgPrice))
@@ -14,6 +14,8 @@ type OrderMetric struct {
 	CanceledNum      metrics.Gauge
 	ExpiredNum       metrics.Gauge
 	PartialFilledNum metrics.Gauge
+	LockedProductNum metrics.Gauge
+	MaxLockedBlocks  metrics.Gauge
 }
 
 // DefaultOrderMetrics returns Metrics build using Prometheus client library if Prometheus is enabled
@@ -62,6 +64,18 @@ func NewOrderMetrics(labelsAndValues ...string) *OrderMetric {
 			Name:      "partial_filled",
 			Help:      "the number of partial_filled order",
 		}, labels).With(labelsAndValues...),
+		LockedProductNum: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: xNameSpace,
+			Subsystem: orderSubSystem,
+			Name:      "locked_products",
+			Help:      "the number of products locked for the lack of deal budget",
+		}, labels).With(labelsAndValues...),
+		MaxLockedBlocks: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: xNameSpace,
+			Subsystem: orderSubSystem,
+			Name:      "max_locked_blocks",
+			Help:      "the most blocks a product has been locked for",
+		}, labels).With(labelsAndValues...),
 	}
 }
 
@@ -73,5 +87,7 @@ func NopOrderMetrics() *OrderMetric {
 		CanceledNum:      discard.NewGauge(),
 		ExpiredNum:       discard.NewGauge(),
 		PartialFilledNum: discard.NewGauge(),
+		LockedProductNum: discard.NewGauge(),
+		MaxLockedBlocks:  discard.NewGauge(),
 	}
 }
@@ -23,6 +23,10 @@ const (
 	DefaultMaxPriceDigitSize    = types.DefaultMaxPriceDigitSize
 	DefaultMaxQuantityDigitSize = types.DefaultMaxQuantityDigitSize
 
+	MatchModePeriodicAuction   = types.MatchModePeriodicAuction
+	MatchModeContinuousAuction = types.MatchModeContinuousAuction
+	DefaultMatchMode           = types.DefaultMatchMode
+
 	AuthFeeCollector = auth.FeeCollectorName
 )
 
@@ -44,10 +48,11 @@ type (
 	MsgTransferOwnership = types.MsgTransferOwnership
 
 	//
-	TokenPair     = types.TokenPair
-	Params        = types.Params
-	WithdrawInfo  = types.WithdrawInfo
-	WithdrawInfos = types.WithdrawInfos
+	TokenPair         = types.TokenPair
+	MatchModeProposal = types.MatchModeProposal
+	Params            = types.Params
+	WithdrawInfo      = types.WithdrawInfo
+	WithdrawInfos     = types.WithdrawInfos
 )
 
 var (
@@ -65,7 +70,11 @@ var (
 	NewMsgDeposit  = types.NewMsgDeposit
 	NewMsgWithdraw = types.NewMsgWithdraw
 
+	NewMatchModeProposal = types.NewMatchModeProposal
+	IsValidMatchMode     = types.IsValidMatchMode
+
 	ErrInvalidProduct      = types.ErrInvalidProduct
 	ErrTokenPairNotFound   = types.ErrTokenPairNotFound
 	ErrDelistOwnerNotMatch = types.ErrDelistOwnerNotMatch
+	ErrInvalidMatchMode    = types.ErrInvalidMatchMode
 )
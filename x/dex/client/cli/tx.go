@@ -330,3 +330,52 @@ Where proposal.json contains:
 	}
 
 }
+
+// GetCmdSubmitMatchModeProposal implememts a command handler for submitting a dex match mode proposal transaction
+func GetCmdSubmitMatchModeProposal(cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "match-mode-proposal [proposal-file]",
+		Args:  cobra.ExactArgs(1),
+		Short: "Submit a dex match mode proposal",
+		Long: strings.TrimSpace(
+			fmt.Sprintf(`Submit a dex proposal which switches the match mode of a token pair along with an initial deposit.
+The match mode should be one of %s and %s.
+The proposal details must be supplied via a JSON file.
+
+Example:
+$ %s tx gov submit-proposal match-mode-proposal <path/to/proposal.json> --from=<key_or_address>
+
+Where proposal.json contains:
+
+{
+ "title": "match xxx_%s by continuous auction",
+ "description": "switch the match mode of token pair",
+ "product": "xxx_%s",
+ "match_mode": "%s",
+ "deposit": [
+   {
+     "denom": "%s",
+     "amount": "100"
+   }
+ ]
+}
+`, types.MatchModePeriodicAuction, types.MatchModeContinuousAuction, version.ClientName,
+				sdk.DefaultBondDenom, sdk.DefaultBondDenom, types.MatchModeContinuousAuction, sdk.DefaultBondDenom,
+			)),
+		RunE: func(_ *cobra.Command, args []string) error {
+			txBldr := auth.NewTxBuilderFromCLI().WithTxEncoder(utils.GetTxEncoder(cdc))
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			proposal, err := dexUtils.ParseMatchModeProposalJSON(cdc, args[0])
+			if err != nil {
+				return err
+			}
+
+			from := cliCtx.GetFromAddress()
+			content := types.NewMatchModeProposal(proposal.Title, proposal.Description, from, proposal.Product,
+				proposal.MatchMode)
+			msg := gov.NewMsgSubmitProposal(content, proposal.Deposit, from)
+			return utils.GenerateOrBroadcastMsgs(cliCtx, txBldr, []sdk.Msg{msg})
+		},
+	}
+}
@@ -10,4 +10,7 @@ import (
 var (
 	// DelistProposalHandler alias gov NewProposalHandler
 	DelistProposalHandler = govclient.NewProposalHandler(cli.GetCmdSubmitDelistProposal, rest.DelistProposalRESTHandler)
+	// MatchModeProposalHandler alias gov NewProposalHandler
+	MatchModeProposalHandler = govclient.NewProposalHandler(cli.GetCmdSubmitMatchModeProposal,
+		rest.MatchModeProposalRESTHandler)
 )
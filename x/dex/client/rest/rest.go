@@ -141,3 +141,8 @@ func matchOrderHandler(cliContext context.CLIContext) func(http.ResponseWriter,
 func DelistProposalRESTHandler(context.CLIContext) govRest.ProposalRESTHandler {
 	return govRest.ProposalRESTHandler{}
 }
+
+// MatchModeProposalRESTHandler defines dex match mode proposal handler
+func MatchModeProposalRESTHandler(context.CLIContext) govRest.ProposalRESTHandler {
+	return govRest.ProposalRESTHandler{}
+}
@@ -31,3 +31,28 @@ func ParseDelistProposalJSON(cdc *codec.Codec, proposalFilePath string) (proposa
 
 	return proposal, nil
 }
+
+// MatchModeProposalJSON defines a MatchModeProposal with a deposit used
+// to parse match mode proposals from a JSON file.
+type MatchModeProposalJSON struct {
+	Title       string         `json:"title" yaml:"title"`
+	Description string         `json:"description" yaml:"description"`
+	Proposer    sdk.AccAddress `json:"proposer" yaml:"proposer"`
+	Product     string         `json:"product" yaml:"product"`
+	MatchMode   string         `json:"match_mode" yaml:"match_mode"`
+	Deposit     sdk.DecCoins   `json:"deposit" yaml:"deposit"`
+}
+
+// ParseMatchModeProposalJSON parse json from proposal file to MatchModeProposalJSON struct
+func ParseMatchModeProposalJSON(cdc *codec.Codec, proposalFilePath string) (proposal MatchModeProposalJSON, err error) {
+	contents, err := ioutil.ReadFile(proposalFilePath)
+	if err != nil {
+		return proposal, err
+	}
+
+	if err := cdc.UnmarshalJSON(contents, &proposal); err != nil {
+		return proposal, err
+	}
+
+	return proposal, nil
+}
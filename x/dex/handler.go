@@ -73,6 +73,7 @@ func handleMsgList(ctx sdk.Context, keeper IKeeper, msg MsgList, logger log.Logg
 		Delisting:        false,
 		Deposits:         DefaultTokenPairDeposit,
 		BlockHeight:      ctx.BlockHeight(),
+		MatchMode:        DefaultMatchMode,
 	}
 
 	// check tokenpair exist
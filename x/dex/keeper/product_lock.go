@@ -26,11 +26,18 @@ func (k Keeper) LockTokenPair(ctx sdk.Context, product string, lock *ordertypes.
 	k.cache.lockMap.Data[product] = lock
 }
 
-// UnlockTokenPair unlocks token pair
+// UnlockTokenPair unlocks token pair, and switches it to the match mode passed while it was locked
 func (k Keeper) UnlockTokenPair(ctx sdk.Context, product string) {
 	store := ctx.KVStore(k.storeKey)
 	store.Delete(types.GetLockProductKey(product))
 	delete(k.cache.lockMap.Data, product)
+
+	tokenPair := k.GetTokenPair(ctx, product)
+	if tokenPair != nil && tokenPair.PendingMatchMode != "" {
+		tokenPair.MatchMode = tokenPair.PendingMatchMode
+		tokenPair.PendingMatchMode = ""
+		k.UpdateTokenPair(ctx, product, tokenPair)
+	}
 }
 
 // LoadProductLocks loads product locked
@@ -13,7 +13,10 @@ import (
 
 // GetMinDeposit returns min deposit
 func (k Keeper) GetMinDeposit(ctx sdk.Context, content gov.Content) (minDeposit sdk.DecCoins) {
-	if _, ok := content.(types.DelistProposal); ok {
+	// match mode proposal shares the deposit and voting params with delist proposal, as both are raised by validators
+	// to change the trading of one token pair, and call for the same stake and time to vote
+	switch content.(type) {
+	case types.DelistProposal, types.MatchModeProposal:
 		minDeposit = k.GetParams(ctx).DelistMinDeposit
 	}
 	return
@@ -21,7 +24,8 @@ func (k Keeper) GetMinDeposit(ctx sdk.Context, content gov.Content) (minDeposit
 
 // GetMaxDepositPeriod returns max deposit period
 func (k Keeper) GetMaxDepositPeriod(ctx sdk.Context, content gov.Content) (maxDepositPeriod time.Duration) {
-	if _, ok := content.(types.DelistProposal); ok {
+	switch content.(type) {
+	case types.DelistProposal, types.MatchModeProposal:
 		maxDepositPeriod = k.GetParams(ctx).DelistMaxDepositPeriod
 	}
 	return
@@ -29,7 +33,8 @@ func (k Keeper) GetMaxDepositPeriod(ctx sdk.Context, content gov.Content) (maxDe
 
 // GetVotingPeriod returns voting period
 func (k Keeper) GetVotingPeriod(ctx sdk.Context, content gov.Content) (votingPeriod time.Duration) {
-	if _, ok := content.(types.DelistProposal); ok {
+	switch content.(type) {
+	case types.DelistProposal, types.MatchModeProposal:
 		votingPeriod = k.GetParams(ctx).DelistVotingPeriod
 	}
 	return
@@ -38,36 +43,71 @@ func (k Keeper) GetVotingPeriod(ctx sdk.Context, content gov.Content) (votingPer
 // check msg Delist proposal
 func (k Keeper) checkMsgDelistProposal(ctx sdk.Context, delistProposal types.DelistProposal, proposer sdk.AccAddress, initialDeposit sdk.DecCoins) sdk.Error {
 	// check the proposer of the msg is a validator
-	if !k.stakingKeeper.IsValidator(ctx, proposer) {
-		return gov.ErrInvalidProposer(types.DefaultCodespace, "failed to submit proposal because the proposer of delist proposal should be a validator")
+	if err := k.checkProposer(ctx, proposer, "delist"); err != nil {
+		return err
 	}
 
 	// check whether the baseAsset is in the Dex list
 	if !k.isTokenPairExisted(ctx, delistProposal.BaseAsset, delistProposal.QuoteAsset) {
-		return types.ErrInvalidProduct(fmt.Sprintf("failed to submit proposal because the asset with base asset '%s' and quote asset '%s' didn't exist on the Dex", delistProposal.BaseAsset, delistProposal.QuoteAsset))
+		return types.ErrInvalidProduct(fmt.Sprintf("failed to submit proposal because the asset with base asset "+
+			"'%s' and quote asset '%s' didn't exist on the Dex", delistProposal.BaseAsset, delistProposal.QuoteAsset))
 	}
 
-	// check the initial deposit
-	localMinDeposit := k.GetParams(ctx).DelistMinDeposit.MulDec(sdk.NewDecWithPrec(1, 1))
-	err := common.HasSufficientCoins(proposer, initialDeposit, localMinDeposit)
+	return k.checkInitialDeposit(ctx, proposer, initialDeposit)
+}
 
-	if err != nil {
-		return types.ErrInvalidAsset(fmt.Sprintf("failed to submit proposal because initial deposit should be more than %s", localMinDeposit.String()))
+// checkProposer checks the proposer of the msg is a validator
+func (k Keeper) checkProposer(ctx sdk.Context, proposer sdk.AccAddress, proposalName string) sdk.Error {
+	if !k.stakingKeeper.IsValidator(ctx, proposer) {
+		return gov.ErrInvalidProposer(types.DefaultCodespace, fmt.Sprintf("failed to submit proposal because "+
+			"the proposer of %s proposal should be a validator", proposalName))
 	}
+	return nil
+}
 
-	// check whether the proposer can afford the initial deposit
-	err = common.HasSufficientCoins(proposer, k.bankKeeper.GetCoins(ctx, proposer), initialDeposit)
-	if err != nil {
-		return types.ErrInvalidBalanceNotEnough(fmt.Sprintf("failed to submit proposal because proposer %s didn't have enough coins to pay for the initial deposit %s", proposer, initialDeposit))
+// checkInitialDeposit checks the initial deposit is enough and the proposer can afford it
+func (k Keeper) checkInitialDeposit(ctx sdk.Context, proposer sdk.AccAddress, initialDeposit sdk.DecCoins) sdk.Error {
+	localMinDeposit := k.GetParams(ctx).DelistMinDeposit.MulDec(sdk.NewDecWithPrec(1, 1))
+	if err := common.HasSufficientCoins(proposer, initialDeposit, localMinDeposit); err != nil {
+		return types.ErrInvalidAsset(fmt.Sprintf("failed to submit proposal because initial deposit should be "+
+			"more than %s", localMinDeposit.String()))
+	}
+
+	if err := common.HasSufficientCoins(proposer, k.bankKeeper.GetCoins(ctx, proposer), initialDeposit); err != nil {
+		return types.ErrInvalidBalanceNotEnough(fmt.Sprintf("failed to submit proposal because proposer %s "+
+			"didn't have enough coins to pay for the initial deposit %s", proposer, initialDeposit))
 	}
 	return nil
 }
 
+// check msg match mode proposal
+func (k Keeper) checkMsgMatchModeProposal(ctx sdk.Context, matchModeProposal types.MatchModeProposal, proposer sdk.AccAddress, initialDeposit sdk.DecCoins) sdk.Error {
+	// check the proposer of the msg is a validator
+	if err := k.checkProposer(ctx, proposer, "match mode"); err != nil {
+		return err
+	}
+
+	// check whether the token pair is in the Dex list and not in the same match mode
+	tokenPair := k.GetTokenPair(ctx, matchModeProposal.Product)
+	if tokenPair == nil {
+		return types.ErrTokenPairNotFound(fmt.Sprintf("failed to submit proposal because the token pair '%s' "+
+			"didn't exist on the Dex", matchModeProposal.Product))
+	}
+	if tokenPair.GetMatchMode() == matchModeProposal.MatchMode {
+		return types.ErrInvalidMatchMode(fmt.Sprintf("failed to submit proposal because the token pair '%s' "+
+			"is already matched by %s", matchModeProposal.Product, matchModeProposal.MatchMode))
+	}
+
+	return k.checkInitialDeposit(ctx, proposer, initialDeposit)
+}
+
 // CheckMsgSubmitProposal validates MsgSubmitProposal
 func (k Keeper) CheckMsgSubmitProposal(ctx sdk.Context, msg govTypes.MsgSubmitProposal) (sdkErr sdk.Error) {
 	switch content := msg.Content.(type) {
 	case types.DelistProposal:
 		sdkErr = k.checkMsgDelistProposal(ctx, content, msg.Proposer, msg.InitialDeposit)
+	case types.MatchModeProposal:
+		sdkErr = k.checkMsgMatchModeProposal(ctx, content, msg.Proposer, msg.InitialDeposit)
 	default:
 		errContent := fmt.Sprintf("unrecognized dex proposal content type: %T", content)
 		sdkErr = sdk.ErrUnknownRequest(errContent)
@@ -90,15 +130,20 @@ func (k Keeper) isTokenPairExisted(ctx sdk.Context, baseAsset, quoteAsset string
 // nolint
 func (k Keeper) AfterSubmitProposalHandler(ctx sdk.Context, proposal govTypes.Proposal) {}
 
-// VoteHandler handles  delist proposal when voted
+// VoteHandler handles delist proposal and match mode proposal when voted
 func (k Keeper) VoteHandler(ctx sdk.Context, proposal govTypes.Proposal, vote govTypes.Vote) (string, sdk.Error) {
-	if _, ok := proposal.Content.(types.DelistProposal); ok {
-		delistProposal := proposal.Content.(types.DelistProposal)
-		tokenPairName := delistProposal.BaseAsset + "_" + delistProposal.QuoteAsset
-		if k.IsTokenPairLocked(tokenPairName) {
-			errContent := fmt.Sprintf("the trading pair (%s) is locked, please retry later", tokenPairName)
-			return "", sdk.ErrInternal(errContent)
-		}
+	var tokenPairName string
+	switch content := proposal.Content.(type) {
+	case types.DelistProposal:
+		tokenPairName = content.BaseAsset + "_" + content.QuoteAsset
+	case types.MatchModeProposal:
+		tokenPairName = content.Product
+	default:
+		return "", nil
+	}
+	if k.IsTokenPairLocked(tokenPairName) {
+		errContent := fmt.Sprintf("the trading pair (%s) is locked, please retry later", tokenPairName)
+		return "", sdk.ErrInternal(errContent)
 	}
 	return "", nil
 }
@@ -91,6 +91,47 @@ func TestKeeper_CheckMsgSubmitProposal(t *testing.T) {
 
 }
 
+func TestKeeper_CheckMsgMatchModeProposal(t *testing.T) {
+	testInput := createTestInputWithBalance(t, 1, 10000)
+	ctx := testInput.Ctx
+
+	testInput.DexKeeper.SetParams(ctx, *types.DefaultParams())
+	tokenPair := GetBuiltInTokenPair()
+	deposit := sdk.DecCoins{sdk.NewDecCoin(common.NativeToken, sdk.NewInt(150))}
+
+	content := types.NewMatchModeProposal("continuous xxb_okt", "match xxb_okt by continuous auction",
+		tokenPair.Owner, tokenPair.Name(), types.MatchModeContinuousAuction)
+	proposal := govTypes.NewMsgSubmitProposal(content, deposit, tokenPair.Owner)
+
+	// error case : fail to check proposal because product(token pair) not exist
+	err := testInput.DexKeeper.CheckMsgSubmitProposal(ctx, proposal)
+	require.Error(t, err)
+	// SaveTokenPair
+	saveErr := testInput.DexKeeper.SaveTokenPair(ctx, tokenPair)
+	require.Nil(t, saveErr)
+
+	// successful case : check proposal successfully
+	err = testInput.DexKeeper.CheckMsgSubmitProposal(ctx, proposal)
+	require.NoError(t, err)
+
+	// error case : fail to check proposal because the token pair is already matched by periodic auction
+	content.MatchMode = types.MatchModePeriodicAuction
+	proposal1 := govTypes.NewMsgSubmitProposal(content, deposit, tokenPair.Owner)
+	err = testInput.DexKeeper.CheckMsgSubmitProposal(ctx, proposal1)
+	require.Error(t, err)
+
+	// match mode proposal shares the deposit and voting params with delist proposal
+	params := testInput.DexKeeper.GetParams(ctx)
+	require.True(t, testInput.DexKeeper.GetMinDeposit(ctx, content).IsEqual(params.DelistMinDeposit))
+	require.EqualValues(t, params.DelistMaxDepositPeriod, testInput.DexKeeper.GetMaxDepositPeriod(ctx, content))
+	require.EqualValues(t, params.DelistVotingPeriod, testInput.DexKeeper.GetVotingPeriod(ctx, content))
+
+	// error case : fail to vote because the token pair is locked
+	testInput.DexKeeper.LockTokenPair(ctx, tokenPair.Name(), &ordertypes.ProductLock{})
+	_, err = testInput.DexKeeper.VoteHandler(ctx, govTypes.Proposal{Content: content}, govTypes.Vote{})
+	require.NotNil(t, err)
+}
+
 func TestKeeper_RejectedHandler(t *testing.T) {
 	testInput := createTestInputWithBalance(t, 1, 10000)
 	ctx := testInput.Ctx
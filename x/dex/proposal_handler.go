@@ -14,6 +14,8 @@ func NewProposalHandler(k *Keeper) govTypes.Handler {
 		switch c := proposal.Content.(type) {
 		case types.DelistProposal:
 			return handleDelistProposal(ctx, k, proposal)
+		case types.MatchModeProposal:
+			return handleMatchModeProposal(ctx, k, proposal)
 		default:
 			errMsg := fmt.Sprintf("unrecognized param proposal content type: %s", c)
 			return sdk.ErrUnknownRequest(errMsg)
@@ -56,3 +58,33 @@ func handleDelistProposal(ctx sdk.Context, keeper *Keeper, proposal *govTypes.Pr
 		))
 	return nil
 }
+
+// handleMatchModeProposal switches the match mode of the token pair. The proposal is executed in gov EndBlocker,
+// so the new match mode takes effect from the match of the block in which the proposal passes. The locked token pair
+// is still being filled by periodic auction, so its new match mode is kept pending until it's unlocked
+func handleMatchModeProposal(ctx sdk.Context, keeper *Keeper, proposal *govTypes.Proposal) (err sdk.Error) {
+	p := proposal.Content.(types.MatchModeProposal)
+	logger := ctx.Logger().With("module", types.ModuleName)
+	logger.Debug("execute MatchModeProposal begin")
+
+	tokenPair := keeper.GetTokenPair(ctx, p.Product)
+	if tokenPair == nil {
+		return ErrTokenPairNotFound(fmt.Sprintf("%+v", p))
+	}
+	attrKey := "token-pair-match-mode"
+	if keeper.IsTokenPairLocked(p.Product) {
+		tokenPair.PendingMatchMode = p.MatchMode
+		attrKey = "token-pair-pending-match-mode"
+	} else {
+		tokenPair.MatchMode = p.MatchMode
+		tokenPair.PendingMatchMode = ""
+	}
+	keeper.UpdateTokenPair(ctx, p.Product, tokenPair)
+
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			sdk.EventTypeMessage,
+			sdk.NewAttribute(attrKey, fmt.Sprintf("%s:%s", p.Product, p.MatchMode)),
+		))
+	return nil
+}
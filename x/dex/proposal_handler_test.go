@@ -67,3 +67,49 @@ func TestProposal_NewProposalHandler(t *testing.T) {
 	require.Error(t, err)
 
 }
+
+func TestProposal_HandleMatchModeProposal(t *testing.T) {
+	fakeTokenKeeper := newMockTokenKeeper()
+	fakeSupplyKeeper := newMockSupplyKeeper()
+
+	mApp, mDexKeeper, err := newMockApp(fakeTokenKeeper, fakeSupplyKeeper, 10)
+	require.True(t, err == nil)
+
+	mApp.BeginBlock(abci.RequestBeginBlock{Header: abci.Header{Height: 2}})
+	ctx := mApp.BaseApp.NewContext(false, abci.Header{})
+
+	proposalHandler := NewProposalHandler(mDexKeeper.Keeper)
+	tokenPair := GetBuiltInTokenPair()
+	content := types.NewMatchModeProposal("continuous xxb_okt", "match xxb_okt by continuous auction",
+		tokenPair.Owner, tokenPair.Name(), types.MatchModeContinuousAuction)
+	proposal := govTypes.Proposal{Content: content}
+
+	// error case : fail to handle proposal because product(token pair) not exist
+	err = proposalHandler(ctx, &proposal)
+	require.Error(t, err)
+
+	// the token pair without match mode is matched by periodic auction
+	saveErr := mApp.dexKeeper.SaveTokenPair(ctx, tokenPair)
+	require.Nil(t, saveErr)
+	require.Equal(t, types.MatchModePeriodicAuction, mDexKeeper.Keeper.GetTokenPair(ctx, tokenPair.Name()).GetMatchMode())
+
+	// the locked token pair is switched to continuous auction once unlocked
+	mDexKeeper.LockTokenPair(ctx, tokenPair.Name(), &ordertypes.ProductLock{})
+	err = proposalHandler(ctx, &proposal)
+	require.Nil(t, err)
+	require.Equal(t, types.MatchModePeriodicAuction, mDexKeeper.Keeper.GetTokenPair(ctx, tokenPair.Name()).GetMatchMode())
+	require.Equal(t, types.MatchModeContinuousAuction,
+		mDexKeeper.GetTokenPairFromStore(ctx, tokenPair.Name()).PendingMatchMode)
+	mDexKeeper.UnlockTokenPair(ctx, tokenPair.Name())
+	require.Equal(t, types.MatchModeContinuousAuction, mDexKeeper.Keeper.GetTokenPair(ctx, tokenPair.Name()).GetMatchMode())
+	require.Equal(t, "", mDexKeeper.GetTokenPairFromStore(ctx, tokenPair.Name()).PendingMatchMode)
+
+	// successful case : switch back to periodic auction
+	content.MatchMode = types.MatchModePeriodicAuction
+	proposal = govTypes.Proposal{Content: content}
+	err = proposalHandler(ctx, &proposal)
+	require.Nil(t, err)
+	require.Equal(t, types.MatchModePeriodicAuction, mDexKeeper.Keeper.GetTokenPair(ctx, tokenPair.Name()).GetMatchMode())
+	require.Equal(t, types.MatchModePeriodicAuction,
+		mDexKeeper.GetTokenPairFromStore(ctx, tokenPair.Name()).GetMatchMode())
+}
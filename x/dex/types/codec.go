@@ -10,6 +10,7 @@ func RegisterCodec(cdc *codec.Codec) {
 	cdc.RegisterConcrete(MsgWithdraw{}, "okchain/dex/MsgWithdraw", nil)
 	cdc.RegisterConcrete(MsgTransferOwnership{}, "okchain/dex/MsgTransferTradingPairOwnership", nil)
 	cdc.RegisterConcrete(DelistProposal{}, "okchain/dex/DelistProposal", nil)
+	cdc.RegisterConcrete(MatchModeProposal{}, "okchain/dex/MatchModeProposal", nil)
 
 }
 
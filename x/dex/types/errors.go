@@ -14,6 +14,7 @@ const (
 
 	codeInvalidBalanceNotEnough sdk.CodeType = 4
 	codeInvalidAsset            sdk.CodeType = 5
+	codeInvalidMatchMode        sdk.CodeType = 6
 )
 
 // CodeType to Message
@@ -25,6 +26,8 @@ func codeToDefaultMsg(code sdk.CodeType) string {
 		return "tokenpair not found"
 	case codeDelistOwnerNotMatch:
 		return "tokenpair delistor should be it's owner "
+	case codeInvalidMatchMode:
+		return "invalid match mode"
 	default:
 		return fmt.Sprintf("unknown code %d", code)
 	}
@@ -54,3 +57,8 @@ func ErrInvalidBalanceNotEnough(message string) sdk.Error {
 func ErrInvalidAsset(message string) sdk.Error {
 	return sdk.NewError(DefaultCodespace, codeInvalidAsset, message)
 }
+
+// ErrInvalidMatchMode returns invalid match mode error
+func ErrInvalidMatchMode(msg string) sdk.Error {
+	return sdk.NewError(DefaultCodespace, codeInvalidMatchMode, codeToDefaultMsg(codeInvalidMatchMode)+": %s", msg)
+}
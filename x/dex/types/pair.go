@@ -10,6 +10,18 @@ import (
 // DefaultTokenPairDeposit defines default deposit of token pair
 var DefaultTokenPairDeposit = sdk.NewDecCoin(sdk.DefaultBondDenom, sdk.NewInt(0))
 
+// nolint
+const (
+	MatchModePeriodicAuction   = "periodicauction"
+	MatchModeContinuousAuction = "continuousauction"
+	DefaultMatchMode           = MatchModePeriodicAuction
+)
+
+// IsValidMatchMode returns true if the match mode is supported by order module
+func IsValidMatchMode(matchMode string) bool {
+	return matchMode == MatchModePeriodicAuction || matchMode == MatchModeContinuousAuction
+}
+
 // TokenPair represents token pair object
 type TokenPair struct {
 	BaseAssetSymbol  string         `json:"base_asset_symbol"`
@@ -23,6 +35,10 @@ type TokenPair struct {
 	Owner            sdk.AccAddress `json:"owner"`
 	Deposits         sdk.DecCoin    `json:"deposits"`
 	BlockHeight      int64          `json:"block_height"`
+	MatchMode        string         `json:"match_mode"`
+	// PendingMatchMode is the match mode passed by proposal while the token pair is locked, which is switched to once
+	// the token pair is unlocked
+	PendingMatchMode string `json:"pending_match_mode,omitempty"`
 }
 
 // Name returns name of token pair
@@ -30,6 +46,15 @@ func (tp *TokenPair) Name() string {
 	return fmt.Sprintf("%s_%s", tp.BaseAssetSymbol, tp.QuoteAssetSymbol)
 }
 
+// GetMatchMode returns the match mode of token pair, the token pairs listed before match mode was
+// introduced are matched by periodic auction
+func (tp *TokenPair) GetMatchMode() string {
+	if tp.MatchMode == "" {
+		return DefaultMatchMode
+	}
+	return tp.MatchMode
+}
+
 // IsGT returns true if the token pair is greater than the other one
 // 1. compare deposits
 // 2. compare block height
@@ -9,13 +9,15 @@ import (
 )
 
 const (
-	proposalTypeDelist = "Delist"
+	proposalTypeDelist    = "Delist"
+	proposalTypeMatchMode = "MatchMode"
 )
 
 func init() {
 	govtypes.RegisterProposalType(proposalTypeDelist)
 	govtypes.RegisterProposalTypeCodec(DelistProposal{}, "okchain/dex/DelistProposal")
-
+	govtypes.RegisterProposalType(proposalTypeMatchMode)
+	govtypes.RegisterProposalTypeCodec(MatchModeProposal{}, "okchain/dex/MatchModeProposal")
 }
 
 // Assert DelistProposal implements govtypes.Content at compile-time
@@ -107,3 +109,97 @@ func (drp DelistProposal) String() string {
 		drp.BaseAsset, drp.QuoteAsset,
 	)
 }
+
+// Assert MatchModeProposal implements govtypes.Content at compile-time
+var _ govtypes.Content = (*MatchModeProposal)(nil)
+
+// MatchModeProposal represents the proposal object which switches the match mode of a token pair
+type MatchModeProposal struct {
+	Title       string         `json:"title" yaml:"title"`
+	Description string         `json:"description" yaml:"description"`
+	Proposer    sdk.AccAddress `json:"proposer" yaml:"proposer"`
+	Product     string         `json:"product" yaml:"product"`
+	MatchMode   string         `json:"match_mode" yaml:"match_mode"`
+}
+
+// NewMatchModeProposal create a new match mode proposal object
+func NewMatchModeProposal(title, description string, proposer sdk.AccAddress, product, matchMode string) MatchModeProposal {
+	return MatchModeProposal{
+		Title:       title,
+		Description: description,
+		Proposer:    proposer,
+		Product:     product,
+		MatchMode:   matchMode,
+	}
+}
+
+// GetTitle returns title of match mode proposal object
+func (mmp MatchModeProposal) GetTitle() string {
+	return mmp.Title
+}
+
+// GetDescription returns description of match mode proposal object
+func (mmp MatchModeProposal) GetDescription() string {
+	return mmp.Description
+}
+
+// ProposalRoute returns route key of match mode proposal object
+func (MatchModeProposal) ProposalRoute() string {
+	return RouterKey
+}
+
+// ProposalType returns type of match mode proposal object
+func (MatchModeProposal) ProposalType() string {
+	return proposalTypeMatchMode
+}
+
+// ValidateBasic validates match mode proposal
+func (mmp MatchModeProposal) ValidateBasic() sdk.Error {
+	if len(strings.TrimSpace(mmp.Title)) == 0 {
+		return govtypes.ErrInvalidProposalContent(DefaultCodespace, "failed to submit match mode proposal because title is blank")
+	}
+	if len(mmp.Title) > govtypes.MaxTitleLength {
+		return govtypes.ErrInvalidProposalContent(DefaultCodespace, fmt.Sprintf("failed to submit match mode proposal because title is longer than max length of %d", govtypes.MaxTitleLength))
+	}
+
+	if len(mmp.Description) == 0 {
+		return govtypes.ErrInvalidProposalContent(DefaultCodespace, "failed to submit match mode proposal because description is blank")
+	}
+
+	if len(mmp.Description) > govtypes.MaxDescriptionLength {
+		return govtypes.ErrInvalidProposalContent(DefaultCodespace, fmt.Sprintf("failed to submit match mode proposal because description is longer than max length of %d", govtypes.MaxDescriptionLength))
+	}
+
+	if mmp.ProposalType() != proposalTypeMatchMode {
+		return govtypes.ErrInvalidProposalType(DefaultCodespace, mmp.ProposalType())
+	}
+
+	if mmp.Proposer.Empty() {
+		return sdk.ErrInvalidAddress(mmp.Proposer.String())
+	}
+
+	if len(strings.Split(mmp.Product, "_")) != 2 {
+		return ErrInvalidProduct(mmp.Product)
+	}
+
+	if !IsValidMatchMode(mmp.MatchMode) {
+		return ErrInvalidMatchMode(mmp.MatchMode)
+	}
+
+	return nil
+}
+
+// String converts match mode proposal object to string
+func (mmp MatchModeProposal) String() string {
+	return fmt.Sprintf(`MatchModeProposal:
+ Title:               %s
+ Description:         %s
+ Type:                %s
+ Proposer:            %s
+ Product:             %s
+ MatchMode:           %s
+`, mmp.Title, mmp.Description,
+		mmp.ProposalType(), mmp.Proposer,
+		mmp.Product, mmp.MatchMode,
+	)
+}
@@ -47,6 +47,53 @@ func TestDelistProposal_ValidateBasic(t *testing.T) {
 	}
 }
 
+func TestMatchModeProposal_ValidateBasic(t *testing.T) {
+	addr, err := sdk.AccAddressFromBech32(TestTokenPairOwner)
+	require.Nil(t, err)
+
+	proposal := NewMatchModeProposal("proposal", "right match mode proposal", addr, "eth_btc",
+		MatchModeContinuousAuction)
+	require.Equal(t, "proposal", proposal.GetTitle())
+	require.Equal(t, "right match mode proposal", proposal.GetDescription())
+	require.Equal(t, RouterKey, proposal.ProposalRoute())
+	require.Equal(t, proposalTypeMatchMode, proposal.ProposalType())
+
+	tests := []struct {
+		name   string
+		mmp    MatchModeProposal
+		result bool
+	}{
+		{"match-mode-proposal", proposal, true},
+		{"periodic-auction", MatchModeProposal{"proposal", "match mode proposal", addr, "eth_btc",
+			MatchModePeriodicAuction}, true},
+
+		{"no-title", MatchModeProposal{"", "match mode proposal", addr, "eth_btc",
+			MatchModeContinuousAuction}, false},
+		{"no-description", MatchModeProposal{"proposal", "", addr, "eth_btc",
+			MatchModeContinuousAuction}, false},
+		{"no-proposer", MatchModeProposal{"proposal", "match mode proposal", nil, "eth_btc",
+			MatchModeContinuousAuction}, false},
+		{"invalid-product", MatchModeProposal{"proposal", "match mode proposal", addr, "eth",
+			MatchModeContinuousAuction}, false},
+		{"invalid-match-mode", MatchModeProposal{"proposal", "match mode proposal", addr, "eth_btc",
+			"callauction"}, false},
+
+		{"long-title", MatchModeProposal{getLongString(15),
+			"right match mode proposal", addr, "eth_btc", MatchModeContinuousAuction}, false},
+		{"long-description", MatchModeProposal{"proposal",
+			getLongString(501), addr, "eth_btc", MatchModeContinuousAuction}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.result {
+				require.Nil(t, tt.mmp.ValidateBasic(), "test: %v", tt.name)
+			} else {
+				require.NotNil(t, tt.mmp.ValidateBasic(), "test: %v", tt.name)
+			}
+		})
+	}
+}
+
 func getLongString(n int) (s string) {
 	str := "0123456789"
 	for i := 0; i < n; i++ {
@@ -7,8 +7,3 @@ import (
 type (
 	stakingMsgBuildingHelpers = genutilcli.StakingMsgBuildingHelpers
 )
-
-var (
-	// nolint
-	ValidateGenesisCmd = genutilcli.ValidateGenesisCmd
-)
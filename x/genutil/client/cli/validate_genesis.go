@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	tmtypes "github.com/tendermint/tendermint/types"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	"github.com/cosmos/cosmos-sdk/server"
+)
+
+// ValidateGenesisCmd returns the cobra command to validate the genesis file. Besides the genesis of every module,
+// validateGenesis checks the state of the modules depending on each other, so a genesis passing the command doesn't
+// fail the node in InitChain
+func ValidateGenesisCmd(ctx *server.Context, cdc *codec.Codec,
+	validateGenesis func(genesisState map[string]json.RawMessage) error) *cobra.Command {
+
+	return &cobra.Command{
+		Use:   "validate-genesis [file]",
+		Args:  cobra.RangeArgs(0, 1),
+		Short: "validates the genesis file at the default location or at the location passed as an arg",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// load default if passed no args, otherwise load passed file
+			genesis := ctx.Config.GenesisFile()
+			if len(args) > 0 {
+				genesis = args[0]
+			}
+
+			fmt.Fprintf(os.Stderr, "validating genesis file at %s\n", genesis)
+
+			genDoc, err := tmtypes.GenesisDocFromFile(genesis)
+			if err != nil {
+				return fmt.Errorf("error loading genesis doc from %s: %s", genesis, err.Error())
+			}
+
+			var genesisState map[string]json.RawMessage
+			if err = cdc.UnmarshalJSON(genDoc.AppState, &genesisState); err != nil {
+				return fmt.Errorf("error unmarshalling genesis doc %s: %s", genesis, err.Error())
+			}
+
+			if err = validateGenesis(genesisState); err != nil {
+				return fmt.Errorf("error validating genesis file %s: %s", genesis, err.Error())
+			}
+
+			fmt.Printf("File at %s is a valid genesis file\n", genesis)
+			return nil
+		},
+	}
+}
@@ -19,26 +19,37 @@ const (
 // nolint
 // types aliases
 type (
-	Keeper          = keeper.Keeper
-	Order           = types.Order
-	DepthBook       = types.DepthBook
-	MatchResult     = types.MatchResult
-	Deal            = types.Deal
-	Params          = types.Params
-	MsgNewOrder     = types.MsgNewOrder
-	MsgCancelOrder  = types.MsgCancelOrder
-	MsgNewOrders    = types.MsgNewOrders
-	MsgCancelOrders = types.MsgCancelOrders
+	Keeper                    = keeper.Keeper
+	Order                     = types.Order
+	DepthBook                 = types.DepthBook
+	MatchResult               = types.MatchResult
+	Deal                      = types.Deal
+	Params                    = types.Params
+	MsgNewOrder               = types.MsgNewOrder
+	MsgCancelOrder            = types.MsgCancelOrder
+	MsgNewOrders              = types.MsgNewOrders
+	MsgCancelOrders           = types.MsgCancelOrders
+	MsgReplaceOrders          = types.MsgReplaceOrders
+	MsgSetSelfTradePrevention = types.MsgSetSelfTradePrevention
+	MsgSetCancelAfter         = types.MsgSetCancelAfter
+	MsgGrantTrading           = types.MsgGrantTrading
+	MsgRevokeTrading          = types.MsgRevokeTrading
 )
 
 // nolint
 // functions aliases
 var (
-	RegisterCodec     = types.RegisterCodec
-	DefaultParams     = types.DefaultParams
-	NewMsgNewOrder    = types.NewMsgNewOrder
-	NewMsgCancelOrder = types.NewMsgCancelOrder
-	NewKeeper         = keeper.NewKeeper
-	NewQuerier        = keeper.NewQuerier
-	FormatOrderIDsKey = types.FormatOrderIDsKey
+	RegisterCodec                = types.RegisterCodec
+	DefaultParams                = types.DefaultParams
+	ValidateParamsChange         = types.ValidateParamsChange
+	NewMsgNewOrder               = types.NewMsgNewOrder
+	NewMsgCancelOrder            = types.NewMsgCancelOrder
+	NewMsgReplaceOrders          = types.NewMsgReplaceOrders
+	NewMsgSetSelfTradePrevention = types.NewMsgSetSelfTradePrevention
+	NewMsgSetCancelAfter         = types.NewMsgSetCancelAfter
+	NewMsgGrantTrading           = types.NewMsgGrantTrading
+	NewMsgRevokeTrading          = types.NewMsgRevokeTrading
+	NewKeeper                    = keeper.NewKeeper
+	NewQuerier                   = keeper.NewQuerier
+	FormatOrderIDsKey            = types.FormatOrderIDsKey
 )
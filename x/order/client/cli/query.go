@@ -27,6 +27,11 @@ func GetQueryCmd(queryRoute string, cdc *codec.Codec) *cobra.Command {
 		GetCmdDepthBook(queryRoute, cdc),
 		GetCmdQueryStore(queryRoute, cdc),
 		GetCmdQueryParams(queryRoute, cdc),
+		GetCmdQueryHalts(queryRoute, cdc),
+		GetCmdQueryAuction(queryRoute, cdc),
+		GetCmdQueryCancelAfter(queryRoute, cdc),
+		GetCmdQueryGrants(queryRoute, cdc),
+		GetCmdQueryOpenOrders(queryRoute, cdc),
 	)...)
 
 	queryCmd.Flags().StringP(client.FlagNode, "n", "tcp://localhost:26657", "Node to connect to")
@@ -170,3 +175,144 @@ $ okchaincli query order params
 		},
 	}
 }
+
+// GetCmdQueryHalts queries the products halted by the circuit breaker
+func GetCmdQueryHalts(queryRoute string, cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "halts [product]",
+		Short: "Query the products halted by the circuit breaker",
+		Long: strings.TrimSpace(`Query all the halted products, or the halt of the specified product:
+
+$ okchaincli query order halts
+$ okchaincli query order halts xxb_okt
+`),
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			route := fmt.Sprintf("custom/%s/%s", queryRoute, types.QueryHalts)
+			if len(args) > 0 {
+				route = fmt.Sprintf("%s/%s", route, args[0])
+			}
+			res, _, err := cliCtx.QueryWithData(route, nil)
+			if err != nil {
+				return err
+			}
+
+			fmt.Println(string(res))
+			return nil
+		},
+	}
+}
+
+// GetCmdQueryAuction queries the indicative clearing price of the next periodic auction of a product
+func GetCmdQueryAuction(queryRoute string, cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "auction [product]",
+		Short: "Query the indicative price of the next periodic auction of a trading pair",
+		Long: strings.TrimSpace(`Query the indicative clearing price, the matchable volume, the imbalance and the rule deciding
+the price of the next periodic auction, which is calculated on current depth book:
+
+$ okchaincli query order auction xxb_okt
+`),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			res, _, err := cliCtx.QueryWithData(
+				fmt.Sprintf("custom/%s/%s/%s", queryRoute, types.QueryAuction, args[0]), nil)
+			if err != nil {
+				return err
+			}
+
+			fmt.Println(string(res))
+			return nil
+		},
+	}
+}
+
+// GetCmdQueryCancelAfter queries the cancel-after deadline of an account
+func GetCmdQueryCancelAfter(queryRoute string, cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "cancel-after [address]",
+		Short: "Query the cancel-after deadline of an account",
+		Long: strings.TrimSpace(`Query the block height at which the open orders of the account are cancelled unless refreshed:
+
+$ okchaincli query order cancel-after okchain1hw4r48aww06ldrfeuq2v438ujnl6alszzzqpph
+`),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			res, _, err := cliCtx.QueryWithData(
+				fmt.Sprintf("custom/%s/%s/%s", queryRoute, types.QueryCancelAfter, args[0]), nil)
+			if err != nil {
+				return err
+			}
+
+			fmt.Println(string(res))
+			return nil
+		},
+	}
+}
+
+// GetCmdQueryGrants queries the trading grants of a granter
+func GetCmdQueryGrants(queryRoute string, cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "grants [granter] [grantee]",
+		Short: "Query the trading grants of a granter",
+		Long: strings.TrimSpace(`Query all the trading grants of the granter, or the grant to the specified grantee:
+
+$ okchaincli query order grants okchain1hw4r48aww06ldrfeuq2v438ujnl6alszzzqpph
+$ okchaincli query order grants okchain1hw4r48aww06ldrfeuq2v438ujnl6alszzzqpph okchain1skjwj5whet0lpe65qaq4rpq03hjxlwd9nf39lk
+`),
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			route := fmt.Sprintf("custom/%s/%s/%s", queryRoute, types.QueryGrants, strings.Join(args, "/"))
+			res, _, err := cliCtx.QueryWithData(route, nil)
+			if err != nil {
+				return err
+			}
+
+			fmt.Println(string(res))
+			return nil
+		},
+	}
+}
+
+// GetCmdQueryOpenOrders queries the open orders of an address from the order store
+func GetCmdQueryOpenOrders(queryRoute string, cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "open [addr]",
+		Short: "Query the open orders of an address",
+		Long: strings.TrimSpace(`Query the open orders of an address, which are read from the chain state, so the backend is not needed:
+
+$ okchaincli query order open okchain1hw4r48aww06ldrfeuq2v438ujnl6alszzzqpph --product mytoken_okt --side BUY
+`),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+			params := keeper.NewQueryOpenOrdersParams(args[0], viper.GetString("product"), viper.GetString("side"),
+				viper.GetInt("page"), viper.GetInt("per-page"))
+			bz, err := cdc.MarshalJSON(params)
+			if err != nil {
+				return err
+			}
+
+			res, _, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/%s", queryRoute, types.QueryOpenOrders), bz)
+			if err != nil {
+				return err
+			}
+
+			fmt.Println(string(res))
+			return nil
+		},
+	}
+	cmd.Flags().StringP("product", "p", "", "filter orders by product")
+	cmd.Flags().String("side", "", "filter orders by side, support SELL|BUY, default for empty string means all")
+	cmd.Flags().Int("page", keeper.DefaultPage, "page num")
+	cmd.Flags().Int("per-page", keeper.DefaultPerPage, "items per page")
+	return cmd
+}
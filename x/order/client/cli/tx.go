@@ -2,6 +2,7 @@ package cli
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/cosmos/cosmos-sdk/client"
@@ -25,6 +26,11 @@ func GetTxCmd(cdc *codec.Codec) *cobra.Command {
 	txCmd.AddCommand(client.PostCommands(
 		getCmdNewOrder(cdc),
 		getCmdCancelOrder(cdc),
+		getCmdReplaceOrder(cdc),
+		getCmdSetSelfTradePrevention(cdc),
+		getCmdSetCancelAfter(cdc),
+		getCmdGrantTrading(cdc),
+		getCmdRevokeTrading(cdc),
 	)...)
 
 	return txCmd
@@ -36,6 +42,12 @@ func getCmdNewOrder(cdc *codec.Codec) *cobra.Command {
 	var side string
 	var price string
 	var quantity string
+	var orderType string
+	var timeInForce string
+	var expireHeight string
+	var selfTradePrevention string
+	var displayQuantity string
+	var granter string
 	cmd := &cobra.Command{
 		Use:   "new",
 		Short: "place a new order",
@@ -52,7 +64,8 @@ func getCmdNewOrder(cdc *codec.Codec) *cobra.Command {
 				return errors.New("invalid param counts")
 			}
 
-			err := handleNewOrder(cdc, product, side, price, quantity)
+			err := handleNewOrder(cdc, product, side, price, quantity, orderType, timeInForce, expireHeight,
+				selfTradePrevention, displayQuantity, granter)
 			return err
 
 		},
@@ -62,15 +75,43 @@ func getCmdNewOrder(cdc *codec.Codec) *cobra.Command {
 	cmd.Flags().StringVarP(&side, "side", "s", "", "BUY or SELL (default \"SELL\")")
 	cmd.Flags().StringVarP(&price, "price", "p", "", "The price of the order")
 	cmd.Flags().StringVarP(&quantity, "quantity", "q", "", "The quantity of the order")
+	cmd.Flags().StringVarP(&orderType, "type", "t", "", "LIMIT or MARKET (default \"LIMIT\"), the price of MARKET order should be 0")
+	cmd.Flags().StringVarP(&timeInForce, "time-in-force", "", "", "GTC, IOC, FOK, POST_ONLY or GTB (default \"GTC\")")
+	cmd.Flags().StringVarP(&expireHeight, "expire-height", "", "", "The last block height of GTB order, 0 for the other orders")
+	cmd.Flags().StringVarP(&selfTradePrevention, "stp", "", "", "Self-trade prevention: CANCEL_NEWEST, CANCEL_OLDEST or DECREMENT_BOTH (default the mode of the account)")
+	cmd.Flags().StringVarP(&displayQuantity, "display-quantity", "", "", "The visible slice of iceberg order, empty for the orders fully visible")
+	cmd.Flags().StringVarP(&granter, "granter", "", "", "Place the orders on behalf of the granter by its trading grant")
 	return cmd
 }
 
-func handleNewOrder(cdc *codec.Codec, product string, side string, price string, quantity string) error {
+func handleNewOrder(cdc *codec.Codec, product string, side string, price string, quantity string,
+	orderType string, timeInForce string, expireHeight string, selfTradePrevention string, displayQuantity string,
+	granter string) error {
 	var items []types.OrderItem
 	productArr := strings.Split(product, ",")
 	sideArr := strings.Split(side, ",")
 	priceArr := strings.Split(price, ",")
 	quantityArr := strings.Split(quantity, ",")
+	typeArr := make([]string, len(productArr))
+	if len(orderType) > 0 {
+		typeArr = strings.Split(orderType, ",")
+	}
+	timeInForceArr := make([]string, len(productArr))
+	if len(timeInForce) > 0 {
+		timeInForceArr = strings.Split(timeInForce, ",")
+	}
+	expireHeightArr := make([]string, len(productArr))
+	if len(expireHeight) > 0 {
+		expireHeightArr = strings.Split(expireHeight, ",")
+	}
+	stpArr := make([]string, len(productArr))
+	if len(selfTradePrevention) > 0 {
+		stpArr = strings.Split(selfTradePrevention, ",")
+	}
+	displayQuantityArr := make([]string, len(productArr))
+	if len(displayQuantity) > 0 {
+		displayQuantityArr = strings.Split(displayQuantity, ",")
+	}
 	if len(productArr) != len(sideArr) {
 		return errors.New("invalid param side counts")
 	}
@@ -83,6 +124,26 @@ func handleNewOrder(cdc *codec.Codec, product string, side string, price string,
 		return errors.New("invalid param quantity counts")
 	}
 
+	if len(productArr) != len(typeArr) {
+		return errors.New("invalid param type counts")
+	}
+
+	if len(productArr) != len(timeInForceArr) {
+		return errors.New("invalid param time-in-force counts")
+	}
+
+	if len(productArr) != len(expireHeightArr) {
+		return errors.New("invalid param expire-height counts")
+	}
+
+	if len(productArr) != len(stpArr) {
+		return errors.New("invalid param stp counts")
+	}
+
+	if len(productArr) != len(displayQuantityArr) {
+		return errors.New("invalid param display-quantity counts")
+	}
+
 	for i := 0; i < len(productArr); i++ {
 		product := productArr[i]
 		side := sideArr[i]
@@ -94,11 +155,32 @@ func handleNewOrder(cdc *codec.Codec, product string, side string, price string,
 		if err != nil {
 			return errors.New(err.Error())
 		}
+		var height int64
+		if len(expireHeightArr[i]) > 0 {
+			parsedHeight, parseErr := strconv.ParseInt(expireHeightArr[i], 10, 64)
+			if parseErr != nil {
+				return errors.New(parseErr.Error())
+			}
+			height = parsedHeight
+		}
+		var display sdk.Dec
+		if len(displayQuantityArr[i]) > 0 {
+			display, err = sdk.NewDecFromStr(displayQuantityArr[i])
+			if err != nil {
+				return errors.New(err.Error())
+			}
+		}
 		items = append(items, types.OrderItem{
-			Product:  product,
-			Side:     side,
-			Price:    price,
-			Quantity: quantity,
+			Product:      product,
+			Side:         side,
+			Price:        price,
+			Quantity:     quantity,
+			Type:         typeArr[i],
+			TimeInForce:  timeInForceArr[i],
+			ExpireHeight: height,
+
+			SelfTradePrevention: stpArr[i],
+			DisplayQuantity:     display,
 		})
 	}
 
@@ -106,12 +188,20 @@ func handleNewOrder(cdc *codec.Codec, product string, side string, price string,
 	cliCtx := context.NewCLIContext().WithCodec(cdc)
 
 	msg := types.NewMsgNewOrders(cliCtx.GetFromAddress(), items)
+	if len(granter) > 0 {
+		granterAddr, err := sdk.AccAddressFromBech32(granter)
+		if err != nil {
+			return err
+		}
+		msg.Sender, msg.Grantee = granterAddr, cliCtx.GetFromAddress()
+	}
 	err := utils.GenerateOrBroadcastMsgs(cliCtx, txBldr, []sdk.Msg{msg})
 	return err
 }
 
 func getCmdCancelOrder(cdc *codec.Codec) *cobra.Command {
-	return &cobra.Command{
+	var granter string
+	cmd := &cobra.Command{
 		Use:   "cancel [order-id]",
 		Short: "cancel order",
 		Args:  cobra.ExactArgs(1),
@@ -122,6 +212,13 @@ func getCmdCancelOrder(cdc *codec.Codec) *cobra.Command {
 			cliCtx := context.NewCLIContext().WithCodec(cdc)
 
 			msg := types.NewMsgCancelOrders(cliCtx.GetFromAddress(), orderIDs)
+			if len(granter) > 0 {
+				granterAddr, err := sdk.AccAddressFromBech32(granter)
+				if err != nil {
+					return err
+				}
+				msg.Sender, msg.Grantee = granterAddr, cliCtx.GetFromAddress()
+			}
 			err := utils.GenerateOrBroadcastMsgs(cliCtx, txBldr, []sdk.Msg{msg})
 			if err != nil {
 				fmt.Println(err)
@@ -129,4 +226,139 @@ func getCmdCancelOrder(cdc *codec.Codec) *cobra.Command {
 			return err
 		},
 	}
+	cmd.Flags().StringVarP(&granter, "granter", "", "", "Cancel the orders on behalf of the granter by its trading grant")
+	return cmd
+}
+
+func getCmdReplaceOrder(cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "replace [order-id] [price] [quantity]",
+		Short: "replace the price and quantity of open order",
+		Long: `replace the price and total quantity of open order without cancelling it,
+use comma "," to replace multi orders, e.g. replace ID0000000010-1,ID0000000010-2 10.1,10.2 1,2`,
+		Args: cobra.ExactArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			orderIDs := strings.Split(args[0], ",")
+			prices := strings.Split(args[1], ",")
+			quantities := strings.Split(args[2], ",")
+			if len(orderIDs) != len(prices) || len(orderIDs) != len(quantities) {
+				return errors.New("invalid param counts")
+			}
+
+			items := make([]types.ReplaceOrderItem, 0, len(orderIDs))
+			for i, orderID := range orderIDs {
+				price, err := sdk.NewDecFromStr(prices[i])
+				if err != nil {
+					return err
+				}
+				quantity, err := sdk.NewDecFromStr(quantities[i])
+				if err != nil {
+					return err
+				}
+				items = append(items, types.ReplaceOrderItem{OrderID: orderID, Price: price, Quantity: quantity})
+			}
+
+			txBldr := authtxb.NewTxBuilderFromCLI().WithTxEncoder(utils.GetTxEncoder(cdc))
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			msg := types.NewMsgReplaceOrders(cliCtx.GetFromAddress(), items)
+			return utils.GenerateOrBroadcastMsgs(cliCtx, txBldr, []sdk.Msg{msg})
+		},
+	}
+}
+
+func getCmdSetSelfTradePrevention(cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "set-stp [mode]",
+		Short: "set the self-trade prevention mode of the account",
+		Long: `set the self-trade prevention mode applied to the orders of the account without their own mode,
+the mode is CANCEL_NEWEST, CANCEL_OLDEST or DECREMENT_BOTH, and "" allows self trades`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			txBldr := authtxb.NewTxBuilderFromCLI().WithTxEncoder(utils.GetTxEncoder(cdc))
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			msg := types.NewMsgSetSelfTradePrevention(cliCtx.GetFromAddress(), strings.ToUpper(args[0]))
+			return utils.GenerateOrBroadcastMsgs(cliCtx, txBldr, []sdk.Msg{msg})
+		},
+	}
+}
+
+func getCmdSetCancelAfter(cdc *codec.Codec) *cobra.Command {
+	var product string
+	cmd := &cobra.Command{
+		Use:   "set-cancel-after [deadline]",
+		Short: "set the block height at which the open orders of the account are cancelled",
+		Long: `set the dead man's switch of the account, the open orders of the account, or only the orders of the
+product if specified, are cancelled at the deadline block height unless refreshed before, and 0 turns it off`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			txBldr := authtxb.NewTxBuilderFromCLI().WithTxEncoder(utils.GetTxEncoder(cdc))
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			deadline, err := strconv.ParseInt(args[0], 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid deadline %s: %v", args[0], err)
+			}
+			msg := types.NewMsgSetCancelAfter(cliCtx.GetFromAddress(), product, deadline)
+			return utils.GenerateOrBroadcastMsgs(cliCtx, txBldr, []sdk.Msg{msg})
+		},
+	}
+	cmd.Flags().StringVarP(&product, "product", "", "", "only cancel the orders of the trading pair")
+	return cmd
+}
+
+func getCmdGrantTrading(cdc *codec.Codec) *cobra.Command {
+	var products string
+	var notionalCap string
+	var expireHeight int64
+	cmd := &cobra.Command{
+		Use:   "grant-trading [grantee]",
+		Short: "permit the grantee to place and cancel orders on behalf of the account",
+		Long: `permit the grantee to place and cancel orders on behalf of the account, optionally limited to the products,
+the total notional of the orders placed and the last block height, the grantee is not able to send or withdraw coins`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			txBldr := authtxb.NewTxBuilderFromCLI().WithTxEncoder(utils.GetTxEncoder(cdc))
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			grantee, err := sdk.AccAddressFromBech32(args[0])
+			if err != nil {
+				return err
+			}
+			var productArr []string
+			if len(products) > 0 {
+				productArr = strings.Split(products, ",")
+			}
+			cap, err := sdk.NewDecFromStr(notionalCap)
+			if err != nil {
+				return err
+			}
+			msg := types.NewMsgGrantTrading(cliCtx.GetFromAddress(), grantee, productArr, cap, expireHeight)
+			return utils.GenerateOrBroadcastMsgs(cliCtx, txBldr, []sdk.Msg{msg})
+		},
+	}
+	cmd.Flags().StringVarP(&products, "products", "", "", "The trading pairs permitted, separated by comma (default all the trading pairs)")
+	cmd.Flags().StringVarP(&notionalCap, "notional-cap", "", "0", "The max total notional of the orders placed by the grantee, 0 for no cap")
+	cmd.Flags().Int64VarP(&expireHeight, "expire-height", "", 0, "The last block height of the grant, 0 for no expiry")
+	return cmd
+}
+
+func getCmdRevokeTrading(cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "revoke-trading [grantee]",
+		Short: "revoke the trading grant to the grantee",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			txBldr := authtxb.NewTxBuilderFromCLI().WithTxEncoder(utils.GetTxEncoder(cdc))
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			grantee, err := sdk.AccAddressFromBech32(args[0])
+			if err != nil {
+				return err
+			}
+			msg := types.NewMsgRevokeTrading(cliCtx.GetFromAddress(), grantee)
+			return utils.GenerateOrBroadcastMsgs(cliCtx, txBldr, []sdk.Msg{msg})
+		},
+	}
 }
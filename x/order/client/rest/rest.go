@@ -19,6 +19,7 @@ import (
 // RegisterRoutes - Central function to define routes that get registered by the main application
 func RegisterRoutes(cliCtx context.CLIContext, r *mux.Router) {
 	r.HandleFunc("/order/depthbook", orderBookHandler(cliCtx)).Methods("GET")
+	r.HandleFunc("/order/auction/{product}", indicativeAuctionHandler(cliCtx)).Methods("GET")
 	r.HandleFunc("/order/{orderID}", orderDetailHandler(cliCtx)).Methods("GET")
 }
 
@@ -91,3 +92,25 @@ func orderBookHandler(cliCtx context.CLIContext) http.HandlerFunc {
 		rest.PostProcessResponse(w, cliCtx, resBytes)
 	}
 }
+
+func indicativeAuctionHandler(cliCtx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		product := mux.Vars(r)["product"]
+
+		res, _, err := cliCtx.QueryWithData(fmt.Sprintf("custom/order/%s/%s", types.QueryAuction, product), nil)
+		if err != nil {
+			common.HandleErrorMsg(w, cliCtx, err.Error())
+			return
+		}
+
+		auctionRes := &keeper.IndicativeAuctionRes{}
+		codec.Cdc.MustUnmarshalJSON(res, auctionRes)
+		response := common.GetBaseResponse(auctionRes)
+		resBytes, err2 := json.Marshal(response)
+		if err2 != nil {
+			common.HandleErrorMsg(w, cliCtx, err2.Error())
+			return
+		}
+		rest.PostProcessResponse(w, cliCtx, resBytes)
+	}
+}
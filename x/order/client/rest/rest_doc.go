@@ -78,3 +78,27 @@ type BookResponse struct {
 //     Schemes: http, https
 //     Responses:
 //       200: BookResponse
+
+// AuctionParam : indicative auction param
+// swagger:parameters getIndicativeAuction
+type AuctionParam struct {
+	// token pair string
+	// Required: true
+	// in: path
+	Product string `json:"product"`
+}
+
+// AuctionResponse : indicative price of the next periodic auction
+// swagger:response AuctionResponse
+type AuctionResponse struct {
+	// in: body
+	Body keeper.IndicativeAuctionRes
+}
+
+// swagger:route GET /order/auction/{product} order getIndicativeAuction
+//
+// Get the indicative price of the next periodic auction
+//
+//     Schemes: http, https
+//     Responses:
+//       200: AuctionResponse
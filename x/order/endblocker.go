@@ -19,7 +19,9 @@ func EndBlocker(ctx sdk.Context, keeper keeper.Keeper) {
 	seq := perf.GetPerf().OnEndBlockEnter(ctx, types.ModuleName)
 	defer perf.GetPerf().OnEndBlockExit(ctx, types.ModuleName, seq)
 
-	match.GetEngine().Run(ctx, keeper)
+	match.Run(ctx, keeper)
+	keeper.CountLockedProducts(ctx)
+	keeper.DropExpiredTradingGrants(ctx, ctx.BlockHeight())
 
 	// flush cache at the end
 	keeper.Cache2Disk(ctx)
@@ -29,12 +31,15 @@ func EndBlocker(ctx sdk.Context, keeper keeper.Keeper) {
 	msg := fmt.Sprintf(
 		"fullFilled<%d>, pending<%d>, "+
 			"canceled<%d>, expired<%d>, "+
-			"partialFilled<%d>",
+			"partialFilled<%d>, "+
+			"lockedProducts<%d>, maxLockedBlocks<%d>",
 		ret.FullFillNum,
 		ret.OpenNum,
 		ret.CancelNum,
 		ret.ExpireNum,
-		ret.PartialFillNum)
+		ret.PartialFillNum,
+		ret.LockedProductNum,
+		ret.MaxLockedBlocks)
 
 	perf.GetPerf().EnqueueMsg(msg)
 }
@@ -547,3 +547,203 @@ func TestEndBlockerCleanupOrdersWhoseTokenPairHaveBeenDelisted(t *testing.T) {
 	collectedFees := feeCollector.GetCoins()
 	require.EqualValues(t, "", collectedFees.String())
 }
+
+func TestEndBlockerMarketOrder(t *testing.T) {
+	mapp, addrKeysSlice := getMockApp(t, 2)
+	k := mapp.orderKeeper
+	mapp.BeginBlock(abci.RequestBeginBlock{Header: abci.Header{Height: 2}})
+
+	var startHeight int64 = 10
+	ctx := mapp.BaseApp.NewContext(false, abci.Header{}).WithBlockHeight(startHeight)
+	mapp.supplyKeeper.SetSupply(ctx, supply.NewSupply(mapp.TotalCoinsSupply))
+
+	feeParams := types.DefaultParams()
+	mapp.orderKeeper.SetParams(ctx, &feeParams)
+
+	tokenPair := dex.GetBuiltInTokenPair()
+	err := mapp.dexKeeper.SaveTokenPair(ctx, tokenPair)
+	require.Nil(t, err)
+
+	// mock sell orders
+	orders := []*types.Order{
+		types.MockOrder("", types.TestTokenPair, types.SellOrder, "10.0", "1.0"),
+		types.MockOrder("", types.TestTokenPair, types.SellOrder, "11.0", "1.0"),
+	}
+	for _, order := range orders {
+		order.Sender = addrKeysSlice[1].Address
+		err := k.PlaceOrder(ctx, order)
+		require.NoError(t, err)
+	}
+
+	// place a market buy order, its price is capped by the slippage from the lowest ask
+	handler := NewOrderHandler(k)
+	item := types.NewMarketOrderItem(types.TestTokenPair, types.BuyOrder, "2.0")
+	result := handler(ctx, types.NewMsgNewOrders(addrKeysSlice[0].Address, []types.OrderItem{item}))
+	orderRes := parseOrderResult(result)
+	require.EqualValues(t, sdk.CodeOK, orderRes[0].Code)
+	marketOrder := k.GetOrder(ctx, orderRes[0].OrderID)
+	require.True(t, marketOrder.IsMarketOrder())
+	require.EqualValues(t, sdk.MustNewDecFromStr("10.5"), marketOrder.Price)
+	require.EqualValues(t, sdk.MustNewDecFromStr("21"), marketOrder.RemainLocked)
+	require.EqualValues(t, []string{marketOrder.OrderID}, k.GetImmediateOrderIDs(ctx))
+
+	EndBlocker(ctx, k)
+
+	// the market order is filled at the auction price which is within the slippage cap,
+	// and the remainder is cancelled
+	marketOrder = k.GetOrder(ctx, marketOrder.OrderID)
+	require.EqualValues(t, types.OrderStatusPartialFilledCancelled, marketOrder.Status)
+	require.EqualValues(t, sdk.MustNewDecFromStr("10.5"), marketOrder.FilledAvgPrice)
+	require.EqualValues(t, sdk.MustNewDecFromStr("1.0"), marketOrder.RemainQuantity)
+	require.EqualValues(t, types.OrderStatusFilled, k.GetOrder(ctx, orders[0].OrderID).Status)
+	require.EqualValues(t, types.OrderStatusOpen, k.GetOrder(ctx, orders[1].OrderID).Status)
+	require.Nil(t, k.GetImmediateOrderIDs(ctx))
+
+	// the locked coins of the remainder are refunded
+	acc0 := mapp.AccountKeeper.GetAccount(ctx, addrKeysSlice[0].Address)
+	expectCoins0 := sdk.DecCoins{
+		sdk.NewDecCoinFromDec(common.NativeToken, sdk.MustNewDecFromStr("89.5")),  // 100 - 10.5
+		sdk.NewDecCoinFromDec(common.TestToken, sdk.MustNewDecFromStr("100.999")), // 100 + 1 * (1 - 0.001)
+	}
+	require.EqualValues(t, expectCoins0.String(), acc0.GetCoins().String())
+	require.EqualValues(t, 0, len(mapp.tokenKeeper.GetLockedCoins(ctx, addrKeysSlice[0].Address)))
+}
+
+func TestEndBlockerTimeInForce(t *testing.T) {
+	mapp, addrKeysSlice := getMockApp(t, 2)
+	k := mapp.orderKeeper
+	mapp.BeginBlock(abci.RequestBeginBlock{Header: abci.Header{Height: 2}})
+
+	var startHeight int64 = 10
+	ctx := mapp.BaseApp.NewContext(false, abci.Header{}).WithBlockHeight(startHeight)
+	mapp.supplyKeeper.SetSupply(ctx, supply.NewSupply(mapp.TotalCoinsSupply))
+
+	feeParams := types.DefaultParams()
+	mapp.orderKeeper.SetParams(ctx, &feeParams)
+
+	tokenPair := dex.GetBuiltInTokenPair()
+	err := mapp.dexKeeper.SaveTokenPair(ctx, tokenPair)
+	require.Nil(t, err)
+
+	// mock sell orders
+	orders := []*types.Order{
+		types.MockOrder("", types.TestTokenPair, types.SellOrder, "10.0", "1.0"),
+		types.MockOrder("", types.TestTokenPair, types.SellOrder, "11.0", "1.0"),
+	}
+	for _, order := range orders {
+		order.Sender = addrKeysSlice[1].Address
+		err := k.PlaceOrder(ctx, order)
+		require.NoError(t, err)
+	}
+
+	handler := NewOrderHandler(k)
+	items := []types.OrderItem{
+		types.NewTimeInForceOrderItem(types.TestTokenPair, types.BuyOrder, "10.0", "1.0", types.TimeInForcePostOnly, 0),
+		types.NewTimeInForceOrderItem(types.TestTokenPair, types.BuyOrder, "10.5", "2.0", types.TimeInForceFOK, 0),
+		types.NewTimeInForceOrderItem(types.TestTokenPair, types.BuyOrder, "10.0", "2.0", types.TimeInForceIOC, 0),
+		types.NewTimeInForceOrderItem(types.TestTokenPair, types.BuyOrder, "9.0", "1.0", types.TimeInForceGTB,
+			startHeight),
+	}
+	result := handler(ctx, types.NewMsgNewOrders(addrKeysSlice[0].Address, items))
+	orderRes := parseOrderResult(result)
+	for _, res := range orderRes {
+		require.EqualValues(t, sdk.CodeOK, res.Code)
+	}
+	// GTB order only locks the fee of the blocks it lives
+	require.EqualValues(t, 1, k.GetOrder(ctx, orderRes[3].OrderID).OrderExpireBlocks)
+
+	// the expire height of GTB order must not be passed
+	items[3].ExpireHeight = startHeight - 1
+	result = handler(ctx, types.NewMsgNewOrders(addrKeysSlice[0].Address, items[3:]))
+	require.EqualValues(t, sdk.CodeUnknownRequest, parseOrderResult(result)[0].Code)
+
+	EndBlocker(ctx, k)
+
+	// the FOK order would be partially filled at 10.5, so it is killed. Then the post-only order would be filled
+	// at 10.0, so it is rejected. The IOC order is filled by the ask at 10.0 and the remainder is cancelled.
+	// The GTB order expires after the match of its expire height
+	expectedStatus := []int64{types.OrderStatusPostOnlyRejected, types.OrderStatusFOKKilled,
+		types.OrderStatusPartialFilledIOCCancelled, types.OrderStatusGTBExpired}
+	for i, res := range orderRes {
+		order := k.GetOrder(ctx, res.OrderID)
+		require.EqualValues(t, expectedStatus[i], order.Status, i)
+		require.True(t, order.RemainLocked.IsZero())
+	}
+	require.EqualValues(t, sdk.MustNewDecFromStr("10"), k.GetOrder(ctx, orderRes[2].OrderID).FilledAvgPrice)
+	require.EqualValues(t, types.OrderStatusFilled, k.GetOrder(ctx, orders[0].OrderID).Status)
+	require.EqualValues(t, types.OrderStatusOpen, k.GetOrder(ctx, orders[1].OrderID).Status)
+	require.Nil(t, k.GetImmediateOrderIDs(ctx))
+	require.Nil(t, k.GetGTBOrderIDs(ctx, startHeight))
+
+	// no fee is charged for quitting the orders in the block they are placed
+	acc0 := mapp.AccountKeeper.GetAccount(ctx, addrKeysSlice[0].Address)
+	expectCoins0 := sdk.DecCoins{
+		sdk.NewDecCoinFromDec(common.NativeToken, sdk.MustNewDecFromStr("90")),    // 100 - 10
+		sdk.NewDecCoinFromDec(common.TestToken, sdk.MustNewDecFromStr("100.999")), // 100 + 1 * (1 - 0.001)
+	}
+	require.EqualValues(t, expectCoins0.String(), acc0.GetCoins().String())
+	require.EqualValues(t, 0, len(mapp.tokenKeeper.GetLockedCoins(ctx, addrKeysSlice[0].Address)))
+}
+
+func TestEndBlockerTriggerOrder(t *testing.T) {
+	mapp, addrKeysSlice := getMockApp(t, 2)
+	k := mapp.orderKeeper
+	mapp.BeginBlock(abci.RequestBeginBlock{Header: abci.Header{Height: 2}})
+
+	var startHeight int64 = 10
+	ctx := mapp.BaseApp.NewContext(false, abci.Header{}).WithBlockHeight(startHeight)
+	mapp.supplyKeeper.SetSupply(ctx, supply.NewSupply(mapp.TotalCoinsSupply))
+
+	feeParams := types.DefaultParams()
+	mapp.orderKeeper.SetParams(ctx, &feeParams)
+
+	tokenPair := dex.GetBuiltInTokenPair()
+	err := mapp.dexKeeper.SaveTokenPair(ctx, tokenPair)
+	require.Nil(t, err)
+	k.SetLastPrice(ctx, types.TestTokenPair, sdk.MustNewDecFromStr("10.0"))
+
+	// mock buy order
+	buyOrder := types.MockOrder("", types.TestTokenPair, types.BuyOrder, "9.0", "1.0")
+	buyOrder.Sender = addrKeysSlice[1].Address
+	err = k.PlaceOrder(ctx, buyOrder)
+	require.NoError(t, err)
+
+	handler := NewOrderHandler(k)
+	items := []types.OrderItem{
+		types.NewTriggerOrderItem(types.TestTokenPair, types.SellOrder, "9.0", "1.0", types.TriggerTypeStopLoss, "9.5"),
+		types.NewTriggerOrderItem(types.TestTokenPair, types.BuyOrder, "8.0", "1.0", types.TriggerTypeTakeProfit, "8.5"),
+	}
+	result := handler(ctx, types.NewMsgNewOrders(addrKeysSlice[0].Address, items))
+	orderRes := parseOrderResult(result)
+	for _, res := range orderRes {
+		require.EqualValues(t, sdk.CodeOK, res.Code)
+		require.EqualValues(t, types.OrderStatusUntriggered, k.GetOrder(ctx, res.OrderID).Status)
+	}
+	require.EqualValues(t, 2, len(k.GetTriggerOrderIDs(ctx)))
+
+	// untriggered orders are kept out of depth book
+	EndBlocker(ctx, k)
+	depthBook := k.GetDepthBookCopy(types.TestTokenPair)
+	require.EqualValues(t, 1, len(depthBook.Items))
+	require.True(t, depthBook.Items[0].SellQuantity.IsZero())
+	for _, res := range orderRes {
+		require.EqualValues(t, types.OrderStatusUntriggered, k.GetOrder(ctx, res.OrderID).Status)
+	}
+
+	// cancel the untriggered take-profit order
+	mapp.BeginBlock(abci.RequestBeginBlock{Header: abci.Header{Height: 2}})
+	ctx = mapp.BaseApp.NewContext(false, abci.Header{}).WithBlockHeight(startHeight + 1)
+	result = handler(ctx, types.NewMsgCancelOrder(addrKeysSlice[0].Address, orderRes[1].OrderID))
+	require.EqualValues(t, sdk.CodeOK, result.Code)
+	require.EqualValues(t, types.OrderStatusCancelled, k.GetOrder(ctx, orderRes[1].OrderID).Status)
+
+	// the stop-loss order is triggered once the last price falls to its trigger price, and then matched
+	k.SetLastPrice(ctx, types.TestTokenPair, sdk.MustNewDecFromStr("9.4"))
+	EndBlocker(ctx, k)
+	order := k.GetOrder(ctx, orderRes[0].OrderID)
+	require.EqualValues(t, types.OrderStatusFilled, order.Status)
+	require.EqualValues(t, startHeight+1, order.Trigger.TriggeredHeight)
+	require.EqualValues(t, types.OrderStatusFilled, k.GetOrder(ctx, buyOrder.OrderID).Status)
+	require.EqualValues(t, 0, len(k.GetTriggerOrderIDs(ctx)))
+	require.EqualValues(t, 0, len(mapp.tokenKeeper.GetLockedCoins(ctx, addrKeysSlice[0].Address)))
+}
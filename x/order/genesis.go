@@ -2,28 +2,156 @@ package order
 
 import (
 	"fmt"
+	"sort"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/okex/okchain/x/dex"
 	"github.com/okex/okchain/x/order/keeper"
 	"github.com/okex/okchain/x/order/types"
+	tokentypes "github.com/okex/okchain/x/token/types"
 )
 
-// GenesisState - all slashing state that must be provided at genesis
+// GenesisState - all order state that must be provided at genesis. The depth books and the indexes of orders
+// are rebuilt from the open orders, and the product locks of partially executed auctions are kept by dex module
 type GenesisState struct {
-	Params     types.Params   `json:"params"`
-	OpenOrders []*types.Order `json:"open_orders"`
+	Params                 types.Params               `json:"params"`
+	OpenOrders             []*types.Order             `json:"open_orders"`
+	CancelAfters           []*types.CancelAfter       `json:"cancel_afters"`
+	TradingGrants          []*types.TradingGrant      `json:"trading_grants"`
+	LastPrices             []*types.LastPrice         `json:"last_prices"`
+	BlockOrderNums         []*types.BlockOrderNum     `json:"block_order_nums"`
+	ExpireBlockHeights     []*types.ExpireBlockHeight `json:"expire_block_heights"`
+	LastExpiredBlockHeight int64                      `json:"last_expired_block_height"`
+	ProductHalts           []*types.ProductHalt       `json:"product_halts"`
+	SelfTradeModes         []*types.SelfTradeMode     `json:"self_trade_modes"`
+	TradeVolumes           []*types.TradeVolume       `json:"trade_volumes"`
 }
 
 // DefaultGenesisState - default GenesisState used by Cosmos Hub
 func DefaultGenesisState() GenesisState {
 	return GenesisState{
-		Params:     types.DefaultParams(),
-		OpenOrders: nil,
+		Params:        types.DefaultParams(),
+		OpenOrders:    nil,
+		CancelAfters:  nil,
+		TradingGrants: nil,
 	}
 }
 
-// ValidateGenesis validates the slashing genesis parameters
+// ValidateGenesis validates the order genesis parameters, and checks that the state is internally consistent.
+// The state depending on dex and token module is checked by ValidateGenesisWithDependencies
 func ValidateGenesis(data GenesisState) error {
+	if err := data.Params.Validate(); err != nil {
+		return err
+	}
+	if err := types.ValidateCancelAfters(data.CancelAfters); err != nil {
+		return err
+	}
+	if err := types.ValidateTradingGrants(data.TradingGrants); err != nil {
+		return err
+	}
+	if err := types.ValidateOpenOrders(data.OpenOrders); err != nil {
+		return err
+	}
+	if err := types.ValidateLastPrices(data.LastPrices); err != nil {
+		return err
+	}
+	if err := types.ValidateBlockOrderNums(data.BlockOrderNums); err != nil {
+		return err
+	}
+	if err := types.ValidateExpireBlockHeights(data.ExpireBlockHeights); err != nil {
+		return err
+	}
+	if data.LastExpiredBlockHeight < 0 {
+		return fmt.Errorf("invalid last expired block height %d", data.LastExpiredBlockHeight)
+	}
+	if err := types.ValidateProductHalts(data.ProductHalts); err != nil {
+		return err
+	}
+	if err := types.ValidateSelfTradeModes(data.SelfTradeModes); err != nil {
+		return err
+	}
+	if err := types.ValidateTradeVolumes(data.TradeVolumes); err != nil {
+		return err
+	}
+	return validateOrderExpiry(data)
+}
+
+// validateOrderExpiry checks that every open order is covered by the order number of its block and is expired at
+// some block height, if the order numbers and expire block heights are provided
+func validateOrderExpiry(data GenesisState) error {
+	orderNums := make(map[int64]int64, len(data.BlockOrderNums))
+	for _, num := range data.BlockOrderNums {
+		orderNums[num.BlockHeight] = num.OrderNum
+	}
+	expiredHeights := make(map[int64]struct{})
+	for _, expireHeight := range data.ExpireBlockHeights {
+		for _, height := range expireHeight.ExpireBlockHeights {
+			expiredHeights[height] = struct{}{}
+		}
+	}
+
+	for _, order := range data.OpenOrders {
+		height, num, _ := types.ParseOrderID(order.OrderID)
+		if len(data.BlockOrderNums) > 0 && num > orderNums[height] {
+			return fmt.Errorf("the order %s is out of the order number %d of block height %d", order.OrderID,
+				orderNums[height], height)
+		}
+		if _, ok := expiredHeights[height]; len(data.ExpireBlockHeights) > 0 && !ok {
+			return fmt.Errorf("the order %s never expires, block height %d is not in the expire block heights",
+				order.OrderID, height)
+		}
+	}
+	return nil
+}
+
+// ValidateGenesisWithDependencies checks the genesis against the genesis of the modules it depends on. The products of
+// the open orders and halts are listed in the token pairs of dex, and the coins locked in token by every account are
+// the sum of the remain locked coins of its open orders
+func ValidateGenesisWithDependencies(data GenesisState, tokenPairs []*dex.TokenPair,
+	lockedAssets []tokentypes.AccCoins) error {
+
+	products := make(map[string]struct{}, len(tokenPairs))
+	for _, pair := range tokenPairs {
+		products[fmt.Sprintf("%s_%s", pair.BaseAssetSymbol, pair.QuoteAssetSymbol)] = struct{}{}
+	}
+	for _, order := range data.OpenOrders {
+		if _, ok := products[order.Product]; !ok {
+			return fmt.Errorf("the product %s of order %s does not exist", order.Product, order.OrderID)
+		}
+	}
+	for _, halt := range data.ProductHalts {
+		if _, ok := products[halt.Product]; !ok {
+			return fmt.Errorf("the halted product %s does not exist", halt.Product)
+		}
+	}
+
+	orderLocks := make(map[string]sdk.DecCoins)
+	for _, order := range data.OpenOrders {
+		addr := order.Sender.String()
+		orderLocks[addr] = orderLocks[addr].Add(order.NeedUnlockCoins())
+	}
+	tokenLocks := make(map[string]sdk.DecCoins, len(lockedAssets))
+	for _, lock := range lockedAssets {
+		addr := lock.Acc.String()
+		tokenLocks[addr] = tokenLocks[addr].Add(lock.Coins)
+	}
+	addrs := make([]string, 0, len(tokenLocks)+len(orderLocks))
+	for addr := range tokenLocks {
+		addrs = append(addrs, addr)
+	}
+	for addr := range orderLocks {
+		if _, ok := tokenLocks[addr]; !ok {
+			addrs = append(addrs, addr)
+		}
+	}
+	sort.Strings(addrs)
+	for _, addr := range addrs {
+		// compare by difference, since IsEqual panics on mismatched denominations
+		if diff, isNegative := tokenLocks[addr].SafeSub(orderLocks[addr]); isNegative || !diff.IsZero() {
+			return fmt.Errorf("the locked coins %s of %s do not match the remain locked coins %s of its orders",
+				tokenLocks[addr], addr, orderLocks[addr])
+		}
+	}
 	return nil
 }
 
@@ -32,18 +160,46 @@ func ValidateGenesis(data GenesisState) error {
 func InitGenesis(ctx sdk.Context, keeper keeper.Keeper, data GenesisState) {
 	keeper.SetParams(ctx, &data.Params)
 
+	for _, price := range data.LastPrices {
+		keeper.SetLastPrice(ctx, price.Product, price.Price)
+	}
+	// the order numbers and expire block heights are rebuilt from the open orders if they are not provided
+	for _, num := range data.BlockOrderNums {
+		keeper.SetBlockOrderNum(ctx, num.BlockHeight, num.OrderNum)
+	}
+	for _, expireHeight := range data.ExpireBlockHeights {
+		keeper.SetExpireBlockHeight(ctx, expireHeight.BlockHeight, expireHeight.ExpireBlockHeights)
+	}
+	if data.LastExpiredBlockHeight > 0 {
+		keeper.SetLastExpiredBlockHeight(ctx, data.LastExpiredBlockHeight)
+	}
+
 	// reset open order& depth book
 	for _, order := range data.OpenOrders {
 		height := types.GetBlockHeightFromOrderID(order.OrderID)
 
-		futureHeight := height + data.Params.OrderExpireBlocks
-		futureExpireHeightList := keeper.GetExpireBlockHeight(ctx, futureHeight)
-		futureExpireHeightList = append(futureExpireHeightList, height)
-		keeper.SetExpireBlockHeight(ctx, futureHeight, futureExpireHeightList)
-
-		orderNum := keeper.GetBlockOrderNum(ctx, height)
-		keeper.SetBlockOrderNum(ctx, height, orderNum+1)
+		if len(data.ExpireBlockHeights) == 0 {
+			futureHeight := height + data.Params.OrderExpireBlocks
+			futureExpireHeightList := keeper.GetExpireBlockHeight(ctx, futureHeight)
+			futureExpireHeightList = append(futureExpireHeightList, height)
+			keeper.SetExpireBlockHeight(ctx, futureHeight, futureExpireHeightList)
+		}
+		if len(data.BlockOrderNums) == 0 {
+			orderNum := keeper.GetBlockOrderNum(ctx, height)
+			keeper.SetBlockOrderNum(ctx, height, orderNum+1)
+		}
 		keeper.SetOrder(ctx, order.OrderID, order)
+		keeper.SetAccountOrderID(ctx, order.Sender, order.OrderID)
+		if order.IsUntriggered() {
+			keeper.AddUntriggeredOrder(ctx, order)
+			continue
+		}
+		if order.IsImmediateOrder() {
+			keeper.SetImmediateOrderID(ctx, order.OrderID)
+		}
+		if order.IsGTBOrder() {
+			keeper.SetGTBOrderID(ctx, order.ExpireHeight, order.OrderID)
+		}
 
 		// update depth book and orderIDsMap in cache
 		keeper.InsertOrderIntoDepthBook(order)
@@ -51,6 +207,26 @@ func InitGenesis(ctx sdk.Context, keeper keeper.Keeper, data GenesisState) {
 	if len(data.OpenOrders) > 0 {
 		keeper.Cache2Disk(ctx)
 	}
+
+	for _, cancelAfter := range data.CancelAfters {
+		keeper.SetCancelAfter(ctx, cancelAfter)
+	}
+
+	for _, grant := range data.TradingGrants {
+		keeper.SetTradingGrant(ctx, grant)
+	}
+
+	for _, halt := range data.ProductHalts {
+		keeper.SetProductHalt(ctx, halt)
+	}
+
+	for _, mode := range data.SelfTradeModes {
+		keeper.SetAccountSelfTradePrevention(ctx, mode.Address, mode.Mode)
+	}
+
+	for _, volume := range data.TradeVolumes {
+		keeper.SetTradeVolume(ctx, volume)
+	}
 }
 
 // ExportGenesis writes the current store values
@@ -64,9 +240,6 @@ func ExportGenesis(ctx sdk.Context, keeper keeper.Keeper) (data GenesisState) {
 	var num int64 = 1
 	for _, pair := range tokenPairs {
 		product := fmt.Sprintf("%s_%s", pair.BaseAssetSymbol, pair.QuoteAssetSymbol)
-		// update token pairs price
-		pair.InitPrice = keeper.GetLastPrice(ctx, product)
-		keeper.GetDexKeeper().UpdateTokenPair(ctx, product, pair)
 
 		// get open orders
 		depthBook := keeper.GetDepthBookFromDB(ctx, product)
@@ -103,8 +276,25 @@ func ExportGenesis(ctx sdk.Context, keeper keeper.Keeper) (data GenesisState) {
 		}
 	}
 
+	// untriggered orders are kept out of depth book
+	for _, orderID := range keeper.GetTriggerOrderIDs(ctx) {
+		order := keeper.GetOrder(ctx, orderID)
+		if order != nil && order.IsUntriggered() {
+			openOrders = append(openOrders, order)
+		}
+	}
+
 	return GenesisState{
-		Params:     *params,
-		OpenOrders: openOrders,
+		Params:                 *params,
+		OpenOrders:             openOrders,
+		CancelAfters:           keeper.GetCancelAfters(ctx),
+		TradingGrants:          keeper.GetTradingGrants(ctx, nil),
+		LastPrices:             keeper.GetLastPrices(ctx),
+		BlockOrderNums:         keeper.GetBlockOrderNums(ctx),
+		ExpireBlockHeights:     keeper.GetExpireBlockHeights(ctx),
+		LastExpiredBlockHeight: keeper.GetLastExpiredBlockHeight(ctx),
+		ProductHalts:           keeper.GetProductHalts(ctx),
+		SelfTradeModes:         keeper.GetSelfTradeModes(ctx),
+		TradeVolumes:           keeper.GetTradeVolumes(ctx),
 	}
 }
@@ -17,6 +17,194 @@ func TestValidateGenesis(t *testing.T) {
 	genesisState := DefaultGenesisState()
 	err := ValidateGenesis(genesisState)
 	require.NoError(t, err)
+
+	genesisState.Params.DealBudgetPolicy = "RANDOM"
+	require.Error(t, ValidateGenesis(genesisState))
+	genesisState.Params.DealBudgetPolicy = types.DealBudgetPolicySequential
+	genesisState.Params.MinDealsPerProduct = -1
+	require.Error(t, ValidateGenesis(genesisState))
+	genesisState.Params.MinDealsPerProduct = types.DefaultMinDealsPerProduct
+	genesisState.Params.AllocationRule = "LIFO"
+	require.Error(t, ValidateGenesis(genesisState))
+	genesisState.Params.AllocationRule = types.AllocationRuleSizeTime
+	genesisState.Params.MarketPressureRate = sdk.OneDec()
+	require.Error(t, ValidateGenesis(genesisState))
+	genesisState.Params.MarketPressureRate = sdk.MustNewDecFromStr(types.DefaultMarketPressureRate)
+
+	addr := sdk.AccAddress([]byte("cancel-after-address"))
+	genesisState.CancelAfters = []*types.CancelAfter{types.NewCancelAfter(addr, "", 0)}
+	require.Error(t, ValidateGenesis(genesisState))
+	genesisState.CancelAfters = []*types.CancelAfter{types.NewCancelAfter(nil, "", 10)}
+	require.Error(t, ValidateGenesis(genesisState))
+	genesisState.CancelAfters = []*types.CancelAfter{types.NewCancelAfter(addr, "", 10),
+		types.NewCancelAfter(addr, types.TestTokenPair, 20)}
+	require.Error(t, ValidateGenesis(genesisState))
+	genesisState.CancelAfters = genesisState.CancelAfters[1:]
+	require.NoError(t, ValidateGenesis(genesisState))
+
+	grantee := sdk.AccAddress([]byte("trading-grant-grantee"))
+	genesisState.TradingGrants = []*types.TradingGrant{
+		types.NewTradingGrant(addr, addr, nil, sdk.ZeroDec(), 0)}
+	require.Error(t, ValidateGenesis(genesisState))
+	genesisState.TradingGrants = []*types.TradingGrant{
+		types.NewTradingGrant(addr, grantee, nil, sdk.NewDec(-1), 0)}
+	require.Error(t, ValidateGenesis(genesisState))
+	genesisState.TradingGrants = []*types.TradingGrant{
+		types.NewTradingGrant(addr, grantee, nil, sdk.ZeroDec(), 0),
+		types.NewTradingGrant(addr, grantee, []string{types.TestTokenPair}, sdk.NewDec(100), 20)}
+	require.Error(t, ValidateGenesis(genesisState))
+	genesisState.TradingGrants = genesisState.TradingGrants[1:]
+	require.NoError(t, ValidateGenesis(genesisState))
+}
+
+func TestValidateGenesisOrderState(t *testing.T) {
+	addr := sdk.AccAddress([]byte("open-orders-address0"))
+	newOrder := func(orderID, side, price, quantity string) *types.Order {
+		order := types.NewOrder("txHash", addr, types.TestTokenPair, side, sdk.MustNewDecFromStr(price),
+			sdk.MustNewDecFromStr(quantity), time.Now().Unix(), 5, sdk.NewDecCoinFromDec(common.NativeToken,
+				sdk.NewDec(1)))
+		order.OrderID = orderID
+		order.FilledAvgPrice = sdk.ZeroDec()
+		return order
+	}
+	genesisState := DefaultGenesisState()
+	genesisState.OpenOrders = []*types.Order{
+		newOrder(types.FormatOrderID(10, 1), types.BuyOrder, "10", "2"),
+		newOrder(types.FormatOrderID(10, 2), types.SellOrder, "10", "2"),
+	}
+	require.NoError(t, ValidateGenesis(genesisState))
+
+	// a partially filled buy order might lock more than the remain quantity at its price
+	genesisState.OpenOrders[0].Fill(sdk.MustNewDecFromStr("9"), sdk.OneDec())
+	require.NoError(t, ValidateGenesis(genesisState))
+	genesisState.OpenOrders[0].RemainLocked = sdk.MustNewDecFromStr("9.9")
+	require.Error(t, ValidateGenesis(genesisState))
+	genesisState.OpenOrders[0].RemainLocked = sdk.MustNewDecFromStr("11")
+	require.NoError(t, ValidateGenesis(genesisState))
+	genesisState.OpenOrders[1].RemainLocked = sdk.OneDec()
+	require.Error(t, ValidateGenesis(genesisState))
+	genesisState.OpenOrders[1].RemainLocked = genesisState.OpenOrders[1].RemainQuantity
+
+	for _, orderID := range []string{types.FormatOrderID(10, 1), "ID10-3", "ID0000000000-1", "invalid"} {
+		genesisState.OpenOrders[1].OrderID = orderID
+		require.Error(t, ValidateGenesis(genesisState), orderID)
+	}
+	genesisState.OpenOrders[1].OrderID = types.FormatOrderID(10, 2)
+	genesisState.OpenOrders[1].Status = types.OrderStatusFilled
+	require.Error(t, ValidateGenesis(genesisState))
+	genesisState.OpenOrders[1].Status = types.OrderStatusOpen
+
+	// the orders are covered by the order numbers and expire block heights
+	genesisState.BlockOrderNums = []*types.BlockOrderNum{{BlockHeight: 10, OrderNum: 1}}
+	require.Error(t, ValidateGenesis(genesisState))
+	genesisState.BlockOrderNums[0].OrderNum = 3
+	require.NoError(t, ValidateGenesis(genesisState))
+	genesisState.ExpireBlockHeights = []*types.ExpireBlockHeight{{BlockHeight: 110, ExpireBlockHeights: []int64{9}}}
+	require.Error(t, ValidateGenesis(genesisState))
+	genesisState.ExpireBlockHeights[0].ExpireBlockHeights = []int64{9, 10}
+	require.NoError(t, ValidateGenesis(genesisState))
+
+	genesisState.LastPrices = []*types.LastPrice{{Product: types.TestTokenPair, Price: sdk.ZeroDec()}}
+	require.Error(t, ValidateGenesis(genesisState))
+	genesisState.LastPrices[0].Price = sdk.OneDec()
+	require.NoError(t, ValidateGenesis(genesisState))
+	genesisState.ProductHalts = []*types.ProductHalt{{Product: types.TestTokenPair, HaltHeight: 10, ResumeHeight: 10}}
+	require.Error(t, ValidateGenesis(genesisState))
+	genesisState.ProductHalts[0].ResumeHeight = 20
+	require.NoError(t, ValidateGenesis(genesisState))
+	genesisState.SelfTradeModes = []*types.SelfTradeMode{{Address: addr, Mode: "RANDOM"}}
+	require.Error(t, ValidateGenesis(genesisState))
+	genesisState.SelfTradeModes[0].Mode = types.SelfTradePreventionCancelOldest
+	require.NoError(t, ValidateGenesis(genesisState))
+	genesisState.TradeVolumes = []*types.TradeVolume{{Address: addr, Product: types.TestTokenPair, Day: 1,
+		Volume: sdk.OneDec()}, {Address: addr, Product: types.TestTokenPair, Day: 1, Volume: sdk.OneDec()}}
+	require.Error(t, ValidateGenesis(genesisState))
+	genesisState.TradeVolumes[1].Day = 2
+	require.NoError(t, ValidateGenesis(genesisState))
+}
+
+func TestExportGenesisOrderState(t *testing.T) {
+	testInput := keeper.CreateTestInput(t)
+	ctx := testInput.Ctx.WithBlockHeight(10)
+	orderKeeper := testInput.OrderKeeper
+	tokenPair := dex.GetBuiltInTokenPair()
+	require.NoError(t, testInput.DexKeeper.SaveTokenPair(ctx, tokenPair))
+
+	order := types.NewOrder("txHash", testInput.TestAddrs[0], types.TestTokenPair, types.BuyOrder, sdk.NewDec(1),
+		sdk.NewDec(2), time.Now().Unix(), 5, sdk.NewDecCoinFromDec(common.NativeToken, sdk.NewDec(1)))
+	require.NoError(t, orderKeeper.PlaceOrder(ctx, order))
+	orderKeeper.SetBlockOrderNum(ctx, 10, 3)
+	orderKeeper.SetExpireBlockHeight(ctx, 110, []int64{9, 10})
+	orderKeeper.SetLastExpiredBlockHeight(ctx, 9)
+	orderKeeper.SetLastPrice(ctx, types.TestTokenPair, sdk.MustNewDecFromStr("1.5"))
+	orderKeeper.SetProductHalt(ctx, &types.ProductHalt{Product: types.TestTokenPair, HaltHeight: 10,
+		ResumeHeight: 20, LastPrice: sdk.OneDec(), HaltPrice: sdk.NewDec(2)})
+	orderKeeper.SetAccountSelfTradePrevention(ctx, testInput.TestAddrs[1], types.SelfTradePreventionDecrementBoth)
+	orderKeeper.AddTradeVolume(ctx, testInput.TestAddrs[1], types.TestTokenPair, sdk.NewDec(100))
+	orderKeeper.Cache2Disk(ctx)
+
+	exportGenesis := ExportGenesis(ctx, orderKeeper)
+	require.NoError(t, ValidateGenesis(exportGenesis))
+	require.Equal(t, []*types.LastPrice{{Product: types.TestTokenPair, Price: sdk.MustNewDecFromStr("1.5")}},
+		exportGenesis.LastPrices)
+	require.Equal(t, []*types.BlockOrderNum{{BlockHeight: 10, OrderNum: 3}}, exportGenesis.BlockOrderNums)
+	require.Equal(t, []*types.ExpireBlockHeight{{BlockHeight: 110, ExpireBlockHeights: []int64{9, 10}}},
+		exportGenesis.ExpireBlockHeights)
+	require.Equal(t, int64(9), exportGenesis.LastExpiredBlockHeight)
+	require.Equal(t, []*types.SelfTradeMode{{Address: testInput.TestAddrs[1],
+		Mode: types.SelfTradePreventionDecrementBoth}}, exportGenesis.SelfTradeModes)
+	require.Equal(t, 1, len(exportGenesis.TradeVolumes))
+	require.Equal(t, testInput.TestAddrs[1], exportGenesis.TradeVolumes[0].Address)
+	require.Equal(t, types.TestTokenPair, exportGenesis.TradeVolumes[0].Product)
+	require.Equal(t, sdk.NewDec(100), exportGenesis.TradeVolumes[0].Volume)
+
+	// the imported state is exported as it is
+	newTestInput := keeper.CreateTestInput(t)
+	newCtx := newTestInput.Ctx
+	require.NoError(t, newTestInput.DexKeeper.SaveTokenPair(newCtx, tokenPair))
+	InitGenesis(newCtx, newTestInput.OrderKeeper, exportGenesis)
+	require.Equal(t, exportGenesis, ExportGenesis(newCtx, newTestInput.OrderKeeper))
+	require.Equal(t, sdk.MustNewDecFromStr("1.5"), newTestInput.OrderKeeper.GetLastPrice(newCtx, types.TestTokenPair))
+	require.Equal(t, sdk.NewDec(100), newTestInput.OrderKeeper.GetTrailingTradeVolume(newCtx,
+		testInput.TestAddrs[1], types.TestTokenPair))
+
+	// the products should exist in dex, and the coins locked in token should match the open orders
+	tokenPairs := []*dex.TokenPair{tokenPair}
+	lockedAssets := testInput.TokenKeeper.GetAllLockedCoins(ctx)
+	require.NoError(t, ValidateGenesisWithDependencies(exportGenesis, tokenPairs, lockedAssets))
+	require.Error(t, ValidateGenesisWithDependencies(exportGenesis, nil, lockedAssets))
+	require.Error(t, ValidateGenesisWithDependencies(exportGenesis, tokenPairs, nil))
+	lockedAssets[0].Coins = lockedAssets[0].Coins.Add(sdk.DecCoins{sdk.NewDecCoinFromDec(common.NativeToken,
+		sdk.OneDec())})
+	require.Error(t, ValidateGenesisWithDependencies(exportGenesis, tokenPairs, lockedAssets))
+}
+
+func TestExportGenesisCancelAfters(t *testing.T) {
+	testInput := keeper.CreateTestInput(t)
+	ctx := testInput.Ctx
+	orderKeeper := testInput.OrderKeeper
+
+	cancelAfters := []*types.CancelAfter{types.NewCancelAfter(testInput.TestAddrs[0], "", 20)}
+	initGenesis := DefaultGenesisState()
+	initGenesis.CancelAfters = cancelAfters
+	InitGenesis(ctx, orderKeeper, initGenesis)
+	require.EqualValues(t, cancelAfters, orderKeeper.GetExpiredCancelAfters(ctx, 20))
+	require.EqualValues(t, cancelAfters, ExportGenesis(ctx, orderKeeper).CancelAfters)
+}
+
+func TestExportGenesisTradingGrants(t *testing.T) {
+	testInput := keeper.CreateTestInput(t)
+	ctx := testInput.Ctx
+	orderKeeper := testInput.OrderKeeper
+
+	grant := types.NewTradingGrant(testInput.TestAddrs[0], testInput.TestAddrs[1], []string{types.TestTokenPair},
+		sdk.NewDec(100), 20)
+	grant.UsedNotional = sdk.NewDec(30)
+	initGenesis := DefaultGenesisState()
+	initGenesis.TradingGrants = []*types.TradingGrant{grant}
+	InitGenesis(ctx, orderKeeper, initGenesis)
+	require.EqualValues(t, grant, orderKeeper.GetTradingGrant(ctx, testInput.TestAddrs[0], testInput.TestAddrs[1]))
+	require.EqualValues(t, initGenesis.TradingGrants, ExportGenesis(ctx, orderKeeper).TradingGrants)
 }
 
 func TestExportGenesis(t *testing.T) {
@@ -78,6 +266,8 @@ func TestExportGenesis(t *testing.T) {
 	require.Equal(t, int64(1), orderKeeper.GetOpenOrderNum(ctx))
 	// 0x20
 	require.Equal(t, int64(1), orderKeeper.GetStoreOrderNum(ctx))
+	// 0x2A
+	require.Equal(t, []string{order1.OrderID}, orderKeeper.GetAccountOrderIDs(ctx, testInput.TestAddrs[0]))
 
 	exportGenesis := ExportGenesis(ctx, orderKeeper)
 	require.Equal(t, params, exportGenesis.Params)
@@ -102,6 +292,8 @@ func TestExportGenesis(t *testing.T) {
 	ctx = ctx.WithBlockHeight(1000)
 	err = orderKeeper.PlaceOrder(ctx, order2)
 	require.NoError(t, err)
+	// the orders placed in the block expire after the order expire blocks, which is marked in EndBlock
+	orderKeeper.SetExpireBlockHeight(ctx, 1000+params.OrderExpireBlocks, []int64{1000})
 	orderKeeper.Cache2Disk(ctx)
 
 	exportGenesis = ExportGenesis(ctx, orderKeeper)
@@ -134,7 +326,8 @@ func TestExportGenesis(t *testing.T) {
 	// 0x14
 	require.Equal(t, tokenPair.InitPrice, newOrderKeeper.GetLastPrice(newCtx, product))
 	// 0x15
-	require.Equal(t, []int64{10}, newOrderKeeper.GetExpireBlockHeight(newCtx, exportGenesis.Params.OrderExpireBlocks+10))
+	// the expire block heights are restored rather than rebuilt by the changed order expire blocks
+	require.Equal(t, []int64{10}, newOrderKeeper.GetExpireBlockHeight(newCtx, initGenesis.Params.OrderExpireBlocks+10))
 	require.Equal(t, []int64{1000}, newOrderKeeper.GetExpireBlockHeight(newCtx, exportGenesis.Params.OrderExpireBlocks+1000))
 	// 0x16
 	require.Equal(t, int64(1), newOrderKeeper.GetBlockOrderNum(newCtx, 10))
@@ -147,4 +340,7 @@ func TestExportGenesis(t *testing.T) {
 	require.Equal(t, int64(2), newOrderKeeper.GetOpenOrderNum(newCtx))
 	// 0x20
 	require.Equal(t, int64(2), newOrderKeeper.GetStoreOrderNum(newCtx))
+	// 0x2A
+	require.Equal(t, []string{order1.OrderID, order2.OrderID},
+		newOrderKeeper.GetAccountOrderIDs(newCtx, testInput.TestAddrs[0]))
 }
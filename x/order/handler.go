@@ -32,6 +32,31 @@ func NewOrderHandler(keeper keeper.Keeper) sdk.Handler {
 			handlerFun = func() sdk.Result {
 				return handleMsgCancelOrders(ctx, keeper, msg, logger)
 			}
+		case types.MsgReplaceOrders:
+			name = "handleMsgReplaceOrders"
+			handlerFun = func() sdk.Result {
+				return handleMsgReplaceOrders(ctx, keeper, msg, logger)
+			}
+		case types.MsgSetSelfTradePrevention:
+			name = "handleMsgSetSelfTradePrevention"
+			handlerFun = func() sdk.Result {
+				return handleMsgSetSelfTradePrevention(ctx, keeper, msg, logger)
+			}
+		case types.MsgGrantTrading:
+			name = "handleMsgGrantTrading"
+			handlerFun = func() sdk.Result {
+				return handleMsgGrantTrading(ctx, keeper, msg, logger)
+			}
+		case types.MsgRevokeTrading:
+			name = "handleMsgRevokeTrading"
+			handlerFun = func() sdk.Result {
+				return handleMsgRevokeTrading(ctx, keeper, msg, logger)
+			}
+		case types.MsgSetCancelAfter:
+			name = "handleMsgSetCancelAfter"
+			handlerFun = func() sdk.Result {
+				return handleMsgSetCancelAfter(ctx, keeper, msg, logger)
+			}
 		default:
 			errMsg := fmt.Sprintf("Invalid msg type: %v", msg.Type())
 			return sdk.ErrUnknownRequest(errMsg).Result()
@@ -68,6 +93,25 @@ func checkOrderNewMsg(ctx sdk.Context, keeper keeper.Keeper, msg types.MsgNewOrd
 	if !roundedQuantity.Equal(msg.Quantity) {
 		return fmt.Errorf("quantity(%v) over accuracy(%d)", msg.Quantity, quantityDigit)
 	}
+	if msg.TriggerType != "" && !msg.TriggerPrice.RoundDecimal(priceDigit).Equal(msg.TriggerPrice) {
+		return fmt.Errorf("trigger price(%v) over accuracy(%d)", msg.TriggerPrice, priceDigit)
+	}
+	if !msg.DisplayQuantity.IsNil() && msg.DisplayQuantity.IsPositive() {
+		if !msg.DisplayQuantity.RoundDecimal(quantityDigit).Equal(msg.DisplayQuantity) {
+			return fmt.Errorf("display quantity(%v) over accuracy(%d)", msg.DisplayQuantity, quantityDigit)
+		}
+		if msg.DisplayQuantity.LT(tokenPair.MinQuantity) {
+			return fmt.Errorf("display quantity should be greater than %s", tokenPair.MinQuantity)
+		}
+	}
+
+	if msg.TimeInForce == types.TimeInForceGTB {
+		maxExpireHeight := ctx.BlockHeight() + keeper.GetParams(ctx).OrderExpireBlocks
+		if msg.ExpireHeight < ctx.BlockHeight() || msg.ExpireHeight > maxExpireHeight {
+			return fmt.Errorf("expire height(%d) of GTB order should be in [%d, %d]", msg.ExpireHeight,
+				ctx.BlockHeight(), maxExpireHeight)
+		}
+	}
 
 	if msg.Quantity.LT(tokenPair.MinQuantity) {
 		return fmt.Errorf("quantity should be greater than %s", tokenPair.MinQuantity)
@@ -80,11 +124,41 @@ func checkOrderNewMsg(ctx sdk.Context, keeper keeper.Keeper, msg types.MsgNewOrd
 	return nil
 }
 
+// setMarketOrderPrice sets the price of market order msg to the worst price it accepts, which is limited by
+// the slippage cap from the best opposite price. The price of a stop-loss or take-profit market order is limited
+// by the slippage cap from its trigger price
+func setMarketOrderPrice(ctx sdk.Context, keeper keeper.Keeper, msg *types.MsgNewOrder) error {
+	if msg.Type != types.OrderTypeMarket {
+		return nil
+	}
+	msg.Price = sdk.ZeroDec()
+
+	tokenPair := keeper.GetDexKeeper().GetTokenPair(ctx, msg.Product)
+	if tokenPair == nil {
+		return fmt.Errorf("trading pair '%s' does not exist", msg.Product)
+	}
+	refPrice := msg.TriggerPrice
+	if msg.TriggerType == "" {
+		refPrice = keeper.GetMarketOrderRefPrice(ctx, msg.Product, msg.Side)
+	}
+	price := types.GetMarketOrderPrice(msg.Side, refPrice, keeper.GetParams(ctx).MaxMarketOrderSlippage,
+		tokenPair.MaxPriceDigit)
+	if !price.IsPositive() {
+		return fmt.Errorf("no available price for market order of trading pair '%s'", msg.Product)
+	}
+	msg.Price = price
+	return nil
+}
+
 func getOrderFromMsg(ctx sdk.Context, k keeper.Keeper, msg types.MsgNewOrder, ratio string) *types.Order {
 	feeParams := k.GetParams(ctx)
 	feePerBlockAmount := feeParams.FeePerBlock.Amount.Mul(sdk.MustNewDecFromStr(ratio))
 	feePerBlock := sdk.NewDecCoinFromDec(feeParams.FeePerBlock.Denom, feePerBlockAmount)
-	return types.NewOrder(
+	newOrder := types.NewOrder
+	if msg.Type == types.OrderTypeMarket {
+		newOrder = types.NewMarketOrder
+	}
+	order := newOrder(
 		fmt.Sprintf("%X", tmhash.Sum(ctx.TxBytes())),
 		msg.Sender,
 		msg.Product,
@@ -95,23 +169,67 @@ func getOrderFromMsg(ctx sdk.Context, k keeper.Keeper, msg types.MsgNewOrder, ra
 		feeParams.OrderExpireBlocks,
 		feePerBlock,
 	)
+	order.TimeInForce = msg.TimeInForce
+	order.SelfTradePrevention = msg.SelfTradePrevention
+	if !msg.DisplayQuantity.IsNil() && msg.DisplayQuantity.IsPositive() {
+		displayQuantity := msg.DisplayQuantity
+		order.DisplayQuantity = &displayQuantity
+	}
+	if msg.TriggerType != "" {
+		order.Status = types.OrderStatusUntriggered
+		order.Trigger = &types.OrderTrigger{Type: msg.TriggerType, Price: msg.TriggerPrice}
+	}
+	if msg.TimeInForce == types.TimeInForceGTB {
+		// GTB order only locks the fee of the blocks it lives, including current block
+		order.ExpireHeight = msg.ExpireHeight
+		if lifeBlocks := msg.ExpireHeight - ctx.BlockHeight() + 1; lifeBlocks < order.OrderExpireBlocks {
+			order.OrderExpireBlocks = lifeBlocks
+		}
+	}
+	return order
+}
+
+// consumeTradingGrant authorizes the order placed by the grantee on behalf of its sender, nothing to do if the sender
+// signs itself. The grantee is recorded in the order, so the unfilled notional is given back to the grant once the
+// order quits
+func consumeTradingGrant(ctx sdk.Context, k Keeper, grantee sdk.AccAddress, order *types.Order) error {
+	if grantee.Empty() {
+		return nil
+	}
+	if err := k.ConsumeTradingGrant(ctx, order.Sender, grantee, order.Product,
+		order.Price.Mul(order.Quantity)); err != nil {
+		return err
+	}
+	order.Grantee = grantee
+	return nil
 }
 
-func handleNewOrder(ctx sdk.Context, k Keeper, sender sdk.AccAddress,
+func handleNewOrder(ctx sdk.Context, k Keeper, sender, grantee sdk.AccAddress,
 	item types.OrderItem, ratio string, logger log.Logger) (types.OrderResult, sdk.CacheMultiStore, error) {
 
 	cacheItem := ctx.MultiStore().CacheMultiStore()
 	ctxItem := ctx.WithMultiStore(cacheItem)
 	msg := MsgNewOrder{
-		Sender:   sender,
-		Product:  item.Product,
-		Side:     item.Side,
-		Price:    item.Price,
-		Quantity: item.Quantity,
+		Sender:       sender,
+		Product:      item.Product,
+		Side:         item.Side,
+		Price:        item.Price,
+		Quantity:     item.Quantity,
+		Type:         item.Type,
+		TimeInForce:  item.TimeInForce,
+		ExpireHeight: item.ExpireHeight,
+		TriggerType:  item.TriggerType,
+		TriggerPrice: item.TriggerPrice,
+
+		SelfTradePrevention: item.SelfTradePrevention,
+		DisplayQuantity:     item.DisplayQuantity,
 	}
+	err := setMarketOrderPrice(ctxItem, k, &msg)
 	order := getOrderFromMsg(ctxItem, k, msg, ratio)
 	code := sdk.CodeOK
-	err := checkOrderNewMsg(ctxItem, k, msg)
+	if err == nil {
+		err = checkOrderNewMsg(ctxItem, k, msg)
+	}
 
 	if err != nil {
 		code = sdk.CodeUnknownRequest
@@ -119,6 +237,8 @@ func handleNewOrder(ctx sdk.Context, k Keeper, sender sdk.AccAddress,
 		if k.IsProductLocked(msg.Product) {
 			code = sdk.CodeInternal
 			err = fmt.Errorf("the trading pair (%s) is locked, please retry later", order.Product)
+		} else if err = consumeTradingGrant(ctxItem, k, grantee, order); err != nil {
+			code = sdk.CodeUnauthorized
 		} else if err = k.PlaceOrder(ctxItem, order); err != nil {
 			code = sdk.CodeInsufficientCoins
 		}
@@ -156,7 +276,7 @@ func handleMsgNewOrders(ctx sdk.Context, k Keeper, msg types.MsgNewOrders,
 
 	rs := make([]types.OrderResult, 0, len(msg.OrderItems))
 	for _, item := range msg.OrderItems {
-		res, cacheItem, err := handleNewOrder(ctx, k, msg.Sender, item, ratio, logger)
+		res, cacheItem, err := handleNewOrder(ctx, k, msg.Sender, msg.Grantee, item, ratio, logger)
 		if err == nil {
 			cacheItem.Write()
 		}
@@ -180,15 +300,27 @@ func ValidateMsgNewOrders(ctx sdk.Context, k keeper.Keeper, msg types.MsgNewOrde
 		ratio = "0.8"
 	}
 
+	grantee := msg.Grantee
 	for _, item := range msg.OrderItems {
 		msg := MsgNewOrder{
-			Sender:   msg.Sender,
-			Product:  item.Product,
-			Side:     item.Side,
-			Price:    item.Price,
-			Quantity: item.Quantity,
+			Sender:       msg.Sender,
+			Product:      item.Product,
+			Side:         item.Side,
+			Price:        item.Price,
+			Quantity:     item.Quantity,
+			Type:         item.Type,
+			TimeInForce:  item.TimeInForce,
+			ExpireHeight: item.ExpireHeight,
+			TriggerType:  item.TriggerType,
+			TriggerPrice: item.TriggerPrice,
+
+			SelfTradePrevention: item.SelfTradePrevention,
+			DisplayQuantity:     item.DisplayQuantity,
+		}
+		err := setMarketOrderPrice(ctx, k, &msg)
+		if err == nil {
+			err = checkOrderNewMsg(ctx, k, msg)
 		}
-		err := checkOrderNewMsg(ctx, k, msg)
 		if err != nil {
 			return sdk.Result{
 				Code: sdk.CodeUnknownRequest,
@@ -203,6 +335,15 @@ func ValidateMsgNewOrders(ctx sdk.Context, k keeper.Keeper, msg types.MsgNewOrde
 		}
 
 		order := getOrderFromMsg(ctx, k, msg, ratio)
+		if !grantee.Empty() {
+			if err = k.AuthorizeTrading(ctx, order.Sender, grantee, order.Product,
+				order.Price.Mul(order.Quantity)); err != nil {
+				return sdk.Result{
+					Code: sdk.CodeUnauthorized,
+					Log:  err.Error(),
+				}
+			}
+		}
 		_, err = k.TryPlaceOrder(ctx, order)
 		if err != nil {
 			return sdk.Result{
@@ -216,8 +357,8 @@ func ValidateMsgNewOrders(ctx sdk.Context, k keeper.Keeper, msg types.MsgNewOrde
 
 }
 
-func handleCancelOrder(context sdk.Context, k Keeper, sender sdk.AccAddress, orderID string, logger log.Logger) (
-	types.OrderResult, sdk.CacheMultiStore) {
+func handleCancelOrder(context sdk.Context, k Keeper, sender, grantee sdk.AccAddress, orderID string,
+	logger log.Logger) (types.OrderResult, sdk.CacheMultiStore) {
 
 	cacheItem := context.MultiStore().CacheMultiStore()
 	ctx := context.WithMultiStore(cacheItem)
@@ -226,6 +367,7 @@ func handleCancelOrder(context sdk.Context, k Keeper, sender sdk.AccAddress, ord
 	msg := MsgCancelOrder{
 		Sender:  sender,
 		OrderID: orderID,
+		Grantee: grantee,
 	}
 	validateResult := validateCancelOrder(ctx, k, msg)
 	var message string
@@ -252,7 +394,7 @@ func handleMsgCancelOrders(ctx sdk.Context, k Keeper, msg types.MsgCancelOrders,
 	cancelRes := []types.OrderResult{}
 	for _, orderID := range msg.OrderIDs {
 
-		res, cacheItem := handleCancelOrder(ctx, k, msg.Sender, orderID, logger)
+		res, cacheItem := handleCancelOrder(ctx, k, msg.Sender, msg.Grantee, orderID, logger)
 		cancelRes = append(cancelRes, res)
 		cacheItem.Write()
 
@@ -286,7 +428,7 @@ func validateCancelOrder(ctx sdk.Context, keeper keeper.Keeper, msg types.MsgCan
 			Log:  fmt.Sprintf("order(%s) does not exist or already closed", msg.OrderID),
 		}
 	}
-	if order.Status != types.OrderStatusOpen {
+	if order.Status != types.OrderStatusOpen && order.Status != types.OrderStatusUntriggered {
 		return sdk.Result{
 			Code: sdk.CodeInternal,
 			Log:  fmt.Sprintf("cannot cancel order with status(%d)", order.Status),
@@ -298,6 +440,14 @@ func validateCancelOrder(ctx sdk.Context, keeper keeper.Keeper, msg types.MsgCan
 			Log:  fmt.Sprintf("not the owner of order(%v)", msg.OrderID),
 		}
 	}
+	if !msg.Grantee.Empty() {
+		if err := keeper.AuthorizeTrading(ctx, order.Sender, msg.Grantee, order.Product, sdk.ZeroDec()); err != nil {
+			return sdk.Result{
+				Code: sdk.CodeUnauthorized,
+				Log:  err.Error(),
+			}
+		}
+	}
 	if keeper.IsProductLocked(order.Product) {
 		return sdk.Result{
 			Code: sdk.CodeInternal,
@@ -313,6 +463,7 @@ func ValidateMsgCancelOrders(ctx sdk.Context, keeper keeper.Keeper, msg types.Ms
 		msg := MsgCancelOrder{
 			Sender:  msg.Sender,
 			OrderID: orderID,
+			Grantee: msg.Grantee,
 		}
 		res := validateCancelOrder(ctx, keeper, msg)
 		if sdk.CodeOK != res.Code {
@@ -322,3 +473,220 @@ func ValidateMsgCancelOrders(ctx sdk.Context, keeper keeper.Keeper, msg types.Ms
 
 	return sdk.Result{}
 }
+
+func handleReplaceOrder(context sdk.Context, k Keeper, sender sdk.AccAddress, item types.ReplaceOrderItem,
+	logger log.Logger) (types.OrderResult, sdk.CacheMultiStore, error) {
+
+	cacheItem := context.MultiStore().CacheMultiStore()
+	ctx := context.WithMultiStore(cacheItem)
+
+	res := types.OrderResult{
+		Code:    sdk.CodeOK,
+		OrderID: item.OrderID,
+	}
+	validateResult := validateReplaceOrder(ctx, k, sender, item)
+	if !validateResult.IsOK() {
+		res.Code = validateResult.Code
+		res.Message = validateResult.Log
+		return res, cacheItem, errors.New(validateResult.Log)
+	}
+
+	order := k.GetOrder(ctx, item.OrderID)
+	if err := k.ReplaceOrder(ctx, order, item.Price, item.Quantity); err != nil {
+		res.Code = sdk.CodeInsufficientCoins
+		res.Message = err.Error()
+		return res, cacheItem, err
+	}
+
+	logger.Debug(fmt.Sprintf("BlockHeight<%d>, handler<%s>\n"+
+		"    msg<Sender:%s,ID:%s,Price:%s,Quantity:%s>\n"+
+		"    result<The User have replaced an order {ID:%s,RemainQuantity:%s,Status:%s} >\n",
+		ctx.BlockHeight(), "handleMsgReplaceOrder",
+		sender, item.OrderID, item.Price.String(), item.Quantity.String(),
+		order.OrderID, order.RemainQuantity.String(), types.OrderStatus(order.Status)))
+	return res, cacheItem, nil
+}
+
+func handleMsgReplaceOrders(ctx sdk.Context, k Keeper, msg types.MsgReplaceOrders, logger log.Logger) sdk.Result {
+	replaceRes := make([]types.OrderResult, 0, len(msg.ReplaceItems))
+	for _, item := range msg.ReplaceItems {
+		res, cacheItem, err := handleReplaceOrder(ctx, k, msg.Sender, item, logger)
+		if err == nil {
+			cacheItem.Write()
+		}
+		replaceRes = append(replaceRes, res)
+	}
+	rss, err := json.Marshal(&replaceRes)
+	if err != nil {
+		rss = []byte(fmt.Sprintf("failed to marshal result to JSON: %s", err))
+	}
+
+	event := sdk.NewEvent(sdk.EventTypeMessage, sdk.NewAttribute(sdk.AttributeKeyModule, types.ModuleName))
+	event = event.AppendAttributes(sdk.NewAttribute("orders", string(rss)))
+	ctx.EventManager().EmitEvent(event)
+	return sdk.Result{
+		Events: ctx.EventManager().Events(),
+	}
+}
+
+// validateReplaceOrder checks the order to be replaced, and checks the new price and quantity as a new order
+func validateReplaceOrder(ctx sdk.Context, keeper keeper.Keeper, sender sdk.AccAddress,
+	item types.ReplaceOrderItem) sdk.Result {
+
+	res := validateCancelOrder(ctx, keeper, types.MsgCancelOrder{Sender: sender, OrderID: item.OrderID})
+	if !res.IsOK() {
+		return res
+	}
+	order := keeper.GetOrder(ctx, item.OrderID)
+	if order.Status != types.OrderStatusOpen || order.IsImmediateOrder() {
+		return sdk.Result{
+			Code: sdk.CodeInternal,
+			Log:  fmt.Sprintf("cannot replace order(%s) which is untriggered or never rests in depth book", item.OrderID),
+		}
+	}
+
+	filledQuantity := order.Quantity.Sub(order.RemainQuantity)
+	if item.Quantity.LTE(filledQuantity) {
+		return sdk.Result{
+			Code: sdk.CodeUnknownRequest,
+			Log:  fmt.Sprintf("quantity should be greater than the filled quantity %s", filledQuantity),
+		}
+	}
+	msg := MsgNewOrder{
+		Sender:       sender,
+		Product:      order.Product,
+		Side:         order.Side,
+		Price:        item.Price,
+		Quantity:     item.Quantity,
+		Type:         order.Type,
+		TimeInForce:  order.TimeInForce,
+		ExpireHeight: order.ExpireHeight,
+	}
+	if err := checkOrderNewMsg(ctx, keeper, msg); err != nil {
+		return sdk.Result{
+			Code: sdk.CodeUnknownRequest,
+			Log:  err.Error(),
+		}
+	}
+	if order.TimeInForce == types.TimeInForcePostOnly &&
+		isCrossedBy(keeper.GetDepthBookCopy(order.Product), order.Side, item.Price) {
+		return sdk.Result{
+			Code: sdk.CodeUnknownRequest,
+			Log:  fmt.Sprintf("post-only order(%s) would take resting orders at price %s", item.OrderID, item.Price),
+		}
+	}
+	return sdk.Result{}
+}
+
+// isCrossedBy returns true if an order of the side at the price would take the opposite side of depth book
+func isCrossedBy(book *types.DepthBook, side string, price sdk.Dec) bool {
+	for _, item := range book.Items {
+		if side == types.BuyOrder && item.SellQuantity.IsPositive() && item.Price.LTE(price) {
+			return true
+		}
+		if side == types.SellOrder && item.BuyQuantity.IsPositive() && item.Price.GTE(price) {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateMsgReplaceOrders validates whether the msg of replaceOrders is valid.
+func ValidateMsgReplaceOrders(ctx sdk.Context, keeper keeper.Keeper, msg types.MsgReplaceOrders) sdk.Result {
+	for _, item := range msg.ReplaceItems {
+		res := validateReplaceOrder(ctx, keeper, msg.Sender, item)
+		if sdk.CodeOK != res.Code {
+			return res
+		}
+	}
+
+	return sdk.Result{}
+}
+
+func handleMsgSetSelfTradePrevention(ctx sdk.Context, k Keeper, msg types.MsgSetSelfTradePrevention,
+	logger log.Logger) sdk.Result {
+
+	k.SetAccountSelfTradePrevention(ctx, msg.Sender, msg.Mode)
+	logger.Debug(fmt.Sprintf("BlockHeight<%d>, handler<%s>\n"+
+		"    msg<Sender:%s,Mode:%s>\n",
+		ctx.BlockHeight(), "handleMsgSetSelfTradePrevention", msg.Sender, msg.Mode))
+
+	ctx.EventManager().EmitEvent(sdk.NewEvent(sdk.EventTypeMessage,
+		sdk.NewAttribute(sdk.AttributeKeyModule, types.ModuleName),
+		sdk.NewAttribute(sdk.AttributeKeySender, msg.Sender.String()),
+	))
+	return sdk.Result{
+		Events: ctx.EventManager().Events(),
+	}
+}
+
+func handleMsgSetCancelAfter(ctx sdk.Context, k Keeper, msg types.MsgSetCancelAfter, logger log.Logger) sdk.Result {
+	if msg.Deadline == 0 {
+		k.DropCancelAfter(ctx, msg.Sender)
+	} else {
+		if msg.Deadline <= ctx.BlockHeight() {
+			return sdk.ErrUnknownRequest(fmt.Sprintf("the deadline %d should be greater than current height %d",
+				msg.Deadline, ctx.BlockHeight())).Result()
+		}
+		if msg.Product != "" && k.GetDexKeeper().GetTokenPair(ctx, msg.Product) == nil {
+			return sdk.ErrUnknownRequest(fmt.Sprintf("trading pair '%s' does not exist", msg.Product)).Result()
+		}
+		k.SetCancelAfter(ctx, types.NewCancelAfter(msg.Sender, msg.Product, msg.Deadline))
+	}
+	logger.Debug(fmt.Sprintf("BlockHeight<%d>, handler<%s>\n"+
+		"    msg<Sender:%s,Product:%s,Deadline:%d>\n",
+		ctx.BlockHeight(), "handleMsgSetCancelAfter", msg.Sender, msg.Product, msg.Deadline))
+
+	ctx.EventManager().EmitEvent(sdk.NewEvent(sdk.EventTypeMessage,
+		sdk.NewAttribute(sdk.AttributeKeyModule, types.ModuleName),
+		sdk.NewAttribute(sdk.AttributeKeySender, msg.Sender.String()),
+	))
+	return sdk.Result{
+		Events: ctx.EventManager().Events(),
+	}
+}
+
+func handleMsgGrantTrading(ctx sdk.Context, k Keeper, msg types.MsgGrantTrading, logger log.Logger) sdk.Result {
+	for _, product := range msg.Products {
+		if k.GetDexKeeper().GetTokenPair(ctx, product) == nil {
+			return sdk.ErrUnknownRequest(fmt.Sprintf("trading pair '%s' does not exist", product)).Result()
+		}
+	}
+	if msg.ExpireHeight > 0 && msg.ExpireHeight < ctx.BlockHeight() {
+		return sdk.ErrUnknownRequest(fmt.Sprintf("the expire height %d should not be less than current height %d",
+			msg.ExpireHeight, ctx.BlockHeight())).Result()
+	}
+	k.SetTradingGrant(ctx, types.NewTradingGrant(msg.Granter, msg.Grantee, msg.Products, msg.NotionalCap,
+		msg.ExpireHeight))
+	logger.Debug(fmt.Sprintf("BlockHeight<%d>, handler<%s>\n"+
+		"    msg<Granter:%s,Grantee:%s,Products:%v,NotionalCap:%s,ExpireHeight:%d>\n",
+		ctx.BlockHeight(), "handleMsgGrantTrading", msg.Granter, msg.Grantee, msg.Products, msg.NotionalCap,
+		msg.ExpireHeight))
+
+	ctx.EventManager().EmitEvent(sdk.NewEvent(sdk.EventTypeMessage,
+		sdk.NewAttribute(sdk.AttributeKeyModule, types.ModuleName),
+		sdk.NewAttribute(sdk.AttributeKeySender, msg.Granter.String()),
+	))
+	return sdk.Result{
+		Events: ctx.EventManager().Events(),
+	}
+}
+
+func handleMsgRevokeTrading(ctx sdk.Context, k Keeper, msg types.MsgRevokeTrading, logger log.Logger) sdk.Result {
+	if k.GetTradingGrant(ctx, msg.Granter, msg.Grantee) == nil {
+		return sdk.ErrUnknownRequest(fmt.Sprintf("%s is not granted to trade on behalf of %s", msg.Grantee,
+			msg.Granter)).Result()
+	}
+	k.DropTradingGrant(ctx, msg.Granter, msg.Grantee)
+	logger.Debug(fmt.Sprintf("BlockHeight<%d>, handler<%s>\n"+
+		"    msg<Granter:%s,Grantee:%s>\n",
+		ctx.BlockHeight(), "handleMsgRevokeTrading", msg.Granter, msg.Grantee))
+
+	ctx.EventManager().EmitEvent(sdk.NewEvent(sdk.EventTypeMessage,
+		sdk.NewAttribute(sdk.AttributeKeyModule, types.ModuleName),
+		sdk.NewAttribute(sdk.AttributeKeySender, msg.Granter.String()),
+	))
+	return sdk.Result{
+		Events: ctx.EventManager().Events(),
+	}
+}
@@ -44,7 +44,8 @@ func TestEventNewOrders(t *testing.T) {
 	msg := types.NewMsgNewOrders(addrKeysSlice[0].Address, orderItems)
 	result := handler(ctx, msg)
 
-	require.EqualValues(t, 2, len(result.Events[4].Attributes))
+	// each accepted order emits transfer, message and order_accepted events before the result event
+	require.EqualValues(t, 2, len(result.Events[10].Attributes))
 
 }
 
@@ -329,6 +330,184 @@ func TestHandleMsgCancelOrderInvalid(t *testing.T) {
 	require.EqualValues(t, sdk.CodeInternal, orderRes[0].Code)
 }
 
+func TestHandleMsgReplaceOrders(t *testing.T) {
+	mapp, addrKeysSlice := getMockApp(t, 2)
+	keeper := mapp.orderKeeper
+	mapp.BeginBlock(abci.RequestBeginBlock{Header: abci.Header{Height: 2}})
+
+	var startHeight int64 = 10
+	ctx := mapp.BaseApp.NewContext(false, abci.Header{}).WithBlockHeight(startHeight)
+	feeParams := types.DefaultParams()
+	mapp.orderKeeper.SetParams(ctx, &feeParams)
+	tokenPair := dex.GetBuiltInTokenPair()
+	mapp.supplyKeeper.SetSupply(ctx, supply.NewSupply(mapp.TotalCoinsSupply))
+	err := mapp.dexKeeper.SaveTokenPair(ctx, tokenPair)
+	require.Nil(t, err)
+
+	// mock orders
+	orders := []*types.Order{
+		types.MockOrder("", types.TestTokenPair, types.SellOrder, "10.0", "2.0"),
+		types.MockOrder("", types.TestTokenPair, types.BuyOrder, "9.0", "1.0"),
+		types.MockOrder("", types.TestTokenPair, types.BuyOrder, "9.5", "1.0"),
+	}
+	orders[0].Sender = addrKeysSlice[0].Address
+	orders[1].Sender = addrKeysSlice[0].Address
+	orders[1].TimeInForce = types.TimeInForcePostOnly
+	orders[2].Sender = addrKeysSlice[1].Address
+	for _, order := range orders {
+		err := keeper.PlaceOrder(ctx, order)
+		require.NoError(t, err)
+	}
+
+	handler := NewOrderHandler(keeper)
+	items := []types.ReplaceOrderItem{
+		types.NewReplaceOrderItem(orders[0].OrderID, "10.0", "1.0"),
+		// post-only order would take the ask
+		types.NewReplaceOrderItem(orders[1].OrderID, "10.0", "1.0"),
+		// not the owner
+		types.NewReplaceOrderItem(orders[2].OrderID, "9.6", "1.0"),
+		// not exist
+		types.NewReplaceOrderItem(types.FormatOrderID(startHeight, 100), "9.6", "1.0"),
+	}
+	msg := types.NewMsgReplaceOrders(addrKeysSlice[0].Address, items)
+	require.EqualValues(t, sdk.CodeUnknownRequest, ValidateMsgReplaceOrders(ctx, keeper, msg).Code)
+	result := handler(ctx, msg)
+	orderRes := parseOrderResult(result)
+	expectCodes := []sdk.CodeType{sdk.CodeOK, sdk.CodeUnknownRequest, sdk.CodeUnauthorized, sdk.CodeUnknownRequest}
+	for i, res := range orderRes {
+		require.EqualValues(t, expectCodes[i], res.Code, i)
+	}
+
+	// the sell order is replaced without any cancel fee, and the unneeded coins are unlocked
+	order := keeper.GetOrder(ctx, orders[0].OrderID)
+	require.EqualValues(t, types.OrderStatusOpen, order.Status)
+	require.EqualValues(t, sdk.MustNewDecFromStr("1"), order.RemainQuantity)
+	require.EqualValues(t, sdk.MustNewDecFromStr("1"), order.RemainLocked)
+	require.EqualValues(t, sdk.MustNewDecFromStr("9.0"), keeper.GetOrder(ctx, orders[1].OrderID).Price)
+	acc0 := mapp.AccountKeeper.GetAccount(ctx, addrKeysSlice[0].Address)
+	expectCoins0 := sdk.DecCoins{
+		// 100 - 9 - 0.2592 * 2
+		sdk.NewDecCoinFromDec(common.NativeToken, sdk.MustNewDecFromStr("90.4816")),
+		// 100 - 1
+		sdk.NewDecCoinFromDec(common.TestToken, sdk.MustNewDecFromStr("99")),
+	}
+	require.EqualValues(t, expectCoins0.String(), acc0.GetCoins().String())
+}
+
+func TestHandleMsgSetSelfTradePrevention(t *testing.T) {
+	mapp, addrKeysSlice := getMockApp(t, 1)
+	keeper := mapp.orderKeeper
+	mapp.BeginBlock(abci.RequestBeginBlock{Header: abci.Header{Height: 2}})
+	ctx := mapp.BaseApp.NewContext(false, abci.Header{}).WithBlockHeight(10)
+	addr := addrKeysSlice[0].Address
+
+	handler := NewOrderHandler(keeper)
+	msg := types.NewMsgSetSelfTradePrevention(addr, types.SelfTradePreventionDecrementBoth)
+	result := handler(ctx, msg)
+	require.True(t, result.IsOK())
+	require.EqualValues(t, types.SelfTradePreventionDecrementBoth, keeper.GetAccountSelfTradePrevention(ctx, addr))
+
+	// the mode of the order takes precedence over the mode of the account
+	order := types.MockOrder("", types.TestTokenPair, types.SellOrder, "10.0", "1.0")
+	order.Sender = addr
+	require.EqualValues(t, types.SelfTradePreventionDecrementBoth, keeper.GetSelfTradePrevention(ctx, order))
+	order.SelfTradePrevention = types.SelfTradePreventionCancelNewest
+	require.EqualValues(t, types.SelfTradePreventionCancelNewest, keeper.GetSelfTradePrevention(ctx, order))
+
+	// empty mode allows self trades again
+	result = handler(ctx, types.NewMsgSetSelfTradePrevention(addr, ""))
+	require.True(t, result.IsOK())
+	require.EqualValues(t, "", keeper.GetAccountSelfTradePrevention(ctx, addr))
+}
+
+func TestHandleMsgSetCancelAfter(t *testing.T) {
+	mapp, addrKeysSlice := getMockApp(t, 1)
+	keeper := mapp.orderKeeper
+	mapp.BeginBlock(abci.RequestBeginBlock{Header: abci.Header{Height: 2}})
+	ctx := mapp.BaseApp.NewContext(false, abci.Header{}).WithBlockHeight(10)
+	addr := addrKeysSlice[0].Address
+
+	handler := NewOrderHandler(keeper)
+	result := handler(ctx, types.NewMsgSetCancelAfter(addr, "", 20))
+	require.True(t, result.IsOK())
+	require.EqualValues(t, types.NewCancelAfter(addr, "", 20), keeper.GetCancelAfter(ctx, addr))
+
+	// the deadline should be in the future, and the product should exist
+	result = handler(ctx, types.NewMsgSetCancelAfter(addr, "", 10))
+	require.EqualValues(t, sdk.CodeUnknownRequest, result.Code)
+	result = handler(ctx, types.NewMsgSetCancelAfter(addr, "nope_okt", 30))
+	require.EqualValues(t, sdk.CodeUnknownRequest, result.Code)
+	require.EqualValues(t, 20, keeper.GetCancelAfter(ctx, addr).Deadline)
+
+	// zero deadline turns the switch off
+	result = handler(ctx, types.NewMsgSetCancelAfter(addr, "", 0))
+	require.True(t, result.IsOK())
+	require.Nil(t, keeper.GetCancelAfter(ctx, addr))
+}
+
+func TestHandleMsgGrantTrading(t *testing.T) {
+	mapp, addrKeysSlice := getMockApp(t, 2)
+	keeper := mapp.orderKeeper
+	mapp.BeginBlock(abci.RequestBeginBlock{Header: abci.Header{Height: 2}})
+	ctx := mapp.BaseApp.NewContext(false, abci.Header{}).WithBlockHeight(10)
+	feeParams := types.DefaultParams()
+	mapp.orderKeeper.SetParams(ctx, &feeParams)
+	err := mapp.dexKeeper.SaveTokenPair(ctx, dex.GetBuiltInTokenPair())
+	require.Nil(t, err)
+	granter, grantee := addrKeysSlice[0].Address, addrKeysSlice[1].Address
+
+	handler := NewOrderHandler(keeper)
+
+	// the products should exist, and the grant should not expire
+	result := handler(ctx, types.NewMsgGrantTrading(granter, grantee, []string{"nope_okt"}, sdk.ZeroDec(), 0))
+	require.EqualValues(t, sdk.CodeUnknownRequest, result.Code)
+	result = handler(ctx, types.NewMsgGrantTrading(granter, grantee, nil, sdk.ZeroDec(), 9))
+	require.EqualValues(t, sdk.CodeUnknownRequest, result.Code)
+	result = handler(ctx, types.NewMsgGrantTrading(granter, grantee, []string{types.TestTokenPair},
+		sdk.NewDec(15), 0))
+	require.True(t, result.IsOK())
+
+	// the grantee places the order of the granter, the coins of the granter are locked
+	msg := types.NewMsgNewOrders(granter, []types.OrderItem{
+		types.NewOrderItem(types.TestTokenPair, types.BuyOrder, "10.0", "1.0")})
+	msg.Grantee = grantee
+	result = handler(ctx, msg)
+	require.True(t, result.IsOK())
+	orderID := getOrderID(result)
+	order := keeper.GetOrder(ctx, orderID)
+	require.NotNil(t, order)
+	require.EqualValues(t, granter, order.Sender)
+	require.EqualValues(t, sdk.NewDec(10), keeper.GetTradingGrant(ctx, granter, grantee).UsedNotional)
+	acc := mapp.AccountKeeper.GetAccount(ctx, grantee)
+	require.EqualValues(t, sdk.MustNewDecFromStr("100"), acc.GetCoins().AmountOf(common.NativeToken))
+
+	// the notional cap is exceeded
+	result = handler(ctx, msg)
+	require.EqualValues(t, sdk.CodeUnauthorized, parseOrderResult(result)[0].Code)
+
+	// the grantee cancels the order of the granter
+	cancelMsg := types.NewMsgCancelOrders(granter, []string{orderID})
+	cancelMsg.Grantee = grantee
+	result = handler(ctx, cancelMsg)
+	require.True(t, result.IsOK())
+	require.EqualValues(t, types.OrderStatusCancelled, keeper.GetOrder(ctx, orderID).Status)
+
+	// the notional of the order cancelled is given back to the grant
+	require.EqualValues(t, sdk.ZeroDec(), keeper.GetTradingGrant(ctx, granter, grantee).UsedNotional)
+	result = handler(ctx, msg)
+	require.True(t, result.IsOK())
+	require.EqualValues(t, sdk.NewDec(10), keeper.GetTradingGrant(ctx, granter, grantee).UsedNotional)
+
+	// the grant is revoked
+	result = handler(ctx, types.NewMsgRevokeTrading(granter, grantee))
+	require.True(t, result.IsOK())
+	require.Nil(t, keeper.GetTradingGrant(ctx, granter, grantee))
+	result = handler(ctx, types.NewMsgRevokeTrading(granter, grantee))
+	require.EqualValues(t, sdk.CodeUnknownRequest, result.Code)
+	result = handler(ctx, msg)
+	require.EqualValues(t, sdk.CodeUnauthorized, parseOrderResult(result)[0].Code)
+}
+
 func TestHandleInvalidMsg(t *testing.T) {
 	mapp, _ := getMockApp(t, 0)
 	keeper := mapp.orderKeeper
@@ -515,6 +694,43 @@ func TestHandleMsgMultiNewOrder(t *testing.T) {
 	require.EqualValues(t, expectCoins.String(), acc.GetCoins().String())
 }
 
+func TestHandleMsgNewOrderIceberg(t *testing.T) {
+	mapp, addrKeysSlice := getMockApp(t, 1)
+	keeper := mapp.orderKeeper
+	mapp.BeginBlock(abci.RequestBeginBlock{Header: abci.Header{Height: 2}})
+	ctx := mapp.BaseApp.NewContext(false, abci.Header{}).WithBlockHeight(10)
+	feeParams := types.DefaultParams()
+	mapp.orderKeeper.SetParams(ctx, &feeParams)
+	tokenPair := dex.GetBuiltInTokenPair()
+	tokenPair.MaxQuantityDigit = 2
+	tokenPair.MinQuantity = sdk.MustNewDecFromStr("0.5")
+	err := mapp.dexKeeper.SaveTokenPair(ctx, tokenPair)
+	require.Nil(t, err)
+
+	handler := NewOrderHandler(keeper)
+	item := types.NewOrderItem(types.TestTokenPair, types.BuyOrder, "10.0", "5.0")
+
+	// the display quantity is checked as the quantity
+	for _, display := range []string{"0.123", "0.1"} {
+		item.DisplayQuantity = sdk.MustNewDecFromStr(display)
+		result := handler(ctx, types.NewMsgNewOrders(addrKeysSlice[0].Address, []types.OrderItem{item}))
+		require.EqualValues(t, sdk.CodeUnknownRequest, parseOrderResult(result)[0].Code, display)
+	}
+
+	item.DisplayQuantity = sdk.MustNewDecFromStr("1.0")
+	result := handler(ctx, types.NewMsgNewOrders(addrKeysSlice[0].Address, []types.OrderItem{item}))
+	require.True(t, result.IsOK())
+	order := keeper.GetOrder(ctx, getOrderID(result))
+	require.NotNil(t, order)
+	require.EqualValues(t, item.DisplayQuantity, *order.DisplayQuantity)
+
+	// the full quantity is locked and kept in depth book, only the display quantity is visible
+	depthBook := keeper.GetDepthBookCopy(types.TestTokenPair)
+	require.EqualValues(t, sdk.MustNewDecFromStr("5.0"), depthBook.Items[0].BuyQuantity)
+	require.EqualValues(t, sdk.MustNewDecFromStr("1.0"), depthBook.Visible().Items[0].BuyQuantity)
+	require.EqualValues(t, sdk.MustNewDecFromStr("50"), order.RemainLocked)
+}
+
 func TestHandleMsgMultiCancelOrder(t *testing.T) {
 	mapp, addrKeysSlice := getMockApp(t, 1)
 	keeper := mapp.orderKeeper
@@ -979,3 +1195,74 @@ func handleOrders(t *testing.T, baseasset string, quoteasset string, orders []*t
 	require.NotNil(t, acc1)
 	return acc0.GetCoins()
 }
+
+func TestHandleOrderLifecycleEvents(t *testing.T) {
+	mapp, addrKeysSlice := getMockApp(t, 2)
+	keeper := mapp.orderKeeper
+	mapp.BeginBlock(abci.RequestBeginBlock{Header: abci.Header{Height: 2}})
+	ctx := mapp.BaseApp.NewContext(false, abci.Header{}).WithBlockHeight(10)
+	feeParams := types.DefaultParams()
+	mapp.orderKeeper.SetParams(ctx, &feeParams)
+	tokenPair := dex.GetBuiltInTokenPair()
+	err := mapp.dexKeeper.SaveTokenPair(ctx, tokenPair)
+	require.Nil(t, err)
+
+	// only the accepted order emits the accepted event
+	handler := NewOrderHandler(keeper)
+	items := []types.OrderItem{
+		types.NewOrderItem(types.TestTokenPair, types.BuyOrder, "10.0", "2.0"),
+		types.NewOrderItem(types.TestTokenPair, types.BuyOrder, "10.0", "1000.0"),
+	}
+	result := handler(ctx, types.NewMsgNewOrders(addrKeysSlice[0].Address, items))
+	require.True(t, result.IsOK())
+	buyOrderID := getOrderIDList(result)[0]
+	events := filterEvents(result.Events, types.EventTypeOrderAccepted)
+	require.Equal(t, 1, len(events))
+	requireAttribute(t, events[0], types.TagKeyOrderID, buyOrderID)
+	requireAttribute(t, events[0], types.TagKeyQuantity, "2.00000000")
+
+	item := types.NewOrderItem(types.TestTokenPair, types.SellOrder, "10.0", "1.0")
+	result = handler(ctx, types.NewMsgNewOrders(addrKeysSlice[1].Address, []types.OrderItem{item}))
+	require.True(t, result.IsOK())
+	sellOrderID := getOrderID(result)
+
+	// the periodic auction fills orders in EndBlock
+	ctx = ctx.WithEventManager(sdk.NewEventManager())
+	EndBlocker(ctx, keeper)
+	events = filterEvents(ctx.EventManager().Events(), types.EventTypeOrderPartiallyFilled)
+	require.Equal(t, 1, len(events))
+	requireAttribute(t, events[0], types.TagKeyOrderID, buyOrderID)
+	requireAttribute(t, events[0], types.TagKeyFillQuantity, "1.00000000")
+	events = filterEvents(ctx.EventManager().Events(), types.EventTypeOrderFilled)
+	require.Equal(t, 1, len(events))
+	requireAttribute(t, events[0], types.TagKeyOrderID, sellOrderID)
+	requireAttribute(t, events[0], types.TagKeyCounterSide, types.BuyOrder)
+
+	// cancel the remainder
+	ctx = ctx.WithBlockHeight(11)
+	result = handler(ctx, types.NewMsgCancelOrder(addrKeysSlice[0].Address, buyOrderID))
+	require.True(t, result.IsOK())
+	events = filterEvents(result.Events, types.EventTypeOrderCancelled)
+	require.Equal(t, 1, len(events))
+	requireAttribute(t, events[0], types.TagKeyOrderID, buyOrderID)
+	requireAttribute(t, events[0], types.TagKeyStatus, types.PartialFilledCancelled.String())
+}
+
+func filterEvents(events sdk.Events, eventType string) (filtered sdk.Events) {
+	for _, event := range events {
+		if event.Type == eventType {
+			filtered = append(filtered, event)
+		}
+	}
+	return filtered
+}
+
+func requireAttribute(t *testing.T, event sdk.Event, key, value string) {
+	for _, attr := range event.Attributes {
+		if string(attr.Key) == key {
+			require.Equal(t, value, string(attr.Value), key)
+			return
+		}
+	}
+	require.Fail(t, "attribute not found", key)
+}
@@ -0,0 +1,70 @@
+package keeper
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/okex/okchain/x/order/types"
+)
+
+// SetCancelAfter sets the cancel-after of the account, and replaces its previous deadline in the deadline index
+func (k Keeper) SetCancelAfter(ctx sdk.Context, cancelAfter *types.CancelAfter) {
+	k.DropCancelAfter(ctx, cancelAfter.Address)
+	store := ctx.KVStore(k.orderStoreKey)
+	store.Set(types.GetCancelAfterKey(cancelAfter.Address), k.cdc.MustMarshalBinaryBare(cancelAfter))
+	store.Set(types.GetDeadlineKey(cancelAfter.Deadline, cancelAfter.Address), []byte{})
+}
+
+// GetCancelAfter returns the cancel-after of the account, nil if not set
+func (k Keeper) GetCancelAfter(ctx sdk.Context, addr sdk.AccAddress) *types.CancelAfter {
+	store := ctx.KVStore(k.orderStoreKey)
+	bz := store.Get(types.GetCancelAfterKey(addr))
+	if bz == nil {
+		return nil
+	}
+	var cancelAfter types.CancelAfter
+	k.cdc.MustUnmarshalBinaryBare(bz, &cancelAfter)
+	return &cancelAfter
+}
+
+// DropCancelAfter deletes the cancel-after of the account and its deadline
+func (k Keeper) DropCancelAfter(ctx sdk.Context, addr sdk.AccAddress) {
+	cancelAfter := k.GetCancelAfter(ctx, addr)
+	if cancelAfter == nil {
+		return
+	}
+	store := ctx.KVStore(k.orderStoreKey)
+	store.Delete(types.GetDeadlineKey(cancelAfter.Deadline, addr))
+	store.Delete(types.GetCancelAfterKey(addr))
+}
+
+// GetCancelAfters returns the cancel-afters of all the accounts
+func (k Keeper) GetCancelAfters(ctx sdk.Context) []*types.CancelAfter {
+	store := ctx.KVStore(k.orderStoreKey)
+	iter := sdk.KVStorePrefixIterator(store, types.CancelAfterKey)
+	defer iter.Close()
+
+	var cancelAfters []*types.CancelAfter
+	for ; iter.Valid(); iter.Next() {
+		var cancelAfter types.CancelAfter
+		k.cdc.MustUnmarshalBinaryBare(iter.Value(), &cancelAfter)
+		cancelAfters = append(cancelAfters, &cancelAfter)
+	}
+	return cancelAfters
+}
+
+// GetExpiredCancelAfters returns the cancel-afters whose deadline is not greater than the specified height, sorted
+// by deadline
+func (k Keeper) GetExpiredCancelAfters(ctx sdk.Context, height int64) []*types.CancelAfter {
+	store := ctx.KVStore(k.orderStoreKey)
+	iter := store.Iterator(types.DeadlineKey, types.GetDeadlinePrefix(height+1))
+	defer iter.Close()
+
+	prefixLen := len(types.GetDeadlinePrefix(height))
+	var cancelAfters []*types.CancelAfter
+	for ; iter.Valid(); iter.Next() {
+		if cancelAfter := k.GetCancelAfter(ctx, iter.Key()[prefixLen:]); cancelAfter != nil {
+			cancelAfters = append(cancelAfters, cancelAfter)
+		}
+	}
+	return cancelAfters
+}
@@ -0,0 +1,37 @@
+package keeper
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/okex/okchain/x/order/types"
+)
+
+func TestKeeper_CancelAfter(t *testing.T) {
+	testInput := CreateTestInput(t)
+	keeper := testInput.OrderKeeper
+	ctx := testInput.Ctx.WithBlockHeight(10)
+	addr0, addr1 := testInput.TestAddrs[0], testInput.TestAddrs[1]
+
+	require.Nil(t, keeper.GetCancelAfter(ctx, addr0))
+	keeper.SetCancelAfter(ctx, types.NewCancelAfter(addr0, "", 20))
+	keeper.SetCancelAfter(ctx, types.NewCancelAfter(addr1, types.TestTokenPair, 15))
+	require.EqualValues(t, types.NewCancelAfter(addr0, "", 20), keeper.GetCancelAfter(ctx, addr0))
+	require.EqualValues(t, 2, len(keeper.GetCancelAfters(ctx)))
+
+	// the cancel-afters are sorted by deadline
+	require.Nil(t, keeper.GetExpiredCancelAfters(ctx, 14))
+	require.EqualValues(t, []*types.CancelAfter{types.NewCancelAfter(addr1, types.TestTokenPair, 15),
+		types.NewCancelAfter(addr0, "", 20)}, keeper.GetExpiredCancelAfters(ctx, 20))
+
+	// the heartbeat replaces the previous deadline
+	keeper.SetCancelAfter(ctx, types.NewCancelAfter(addr1, types.TestTokenPair, 30))
+	require.EqualValues(t, []*types.CancelAfter{types.NewCancelAfter(addr0, "", 20)},
+		keeper.GetExpiredCancelAfters(ctx, 29))
+
+	keeper.DropCancelAfter(ctx, addr0)
+	require.Nil(t, keeper.GetCancelAfter(ctx, addr0))
+	require.Nil(t, keeper.GetExpiredCancelAfters(ctx, 29))
+	require.EqualValues(t, 1, len(keeper.GetCancelAfters(ctx)))
+}
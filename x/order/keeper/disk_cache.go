@@ -175,6 +175,18 @@ func (c *DiskCache) GetNewDepthbookKeys() []string {
 
 // insertOrder inserts a new order into orderIDsMap
 func (c *DiskCache) insertOrder(order *types.Order) {
+	c.insertOrderIntoBook(order)
+	c.countNewOrder()
+}
+
+// countNewOrder counts a new order, the untriggered order is counted when placed but kept out of depth book
+func (c *DiskCache) countNewOrder() {
+	c.openNum++
+	c.storeOrderNum++
+}
+
+// insertOrderIntoBook inserts an order into depthBookMap and orderIDsMap without counting it
+func (c *DiskCache) insertOrderIntoBook(order *types.Order) {
 	// 1. update depthBookMap
 	depthBook, ok := c.depthBookMap.data[order.Product]
 	if !ok {
@@ -195,9 +207,6 @@ func (c *DiskCache) insertOrder(order *types.Order) {
 	orderIDs = append(orderIDs, order.OrderID)
 	orderIDsMap.Data[key] = orderIDs
 	c.orderIDsMap.updatedItems[key] = struct{}{}
-
-	c.openNum++
-	c.storeOrderNum++
 }
 
 func (c *DiskCache) closeOrder(orderID string) {
@@ -207,6 +216,28 @@ func (c *DiskCache) closeOrder(orderID string) {
 
 // remove an order from orderIDsMap when order cancelled/expired
 func (c *DiskCache) removeOrder(order *types.Order) {
+	c.removeOrderFromBook(order)
+	c.closeOrder(order.OrderID)
+}
+
+// replaceOrder moves the replaced order from its old price and quantity in depthBookMap and orderIDsMap. The order
+// keeps its place in orderIDsMap if keepPlace is true, otherwise it is queued at the end of its new price
+func (c *DiskCache) replaceOrder(oldOrder, order *types.Order, keepPlace bool) {
+	if !keepPlace {
+		c.removeOrderFromBook(oldOrder)
+		c.insertOrderIntoBook(order)
+		return
+	}
+	depthBook := c.getDepthBook(order.Product)
+	if depthBook != nil {
+		depthBook.RemoveOrder(oldOrder)
+		depthBook.InsertOrder(order)
+		c.setDepthBook(order.Product, depthBook)
+	}
+}
+
+// removeOrderFromBook removes an order from depthBookMap and orderIDsMap without closing it
+func (c *DiskCache) removeOrderFromBook(order *types.Order) {
 
 	// update depth book map
 	depthBook := c.getDepthBook(order.Product)
@@ -227,6 +258,4 @@ func (c *DiskCache) removeOrder(order *types.Order) {
 			break
 		}
 	}
-
-	c.closeOrder(order.OrderID)
 }
@@ -53,6 +53,142 @@ func (k Keeper) DropOrder(ctx sdk.Context, orderID string) {
 	store.Delete(types.GetOrderKey(orderID))
 }
 
+// ===============================================
+
+// SetImmediateOrderID records the market, IOC or FOK order whose remainder will be quit after its first match
+func (k Keeper) SetImmediateOrderID(ctx sdk.Context, orderID string) {
+	store := ctx.KVStore(k.orderStoreKey)
+	store.Set(types.GetImmediateOrderKey(orderID), []byte{})
+}
+
+// DropImmediateOrderID deletes the record of the immediate order
+func (k Keeper) DropImmediateOrderID(ctx sdk.Context, orderID string) {
+	store := ctx.KVStore(k.orderStoreKey)
+	store.Delete(types.GetImmediateOrderKey(orderID))
+}
+
+// GetImmediateOrderIDs returns the IDs of immediate orders which have not been quit
+func (k Keeper) GetImmediateOrderIDs(ctx sdk.Context) []string {
+	store := ctx.KVStore(k.orderStoreKey)
+	iter := sdk.KVStorePrefixIterator(store, types.ImmediateOrderKey)
+	defer iter.Close()
+
+	var orderIDs []string
+	for ; iter.Valid(); iter.Next() {
+		orderIDs = append(orderIDs, types.GetKey(iter))
+	}
+	return orderIDs
+}
+
+// SetPendingIncomingOrderIDs records the incoming orders of continuous auction left unmatched when the deal budget
+// of the block is used up, in their arrival sequence. They are matched first in the next block
+func (k Keeper) SetPendingIncomingOrderIDs(ctx sdk.Context, orderIDs []string) {
+	store := ctx.KVStore(k.orderStoreKey)
+	if len(orderIDs) == 0 {
+		store.Delete(types.PendingIncomingOrdersKey)
+		return
+	}
+	store.Set(types.PendingIncomingOrdersKey, k.cdc.MustMarshalBinaryBare(orderIDs))
+}
+
+// GetPendingIncomingOrderIDs returns the IDs of the incoming orders of continuous auction left unmatched in the
+// previous block
+func (k Keeper) GetPendingIncomingOrderIDs(ctx sdk.Context) []string {
+	store := ctx.KVStore(k.orderStoreKey)
+	var orderIDs []string
+	if bz := store.Get(types.PendingIncomingOrdersKey); bz != nil {
+		k.cdc.MustUnmarshalBinaryBare(bz, &orderIDs)
+	}
+	return orderIDs
+}
+
+// SetGTBOrderID records the GTB order which expires after the match of the expire height
+func (k Keeper) SetGTBOrderID(ctx sdk.Context, expireHeight int64, orderID string) {
+	store := ctx.KVStore(k.orderStoreKey)
+	store.Set(types.GetGTBOrderKey(expireHeight, orderID), []byte{})
+}
+
+// DropGTBOrderIDs deletes the records of GTB orders whose expire height is not greater than the specified height
+func (k Keeper) DropGTBOrderIDs(ctx sdk.Context, height int64) {
+	store := ctx.KVStore(k.orderStoreKey)
+	iter := store.Iterator(types.GTBOrderKey, types.GetGTBOrderHeightPrefix(height+1))
+	var keys [][]byte
+	for ; iter.Valid(); iter.Next() {
+		keys = append(keys, iter.Key())
+	}
+	iter.Close()
+
+	for _, key := range keys {
+		store.Delete(key)
+	}
+}
+
+// GetGTBOrderIDs returns the IDs of GTB orders whose expire height is not greater than the specified height,
+// sorted by expire height
+func (k Keeper) GetGTBOrderIDs(ctx sdk.Context, height int64) []string {
+	store := ctx.KVStore(k.orderStoreKey)
+	iter := store.Iterator(types.GTBOrderKey, types.GetGTBOrderHeightPrefix(height+1))
+	defer iter.Close()
+
+	prefixLen := len(types.GetGTBOrderHeightPrefix(height))
+	var orderIDs []string
+	for ; iter.Valid(); iter.Next() {
+		orderIDs = append(orderIDs, string(iter.Key()[prefixLen:]))
+	}
+	return orderIDs
+}
+
+// SetTriggerOrderID records the untriggered stop-loss or take-profit order
+func (k Keeper) SetTriggerOrderID(ctx sdk.Context, orderID string) {
+	store := ctx.KVStore(k.orderStoreKey)
+	store.Set(types.GetTriggerOrderKey(orderID), []byte{})
+}
+
+// DropTriggerOrderID deletes the record of the trigger order
+func (k Keeper) DropTriggerOrderID(ctx sdk.Context, orderID string) {
+	store := ctx.KVStore(k.orderStoreKey)
+	store.Delete(types.GetTriggerOrderKey(orderID))
+}
+
+// GetTriggerOrderIDs returns the IDs of the untriggered orders in the sequence they arrived
+func (k Keeper) GetTriggerOrderIDs(ctx sdk.Context) []string {
+	store := ctx.KVStore(k.orderStoreKey)
+	iter := sdk.KVStorePrefixIterator(store, types.TriggerOrderKey)
+	defer iter.Close()
+
+	var orderIDs []string
+	for ; iter.Valid(); iter.Next() {
+		orderIDs = append(orderIDs, types.GetKey(iter))
+	}
+	return orderIDs
+}
+
+// SetAccountOrderID records the open order of its sender
+func (k Keeper) SetAccountOrderID(ctx sdk.Context, addr sdk.AccAddress, orderID string) {
+	store := ctx.KVStore(k.orderStoreKey)
+	store.Set(types.GetAccountOrderKey(addr, orderID), []byte{})
+}
+
+// DropAccountOrderID deletes the record of the order which is no longer open
+func (k Keeper) DropAccountOrderID(ctx sdk.Context, addr sdk.AccAddress, orderID string) {
+	store := ctx.KVStore(k.orderStoreKey)
+	store.Delete(types.GetAccountOrderKey(addr, orderID))
+}
+
+// GetAccountOrderIDs returns the IDs of the open orders placed by the address
+func (k Keeper) GetAccountOrderIDs(ctx sdk.Context, addr sdk.AccAddress) []string {
+	store := ctx.KVStore(k.orderStoreKey)
+	iter := sdk.KVStorePrefixIterator(store, types.GetAccountOrderPrefix(addr))
+	defer iter.Close()
+
+	prefixLen := len(types.GetAccountOrderPrefix(addr))
+	var orderIDs []string
+	for ; iter.Valid(); iter.Next() {
+		orderIDs = append(orderIDs, string(iter.Key()[prefixLen:]))
+	}
+	return orderIDs
+}
+
 // ===============================================
 // nolint
 func (k Keeper) StoreDepthBook(ctx sdk.Context, product string, depthBook *types.DepthBook) {
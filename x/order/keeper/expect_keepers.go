@@ -19,6 +19,7 @@ type TokenKeeper interface {
 	SendCoinsFromAccountToAccount(ctx sdk.Context, from, to sdk.AccAddress, amt sdk.DecCoins) error
 	// Fee detail
 	AddFeeDetail(ctx sdk.Context, from string, fee sdk.DecCoins, feeType string)
+	AddFeeDetailWithRate(ctx sdk.Context, from string, fee sdk.DecCoins, feeType, feeRate string)
 	GetAllLockedCoins(ctx sdk.Context) (locks []token.AccCoins)
 	IterateLockedFees(ctx sdk.Context, cb func(acc sdk.AccAddress, coins sdk.DecCoins) (stop bool))
 }
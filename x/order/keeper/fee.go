@@ -46,9 +46,9 @@ func GetZeroFee() sdk.DecCoins {
 	return sdk.DecCoins{sdk.ZeroFee()}
 }
 
-// GetDealFee is used to calculate the handling fee when matching an order
+// GetDealFee is used to calculate the handling fee at the fee rate when matching an order
 func GetDealFee(order *types.Order, fillAmt sdk.Dec, ctx sdk.Context, keeper GetFeeKeeper,
-	feeParams *types.Params) sdk.DecCoins {
+	feeRate sdk.Dec) sdk.DecCoins {
 	symbols := strings.Split(order.Product, "_")
 	symbol := symbols[0]
 	quantity := fillAmt
@@ -57,7 +57,7 @@ func GetDealFee(order *types.Order, fillAmt sdk.Dec, ctx sdk.Context, keeper Get
 		quantity = fillAmt.Mul(keeper.GetLastPrice(ctx, order.Product))
 	}
 
-	feeAmt := quantity.Mul(feeParams.TradeFeeRate)
+	feeAmt := quantity.Mul(feeRate)
 	if feeAmt.IsPositive() {
 		return sdk.DecCoins{sdk.NewDecCoinFromDec(symbol, feeAmt)}
 	}
@@ -0,0 +1,85 @@
+package keeper
+
+import (
+	"fmt"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/okex/okchain/x/order/types"
+	token "github.com/okex/okchain/x/token/types"
+)
+
+// FillOrder fills an order with the specified price and quantity. It updates the order, charges fee and
+// transfers tokens, emits the fill event, then returns a deal. If the order is fully filled but still locks some
+// coins, unlock them. The maker order, which provided liquidity, is charged at the maker fee rate, otherwise the taker fee rate.
+func (k Keeper) FillOrder(ctx sdk.Context, order *types.Order, fillPrice, fillQuantity sdk.Dec,
+	feeParams *types.Params, isMaker bool) *types.Deal {
+
+	// update order
+	order.Fill(fillPrice, fillQuantity)
+
+	k.balanceOrderAccount(ctx, order, fillPrice, fillQuantity)
+	// if fully filled and still need unlock coins
+	if order.Status == types.OrderStatusFilled && order.RemainLocked.IsPositive() {
+		needUnlockCoins := order.NeedUnlockCoins()
+		k.UnlockCoins(ctx, order.Sender, needUnlockCoins, token.LockCoinsTypeQuantity)
+		order.Unlock()
+	}
+
+	dealFee := k.chargeOrderFee(ctx, order, fillQuantity, feeParams, isMaker)
+	k.AddTradeVolume(ctx, order.Sender, order.Product, fillPrice.Mul(fillQuantity))
+
+	k.UpdateOrder(order, ctx) // update order info on filled
+
+	deal := &types.Deal{OrderID: order.OrderID, Side: order.Side, Price: fillPrice, Quantity: fillQuantity,
+		Fee: dealFee.String()}
+	ctx.EventManager().EmitEvent(types.NewOrderFillEvent(order, deal))
+	return deal
+}
+
+// balanceOrderAccount transfers the tokens of a filled order
+func (k Keeper) balanceOrderAccount(ctx sdk.Context, order *types.Order, fillPrice, fillQuantity sdk.Dec) {
+	symbols := strings.Split(order.Product, "_")
+	// transfer tokens
+	var outputCoins, inputCoins sdk.DecCoins
+	if order.Side == types.BuyOrder {
+		outputCoins = sdk.DecCoins{{Denom: symbols[1], Amount: fillPrice.Mul(fillQuantity)}}
+		inputCoins = sdk.DecCoins{{Denom: symbols[0], Amount: fillQuantity}}
+	} else {
+		outputCoins = sdk.DecCoins{{Denom: symbols[0], Amount: fillQuantity}}
+		inputCoins = sdk.DecCoins{{Denom: symbols[1], Amount: fillPrice.Mul(fillQuantity)}}
+	}
+	k.BalanceAccount(ctx, order.Sender, outputCoins, inputCoins)
+}
+
+// chargeOrderFee charges the deal fee of a filled order, and settles the locked new-order fee
+// when the order is fully filled
+func (k Keeper) chargeOrderFee(ctx sdk.Context, order *types.Order, fillQuantity sdk.Dec,
+	feeParams *types.Params, isMaker bool) sdk.DecCoins {
+	// charge fee
+	fee := GetZeroFee()
+	if order.Status == types.OrderStatusFilled {
+		lockedFee := GetOrderNewFee(order)
+		fee = GetOrderCostFee(order, ctx)
+		receiveFee := lockedFee.Sub(fee)
+
+		k.UnlockCoins(ctx, order.Sender, lockedFee, token.LockCoinsTypeFee)
+		k.AddFeeDetail(ctx, order.Sender, receiveFee, types.FeeTypeOrderReceive, order.FeePerBlock.String())
+		order.RecordOrderReceiveFee(receiveFee)
+
+		err := k.AddCollectedFees(ctx, fee, order.Sender, types.FeeTypeOrderNew, false)
+		if err != nil {
+			ctx.Logger().Error(fmt.Sprintf("Send fee failed:%s\n", err.Error()))
+		}
+	}
+	feeRate := k.GetDealFeeRate(ctx, order, isMaker, feeParams)
+	dealFee := GetDealFee(order, fillQuantity, ctx, k, feeRate)
+	err := k.SendFeesToProductOwner(ctx, dealFee, order.Sender, types.FeeTypeOrderDeal, order.Product,
+		feeRate.String())
+	if err == nil {
+		order.RecordOrderDealFee(fee)
+	}
+
+	return dealFee
+}
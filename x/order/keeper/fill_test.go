@@ -0,0 +1,109 @@
+package keeper
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/okex/okchain/x/dex"
+	"github.com/okex/okchain/x/order/types"
+)
+
+func TestFillOrder(t *testing.T) {
+	testInput := CreateTestInput(t)
+	keeper := testInput.OrderKeeper
+	ctx := testInput.Ctx
+	tokenPair := dex.GetBuiltInTokenPair()
+	err := testInput.DexKeeper.SaveTokenPair(ctx, tokenPair)
+	require.Nil(t, err)
+
+	// mock orders, DepthBook, and orderIDsMap
+	orders := []*types.Order{
+		mockOrder("", types.TestTokenPair, types.BuyOrder, "10.1", "1.0"),
+		mockOrder("", types.TestTokenPair, types.BuyOrder, "10.1", "2.0"),
+		mockOrder("", types.TestTokenPair, types.SellOrder, "9.9", "3.0"),
+		mockOrder("", types.TestTokenPair, types.SellOrder, "10.2", "1.0"),
+	}
+	orders[0].Sender = testInput.TestAddrs[0]
+	orders[1].Sender = testInput.TestAddrs[0]
+	orders[2].Sender = testInput.TestAddrs[1]
+	orders[3].Sender = testInput.TestAddrs[1]
+
+	for i := 0; i < 4; i++ {
+		err := keeper.PlaceOrder(ctx, orders[i])
+		require.EqualValues(t, nil, err)
+	}
+
+	fillPrice := sdk.NewDec(10.0)
+	fillQuantity := sdk.NewDec(1.0)
+	feeParams := types.DefaultParams()
+
+	for _, order := range orders {
+		retDeals := keeper.FillOrder(ctx, order, fillPrice, fillQuantity, &feeParams, false)
+		require.NotEmpty(t, retDeals)
+	}
+}
+
+func TestBalanceOrderAccount(t *testing.T) {
+	testInput := CreateTestInput(t)
+	keeper := testInput.OrderKeeper
+	ctx := testInput.Ctx
+	tokenPair := dex.GetBuiltInTokenPair()
+	err := testInput.DexKeeper.SaveTokenPair(ctx, tokenPair)
+	require.Nil(t, err)
+
+	// mock orders, DepthBook, and orderIDsMap
+	orders := []*types.Order{
+		mockOrder("", types.TestTokenPair, types.BuyOrder, "10.1", "1.0"),
+		mockOrder("", types.TestTokenPair, types.BuyOrder, "10.1", "2.0"),
+		mockOrder("", types.TestTokenPair, types.SellOrder, "9.9", "3.0"),
+		mockOrder("", types.TestTokenPair, types.SellOrder, "10.2", "1.0"),
+	}
+	orders[0].Sender = testInput.TestAddrs[0]
+	orders[1].Sender = testInput.TestAddrs[0]
+	orders[2].Sender = testInput.TestAddrs[1]
+	orders[3].Sender = testInput.TestAddrs[1]
+
+	for i := 0; i < 4; i++ {
+		err := keeper.PlaceOrder(ctx, orders[i])
+		require.EqualValues(t, nil, err)
+	}
+
+	fillPrice := sdk.NewDec(10.0)
+	fillQuantity := sdk.NewDec(1.0)
+	for _, order := range orders {
+		keeper.balanceOrderAccount(ctx, order, fillPrice, fillQuantity)
+	}
+}
+
+func TestChargeOrderFee(t *testing.T) {
+	testInput := CreateTestInput(t)
+	keeper := testInput.OrderKeeper
+	ctx := testInput.Ctx
+	tokenPair := dex.GetBuiltInTokenPair()
+	err := testInput.DexKeeper.SaveTokenPair(ctx, tokenPair)
+	require.Nil(t, err)
+
+	keeper.ResetCache(ctx)
+	orders := []*types.Order{
+		mockOrder("", types.TestTokenPair, types.BuyOrder, "9.9", "1.0"),
+		mockOrder("", types.TestTokenPair, types.BuyOrder, "10.0", "1.0"),
+		mockOrder("", types.TestTokenPair, types.SellOrder, "10.0", "1.1"),
+		mockOrder("", types.TestTokenPair, types.SellOrder, "10.1", "1.1"),
+	}
+	orders[0].Sender = testInput.TestAddrs[0]
+	orders[1].Sender = testInput.TestAddrs[0]
+	orders[1].Status = types.OrderStatusFilled
+	orders[2].Sender = testInput.TestAddrs[1]
+	orders[2].Status = types.OrderStatusFilled
+	orders[3].Sender = testInput.TestAddrs[1]
+
+	fillQuantity := sdk.NewDec(1.0)
+	feeParams := types.DefaultParams()
+
+	for _, order := range orders {
+		retFee := keeper.chargeOrderFee(ctx, order, fillQuantity, &feeParams, false)
+		require.NotEmpty(t, retFee)
+	}
+}
@@ -2,6 +2,7 @@ package keeper
 
 import (
 	"fmt"
+	"sort"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/okex/okchain/x/order/types"
@@ -11,6 +12,9 @@ import (
 // RegisterInvariants registers all order invariants
 func RegisterInvariants(ir sdk.InvariantRegistry, keeper Keeper) {
 	ir.RegisterRoute(types.ModuleName, "module-account", ModuleAccountInvariant(keeper))
+	ir.RegisterRoute(types.ModuleName, "depth-book", DepthBookInvariant(keeper))
+	ir.RegisterRoute(types.ModuleName, "order-locks", OrderLockInvariant(keeper))
+	ir.RegisterRoute(types.ModuleName, "order-ids", OrderIDsInvariant(keeper))
 }
 
 // ModuleAccountInvariant checks that the module account coins reflects the sum of
@@ -58,3 +62,137 @@ func ModuleAccountInvariant(keeper Keeper) sdk.Invariant {
 				macc.GetCoins(), lockedCoins.Add(lockedFees))), broken
 	}
 }
+
+// DepthBookInvariant checks that the buy and sell quantities of each price level in depth book equal the sum of the
+// remaining quantities of the orders listed under that price
+func DepthBookInvariant(keeper Keeper) sdk.Invariant {
+	return func(ctx sdk.Context) (string, bool) {
+		var msg string
+		var count int
+
+		products := keeper.GetProductsFromDepthBookMap()
+		sort.Strings(products)
+		for _, product := range products {
+			depthBook := keeper.GetDepthBookCopy(product)
+			for _, item := range depthBook.Items {
+				for _, side := range []string{types.BuyOrder, types.SellOrder} {
+					bookQuantity := item.BuyQuantity
+					if side == types.SellOrder {
+						bookQuantity = item.SellQuantity
+					}
+
+					key := types.FormatOrderIDsKey(product, item.Price, side)
+					orderQuantity := sdk.ZeroDec()
+					for _, orderID := range keeper.GetProductPriceOrderIDs(key) {
+						if order := keeper.GetOrder(ctx, orderID); order != nil {
+							orderQuantity = orderQuantity.Add(order.RemainQuantity)
+						}
+					}
+
+					if !bookQuantity.Equal(orderQuantity) {
+						count++
+						msg += fmt.Sprintf("\t%s: depth book quantity %s, sum of order remain quantities %s\n",
+							key, bookQuantity, orderQuantity)
+					}
+				}
+			}
+		}
+
+		broken := count != 0
+		return sdk.FormatInvariant(types.ModuleName, "depth-book",
+			fmt.Sprintf("%d mismatched depth book levels found\n%s", count, msg)), broken
+	}
+}
+
+// OrderLockInvariant checks that the locked coins of each account in token equal the sum of the remaining locked
+// coins of its open and untriggered orders. The open orders are the ones listed in depth book, and the untriggered
+// ones are the trigger orders, so the closed orders kept in store are never visited
+func OrderLockInvariant(keeper Keeper) sdk.Invariant {
+	return func(ctx sdk.Context) (string, bool) {
+		var orderIDs []string
+		products := keeper.GetProductsFromDepthBookMap()
+		sort.Strings(products)
+		for _, product := range products {
+			depthBook := keeper.GetDepthBookCopy(product)
+			for _, item := range depthBook.Items {
+				buyKey := types.FormatOrderIDsKey(product, item.Price, types.BuyOrder)
+				orderIDs = append(orderIDs, keeper.GetProductPriceOrderIDs(buyKey)...)
+				sellKey := types.FormatOrderIDsKey(product, item.Price, types.SellOrder)
+				orderIDs = append(orderIDs, keeper.GetProductPriceOrderIDs(sellKey)...)
+			}
+		}
+		orderIDs = append(orderIDs, keeper.GetTriggerOrderIDs(ctx)...)
+
+		orderLocks := make(map[string]sdk.DecCoins)
+		for _, orderID := range orderIDs {
+			order := keeper.GetOrder(ctx, orderID)
+			if order != nil && (order.Status == types.OrderStatusOpen || order.IsUntriggered()) {
+				addr := order.Sender.String()
+				orderLocks[addr] = orderLocks[addr].Add(order.NeedUnlockCoins())
+			}
+		}
+
+		tokenLocks := make(map[string]sdk.DecCoins)
+		for _, accCoins := range keeper.tokenKeeper.GetAllLockedCoins(ctx) {
+			tokenLocks[accCoins.Acc.String()] = accCoins.Coins
+		}
+
+		var addrs []string
+		for addr := range tokenLocks {
+			addrs = append(addrs, addr)
+		}
+		for addr := range orderLocks {
+			if _, ok := tokenLocks[addr]; !ok {
+				addrs = append(addrs, addr)
+			}
+		}
+		sort.Strings(addrs)
+
+		var msg string
+		var count int
+		for _, addr := range addrs {
+			// compare by difference, since IsEqual panics on mismatched denominations
+			diff, isNegative := tokenLocks[addr].SafeSub(orderLocks[addr])
+			if isNegative || !diff.IsZero() {
+				count++
+				msg += fmt.Sprintf("\t%s: token locked coins %s, sum of order remain locked %s\n",
+					addr, tokenLocks[addr], orderLocks[addr])
+			}
+		}
+
+		broken := count != 0
+		return sdk.FormatInvariant(types.ModuleName, "order-locks",
+			fmt.Sprintf("%d accounts with mismatched locks found\n%s", count, msg)), broken
+	}
+}
+
+// OrderIDsInvariant checks that no closed order is still listed under a price in depth book
+func OrderIDsInvariant(keeper Keeper) sdk.Invariant {
+	return func(ctx sdk.Context) (string, bool) {
+		orderIDsMap := keeper.diskCache.GetOrderIDsMapCopy()
+		var keys []string
+		for key := range orderIDsMap.Data {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		var msg string
+		var count int
+		for _, key := range keys {
+			for _, orderID := range orderIDsMap.Data[key] {
+				order := keeper.GetOrder(ctx, orderID)
+				if order == nil {
+					count++
+					msg += fmt.Sprintf("\t%s: order %s not found\n", key, orderID)
+				} else if order.Status != types.OrderStatusOpen {
+					count++
+					msg += fmt.Sprintf("\t%s: order %s with status %d\n", key, orderID, order.Status)
+				}
+			}
+		}
+
+		broken := count != 0
+		return sdk.FormatInvariant(types.ModuleName, "order-ids",
+			fmt.Sprintf("%d closed orders found in order id lists\n%s", count, msg)), broken
+	}
+}
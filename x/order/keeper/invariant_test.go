@@ -94,3 +94,127 @@ func invariantMsg(lockCoins sdk.DecCoins) string {
 		fmt.Sprintf("\ttoken ModuleAccount coins: %s\n\tsum of locks amounts:  %s\n",
 			lockCoins, lockCoins))
 }
+
+func TestDepthBookInvariant(t *testing.T) {
+	testInput := CreateTestInput(t)
+	keeper := testInput.OrderKeeper
+	ctx := testInput.Ctx.WithBlockHeight(10)
+	invariant := DepthBookInvariant(keeper)
+
+	err := testInput.DexKeeper.SaveTokenPair(ctx, dex.GetBuiltInTokenPair())
+	require.NoError(t, err)
+
+	order1 := mockOrder("", types.TestTokenPair, types.BuyOrder, "10.0", "1.0")
+	order1.Sender = testInput.TestAddrs[0]
+	require.NoError(t, keeper.PlaceOrder(ctx, order1))
+	order2 := mockOrder("", types.TestTokenPair, types.BuyOrder, "10.0", "2.0")
+	order2.Sender = testInput.TestAddrs[1]
+	require.NoError(t, keeper.PlaceOrder(ctx, order2))
+	order3 := mockOrder("", types.TestTokenPair, types.SellOrder, "20.0", "3.0")
+	order3.Sender = testInput.TestAddrs[0]
+	require.NoError(t, keeper.PlaceOrder(ctx, order3))
+
+	_, broken := invariant(ctx)
+	require.False(t, broken)
+
+	keeper.CancelOrder(ctx, order1, ctx.Logger())
+	_, broken = invariant(ctx)
+	require.False(t, broken)
+
+	// error case: the quantity of a price level drifts from its orders
+	depthBook := keeper.GetDepthBookCopy(types.TestTokenPair)
+	depthBook.Items[0].SellQuantity = depthBook.Items[0].SellQuantity.Add(sdk.OneDec())
+	keeper.SetDepthBook(types.TestTokenPair, depthBook)
+	msg, broken := invariant(ctx)
+	require.True(t, broken)
+	require.Contains(t, msg, types.FormatOrderIDsKey(types.TestTokenPair, order3.Price, types.SellOrder))
+}
+
+func TestOrderLockInvariant(t *testing.T) {
+	testInput := CreateTestInput(t)
+	keeper := testInput.OrderKeeper
+	ctx := testInput.Ctx.WithBlockHeight(10)
+	invariant := OrderLockInvariant(keeper)
+
+	err := testInput.DexKeeper.SaveTokenPair(ctx, dex.GetBuiltInTokenPair())
+	require.NoError(t, err)
+
+	_, broken := invariant(ctx)
+	require.False(t, broken)
+
+	order1 := mockOrder("", types.TestTokenPair, types.BuyOrder, "10.0", "1.0")
+	order1.Sender = testInput.TestAddrs[0]
+	require.NoError(t, keeper.PlaceOrder(ctx, order1))
+	order2 := mockOrder("", types.TestTokenPair, types.SellOrder, "20.0", "3.0")
+	order2.Sender = testInput.TestAddrs[0]
+	require.NoError(t, keeper.PlaceOrder(ctx, order2))
+	order3 := mockOrder("", types.TestTokenPair, types.SellOrder, "20.0", "2.0")
+	order3.Sender = testInput.TestAddrs[0]
+	require.NoError(t, keeper.PlaceOrder(ctx, order3))
+	// the untriggered order is kept out of depth book, while its coins are locked
+	order4 := mockOrder("", types.TestTokenPair, types.BuyOrder, "5.0", "1.0")
+	order4.Sender = testInput.TestAddrs[0]
+	order4.Status = types.OrderStatusUntriggered
+	order4.Trigger = &types.OrderTrigger{Type: types.TriggerTypeStopLoss, Price: sdk.MustNewDecFromStr("6.0")}
+	require.NoError(t, keeper.PlaceOrder(ctx, order4))
+
+	_, broken = invariant(ctx)
+	require.False(t, broken)
+
+	ctx = ctx.WithBlockHeight(11)
+	keeper.CancelOrder(ctx, order2, ctx.Logger())
+	_, broken = invariant(ctx)
+	require.False(t, broken)
+
+	// error case: coins locked without any open order
+	lockCoins := sdk.MustParseCoins(sdk.DefaultBondDenom, "1")
+	err = keeper.tokenKeeper.LockCoins(ctx, testInput.TestAddrs[1], lockCoins, token.LockCoinsTypeQuantity)
+	require.NoError(t, err)
+	msg, broken := invariant(ctx)
+	require.True(t, broken)
+	require.Contains(t, msg, testInput.TestAddrs[1].String())
+
+	err = keeper.tokenKeeper.UnlockCoins(ctx, testInput.TestAddrs[1], lockCoins, token.LockCoinsTypeQuantity)
+	require.NoError(t, err)
+	_, broken = invariant(ctx)
+	require.False(t, broken)
+
+	// error case: the remaining locked coins of an order drift from token
+	order3.RemainLocked = order3.RemainLocked.Sub(sdk.OneDec())
+	keeper.SetOrder(ctx, order3.OrderID, order3)
+	msg, broken = invariant(ctx)
+	require.True(t, broken)
+	require.Contains(t, msg, testInput.TestAddrs[0].String())
+}
+
+func TestOrderIDsInvariant(t *testing.T) {
+	testInput := CreateTestInput(t)
+	keeper := testInput.OrderKeeper
+	ctx := testInput.Ctx.WithBlockHeight(10)
+	invariant := OrderIDsInvariant(keeper)
+
+	err := testInput.DexKeeper.SaveTokenPair(ctx, dex.GetBuiltInTokenPair())
+	require.NoError(t, err)
+
+	order1 := mockOrder("", types.TestTokenPair, types.BuyOrder, "10.0", "1.0")
+	order1.Sender = testInput.TestAddrs[0]
+	require.NoError(t, keeper.PlaceOrder(ctx, order1))
+	order2 := mockOrder("", types.TestTokenPair, types.SellOrder, "20.0", "3.0")
+	order2.Sender = testInput.TestAddrs[0]
+	require.NoError(t, keeper.PlaceOrder(ctx, order2))
+
+	_, broken := invariant(ctx)
+	require.False(t, broken)
+
+	ctx = ctx.WithBlockHeight(11)
+	keeper.ExpireOrder(ctx, order1, ctx.Logger())
+	_, broken = invariant(ctx)
+	require.False(t, broken)
+
+	// error case: a closed order is left in its price list
+	order2.Status = types.OrderStatusCancelled
+	keeper.SetOrder(ctx, order2.OrderID, order2)
+	msg, broken := invariant(ctx)
+	require.True(t, broken)
+	require.Contains(t, msg, order2.OrderID)
+}
@@ -1,6 +1,7 @@
 package keeper
 
 import (
+	"encoding/binary"
 	"log"
 
 	"github.com/okex/okchain/x/common/monitor"
@@ -127,6 +128,9 @@ type OrderOperationMetric struct {
 	CancelNum      int64
 	ExpireNum      int64
 	PartialFillNum int64
+
+	LockedProductNum int64
+	MaxLockedBlocks  int64
 }
 
 // GetOperationMetric gets OperationMetric from keeper
@@ -137,6 +141,9 @@ func (k Keeper) GetOperationMetric() OrderOperationMetric {
 		CancelNum:      k.cache.GetCancelNum(),
 		ExpireNum:      k.cache.GetExpireNum(),
 		PartialFillNum: k.cache.GetPartialFillNum(),
+
+		LockedProductNum: k.cache.GetLockedProductNum(),
+		MaxLockedBlocks:  k.cache.GetMaxLockedBlocks(),
 	}
 }
 
@@ -172,6 +179,23 @@ func (k Keeper) GetExpireBlockHeight(ctx sdk.Context, blockHeight int64) []int64
 	return expireBlockNumbers
 }
 
+// GetExpireBlockHeights returns all the records of the block heights whose orders expire at a block height
+func (k Keeper) GetExpireBlockHeights(ctx sdk.Context) []*types.ExpireBlockHeight {
+	store := ctx.KVStore(k.orderStoreKey)
+	iter := sdk.KVStorePrefixIterator(store, types.ExpireBlockHeightKey)
+	defer iter.Close()
+
+	var expireHeights []*types.ExpireBlockHeight
+	for ; iter.Valid(); iter.Next() {
+		expireHeight := &types.ExpireBlockHeight{
+			BlockHeight: int64(binary.BigEndian.Uint64(iter.Key()[len(types.ExpireBlockHeightKey):])),
+		}
+		k.cdc.MustUnmarshalBinaryBare(iter.Value(), &expireHeight.ExpireBlockHeights)
+		expireHeights = append(expireHeights, expireHeight)
+	}
+	return expireHeights
+}
+
 // GetOrder gets order from KVStore
 func (k Keeper) GetOrder(ctx sdk.Context, orderID string) *types.Order {
 	store := ctx.KVStore(k.orderStoreKey)
@@ -210,6 +234,21 @@ func (k Keeper) GetLastPrice(ctx sdk.Context, product string) sdk.Dec {
 	return price
 }
 
+// GetLastPrices returns the last prices of the products in KVStore
+func (k Keeper) GetLastPrices(ctx sdk.Context) []*types.LastPrice {
+	store := ctx.KVStore(k.orderStoreKey)
+	iter := sdk.KVStorePrefixIterator(store, types.PriceKey)
+	defer iter.Close()
+
+	var prices []*types.LastPrice
+	for ; iter.Valid(); iter.Next() {
+		price := &types.LastPrice{Product: types.GetKey(iter)}
+		k.cdc.MustUnmarshalBinaryBare(iter.Value(), &price.Price)
+		prices = append(prices, price)
+	}
+	return prices
+}
+
 // GetDepthBookCopy gets depth book copy from cache, you are supposed to update the Depthbook if you change it
 // create if not exist
 func (k Keeper) GetDepthBookCopy(product string) *types.DepthBook {
@@ -272,6 +311,22 @@ func (k Keeper) GetBlockOrderNum(ctx sdk.Context, blockHeight int64) int64 {
 	return common.BytesToInt64(numBytes)
 }
 
+// GetBlockOrderNums returns the order numbers of all the block heights in KVStore
+func (k Keeper) GetBlockOrderNums(ctx sdk.Context) []*types.BlockOrderNum {
+	store := ctx.KVStore(k.orderStoreKey)
+	iter := sdk.KVStorePrefixIterator(store, types.OrderNumPerBlockKey)
+	defer iter.Close()
+
+	var nums []*types.BlockOrderNum
+	for ; iter.Valid(); iter.Next() {
+		nums = append(nums, &types.BlockOrderNum{
+			BlockHeight: int64(binary.BigEndian.Uint64(iter.Key()[len(types.OrderNumPerBlockKey):])),
+			OrderNum:    common.BytesToInt64(iter.Value()),
+		})
+	}
+	return nums
+}
+
 // GetLastExpiredBlockHeight gets LastExpiredBlockHeight from KVStore
 // LastExpiredBlockHeight means that the block height of his expired height
 // list has been processed by expired recently
@@ -316,6 +371,11 @@ func (k Keeper) GetUpdatedOrderIDs() []string {
 	return k.cache.getUpdatedOrderIDs()
 }
 
+// GetReplacedOrderIDs gets the ids of the orders replaced in current block which lost their place in the queue
+func (k Keeper) GetReplacedOrderIDs() []string {
+	return k.cache.getReplacedOrderIDs()
+}
+
 // nolint
 func (k Keeper) addUpdatedOrderID(orderID string) {
 	if k.enableBackend {
@@ -347,6 +407,21 @@ func (k Keeper) SetBlockMatchResult(result *types.BlockMatchResult) {
 	}
 }
 
+// GetBlockRemainDeals returns the deals left of the MaxDealsPerBlock budget of current block, which is shared by
+// the match engines
+func (k Keeper) GetBlockRemainDeals(ctx sdk.Context) int64 {
+	remainDeals := k.GetParams(ctx).MaxDealsPerBlock - k.cache.getDealNum(ctx.BlockHeight())
+	if remainDeals < 0 {
+		return 0
+	}
+	return remainDeals
+}
+
+// AddBlockDealNum spends num deals of the MaxDealsPerBlock budget of current block
+func (k Keeper) AddBlockDealNum(ctx sdk.Context, num int64) {
+	k.cache.addDealNum(ctx.BlockHeight(), num)
+}
+
 // LockCoins locks coins from the specified address,
 func (k Keeper) LockCoins(ctx sdk.Context, addr sdk.AccAddress, coins sdk.DecCoins, lockCoinsType int) error {
 	return k.tokenKeeper.LockCoins(ctx, addr, coins, lockCoinsType)
@@ -379,20 +454,20 @@ func (k Keeper) GetProductOwner(ctx sdk.Context, product string) sdk.AccAddress
 	return tokenPair.Owner
 }
 
-// AddFeeDetail adds detail message of fee to tokenKeeper
+// AddFeeDetail adds detail message of fee to tokenKeeper, with the fee rate applied
 func (k Keeper) AddFeeDetail(ctx sdk.Context, from sdk.AccAddress, coins sdk.DecCoins,
-	feeType string) {
-	k.tokenKeeper.AddFeeDetail(ctx, from.String(), coins, feeType)
+	feeType string, feeRate string) {
+	k.tokenKeeper.AddFeeDetailWithRate(ctx, from.String(), coins, feeType, feeRate)
 }
 
 // SendFeesToProductOwner sends fees from the specified address to productOwner
 func (k Keeper) SendFeesToProductOwner(ctx sdk.Context, coins sdk.DecCoins, from sdk.AccAddress,
-	feeType string, product string) error {
+	feeType string, product string, feeRate string) error {
 	if coins.IsZero() {
 		return nil
 	}
 	to := k.GetProductOwner(ctx, product)
-	k.tokenKeeper.AddFeeDetail(ctx, from.String(), coins, feeType)
+	k.tokenKeeper.AddFeeDetailWithRate(ctx, from.String(), coins, feeType, feeRate)
 	if err := k.tokenKeeper.SendCoinsFromAccountToAccount(ctx, from, to, coins); err != nil {
 		log.Printf("Send fee(%s) to address(%s) failed\n", coins.String(), to.String())
 		return err
@@ -446,6 +521,8 @@ func (k Keeper) SetMetric() {
 	k.metric.CanceledNum.Set(float64(k.cache.cancelNum))
 	k.metric.ExpiredNum.Set(float64(k.cache.expireNum))
 	k.metric.PartialFilledNum.Set(float64(k.cache.partialFillNum))
+	k.metric.LockedProductNum.Set(float64(k.cache.lockedProductNum))
+	k.metric.MaxLockedBlocks.Set(float64(k.cache.maxLockedBlocks))
 }
 
 // GetBestBidAndAsk gets the highest bidPrice and the lowest askPrice from depthBook
@@ -469,16 +546,52 @@ func (k Keeper) GetBestBidAndAsk(ctx sdk.Context, product string) (sdk.Dec, sdk.
 	return bestBid, bestAsk
 }
 
+// GetMarketOrderRefPrice returns the reference price of a market order: the lowest ask for buy orders and the
+// highest bid for sell orders in depth book. If the opposite side is empty, returns the last price
+func (k Keeper) GetMarketOrderRefPrice(ctx sdk.Context, product string, side string) sdk.Dec {
+	// items in depth book are sorted by price desc
+	book := k.GetDepthBookCopy(product)
+	if side == types.BuyOrder {
+		for i := len(book.Items) - 1; i >= 0; i-- {
+			if book.Items[i].SellQuantity.IsPositive() {
+				return book.Items[i].Price
+			}
+		}
+	} else {
+		for _, item := range book.Items {
+			if item.BuyQuantity.IsPositive() {
+				return item.Price
+			}
+		}
+	}
+	return k.GetLastPrice(ctx, product)
+}
+
 // RemoveOrderFromDepthBook removes order from depthBook, and updates cancelNum, expireNum, updatedOrderIDs from cache
 func (k Keeper) RemoveOrderFromDepthBook(order *types.Order, feeType string) {
 	k.addUpdatedOrderID(order.OrderID)
-	if feeType == types.FeeTypeOrderCancel {
+	k.increaseQuitNum(feeType)
+
+	k.diskCache.removeOrder(order)
+}
+
+// RemoveUntriggeredOrder removes the quit order which has not been triggered, it is not in depth book
+func (k Keeper) RemoveUntriggeredOrder(ctx sdk.Context, order *types.Order, feeType string) {
+	k.addUpdatedOrderID(order.OrderID)
+	k.increaseQuitNum(feeType)
+
+	k.DropTriggerOrderID(ctx, order.OrderID)
+	k.diskCache.closeOrder(order.OrderID)
+}
+
+func (k Keeper) increaseQuitNum(feeType string) {
+	switch feeType {
+	case types.FeeTypeOrderCancel, types.FeeTypeOrderIOCCancel, types.FeeTypeOrderFOKKill,
+		types.FeeTypeOrderPostOnlyReject, types.FeeTypeOrderSelfTrade:
 		k.cache.IncreaseCancelNum()
-	} else if feeType == types.FeeTypeOrderExpire {
+	case types.FeeTypeOrderExpire, types.FeeTypeOrderGTBExpire:
 		k.cache.IncreaseExpireNum()
 	}
-
-	k.diskCache.removeOrder(order)
 }
 
 // nolint
@@ -488,6 +601,7 @@ func (k Keeper) UpdateOrder(order *types.Order, ctx sdk.Context) {
 	// record updated orderID
 	k.addUpdatedOrderID(order.OrderID)
 	if order.Status == types.OrderStatusFilled {
+		k.DropAccountOrderID(ctx, order.Sender, order.OrderID)
 		k.diskCache.closeOrder(order.OrderID)
 		k.cache.IncreaseFullFillNum()
 	} else {
@@ -500,6 +614,23 @@ func (k Keeper) InsertOrderIntoDepthBook(order *types.Order) {
 	k.diskCache.insertOrder(order)
 }
 
+// FilterProductsByMatchMode returns the products which are matched by the specified match mode
+func (k Keeper) FilterProductsByMatchMode(ctx sdk.Context, products []string, matchMode string) []string {
+	var matchedProducts []string
+	for _, product := range products {
+		if k.IsMatchedBy(ctx, product, matchMode) {
+			matchedProducts = append(matchedProducts, product)
+		}
+	}
+	return matchedProducts
+}
+
+// IsMatchedBy returns true if the product exists and is matched by the specified match mode
+func (k Keeper) IsMatchedBy(ctx sdk.Context, product string, matchMode string) bool {
+	tokenPair := k.dexKeeper.GetTokenPair(ctx, product)
+	return tokenPair != nil && tokenPair.GetMatchMode() == matchMode
+}
+
 // FilterDelistedProducts deletes non-existent products from the specified products
 func (k Keeper) FilterDelistedProducts(ctx sdk.Context, products []string) []string {
 	var cleanProducts []string
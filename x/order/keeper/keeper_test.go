@@ -329,7 +329,8 @@ func TestKeeper_SendFeesToProductOwner(t *testing.T) {
 	dealFee := sdk.DecCoins{{Denom: common.NativeToken, Amount: sdk.MustNewDecFromStr("0.2592")}}
 	require.EqualValues(t, fee, dealFee)
 
-	err = keeper.SendFeesToProductOwner(ctx, dealFee, order.Sender, types.FeeTypeOrderDeal, order.Product)
+	err = keeper.SendFeesToProductOwner(ctx, dealFee, order.Sender, types.FeeTypeOrderDeal, order.Product,
+		types.DefaultFeeRateTrade)
 	require.Nil(t, err)
 }
 
@@ -375,3 +376,20 @@ func TestFilterDelistedProducts(t *testing.T) {
 	cleanProducts := keeper.FilterDelistedProducts(ctx, productsList)
 	require.EqualValues(t, expectedProductsList, cleanProducts)
 }
+
+func TestValidateParamsChange(t *testing.T) {
+	testInput := CreateTestInput(t)
+	keeper := testInput.OrderKeeper
+	ctx := testInput.Ctx
+	require.Nil(t, types.ValidateParamsChange(ctx, keeper.paramSpace))
+
+	// the params left by a param change proposal are read from the store, not from the cache
+	keeper.paramSpace.Set(ctx, types.KeyCircuitBreakerBlocks, int64(0))
+	require.NotNil(t, types.ValidateParamsChange(ctx, keeper.paramSpace))
+	keeper.paramSpace.Set(ctx, types.KeyCircuitBreakerBlocks, int64(types.DefaultCircuitBreakerBlocks))
+	keeper.paramSpace.Set(ctx, types.KeyMarketPressureRate, sdk.OneDec())
+	require.NotNil(t, types.ValidateParamsChange(ctx, keeper.paramSpace))
+	keeper.paramSpace.Set(ctx, types.KeyMarketPressureRate, sdk.MustNewDecFromStr(types.DefaultMarketPressureRate))
+	keeper.paramSpace.Set(ctx, types.KeyAllocationRule, "LIFO")
+	require.NotNil(t, types.ValidateParamsChange(ctx, keeper.paramSpace))
+}
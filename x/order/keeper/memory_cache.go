@@ -8,7 +8,10 @@ import (
 type Cache struct {
 	// Reset at BeginBlock
 	updatedOrderIDs  []string
+	replacedOrderIDs []string // orders replaced in this block which lost their place in the queue
 	blockMatchResult *types.BlockMatchResult
+	dealsHeight      int64 // the block height dealNum counts for
+	dealNum          int64 // deals made by the match engines in the block, against MaxDealsPerBlock
 
 	params *types.Params
 
@@ -17,12 +20,16 @@ type Cache struct {
 	expireNum      int64 // expired orders num in this block
 	partialFillNum int64 // partially filled orders num in this block
 	fullFillNum    int64 // fully filled orders num in this block
+
+	lockedProductNum int64 // locked products num at the end of this block
+	maxLockedBlocks  int64 // the most blocks a product has been locked for
 }
 
 // nolint
 func NewCache() *Cache {
 	return &Cache{
 		updatedOrderIDs:  []string{},
+		replacedOrderIDs: []string{},
 		blockMatchResult: nil,
 		params:           nil,
 	}
@@ -31,19 +38,29 @@ func NewCache() *Cache {
 // reset resets temporary cache, called at BeginBlock
 func (c *Cache) reset() {
 	c.updatedOrderIDs = []string{}
+	c.replacedOrderIDs = []string{}
 	c.blockMatchResult = &types.BlockMatchResult{}
+	c.dealsHeight = 0
+	c.dealNum = 0
 	c.params = nil
 
 	c.cancelNum = 0
 	c.expireNum = 0
 	c.fullFillNum = 0
 	c.partialFillNum = 0
+
+	c.lockedProductNum = 0
+	c.maxLockedBlocks = 0
 }
 
 func (c *Cache) addUpdatedOrderID(orderID string) {
 	c.updatedOrderIDs = append(c.updatedOrderIDs, orderID)
 }
 
+func (c *Cache) addReplacedOrderID(orderID string) {
+	c.replacedOrderIDs = append(c.replacedOrderIDs, orderID)
+}
+
 func (c *Cache) setBlockMatchResult(result *types.BlockMatchResult) {
 	c.blockMatchResult = result
 }
@@ -96,6 +113,21 @@ func (c *Cache) getBlockMatchResult() *types.BlockMatchResult {
 	return c.blockMatchResult
 }
 
+func (c *Cache) addDealNum(height, num int64) {
+	if c.dealsHeight != height {
+		c.dealsHeight = height
+		c.dealNum = 0
+	}
+	c.dealNum += num
+}
+
+func (c *Cache) getDealNum(height int64) int64 {
+	if c.dealsHeight != height {
+		return 0
+	}
+	return c.dealNum
+}
+
 // nolint
 func (c *Cache) SetParams(params *types.Params) {
 	c.params = params
@@ -110,6 +142,10 @@ func (c *Cache) getUpdatedOrderIDs() []string {
 	return c.updatedOrderIDs
 }
 
+func (c *Cache) getReplacedOrderIDs() []string {
+	return c.replacedOrderIDs
+}
+
 // nolint
 func (c *Cache) GetFullFillNum() int64 {
 	return c.fullFillNum
@@ -129,3 +165,13 @@ func (c *Cache) GetExpireNum() int64 {
 func (c *Cache) GetPartialFillNum() int64 {
 	return c.partialFillNum
 }
+
+// nolint
+func (c *Cache) GetLockedProductNum() int64 {
+	return c.lockedProductNum
+}
+
+// nolint
+func (c *Cache) GetMaxLockedBlocks() int64 {
+	return c.maxLockedBlocks
+}
@@ -33,14 +33,15 @@ func (k Keeper) TryPlaceOrder(ctx sdk.Context, order *types.Order) (fee sdk.DecC
 	return fee, err
 }
 
-// PlaceOrder updates BlockOrderNum, DepthBook, execute TryPlaceOrder, and set the specified order to keeper
+// PlaceOrder updates BlockOrderNum, DepthBook, execute TryPlaceOrder, set the specified order to keeper, and emits
+// the accepted event
 func (k Keeper) PlaceOrder(ctx sdk.Context, order *types.Order) error {
 	fee, err := k.TryPlaceOrder(ctx, order)
 	if err != nil {
 		return err
 	}
 	order.RecordOrderNewFee(fee)
-	k.AddFeeDetail(ctx, order.Sender, fee, types.FeeTypeOrderNew)
+	k.AddFeeDetail(ctx, order.Sender, fee, types.FeeTypeOrderNew, order.FeePerBlock.String())
 
 	blockHeight := ctx.BlockHeight()
 	orderNum := k.GetBlockOrderNum(ctx, blockHeight)
@@ -48,12 +49,106 @@ func (k Keeper) PlaceOrder(ctx sdk.Context, order *types.Order) error {
 
 	k.SetBlockOrderNum(ctx, blockHeight, orderNum+1)
 	k.SetOrder(ctx, order.OrderID, order)
+	k.SetAccountOrderID(ctx, order.Sender, order.OrderID)
+	ctx.EventManager().EmitEvent(types.NewOrderEvent(types.EventTypeOrderAccepted, order))
+	if order.IsUntriggered() {
+		k.AddUntriggeredOrder(ctx, order)
+		return nil
+	}
+	if order.IsImmediateOrder() {
+		k.SetImmediateOrderID(ctx, order.OrderID)
+	}
+	if order.IsGTBOrder() {
+		k.SetGTBOrderID(ctx, order.ExpireHeight, order.OrderID)
+	}
 
 	// update depth book and orderIDsMap in cache
 	k.InsertOrderIntoDepthBook(order)
 	return nil
 }
 
+// AddUntriggeredOrder records a stop-loss or take-profit order, which is kept out of depth book until triggered
+func (k Keeper) AddUntriggeredOrder(ctx sdk.Context, order *types.Order) {
+	k.SetTriggerOrderID(ctx, order.OrderID)
+	k.diskCache.countNewOrder()
+}
+
+// TriggerOrders turns the untriggered orders whose trigger price has been crossed by the last price into normal
+// orders, and inserts them into depth book. Only the products matched by the match mode and not locked are
+// checked. It returns the triggered orders in the sequence they arrived
+func (k Keeper) TriggerOrders(ctx sdk.Context, matchMode string) []*types.Order {
+	logger := ctx.Logger().With("module", "order")
+	var triggeredOrders []*types.Order
+	for _, orderID := range k.GetTriggerOrderIDs(ctx) {
+		order := k.GetOrder(ctx, orderID)
+		if order == nil || !order.IsUntriggered() {
+			k.DropTriggerOrderID(ctx, orderID)
+			continue
+		}
+		if k.IsProductLocked(order.Product) || !k.IsMatchedBy(ctx, order.Product, matchMode) {
+			continue
+		}
+		lastPrice := k.GetLastPrice(ctx, order.Product)
+		if !order.Trigger.IsTriggeredBy(order.Side, lastPrice) {
+			continue
+		}
+
+		order.TriggerAt(ctx.BlockHeight())
+		k.SetOrder(ctx, orderID, order)
+		k.addUpdatedOrderID(orderID)
+		k.DropTriggerOrderID(ctx, orderID)
+		if order.IsImmediateOrder() {
+			k.SetImmediateOrderID(ctx, orderID)
+		}
+		k.diskCache.insertOrderIntoBook(order)
+		triggeredOrders = append(triggeredOrders, order)
+		logger.Info(fmt.Sprintf("order (%s) triggered by last price %v, trigger: %s %v", orderID, lastPrice,
+			order.Trigger.Type, order.Trigger.Price))
+	}
+	return triggeredOrders
+}
+
+// ReplaceOrder amends the price and the total quantity of an open order, and adjusts its locked coins by the
+// difference. Reducing the quantity at the same price keeps the place of the order in the queue of its price,
+// otherwise the order is queued at the end of the new price and treated as a new arrival in current block
+func (k Keeper) ReplaceOrder(ctx sdk.Context, order *types.Order, price, quantity sdk.Dec) error {
+	oldOrder := *order
+	// the order placed by a grantee is taken over by its sender
+	order.Grantee = nil
+	order.RemainQuantity = quantity.Sub(order.Quantity.Sub(order.RemainQuantity))
+	order.Price = price
+	order.Quantity = quantity
+
+	// lock or unlock the difference between the new locked coins and the remaining locked coins
+	remainLocked := order.RemainQuantity
+	if order.Side == types.BuyOrder {
+		remainLocked = order.Price.Mul(order.RemainQuantity)
+	}
+	denom := order.NeedUnlockCoins()[0].Denom
+	diff := remainLocked.Sub(order.RemainLocked)
+	if diff.IsPositive() {
+		lockCoins := sdk.DecCoins{sdk.NewDecCoinFromDec(denom, diff)}
+		if err := k.LockCoins(ctx, order.Sender, lockCoins, token.LockCoinsTypeQuantity); err != nil {
+			*order = oldOrder
+			return err
+		}
+	} else if diff.IsNegative() {
+		unlockCoins := sdk.DecCoins{sdk.NewDecCoinFromDec(denom, diff.Neg())}
+		k.UnlockCoins(ctx, order.Sender, unlockCoins, token.LockCoinsTypeQuantity)
+	}
+	order.RemainLocked = remainLocked
+	k.releaseTradingGrant(ctx, &oldOrder)
+
+	keepPlace := price.Equal(oldOrder.Price) && order.RemainQuantity.LTE(oldOrder.RemainQuantity)
+	k.SetOrder(ctx, order.OrderID, order)
+	k.addUpdatedOrderID(order.OrderID)
+	k.diskCache.replaceOrder(&oldOrder, order, keepPlace)
+	if !keepPlace {
+		k.cache.addReplacedOrderID(order.OrderID)
+	}
+	return nil
+}
+
 // ExpireOrder quits the specified order with the expired state
 func (k Keeper) ExpireOrder(ctx sdk.Context, order *types.Order, logger log.Logger) {
 	k.quitOrder(ctx, order, types.FeeTypeOrderExpire, logger)
@@ -64,27 +159,72 @@ func (k Keeper) CancelOrder(ctx sdk.Context, order *types.Order, logger log.Logg
 	return k.quitOrder(ctx, order, types.FeeTypeOrderCancel, logger)
 }
 
-// quitOrder unlocks & charges fee, unlocks coins, updates order, and updates DepthBook
+// QuitImmediateOrder quits the remainder of a market, IOC or FOK order after its first match. A market
+// order without time in force is quit with the canceled state, and like the others it's not charged for the blocks
+// it waited for its first match
+func (k Keeper) QuitImmediateOrder(ctx sdk.Context, order *types.Order, logger log.Logger) sdk.DecCoins {
+	switch {
+	case order.TimeInForce == types.TimeInForceFOK:
+		return k.quitOrder(ctx, order, types.FeeTypeOrderFOKKill, logger)
+	case order.TimeInForce == types.TimeInForceIOC:
+		return k.quitOrder(ctx, order, types.FeeTypeOrderIOCCancel, logger)
+	default:
+		return k.quitOrder(ctx, order, types.FeeTypeOrderCancel, logger)
+	}
+}
+
+// FOKKillOrder quits the specified FOK order with the killed state
+func (k Keeper) FOKKillOrder(ctx sdk.Context, order *types.Order, logger log.Logger) sdk.DecCoins {
+	return k.quitOrder(ctx, order, types.FeeTypeOrderFOKKill, logger)
+}
+
+// PostOnlyRejectOrder quits the specified post-only order with the rejected state
+func (k Keeper) PostOnlyRejectOrder(ctx sdk.Context, order *types.Order, logger log.Logger) sdk.DecCoins {
+	return k.quitOrder(ctx, order, types.FeeTypeOrderPostOnlyReject, logger)
+}
+
+// GTBExpireOrder quits the specified GTB order with the expired state
+func (k Keeper) GTBExpireOrder(ctx sdk.Context, order *types.Order, logger log.Logger) sdk.DecCoins {
+	return k.quitOrder(ctx, order, types.FeeTypeOrderGTBExpire, logger)
+}
+
+// quitOrder unlocks & charges fee, unlocks coins, updates order, emits the quit event, and updates DepthBook
 func (k Keeper) quitOrder(ctx sdk.Context, order *types.Order, feeType string, logger log.Logger) (fee sdk.DecCoins) {
+	untriggered := order.IsUntriggered()
 	switch feeType {
-	case types.FeeTypeOrderCancel:
+	case types.FeeTypeOrderCancel, types.FeeTypeOrderSelfTrade:
 		order.Cancel()
 	case types.FeeTypeOrderExpire:
 		order.Expire()
+	case types.FeeTypeOrderIOCCancel:
+		order.IOCCancel()
+	case types.FeeTypeOrderFOKKill:
+		order.FOKKill()
+	case types.FeeTypeOrderPostOnlyReject:
+		order.PostOnlyReject()
+	case types.FeeTypeOrderGTBExpire:
+		order.GTBExpire()
 	default:
 		return
 	}
 
+	k.releaseTradingGrant(ctx, order)
+
 	// unlock coins in this order & charge fee
 	needUnlockCoins := order.NeedUnlockCoins()
 	k.UnlockCoins(ctx, order.Sender, needUnlockCoins, token.LockCoinsTypeQuantity)
 
 	lockedFee := GetOrderNewFee(order)
 	fee = GetOrderCostFee(order, ctx)
+	if isNeverRestingFeeType(feeType) || order.IsMarketOrder() {
+		// IOC, FOK, post-only and market orders never rest in depth book, so they are not charged for the blocks
+		// lived, which are the blocks waited while their product is locked
+		fee = GetZeroFee()
+	}
 	receiveFee := lockedFee.Sub(fee)
 
 	k.UnlockCoins(ctx, order.Sender, lockedFee, token.LockCoinsTypeFee)
-	k.AddFeeDetail(ctx, order.Sender, receiveFee, types.FeeTypeOrderReceive)
+	k.AddFeeDetail(ctx, order.Sender, receiveFee, types.FeeTypeOrderReceive, order.FeePerBlock.String())
 	order.RecordOrderReceiveFee(receiveFee)
 
 	err := k.AddCollectedFees(ctx, fee, order.Sender, feeType, false)
@@ -95,8 +235,20 @@ func (k Keeper) quitOrder(ctx sdk.Context, order *types.Order, feeType string, l
 
 	order.Unlock()
 	k.SetOrder(ctx, order.OrderID, order)
+	k.DropAccountOrderID(ctx, order.Sender, order.OrderID)
+	ctx.EventManager().EmitEvent(types.NewOrderQuitEvent(order, fee))
 
 	// remove order from depth book cache
-	k.RemoveOrderFromDepthBook(order, feeType)
+	if untriggered {
+		k.RemoveUntriggeredOrder(ctx, order, feeType)
+	} else {
+		k.RemoveOrderFromDepthBook(order, feeType)
+	}
 	return fee
 }
+
+// isNeverRestingFeeType returns true if the order quit by the fee type never rests in depth book
+func isNeverRestingFeeType(feeType string) bool {
+	return feeType == types.FeeTypeOrderIOCCancel || feeType == types.FeeTypeOrderFOKKill ||
+		feeType == types.FeeTypeOrderPostOnlyReject
+}
@@ -206,3 +206,154 @@ func TestPlaceOrderAndExpireOrder(t *testing.T) {
 	require.EqualValues(t, 0, keeper.diskCache.openNum)
 	require.EqualValues(t, 1, keeper.cache.expireNum)
 }
+
+func TestReplaceOrder(t *testing.T) {
+	testInput := CreateTestInput(t)
+	keeper := testInput.OrderKeeper
+	ctx := testInput.Ctx.WithBlockHeight(10)
+
+	tokenPair := dex.GetBuiltInTokenPair()
+	err := testInput.DexKeeper.SaveTokenPair(ctx, tokenPair)
+	require.Nil(t, err)
+
+	orders := []*types.Order{
+		mockOrder("", types.TestTokenPair, types.BuyOrder, "10.0", "2.0"),
+		mockOrder("", types.TestTokenPair, types.BuyOrder, "10.0", "1.0"),
+	}
+	orders[0].Sender = testInput.TestAddrs[0]
+	orders[1].Sender = testInput.TestAddrs[1]
+	for _, order := range orders {
+		err := keeper.PlaceOrder(ctx, order)
+		require.Nil(t, err)
+	}
+	orderIDs := []string{orders[0].OrderID, orders[1].OrderID}
+	oldKey := types.FormatOrderIDsKey(types.TestTokenPair, sdk.MustNewDecFromStr("10.0"), types.BuyOrder)
+	newKey := types.FormatOrderIDsKey(types.TestTokenPair, sdk.MustNewDecFromStr("10.5"), types.BuyOrder)
+
+	// reduce quantity at the same price, the order keeps its place
+	err = keeper.ReplaceOrder(ctx, orders[0], sdk.MustNewDecFromStr("10.0"), sdk.MustNewDecFromStr("1.5"))
+	require.Nil(t, err)
+	require.EqualValues(t, orderIDs, keeper.GetProductPriceOrderIDs(oldKey))
+	require.EqualValues(t, sdk.MustNewDecFromStr("15"), keeper.GetOrder(ctx, orders[0].OrderID).RemainLocked)
+	depthBook := keeper.GetDepthBookCopy(types.TestTokenPair)
+	require.EqualValues(t, 1, len(depthBook.Items))
+	require.EqualValues(t, sdk.MustNewDecFromStr("2.5"), depthBook.Items[0].BuyQuantity)
+	require.EqualValues(t, 0, len(keeper.GetReplacedOrderIDs()))
+	acc := testInput.AccountKeeper.GetAccount(ctx, testInput.TestAddrs[0])
+	expectCoins := sdk.DecCoins{
+		// 100 - 10 * 1.5 - 0.2592
+		sdk.NewDecCoinFromDec(common.NativeToken, sdk.MustNewDecFromStr("84.7408")),
+		sdk.NewDecCoinFromDec(common.TestToken, sdk.MustNewDecFromStr("100")),
+	}
+	require.EqualValues(t, expectCoins.String(), acc.GetCoins().String())
+
+	// change price, the order is queued at the new price
+	err = keeper.ReplaceOrder(ctx, orders[0], sdk.MustNewDecFromStr("10.5"), sdk.MustNewDecFromStr("1.5"))
+	require.Nil(t, err)
+	require.EqualValues(t, orderIDs[1:], keeper.GetProductPriceOrderIDs(oldKey))
+	require.EqualValues(t, orderIDs[:1], keeper.GetProductPriceOrderIDs(newKey))
+	require.EqualValues(t, orderIDs[:1], keeper.GetReplacedOrderIDs())
+	depthBook = keeper.GetDepthBookCopy(types.TestTokenPair)
+	require.EqualValues(t, 2, len(depthBook.Items))
+	require.EqualValues(t, sdk.MustNewDecFromStr("1.5"), depthBook.Items[0].BuyQuantity)
+	require.EqualValues(t, sdk.MustNewDecFromStr("1"), depthBook.Items[1].BuyQuantity)
+	acc = testInput.AccountKeeper.GetAccount(ctx, testInput.TestAddrs[0])
+	expectCoins = sdk.DecCoins{
+		// 100 - 10.5 * 1.5 - 0.2592
+		sdk.NewDecCoinFromDec(common.NativeToken, sdk.MustNewDecFromStr("83.9908")),
+		sdk.NewDecCoinFromDec(common.TestToken, sdk.MustNewDecFromStr("100")),
+	}
+	require.EqualValues(t, expectCoins.String(), acc.GetCoins().String())
+
+	// not enough balance, the order is not changed
+	err = keeper.ReplaceOrder(ctx, orders[0], sdk.MustNewDecFromStr("10.5"), sdk.MustNewDecFromStr("100"))
+	require.NotNil(t, err)
+	require.EqualValues(t, sdk.MustNewDecFromStr("1.5"), orders[0].Quantity)
+	require.EqualValues(t, sdk.MustNewDecFromStr("1.5"), keeper.GetOrder(ctx, orders[0].OrderID).RemainQuantity)
+}
+
+func TestOrderLifecycleEvents(t *testing.T) {
+	testInput := CreateTestInput(t)
+	keeper := testInput.OrderKeeper
+	ctx := testInput.Ctx.WithBlockHeight(10).WithEventManager(sdk.NewEventManager())
+
+	tokenPair := dex.GetBuiltInTokenPair()
+	err := testInput.DexKeeper.SaveTokenPair(ctx, tokenPair)
+	require.Nil(t, err)
+
+	orders := []*types.Order{
+		mockOrder("", types.TestTokenPair, types.BuyOrder, "10.0", "2.0"),
+		mockOrder("", types.TestTokenPair, types.SellOrder, "10.0", "1.0"),
+		mockOrder("", types.TestTokenPair, types.SellOrder, "11.0", "1.0"),
+	}
+	orders[0].Sender = testInput.TestAddrs[0]
+	orders[1].Sender = testInput.TestAddrs[1]
+	orders[2].Sender = testInput.TestAddrs[1]
+	for _, order := range orders {
+		require.Nil(t, keeper.PlaceOrder(ctx, order))
+	}
+	events := orderLifecycleEvents(ctx)
+	require.Equal(t, 3, len(events))
+	for i, event := range events {
+		require.Equal(t, types.EventTypeOrderAccepted, event.Type)
+		requireEventAttribute(t, event, types.TagKeyOrderID, orders[i].OrderID)
+		requireEventAttribute(t, event, types.TagKeySender, orders[i].Sender.String())
+		requireEventAttribute(t, event, types.TagKeyStatus, types.Open.String())
+		requireEventAttribute(t, event, types.TagKeyOrderType, types.OrderTypeLimit)
+	}
+
+	// fill orders, one event per deal
+	ctx = ctx.WithEventManager(sdk.NewEventManager())
+	feeParams := types.DefaultParams()
+	price := sdk.MustNewDecFromStr("10.0")
+	quantity := sdk.MustNewDecFromStr("1.0")
+	deal := keeper.FillOrder(ctx, orders[0], price, quantity, &feeParams, true)
+	keeper.FillOrder(ctx, orders[1], price, quantity, &feeParams, false)
+	events = orderLifecycleEvents(ctx)
+	require.Equal(t, 2, len(events))
+	require.Equal(t, types.EventTypeOrderPartiallyFilled, events[0].Type)
+	requireEventAttribute(t, events[0], types.TagKeySide, types.BuyOrder)
+	requireEventAttribute(t, events[0], types.TagKeyCounterSide, types.SellOrder)
+	requireEventAttribute(t, events[0], types.TagKeyFillPrice, price.String())
+	requireEventAttribute(t, events[0], types.TagKeyFillQuantity, quantity.String())
+	requireEventAttribute(t, events[0], types.TagKeyRemainQuantity, "1.00000000")
+	requireEventAttribute(t, events[0], types.TagKeyFee, deal.Fee)
+	require.Equal(t, types.EventTypeOrderFilled, events[1].Type)
+	requireEventAttribute(t, events[1], types.TagKeyCounterSide, types.BuyOrder)
+	requireEventAttribute(t, events[1], types.TagKeyStatus, types.Filled.String())
+
+	// cancel & expire orders
+	ctx = ctx.WithEventManager(sdk.NewEventManager())
+	fee := keeper.CancelOrder(ctx, orders[0], ctx.Logger())
+	keeper.ExpireOrder(ctx, orders[2], ctx.Logger())
+	events = orderLifecycleEvents(ctx)
+	require.Equal(t, 2, len(events))
+	require.Equal(t, types.EventTypeOrderCancelled, events[0].Type)
+	requireEventAttribute(t, events[0], types.TagKeyStatus, types.PartialFilledCancelled.String())
+	requireEventAttribute(t, events[0], types.TagKeyFee, fee.String())
+	require.Equal(t, types.EventTypeOrderExpired, events[1].Type)
+	requireEventAttribute(t, events[1], types.TagKeyOrderID, orders[2].OrderID)
+	requireEventAttribute(t, events[1], types.TagKeyStatus, types.Expired.String())
+}
+
+// orderLifecycleEvents filters out the events emitted by other modules, e.g. transfer
+func orderLifecycleEvents(ctx sdk.Context) (events sdk.Events) {
+	for _, event := range ctx.EventManager().Events() {
+		switch event.Type {
+		case types.EventTypeOrderAccepted, types.EventTypeOrderPartiallyFilled, types.EventTypeOrderFilled,
+			types.EventTypeOrderCancelled, types.EventTypeOrderExpired:
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+func requireEventAttribute(t *testing.T, event sdk.Event, key, value string) {
+	for _, attr := range event.Attributes {
+		if string(attr.Key) == key {
+			require.Equal(t, value, string(attr.Value), key)
+			return
+		}
+	}
+	require.Fail(t, "attribute not found", key)
+}
@@ -0,0 +1,52 @@
+package keeper
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/okex/okchain/x/order/types"
+)
+
+// SetProductHalt halts the periodic auction of the product until the resume height
+func (k Keeper) SetProductHalt(ctx sdk.Context, halt *types.ProductHalt) {
+	store := ctx.KVStore(k.orderStoreKey)
+	store.Set(types.GetProductHaltKey(halt.Product), k.cdc.MustMarshalBinaryBare(halt))
+}
+
+// DropProductHalt deletes the halt record of the product
+func (k Keeper) DropProductHalt(ctx sdk.Context, product string) {
+	store := ctx.KVStore(k.orderStoreKey)
+	store.Delete(types.GetProductHaltKey(product))
+}
+
+// GetProductHalt returns the halt record of the product, nil if the product is not halted
+func (k Keeper) GetProductHalt(ctx sdk.Context, product string) *types.ProductHalt {
+	store := ctx.KVStore(k.orderStoreKey)
+	bz := store.Get(types.GetProductHaltKey(product))
+	if bz == nil {
+		return nil
+	}
+	halt := &types.ProductHalt{}
+	k.cdc.MustUnmarshalBinaryBare(bz, halt)
+	return halt
+}
+
+// GetProductHalts returns the halt records of all products, sorted by product
+func (k Keeper) GetProductHalts(ctx sdk.Context) []*types.ProductHalt {
+	store := ctx.KVStore(k.orderStoreKey)
+	iter := sdk.KVStorePrefixIterator(store, types.ProductHaltKey)
+	defer iter.Close()
+
+	var halts []*types.ProductHalt
+	for ; iter.Valid(); iter.Next() {
+		halt := &types.ProductHalt{}
+		k.cdc.MustUnmarshalBinaryBare(iter.Value(), halt)
+		halts = append(halts, halt)
+	}
+	return halts
+}
+
+// IsProductHalted returns true if the matching of the product is halted at current block
+func (k Keeper) IsProductHalted(ctx sdk.Context, product string) bool {
+	halt := k.GetProductHalt(ctx, product)
+	return halt != nil && halt.ResumeHeight > ctx.BlockHeight()
+}
@@ -0,0 +1,52 @@
+package keeper
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+
+	"github.com/okex/okchain/x/order/types"
+)
+
+func TestKeeper_ProductHalt(t *testing.T) {
+	testInput := CreateTestInput(t)
+	keeper := testInput.OrderKeeper
+	ctx := testInput.Ctx.WithBlockHeight(10)
+	querier := NewQuerier(keeper)
+
+	require.Nil(t, keeper.GetProductHalt(ctx, types.TestTokenPair))
+	require.False(t, keeper.IsProductHalted(ctx, types.TestTokenPair))
+
+	halt := &types.ProductHalt{
+		Product:      types.TestTokenPair,
+		HaltHeight:   10,
+		ResumeHeight: 12,
+		LastPrice:    sdk.MustNewDecFromStr("10.0"),
+		HaltPrice:    sdk.MustNewDecFromStr("12.0"),
+	}
+	keeper.SetProductHalt(ctx, halt)
+	require.EqualValues(t, halt, keeper.GetProductHalt(ctx, types.TestTokenPair))
+	require.EqualValues(t, []*types.ProductHalt{halt}, keeper.GetProductHalts(ctx))
+	require.True(t, keeper.IsProductHalted(ctx, types.TestTokenPair))
+	require.True(t, keeper.IsProductHalted(ctx.WithBlockHeight(11), types.TestTokenPair))
+	require.False(t, keeper.IsProductHalted(ctx.WithBlockHeight(12), types.TestTokenPair))
+
+	// query all halts and the halt of the product
+	bz, err := querier(ctx, []string{types.QueryHalts}, abci.RequestQuery{})
+	require.Nil(t, err)
+	var halts []*types.ProductHalt
+	keeper.cdc.MustUnmarshalJSON(bz, &halts)
+	require.EqualValues(t, []*types.ProductHalt{halt}, halts)
+	_, err = querier(ctx, []string{types.QueryHalts, types.TestTokenPair}, abci.RequestQuery{})
+	require.Nil(t, err)
+
+	keeper.DropProductHalt(ctx, types.TestTokenPair)
+	require.Nil(t, keeper.GetProductHalt(ctx, types.TestTokenPair))
+	_, err = querier(ctx, []string{types.QueryHalts, types.TestTokenPair}, abci.RequestQuery{})
+	require.NotNil(t, err)
+	bz, err = querier(ctx, []string{types.QueryHalts}, abci.RequestQuery{})
+	require.Nil(t, err)
+	require.EqualValues(t, "[]", string(bz))
+}
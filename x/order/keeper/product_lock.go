@@ -24,3 +24,16 @@ func (k Keeper) UnlockProduct(ctx sdk.Context, product string) {
 func (k Keeper) AnyProductLocked() bool {
 	return k.dexKeeper.IsAnyProductLocked()
 }
+
+// CountLockedProducts counts the locked products and the most blocks a product has been locked for, including
+// current block, for the metrics of current block
+func (k Keeper) CountLockedProducts(ctx sdk.Context) {
+	lockMap := k.dexKeeper.GetLockedProductsCopy()
+	k.cache.lockedProductNum = int64(len(lockMap.Data))
+	k.cache.maxLockedBlocks = 0
+	for _, lock := range lockMap.Data {
+		if lockedBlocks := ctx.BlockHeight() - lock.BlockHeight + 1; lockedBlocks > k.cache.maxLockedBlocks {
+			k.cache.maxLockedBlocks = lockedBlocks
+		}
+	}
+}
@@ -3,6 +3,7 @@ package keeper
 import (
 	"testing"
 
+	"github.com/okex/okchain/x/common"
 	"github.com/okex/okchain/x/dex"
 	"github.com/okex/okchain/x/order/types"
 	"github.com/stretchr/testify/require"
@@ -33,3 +34,19 @@ func TestKeeper_AnyProductLocked(t *testing.T) {
 	keeper.UnlockProduct(ctx, types.TestTokenPair)
 	require.EqualValues(t, false, keeper.IsProductLocked(types.TestTokenPair))
 }
+
+func TestKeeper_CountLockedProducts(t *testing.T) {
+	testInput := CreateTestInput(t)
+	keeper := testInput.OrderKeeper
+	ctx := testInput.Ctx.WithBlockHeight(10)
+
+	keeper.CountLockedProducts(ctx)
+	require.EqualValues(t, 0, keeper.GetOperationMetric().LockedProductNum)
+	require.EqualValues(t, 0, keeper.GetOperationMetric().MaxLockedBlocks)
+
+	keeper.SetProductLock(ctx, types.TestTokenPair, &types.ProductLock{BlockHeight: 7})
+	keeper.SetProductLock(ctx, "btc-000_"+common.NativeToken, &types.ProductLock{BlockHeight: 10})
+	keeper.CountLockedProducts(ctx)
+	require.EqualValues(t, 2, keeper.GetOperationMetric().LockedProductNum)
+	require.EqualValues(t, 4, keeper.GetOperationMetric().MaxLockedBlocks)
+}
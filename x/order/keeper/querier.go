@@ -1,6 +1,7 @@
 package keeper
 
 import (
+	"encoding/json"
 	"fmt"
 
 	"github.com/cosmos/cosmos-sdk/codec"
@@ -8,12 +9,15 @@ import (
 	abci "github.com/tendermint/tendermint/abci/types"
 
 	"github.com/okex/okchain/x/common"
+	dex "github.com/okex/okchain/x/dex/types"
 	"github.com/okex/okchain/x/order/types"
 )
 
 // nolint
 const (
 	DefaultBookSize = 200
+	DefaultPage     = 1
+	DefaultPerPage  = 50
 )
 
 // NewQuerier is the module level router for state queries
@@ -31,6 +35,16 @@ func NewQuerier(keeper Keeper) sdk.Querier {
 
 		case types.QueryDepthBookV2:
 			return queryDepthBookV2(ctx, path[1:], req, keeper)
+		case types.QueryHalts:
+			return queryProductHalts(ctx, path[1:], keeper)
+		case types.QueryAuction:
+			return queryIndicativeAuction(ctx, path[1:], keeper)
+		case types.QueryCancelAfter:
+			return queryCancelAfter(ctx, path[1:], keeper)
+		case types.QueryGrants:
+			return queryTradingGrants(ctx, path[1:], keeper)
+		case types.QueryOpenOrders:
+			return queryOpenOrders(ctx, req, keeper)
 		default:
 			return nil, sdk.ErrUnknownRequest("unknown order query endpoint")
 		}
@@ -90,7 +104,8 @@ func queryDepthBook(ctx sdk.Context, path []string, req abci.RequestQuery, keepe
 	if tokenPair == nil {
 		return nil, sdk.ErrUnknownRequest(fmt.Sprintf("Non-exist product: %s", params.Product))
 	}
-	depthBook := keeper.GetDepthBookFromDB(ctx, params.Product)
+	// the hidden reserves of iceberg orders are left out
+	depthBook := keeper.GetDepthBookFromDB(ctx, params.Product).Visible()
 
 	var asks []BookResItem
 	var bids []BookResItem
@@ -187,7 +202,7 @@ func queryDepthBookV2(ctx sdk.Context, path []string, req abci.RequestQuery, kee
 		return nil, sdk.ErrUnknownRequest(err.Error())
 	}
 
-	depthBook := keeper.GetDepthBookFromDB(ctx, params.Product)
+	depthBook := keeper.GetDepthBookFromDB(ctx, params.Product).Visible()
 
 	var asks []BookResItem
 	var bids []BookResItem
@@ -217,3 +232,197 @@ func queryDepthBookV2(ctx sdk.Context, path []string, req abci.RequestQuery, kee
 	}
 	return res, nil
 }
+
+// queryProductHalts returns the products halted by the circuit breaker, or the halt of the specified product
+func queryProductHalts(ctx sdk.Context, path []string, keeper Keeper) ([]byte, sdk.Error) {
+	halts := keeper.GetProductHalts(ctx)
+	if len(path) > 0 && path[0] != "" {
+		halt := keeper.GetProductHalt(ctx, path[0])
+		if halt == nil {
+			return nil, sdk.ErrUnknownRequest(fmt.Sprintf("product(%s) is not halted", path[0]))
+		}
+		halts = []*types.ProductHalt{halt}
+	}
+	if halts == nil {
+		halts = []*types.ProductHalt{}
+	}
+	res, errRes := codec.MarshalJSONIndent(keeper.cdc, halts)
+	if errRes != nil {
+		return nil, sdk.ErrInternal(
+			sdk.AppendMsgToErr("could not marshal result to JSON", errRes.Error()))
+	}
+	return res, nil
+}
+
+// IndicativeAuctionRes is used to return the result of queryIndicativeAuction
+type IndicativeAuctionRes struct {
+	Product       string  `json:"product"`
+	LastPrice     sdk.Dec `json:"last_price"`
+	Price         sdk.Dec `json:"price"`
+	Quantity      sdk.Dec `json:"quantity"`
+	Imbalance     sdk.Dec `json:"imbalance"`
+	ImbalanceSide string  `json:"imbalance_side"`
+	Rule          string  `json:"rule"`
+}
+
+// queryIndicativeAuction calculates the clearing price of the next periodic auction of the product on the visible
+// depth book, without any change of the state. The hidden reserves of iceberg orders are left out, so they can't be
+// told from the result
+func queryIndicativeAuction(ctx sdk.Context, path []string, keeper Keeper) ([]byte, sdk.Error) {
+	if len(path) == 0 || path[0] == "" {
+		return nil, sdk.ErrUnknownRequest("product is empty")
+	}
+	product := path[0]
+	tokenPair := keeper.GetDexKeeper().GetTokenPair(ctx, product)
+	if tokenPair == nil {
+		return nil, sdk.ErrUnknownRequest(fmt.Sprintf("Non-exist product: %s", product))
+	}
+	if tokenPair.GetMatchMode() != dex.MatchModePeriodicAuction {
+		return nil, sdk.ErrUnknownRequest(fmt.Sprintf("product(%s) is not matched by periodic auction", product))
+	}
+
+	lastPrice := keeper.GetLastPrice(ctx, product)
+	auctionPrice := types.CalcAuctionPrice(keeper.GetDepthBookFromDB(ctx, product).Visible(), tokenPair.MaxPriceDigit,
+		lastPrice, keeper.GetParams(ctx).MarketPressureRate)
+	auctionRes := IndicativeAuctionRes{
+		Product:       product,
+		LastPrice:     lastPrice,
+		Price:         auctionPrice.Price,
+		Quantity:      auctionPrice.Quantity,
+		Imbalance:     auctionPrice.Imbalance,
+		ImbalanceSide: auctionPrice.ImbalanceSide,
+		Rule:          auctionPrice.Rule,
+	}
+	res, errRes := codec.MarshalJSONIndent(keeper.cdc, auctionRes)
+	if errRes != nil {
+		return nil, sdk.ErrInternal(
+			sdk.AppendMsgToErr("could not marshal result to JSON", errRes.Error()))
+	}
+	return res, nil
+}
+
+// queryCancelAfter returns the cancel-after of the account
+func queryCancelAfter(ctx sdk.Context, path []string, keeper Keeper) ([]byte, sdk.Error) {
+	if len(path) == 0 {
+		return nil, sdk.ErrInvalidAddress("address is empty")
+	}
+	addr, err := sdk.AccAddressFromBech32(path[0])
+	if err != nil {
+		return nil, sdk.ErrInvalidAddress(fmt.Sprintf("invalid address %s: %s", path[0], err))
+	}
+	cancelAfter := keeper.GetCancelAfter(ctx, addr)
+	if cancelAfter == nil {
+		return nil, sdk.ErrUnknownRequest(fmt.Sprintf("cancel-after of %s is not set", path[0]))
+	}
+	res, errRes := codec.MarshalJSONIndent(keeper.cdc, cancelAfter)
+	if errRes != nil {
+		return nil, sdk.ErrInternal(
+			sdk.AppendMsgToErr("could not marshal result to JSON", errRes.Error()))
+	}
+	return res, nil
+}
+
+// queryTradingGrants returns the trading grants from the granter, or the grant to the specified grantee
+func queryTradingGrants(ctx sdk.Context, path []string, keeper Keeper) ([]byte, sdk.Error) {
+	if len(path) == 0 {
+		return nil, sdk.ErrInvalidAddress("granter is empty")
+	}
+	granter, err := sdk.AccAddressFromBech32(path[0])
+	if err != nil {
+		return nil, sdk.ErrInvalidAddress(fmt.Sprintf("invalid granter %s: %s", path[0], err))
+	}
+	grants := keeper.GetTradingGrants(ctx, granter)
+	if len(path) > 1 && path[1] != "" {
+		grantee, err := sdk.AccAddressFromBech32(path[1])
+		if err != nil {
+			return nil, sdk.ErrInvalidAddress(fmt.Sprintf("invalid grantee %s: %s", path[1], err))
+		}
+		grant := keeper.GetTradingGrant(ctx, granter, grantee)
+		if grant == nil {
+			return nil, sdk.ErrUnknownRequest(fmt.Sprintf("%s is not granted to trade on behalf of %s",
+				path[1], path[0]))
+		}
+		grants = []*types.TradingGrant{grant}
+	}
+	if grants == nil {
+		grants = []*types.TradingGrant{}
+	}
+	res, errRes := codec.MarshalJSONIndent(keeper.cdc, grants)
+	if errRes != nil {
+		return nil, sdk.ErrInternal(
+			sdk.AppendMsgToErr("could not marshal result to JSON", errRes.Error()))
+	}
+	return res, nil
+}
+
+// QueryOpenOrdersParams as input parameters when querying the open orders of an address
+type QueryOpenOrdersParams struct {
+	Address string
+	Product string
+	Side    string
+	Page    int
+	PerPage int
+}
+
+// NewQueryOpenOrdersParams creates a new instance of QueryOpenOrdersParams
+func NewQueryOpenOrdersParams(addr, product, side string, page, perPage int) QueryOpenOrdersParams {
+	if page == 0 && perPage == 0 {
+		page = DefaultPage
+		perPage = DefaultPerPage
+	}
+	return QueryOpenOrdersParams{
+		Address: addr,
+		Product: product,
+		Side:    side,
+		Page:    page,
+		PerPage: perPage,
+	}
+}
+
+// queryOpenOrders returns a page of the open orders placed by an address, which are filtered by product and side
+func queryOpenOrders(ctx sdk.Context, req abci.RequestQuery, keeper Keeper) ([]byte, sdk.Error) {
+	var params QueryOpenOrdersParams
+	if err := keeper.cdc.UnmarshalJSON(req.Data, &params); err != nil {
+		return nil, sdk.ErrUnknownRequest(sdk.AppendMsgToErr("incorrectly formatted request data", err.Error()))
+	}
+	addr, err := sdk.AccAddressFromBech32(params.Address)
+	if err != nil {
+		return nil, sdk.ErrInvalidAddress(fmt.Sprintf("invalid address %s: %s", params.Address, err))
+	}
+	if params.Side != "" && params.Side != types.BuyOrder && params.Side != types.SellOrder {
+		return nil, sdk.ErrUnknownRequest(fmt.Sprintf("invalid side: %s", params.Side))
+	}
+	if params.Page <= 0 || params.PerPage <= 0 {
+		return nil, sdk.ErrUnknownRequest(fmt.Sprintf("invalid page %d or per page %d", params.Page,
+			params.PerPage))
+	}
+
+	var orders []*types.Order
+	for _, orderID := range keeper.GetAccountOrderIDs(ctx, addr) {
+		order := keeper.GetOrder(ctx, orderID)
+		if order == nil || (params.Product != "" && order.Product != params.Product) ||
+			(params.Side != "" && order.Side != params.Side) {
+			continue
+		}
+		orders = append(orders, order)
+	}
+
+	total := len(orders)
+	offset, limit := common.GetPage(params.Page, params.PerPage)
+	var response *common.ListResponse
+	if offset < total {
+		end := offset + limit
+		if end > total {
+			end = total
+		}
+		response = common.GetListResponse(total, params.Page, params.PerPage, orders[offset:end])
+	} else {
+		response = common.GetEmptyListResponse(total, params.Page, params.PerPage)
+	}
+	res, errRes := json.Marshal(response)
+	if errRes != nil {
+		return nil, sdk.ErrInternal(
+			sdk.AppendMsgToErr("could not marshal result to JSON", errRes.Error()))
+	}
+	return res, nil
+}
@@ -1,12 +1,14 @@
 package keeper
 
 import (
+	"encoding/json"
 	"testing"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/stretchr/testify/require"
 	abci "github.com/tendermint/tendermint/abci/types"
 
+	"github.com/okex/okchain/x/common"
 	"github.com/okex/okchain/x/dex"
 	"github.com/okex/okchain/x/order/types"
 )
@@ -88,6 +90,47 @@ func TestQueryDepthBookV2(t *testing.T) {
 	//require.EqualValues(t, expectBookRes, bookRes)
 }
 
+func TestQueryDepthBookIceberg(t *testing.T) {
+	testInput := CreateTestInput(t)
+	keeper := testInput.OrderKeeper
+	ctx := testInput.Ctx
+	querier := NewQuerier(keeper)
+
+	tokenPair := dex.GetBuiltInTokenPair()
+	err := testInput.DexKeeper.SaveTokenPair(ctx, tokenPair)
+	require.Nil(t, err)
+
+	depthBook := &types.DepthBook{}
+	product := types.TestTokenPair
+	order1 := mockOrder("", product, types.SellOrder, "0.6", "5.0")
+	display1, display3 := sdk.MustNewDecFromStr("1.0"), sdk.MustNewDecFromStr("0.5")
+	order1.DisplayQuantity = &display1
+	depthBook.InsertOrder(order1)
+	order2 := mockOrder("", product, types.SellOrder, "0.6", "1.1")
+	depthBook.InsertOrder(order2)
+	order3 := mockOrder("", product, types.BuyOrder, "0.4", "3.0")
+	order3.DisplayQuantity = &display3
+	depthBook.InsertOrder(order3)
+	keeper.StoreDepthBook(ctx, product, depthBook)
+
+	// the hidden reserves of iceberg orders are left out
+	expectBookRes := BookRes{
+		Asks: []BookResItem{{sdk.MustNewDecFromStr("0.6").String(), sdk.MustNewDecFromStr("2.1").String()}},
+		Bids: []BookResItem{{sdk.MustNewDecFromStr("0.4").String(), sdk.MustNewDecFromStr("0.5").String()}},
+	}
+	data := keeper.cdc.MustMarshalJSON(NewQueryDepthBookParams(product, 10))
+	bz, err := querier(ctx, []string{types.QueryDepthBook}, abci.RequestQuery{Data: data})
+	require.Nil(t, err)
+	var bookRes BookRes
+	keeper.cdc.MustUnmarshalJSON(bz, &bookRes)
+	require.EqualValues(t, expectBookRes, bookRes)
+
+	bz, err = querier(ctx, []string{types.QueryDepthBookV2}, abci.RequestQuery{Data: data})
+	require.Nil(t, err)
+	require.Contains(t, string(bz), `"2.10000000"`)
+	require.NotContains(t, string(bz), `"6.10000000"`)
+}
+
 func TestQueryDepthBook(t *testing.T) {
 	testInput := CreateTestInput(t)
 	keeper := testInput.OrderKeeper
@@ -229,6 +272,25 @@ func TestQueryParameters(t *testing.T) {
 		MaxDealsPerBlock:  10000,
 		FeePerBlock:       sdk.NewDecCoinFromDec(types.DefaultFeeDenomPerBlock, sdk.NewDec(1)),
 		TradeFeeRate:      sdk.MustNewDecFromStr("0.001"),
+
+		MaxMarketOrderSlippage: sdk.MustNewDecFromStr("0.1"),
+		AllocationRule:         types.AllocationRuleProRata,
+
+		PriceBand:            sdk.MustNewDecFromStr("0.1"),
+		CircuitBreakerBlocks: 5,
+		MarketPressureRate:   sdk.MustNewDecFromStr("0.02"),
+
+		FeeSchedules: []types.FeeSchedule{{
+			Product:      types.TestTokenPair,
+			MakerFeeRate: sdk.MustNewDecFromStr("0.0005"),
+			TakerFeeRate: sdk.MustNewDecFromStr("0.002"),
+			VolumeTiers: []types.VolumeTier{
+				{MinVolume: sdk.NewDec(10000), Discount: sdk.MustNewDecFromStr("0.2")},
+			},
+		}},
+
+		DealBudgetPolicy:   types.DealBudgetPolicySequential,
+		MinDealsPerProduct: 5,
 	}
 	keeper.SetParams(ctx, params)
 	path := []string{types.QueryParameters}
@@ -240,6 +302,166 @@ func TestQueryParameters(t *testing.T) {
 	require.EqualValues(t, params, res)
 }
 
+func TestQueryIndicativeAuction(t *testing.T) {
+	testInput := CreateTestInput(t)
+	keeper := testInput.OrderKeeper
+	ctx := testInput.Ctx
+	querier := NewQuerier(keeper)
+
+	tokenPair := dex.GetBuiltInTokenPair()
+	err := testInput.DexKeeper.SaveTokenPair(ctx, tokenPair)
+	require.Nil(t, err)
+
+	// the hidden reserve of the iceberg order is left out of the auction
+	icebergOrder := mockOrder("", types.TestTokenPair, types.BuyOrder, "10.1", "5.0")
+	displayQuantity := sdk.MustNewDecFromStr("3.0")
+	icebergOrder.DisplayQuantity = &displayQuantity
+	depthBook := &types.DepthBook{}
+	depthBook.InsertOrder(icebergOrder)
+	depthBook.InsertOrder(mockOrder("", types.TestTokenPair, types.SellOrder, "9.9", "2.0"))
+	keeper.StoreDepthBook(ctx, types.TestTokenPair, depthBook)
+	keeper.SetLastPrice(ctx, types.TestTokenPair, sdk.MustNewDecFromStr("10.0"))
+
+	bz, err := querier(ctx, []string{types.QueryAuction, types.TestTokenPair}, abci.RequestQuery{})
+	require.Nil(t, err)
+	var auctionRes IndicativeAuctionRes
+	keeper.cdc.MustUnmarshalJSON(bz, &auctionRes)
+	require.EqualValues(t, types.TestTokenPair, auctionRes.Product)
+	require.True(t, sdk.MustNewDecFromStr("10.0").Equal(auctionRes.LastPrice))
+	require.True(t, sdk.MustNewDecFromStr("10.1").Equal(auctionRes.Price))
+	require.True(t, sdk.MustNewDecFromStr("2.0").Equal(auctionRes.Quantity))
+	require.True(t, sdk.MustNewDecFromStr("1.0").Equal(auctionRes.Imbalance))
+	require.EqualValues(t, types.BuyOrder, auctionRes.ImbalanceSide)
+	require.EqualValues(t, types.AuctionRuleBuyPressure, auctionRes.Rule)
+
+	// the depth book is not changed by the query
+	require.EqualValues(t, depthBook, keeper.GetDepthBookFromDB(ctx, types.TestTokenPair))
+
+	// query the product not existed
+	_, err = querier(ctx, []string{types.QueryAuction, "nope_okt"}, abci.RequestQuery{})
+	require.NotNil(t, err)
+	_, err = querier(ctx, []string{types.QueryAuction}, abci.RequestQuery{})
+	require.NotNil(t, err)
+}
+
+func TestQueryCancelAfter(t *testing.T) {
+	testInput := CreateTestInput(t)
+	keeper := testInput.OrderKeeper
+	ctx := testInput.Ctx
+	querier := NewQuerier(keeper)
+	addr := testInput.TestAddrs[0]
+
+	_, err := querier(ctx, []string{types.QueryCancelAfter, addr.String()}, abci.RequestQuery{})
+	require.NotNil(t, err)
+
+	keeper.SetCancelAfter(ctx, types.NewCancelAfter(addr, types.TestTokenPair, 20))
+	bz, err := querier(ctx, []string{types.QueryCancelAfter, addr.String()}, abci.RequestQuery{})
+	require.Nil(t, err)
+	var cancelAfter types.CancelAfter
+	keeper.cdc.MustUnmarshalJSON(bz, &cancelAfter)
+	require.EqualValues(t, *types.NewCancelAfter(addr, types.TestTokenPair, 20), cancelAfter)
+
+	_, err = querier(ctx, []string{types.QueryCancelAfter, "invalid"}, abci.RequestQuery{})
+	require.NotNil(t, err)
+}
+
+func TestQueryTradingGrants(t *testing.T) {
+	testInput := CreateTestInput(t)
+	keeper := testInput.OrderKeeper
+	ctx := testInput.Ctx
+	querier := NewQuerier(keeper)
+	granter, grantee := testInput.TestAddrs[0], testInput.TestAddrs[1]
+
+	var grants []*types.TradingGrant
+	bz, err := querier(ctx, []string{types.QueryGrants, granter.String()}, abci.RequestQuery{})
+	require.Nil(t, err)
+	keeper.cdc.MustUnmarshalJSON(bz, &grants)
+	require.EqualValues(t, 0, len(grants))
+	_, err = querier(ctx, []string{types.QueryGrants, granter.String(), grantee.String()}, abci.RequestQuery{})
+	require.NotNil(t, err)
+
+	grant := types.NewTradingGrant(granter, grantee, []string{types.TestTokenPair}, sdk.NewDec(100), 20)
+	keeper.SetTradingGrant(ctx, grant)
+	bz, err = querier(ctx, []string{types.QueryGrants, granter.String(), grantee.String()}, abci.RequestQuery{})
+	require.Nil(t, err)
+	keeper.cdc.MustUnmarshalJSON(bz, &grants)
+	require.EqualValues(t, []*types.TradingGrant{grant}, grants)
+
+	_, err = querier(ctx, []string{types.QueryGrants}, abci.RequestQuery{})
+	require.NotNil(t, err)
+	_, err = querier(ctx, []string{types.QueryGrants, "invalid"}, abci.RequestQuery{})
+	require.NotNil(t, err)
+}
+
+func TestQueryOpenOrders(t *testing.T) {
+	testInput := CreateTestInput(t)
+	keeper := testInput.OrderKeeper
+	ctx := testInput.Ctx.WithBlockHeight(10)
+	querier := NewQuerier(keeper)
+	tokenPair := dex.GetBuiltInTokenPair()
+	err := testInput.DexKeeper.SaveTokenPair(ctx, tokenPair)
+	require.Nil(t, err)
+
+	orders := []*types.Order{
+		mockOrder("", types.TestTokenPair, types.BuyOrder, "1.0", "1.0"),
+		mockOrder("", types.TestTokenPair, types.BuyOrder, "2.0", "1.0"),
+		mockOrder("", types.TestTokenPair, types.SellOrder, "3.0", "1.0"),
+		mockOrder("", types.TestTokenPair, types.SellOrder, "3.0", "1.0"),
+	}
+	for i, order := range orders {
+		order.Sender = testInput.TestAddrs[i/3]
+		require.Nil(t, keeper.PlaceOrder(ctx, order))
+	}
+	addr := testInput.TestAddrs[0].String()
+
+	queryOpenOrders := func(params QueryOpenOrdersParams) (orderIDs []string, total int) {
+		bz, err := querier(ctx, []string{types.QueryOpenOrders}, abci.RequestQuery{Data: keeper.cdc.MustMarshalJSON(params)})
+		require.Nil(t, err)
+		var res struct {
+			Data struct {
+				Data      []types.Order    `json:"data"`
+				ParamPage common.ParamPage `json:"param_page"`
+			} `json:"data"`
+		}
+		require.Nil(t, json.Unmarshal(bz, &res))
+		for _, order := range res.Data.Data {
+			orderIDs = append(orderIDs, order.OrderID)
+		}
+		return orderIDs, res.Data.ParamPage.Total
+	}
+
+	orderIDs, total := queryOpenOrders(NewQueryOpenOrdersParams(addr, "", "", 0, 0))
+	require.EqualValues(t, []string{orders[0].OrderID, orders[1].OrderID, orders[2].OrderID}, orderIDs)
+	require.EqualValues(t, 3, total)
+	orderIDs, total = queryOpenOrders(NewQueryOpenOrdersParams(addr, "", types.BuyOrder, 2, 1))
+	require.EqualValues(t, []string{orders[1].OrderID}, orderIDs)
+	require.EqualValues(t, 2, total)
+	orderIDs, total = queryOpenOrders(NewQueryOpenOrdersParams(addr, types.TestTokenPair, types.SellOrder, 2, 1))
+	require.Nil(t, orderIDs)
+	require.EqualValues(t, 1, total)
+	orderIDs, _ = queryOpenOrders(NewQueryOpenOrdersParams(addr, "xxb_okb", "", 0, 0))
+	require.Nil(t, orderIDs)
+
+	// the filled and canceled orders are no longer open
+	feeParams := types.DefaultParams()
+	keeper.FillOrder(ctx, orders[0], orders[0].Price, orders[0].Quantity, &feeParams, false)
+	keeper.CancelOrder(ctx, orders[2], ctx.Logger())
+	orderIDs, total = queryOpenOrders(NewQueryOpenOrdersParams(addr, "", "", 0, 0))
+	require.EqualValues(t, []string{orders[1].OrderID}, orderIDs)
+	require.EqualValues(t, 1, total)
+
+	for _, params := range []QueryOpenOrdersParams{
+		NewQueryOpenOrdersParams("invalid", "", "", 0, 0),
+		NewQueryOpenOrdersParams(addr, "", "invalid", 0, 0),
+		NewQueryOpenOrdersParams(addr, "", "", 0, 1),
+	} {
+		_, err = querier(ctx, []string{types.QueryOpenOrders}, abci.RequestQuery{Data: keeper.cdc.MustMarshalJSON(params)})
+		require.NotNil(t, err)
+	}
+	_, err = querier(ctx, []string{types.QueryOpenOrders}, abci.RequestQuery{Data: []byte("invalid")})
+	require.NotNil(t, err)
+}
+
 func TestQueryInvalidPath(t *testing.T) {
 	testInput := CreateTestInput(t)
 	keeper := testInput.OrderKeeper
@@ -0,0 +1,126 @@
+package keeper
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/tendermint/tendermint/libs/log"
+
+	"github.com/okex/okchain/x/order/types"
+)
+
+// SetAccountSelfTradePrevention sets the self-trade prevention mode of the account, empty mode allows self trades
+func (k Keeper) SetAccountSelfTradePrevention(ctx sdk.Context, addr sdk.AccAddress, mode string) {
+	store := ctx.KVStore(k.orderStoreKey)
+	if mode == "" {
+		store.Delete(types.GetSelfTradeModeKey(addr))
+		return
+	}
+	store.Set(types.GetSelfTradeModeKey(addr), []byte(mode))
+}
+
+// GetAccountSelfTradePrevention returns the self-trade prevention mode of the account
+func (k Keeper) GetAccountSelfTradePrevention(ctx sdk.Context, addr sdk.AccAddress) string {
+	store := ctx.KVStore(k.orderStoreKey)
+	return string(store.Get(types.GetSelfTradeModeKey(addr)))
+}
+
+// GetSelfTradeModes returns the self-trade prevention modes of all the accounts
+func (k Keeper) GetSelfTradeModes(ctx sdk.Context) []*types.SelfTradeMode {
+	store := ctx.KVStore(k.orderStoreKey)
+	iter := sdk.KVStorePrefixIterator(store, types.SelfTradeModeKey)
+	defer iter.Close()
+
+	var modes []*types.SelfTradeMode
+	for ; iter.Valid(); iter.Next() {
+		modes = append(modes, &types.SelfTradeMode{
+			Address: sdk.AccAddress(iter.Key()[len(types.SelfTradeModeKey):]),
+			Mode:    string(iter.Value()),
+		})
+	}
+	return modes
+}
+
+// GetSelfTradePrevention returns the self-trade prevention mode of the order, which falls back to the mode of its
+// sender account
+func (k Keeper) GetSelfTradePrevention(ctx sdk.Context, order *types.Order) string {
+	if order.SelfTradePrevention != "" {
+		return order.SelfTradePrevention
+	}
+	return k.GetAccountSelfTradePrevention(ctx, order.Sender)
+}
+
+// PreventSelfTrade stops the newer order from trading the quantity with the older order of the same sender, by the
+// self-trade prevention mode of the newer order. The cancelled order is quit with the cancelled state, and the
+// decremented order keeps its place in the queue. It returns the mode applied, empty if the self trade is allowed
+func (k Keeper) PreventSelfTrade(ctx sdk.Context, newer, older *types.Order, quantity sdk.Dec,
+	logger log.Logger) string {
+
+	mode := k.GetSelfTradePrevention(ctx, newer)
+	switch mode {
+	case types.SelfTradePreventionCancelNewest:
+		k.cancelSelfTrade(ctx, newer, older, mode, logger)
+	case types.SelfTradePreventionCancelOldest:
+		k.cancelSelfTrade(ctx, older, newer, mode, logger)
+	case types.SelfTradePreventionDecrementBoth:
+		k.decrementSelfTrade(ctx, newer, older, quantity, logger)
+		k.decrementSelfTrade(ctx, older, newer, quantity, logger)
+	default:
+		return ""
+	}
+	return mode
+}
+
+func (k Keeper) cancelSelfTrade(ctx sdk.Context, order, counterOrder *types.Order, mode string, logger log.Logger) {
+	record := types.SelfTrade{
+		Product:        order.Product,
+		OrderID:        order.OrderID,
+		CounterOrderID: counterOrder.OrderID,
+		Mode:           mode,
+		Quantity:       order.RemainQuantity,
+		Cancelled:      true,
+	}
+	k.quitOrder(ctx, order, types.FeeTypeOrderSelfTrade, logger)
+	k.addSelfTrade(ctx, record)
+	logger.Info(fmt.Sprintf("order (%s) cancelled by self-trade prevention(%s) against order (%s), "+
+		"remainQuantity: %v", order.OrderID, mode, counterOrder.OrderID, record.Quantity))
+}
+
+func (k Keeper) decrementSelfTrade(ctx sdk.Context, order, counterOrder *types.Order, quantity sdk.Dec,
+	logger log.Logger) {
+
+	if quantity.GTE(order.RemainQuantity) {
+		k.cancelSelfTrade(ctx, order, counterOrder, types.SelfTradePreventionDecrementBoth, logger)
+		return
+	}
+	if err := k.ReplaceOrder(ctx, order, order.Price, order.Quantity.Sub(quantity)); err != nil {
+		logger.Error(fmt.Sprintf("failed to decrement order (%s) by self-trade prevention: %v", order.OrderID, err))
+		return
+	}
+	k.addSelfTrade(ctx, types.SelfTrade{
+		Product:        order.Product,
+		OrderID:        order.OrderID,
+		CounterOrderID: counterOrder.OrderID,
+		Mode:           types.SelfTradePreventionDecrementBoth,
+		Quantity:       quantity,
+	})
+	logger.Info(fmt.Sprintf("order (%s) decremented by self-trade prevention against order (%s), "+
+		"quantity: %v, remainQuantity: %v", order.OrderID, counterOrder.OrderID, quantity, order.RemainQuantity))
+}
+
+// addSelfTrade reports the self-trade prevention in the match result of current block
+func (k Keeper) addSelfTrade(ctx sdk.Context, record types.SelfTrade) {
+	if !k.enableBackend {
+		return
+	}
+	result := k.cache.getBlockMatchResult()
+	if result == nil || result.BlockHeight != ctx.BlockHeight() || result.ResultMap == nil {
+		result = &types.BlockMatchResult{
+			BlockHeight: ctx.BlockHeight(),
+			ResultMap:   make(map[string]types.MatchResult),
+			TimeStamp:   ctx.BlockHeader().Time.Unix(),
+		}
+		k.cache.setBlockMatchResult(result)
+	}
+	result.SelfTrades = append(result.SelfTrades, record)
+}
@@ -0,0 +1,57 @@
+package keeper
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/okex/okchain/x/dex"
+	"github.com/okex/okchain/x/order/types"
+)
+
+func TestKeeper_PreventSelfTrade(t *testing.T) {
+	testInput := CreateTestInput(t)
+	keeper := testInput.OrderKeeper
+	ctx := testInput.Ctx.WithBlockHeight(10)
+	err := testInput.DexKeeper.SaveTokenPair(ctx, dex.GetBuiltInTokenPair())
+	require.Nil(t, err)
+	logger := ctx.Logger()
+
+	orders := []*types.Order{
+		types.MockOrder("", types.TestTokenPair, types.BuyOrder, "10.0", "1.0"),
+		types.MockOrder("", types.TestTokenPair, types.BuyOrder, "10.0", "3.0"),
+		types.MockOrder("", types.TestTokenPair, types.SellOrder, "10.0", "2.0"),
+	}
+	for _, order := range orders {
+		order.Sender = testInput.TestAddrs[0]
+		require.NoError(t, keeper.PlaceOrder(ctx, order))
+	}
+
+	// self trades are allowed without any mode
+	require.EqualValues(t, "", keeper.PreventSelfTrade(ctx, orders[2], orders[1], sdk.OneDec(), logger))
+
+	// the bid is decremented and keeps its place in the queue, the ask is closed
+	orders[2].SelfTradePrevention = types.SelfTradePreventionDecrementBoth
+	mode := keeper.PreventSelfTrade(ctx, orders[2], orders[1], sdk.MustNewDecFromStr("2"), logger)
+	require.EqualValues(t, types.SelfTradePreventionDecrementBoth, mode)
+	bid := keeper.GetOrder(ctx, orders[1].OrderID)
+	require.EqualValues(t, types.OrderStatusOpen, bid.Status)
+	require.EqualValues(t, sdk.MustNewDecFromStr("1"), bid.RemainQuantity)
+	require.EqualValues(t, sdk.MustNewDecFromStr("10"), bid.RemainLocked)
+	key := types.FormatOrderIDsKey(types.TestTokenPair, sdk.MustNewDecFromStr("10.0"), types.BuyOrder)
+	require.EqualValues(t, []string{orders[0].OrderID, orders[1].OrderID}, keeper.GetProductPriceOrderIDs(key))
+	require.EqualValues(t, types.OrderStatusCancelled, keeper.GetOrder(ctx, orders[2].OrderID).Status)
+
+	book := keeper.GetDepthBookCopy(types.TestTokenPair)
+	require.EqualValues(t, 1, len(book.Items))
+	require.EqualValues(t, sdk.MustNewDecFromStr("2"), book.Items[0].BuyQuantity)
+	require.True(t, book.Items[0].SellQuantity.IsZero())
+
+	selfTrades := keeper.GetBlockMatchResult().SelfTrades
+	require.EqualValues(t, 2, len(selfTrades))
+	require.EqualValues(t, orders[2].OrderID, selfTrades[0].OrderID)
+	require.True(t, selfTrades[0].Cancelled)
+	require.EqualValues(t, orders[1].OrderID, selfTrades[1].OrderID)
+	require.False(t, selfTrades[1].Cancelled)
+}
@@ -0,0 +1,113 @@
+package keeper
+
+import (
+	"encoding/binary"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/okex/okchain/x/order/types"
+)
+
+// getTradeDay returns the day of the block time, the trade volume is recorded by day
+func getTradeDay(ctx sdk.Context) int64 {
+	return ctx.BlockHeader().Time.Unix() / types.SecondsInADay
+}
+
+// AddTradeVolume adds the trade volume of the address on the token pair to the record of current day, and drops
+// the records out of the trailing days
+func (k Keeper) AddTradeVolume(ctx sdk.Context, addr sdk.AccAddress, product string, volume sdk.Dec) {
+	store := ctx.KVStore(k.orderStoreKey)
+	day := getTradeDay(ctx)
+	key := types.GetTradeVolumeKey(product, addr, day)
+
+	total := volume
+	if bz := store.Get(key); bz != nil {
+		var dayVolume sdk.Dec
+		k.cdc.MustUnmarshalBinaryBare(bz, &dayVolume)
+		total = total.Add(dayVolume)
+	} else {
+		k.dropExpiredTradeVolumes(ctx, addr, product, day-types.TradeVolumeDays+1)
+	}
+	store.Set(key, k.cdc.MustMarshalBinaryBare(total))
+}
+
+// dropExpiredTradeVolumes deletes the trade volume records before the start day
+func (k Keeper) dropExpiredTradeVolumes(ctx sdk.Context, addr sdk.AccAddress, product string, startDay int64) {
+	if startDay <= 0 {
+		return
+	}
+	store := ctx.KVStore(k.orderStoreKey)
+	iter := store.Iterator(types.GetTradeVolumePrefix(product, addr), types.GetTradeVolumeKey(product, addr, startDay))
+	var keys [][]byte
+	for ; iter.Valid(); iter.Next() {
+		keys = append(keys, iter.Key())
+	}
+	iter.Close()
+
+	for _, key := range keys {
+		store.Delete(key)
+	}
+}
+
+// GetTrailingTradeVolume returns the trade volume of the address on the token pair in the trailing days,
+// including current day
+func (k Keeper) GetTrailingTradeVolume(ctx sdk.Context, addr sdk.AccAddress, product string) sdk.Dec {
+	store := ctx.KVStore(k.orderStoreKey)
+	day := getTradeDay(ctx)
+	startDay := day - types.TradeVolumeDays + 1
+	if startDay < 0 {
+		startDay = 0
+	}
+	iter := store.Iterator(types.GetTradeVolumeKey(product, addr, startDay),
+		types.GetTradeVolumeKey(product, addr, day+1))
+	defer iter.Close()
+
+	total := sdk.ZeroDec()
+	for ; iter.Valid(); iter.Next() {
+		var dayVolume sdk.Dec
+		k.cdc.MustUnmarshalBinaryBare(iter.Value(), &dayVolume)
+		total = total.Add(dayVolume)
+	}
+	return total
+}
+
+// SetTradeVolume sets the trade volume record of the address on the token pair in the day
+func (k Keeper) SetTradeVolume(ctx sdk.Context, volume *types.TradeVolume) {
+	store := ctx.KVStore(k.orderStoreKey)
+	store.Set(types.GetTradeVolumeKey(volume.Product, volume.Address, volume.Day),
+		k.cdc.MustMarshalBinaryBare(volume.Volume))
+}
+
+// GetTradeVolumes returns all the trade volume records. The key is made up of the product, a colon, the address
+// and the day, whose lengths are fixed except the product
+func (k Keeper) GetTradeVolumes(ctx sdk.Context) []*types.TradeVolume {
+	store := ctx.KVStore(k.orderStoreKey)
+	iter := sdk.KVStorePrefixIterator(store, types.TradeVolumeKey)
+	defer iter.Close()
+
+	var volumes []*types.TradeVolume
+	for ; iter.Valid(); iter.Next() {
+		key := iter.Key()[len(types.TradeVolumeKey):]
+		dayStart := len(key) - 8
+		addrStart := dayStart - sdk.AddrLen
+		volume := &types.TradeVolume{
+			Address: sdk.AccAddress(key[addrStart:dayStart]),
+			Product: string(key[:addrStart-1]),
+			Day:     int64(binary.BigEndian.Uint64(key[dayStart:])),
+		}
+		k.cdc.MustUnmarshalBinaryBare(iter.Value(), &volume.Volume)
+		volumes = append(volumes, volume)
+	}
+	return volumes
+}
+
+// GetDealFeeRate returns the deal fee rate of the order by the fee schedule of its token pair, the liquidity it
+// provided or took, and the trailing trade volume of its sender
+func (k Keeper) GetDealFeeRate(ctx sdk.Context, order *types.Order, isMaker bool, feeParams *types.Params) sdk.Dec {
+	schedule := feeParams.GetFeeSchedule(order.Product)
+	trailingVolume := sdk.ZeroDec()
+	if len(schedule.VolumeTiers) > 0 {
+		trailingVolume = k.GetTrailingTradeVolume(ctx, order.Sender, order.Product)
+	}
+	return schedule.FeeRate(isMaker, trailingVolume)
+}
@@ -0,0 +1,73 @@
+package keeper
+
+import (
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+
+	"github.com/okex/okchain/x/order/types"
+)
+
+func TestKeeper_TradeVolume(t *testing.T) {
+	testInput := CreateTestInput(t)
+	keeper := testInput.OrderKeeper
+	addr := testInput.TestAddrs[0]
+	start := time.Unix(100*types.SecondsInADay, 0)
+	ctxAtDay := func(day int64) sdk.Context {
+		return testInput.Ctx.WithBlockHeader(abci.Header{Time: start.Add(time.Duration(day) * 24 * time.Hour)})
+	}
+
+	ctx := ctxAtDay(0)
+	require.EqualValues(t, sdk.ZeroDec(), keeper.GetTrailingTradeVolume(ctx, addr, types.TestTokenPair))
+	keeper.AddTradeVolume(ctx, addr, types.TestTokenPair, sdk.NewDec(100))
+	keeper.AddTradeVolume(ctx, addr, types.TestTokenPair, sdk.NewDec(50))
+	require.EqualValues(t, sdk.NewDec(150), keeper.GetTrailingTradeVolume(ctx, addr, types.TestTokenPair))
+	// volume of other addresses and token pairs is not counted
+	require.EqualValues(t, sdk.ZeroDec(), keeper.GetTrailingTradeVolume(ctx, testInput.TestAddrs[1], types.TestTokenPair))
+	require.EqualValues(t, sdk.ZeroDec(), keeper.GetTrailingTradeVolume(ctx, addr, "xxb_yyb"))
+
+	ctx = ctxAtDay(types.TradeVolumeDays - 1)
+	keeper.AddTradeVolume(ctx, addr, types.TestTokenPair, sdk.NewDec(10))
+	require.EqualValues(t, sdk.NewDec(160), keeper.GetTrailingTradeVolume(ctx, addr, types.TestTokenPair))
+
+	// the volume of day 0 is out of the trailing days, and dropped when the volume of a new day is added
+	ctx = ctxAtDay(types.TradeVolumeDays)
+	require.EqualValues(t, sdk.NewDec(10), keeper.GetTrailingTradeVolume(ctx, addr, types.TestTokenPair))
+	keeper.AddTradeVolume(ctx, addr, types.TestTokenPair, sdk.NewDec(1))
+	require.EqualValues(t, sdk.NewDec(11), keeper.GetTrailingTradeVolume(ctx, addr, types.TestTokenPair))
+	require.EqualValues(t, sdk.ZeroDec(), keeper.GetTrailingTradeVolume(ctxAtDay(0), addr, types.TestTokenPair))
+}
+
+func TestKeeper_GetDealFeeRate(t *testing.T) {
+	testInput := CreateTestInput(t)
+	keeper := testInput.OrderKeeper
+	ctx := testInput.Ctx
+
+	order := mockOrder("", types.TestTokenPair, types.BuyOrder, "10.0", "1.0")
+	order.Sender = testInput.TestAddrs[0]
+
+	// the token pair without a fee schedule is charged at the trade fee rate
+	params := types.DefaultParams()
+	require.EqualValues(t, params.TradeFeeRate, keeper.GetDealFeeRate(ctx, order, true, &params))
+	require.EqualValues(t, params.TradeFeeRate, keeper.GetDealFeeRate(ctx, order, false, &params))
+
+	params.FeeSchedules = []types.FeeSchedule{{
+		Product:      types.TestTokenPair,
+		MakerFeeRate: sdk.MustNewDecFromStr("0.001"),
+		TakerFeeRate: sdk.MustNewDecFromStr("0.002"),
+		VolumeTiers: []types.VolumeTier{
+			{MinVolume: sdk.NewDec(100), Discount: sdk.MustNewDecFromStr("0.2")},
+			{MinVolume: sdk.NewDec(1000), Discount: sdk.MustNewDecFromStr("0.5")},
+		},
+	}}
+	require.EqualValues(t, sdk.MustNewDecFromStr("0.001"), keeper.GetDealFeeRate(ctx, order, true, &params))
+	require.EqualValues(t, sdk.MustNewDecFromStr("0.002"), keeper.GetDealFeeRate(ctx, order, false, &params))
+
+	keeper.AddTradeVolume(ctx, order.Sender, types.TestTokenPair, sdk.NewDec(100))
+	require.EqualValues(t, sdk.MustNewDecFromStr("0.0008"), keeper.GetDealFeeRate(ctx, order, true, &params))
+	keeper.AddTradeVolume(ctx, order.Sender, types.TestTokenPair, sdk.NewDec(900))
+	require.EqualValues(t, sdk.MustNewDecFromStr("0.001"), keeper.GetDealFeeRate(ctx, order, false, &params))
+}
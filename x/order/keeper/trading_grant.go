@@ -0,0 +1,120 @@
+package keeper
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/okex/okchain/x/order/types"
+)
+
+// SetTradingGrant sets the trading grant from the granter to the grantee, and replaces the expire height of the
+// previous grant in the expiry index
+func (k Keeper) SetTradingGrant(ctx sdk.Context, grant *types.TradingGrant) {
+	k.DropTradingGrant(ctx, grant.Granter, grant.Grantee)
+	store := ctx.KVStore(k.orderStoreKey)
+	store.Set(types.GetTradingGrantKey(grant.Granter, grant.Grantee), k.cdc.MustMarshalBinaryBare(grant))
+	if grant.ExpireHeight > 0 {
+		store.Set(types.GetGrantExpiryKey(grant.ExpireHeight, grant.Granter, grant.Grantee), []byte{})
+	}
+}
+
+// GetTradingGrant returns the trading grant from the granter to the grantee, nil if not granted
+func (k Keeper) GetTradingGrant(ctx sdk.Context, granter, grantee sdk.AccAddress) *types.TradingGrant {
+	store := ctx.KVStore(k.orderStoreKey)
+	bz := store.Get(types.GetTradingGrantKey(granter, grantee))
+	if bz == nil {
+		return nil
+	}
+	var grant types.TradingGrant
+	k.cdc.MustUnmarshalBinaryBare(bz, &grant)
+	return &grant
+}
+
+// DropTradingGrant revokes the trading grant from the granter to the grantee, and deletes its expire height
+func (k Keeper) DropTradingGrant(ctx sdk.Context, granter, grantee sdk.AccAddress) {
+	grant := k.GetTradingGrant(ctx, granter, grantee)
+	if grant == nil {
+		return
+	}
+	store := ctx.KVStore(k.orderStoreKey)
+	store.Delete(types.GetGrantExpiryKey(grant.ExpireHeight, granter, grantee))
+	store.Delete(types.GetTradingGrantKey(granter, grantee))
+}
+
+// DropExpiredTradingGrants deletes the trading grants whose expire height is not greater than the specified height,
+// they are never authorized after it
+func (k Keeper) DropExpiredTradingGrants(ctx sdk.Context, height int64) {
+	store := ctx.KVStore(k.orderStoreKey)
+	iter := store.Iterator(types.GrantExpiryKey, types.GetGrantExpiryPrefix(height+1))
+	prefixLen := len(types.GetGrantExpiryPrefix(height))
+	var pairs [][2]sdk.AccAddress
+	for ; iter.Valid(); iter.Next() {
+		key := iter.Key()[prefixLen:]
+		pairs = append(pairs, [2]sdk.AccAddress{key[:sdk.AddrLen], key[sdk.AddrLen:]})
+	}
+	iter.Close()
+
+	for _, pair := range pairs {
+		k.DropTradingGrant(ctx, pair[0], pair[1])
+	}
+}
+
+// GetTradingGrants returns the trading grants from the granter, or from all the granters if it is empty
+func (k Keeper) GetTradingGrants(ctx sdk.Context, granter sdk.AccAddress) []*types.TradingGrant {
+	store := ctx.KVStore(k.orderStoreKey)
+	iter := sdk.KVStorePrefixIterator(store, types.GetTradingGrantPrefix(granter))
+	defer iter.Close()
+
+	var grants []*types.TradingGrant
+	for ; iter.Valid(); iter.Next() {
+		var grant types.TradingGrant
+		k.cdc.MustUnmarshalBinaryBare(iter.Value(), &grant)
+		grants = append(grants, &grant)
+	}
+	return grants
+}
+
+// AuthorizeTrading checks whether the grantee is permitted to trade the product on behalf of the granter, and to
+// place the order of the notional if it is positive
+func (k Keeper) AuthorizeTrading(ctx sdk.Context, granter, grantee sdk.AccAddress, product string,
+	notional sdk.Dec) error {
+
+	grant := k.GetTradingGrant(ctx, granter, grantee)
+	if grant == nil {
+		return fmt.Errorf("%s is not granted to trade on behalf of %s", grantee, granter)
+	}
+	return grant.Authorize(ctx.BlockHeight(), product, notional)
+}
+
+// ConsumeTradingGrant authorizes the order placed by the grantee on behalf of the granter, and adds its notional to
+// the notional used by the grant
+func (k Keeper) ConsumeTradingGrant(ctx sdk.Context, granter, grantee sdk.AccAddress, product string,
+	notional sdk.Dec) error {
+
+	if err := k.AuthorizeTrading(ctx, granter, grantee, product, notional); err != nil {
+		return err
+	}
+	grant := k.GetTradingGrant(ctx, granter, grantee)
+	grant.UsedNotional = grant.UsedNotional.Add(notional)
+	k.SetTradingGrant(ctx, grant)
+	return nil
+}
+
+// releaseTradingGrant gives the notional of the unfilled part of the order placed by a grantee back to its trading
+// grant, once the order quits or is replaced by its sender. The filled part stays used
+func (k Keeper) releaseTradingGrant(ctx sdk.Context, order *types.Order) {
+	if order.Grantee.Empty() {
+		return
+	}
+	grant := k.GetTradingGrant(ctx, order.Sender, order.Grantee)
+	if grant == nil {
+		return
+	}
+	grant.UsedNotional = grant.UsedNotional.Sub(order.Price.Mul(order.RemainQuantity))
+	// the grant replaced after the order was placed has not used its notional
+	if grant.UsedNotional.IsNegative() {
+		grant.UsedNotional = sdk.ZeroDec()
+	}
+	k.SetTradingGrant(ctx, grant)
+}
@@ -0,0 +1,89 @@
+package keeper
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/okex/okchain/x/order/types"
+)
+
+func TestKeeper_TradingGrant(t *testing.T) {
+	testInput := CreateTestInput(t)
+	keeper := testInput.OrderKeeper
+	ctx := testInput.Ctx.WithBlockHeight(10)
+	granter, grantee := testInput.TestAddrs[0], testInput.TestAddrs[1]
+
+	require.Nil(t, keeper.GetTradingGrant(ctx, granter, grantee))
+	require.NotNil(t, keeper.AuthorizeTrading(ctx, granter, grantee, types.TestTokenPair, sdk.ZeroDec()))
+
+	keeper.SetTradingGrant(ctx, types.NewTradingGrant(granter, grantee, []string{types.TestTokenPair},
+		sdk.NewDec(100), 20))
+	keeper.SetTradingGrant(ctx, types.NewTradingGrant(grantee, granter, nil, sdk.ZeroDec(), 0))
+	require.EqualValues(t, 1, len(keeper.GetTradingGrants(ctx, granter)))
+	require.EqualValues(t, 2, len(keeper.GetTradingGrants(ctx, nil)))
+
+	// the product and the notional cap of the grant
+	require.Nil(t, keeper.AuthorizeTrading(ctx, granter, grantee, types.TestTokenPair, sdk.NewDec(100)))
+	require.NotNil(t, keeper.AuthorizeTrading(ctx, granter, grantee, "okt_xxb", sdk.ZeroDec()))
+	require.Nil(t, keeper.ConsumeTradingGrant(ctx, granter, grantee, types.TestTokenPair, sdk.NewDec(60)))
+	require.NotNil(t, keeper.ConsumeTradingGrant(ctx, granter, grantee, types.TestTokenPair, sdk.NewDec(60)))
+	require.EqualValues(t, sdk.NewDec(60), keeper.GetTradingGrant(ctx, granter, grantee).UsedNotional)
+	require.Nil(t, keeper.AuthorizeTrading(ctx, granter, grantee, types.TestTokenPair, sdk.ZeroDec()))
+
+	// no cap and all the products are permitted
+	require.Nil(t, keeper.ConsumeTradingGrant(ctx, grantee, granter, "okt_xxb", sdk.NewDec(1000)))
+
+	// the grant expires after its expire height
+	require.Nil(t, keeper.AuthorizeTrading(ctx.WithBlockHeight(20), granter, grantee, types.TestTokenPair,
+		sdk.ZeroDec()))
+	require.NotNil(t, keeper.AuthorizeTrading(ctx.WithBlockHeight(21), granter, grantee, types.TestTokenPair,
+		sdk.ZeroDec()))
+
+	keeper.DropTradingGrant(ctx, granter, grantee)
+	require.Nil(t, keeper.GetTradingGrant(ctx, granter, grantee))
+	require.EqualValues(t, 1, len(keeper.GetTradingGrants(ctx, nil)))
+}
+
+func TestKeeper_DropExpiredTradingGrants(t *testing.T) {
+	testInput := CreateTestInput(t)
+	keeper := testInput.OrderKeeper
+	ctx := testInput.Ctx.WithBlockHeight(10)
+	granter, grantee := testInput.TestAddrs[0], testInput.TestAddrs[1]
+
+	keeper.SetTradingGrant(ctx, types.NewTradingGrant(granter, grantee, nil, sdk.ZeroDec(), 20))
+	keeper.SetTradingGrant(ctx, types.NewTradingGrant(grantee, granter, nil, sdk.ZeroDec(), 0))
+	// the grant replaced expires at its new expire height
+	keeper.SetTradingGrant(ctx, types.NewTradingGrant(granter, grantee, nil, sdk.ZeroDec(), 30))
+
+	keeper.DropExpiredTradingGrants(ctx, 29)
+	require.EqualValues(t, 2, len(keeper.GetTradingGrants(ctx, nil)))
+	keeper.DropExpiredTradingGrants(ctx, 30)
+	require.Nil(t, keeper.GetTradingGrant(ctx, granter, grantee))
+	require.EqualValues(t, 1, len(keeper.GetTradingGrants(ctx, nil)))
+	keeper.DropExpiredTradingGrants(ctx, 100)
+	require.NotNil(t, keeper.GetTradingGrant(ctx, grantee, granter))
+}
+
+func TestKeeper_ReleaseTradingGrant(t *testing.T) {
+	testInput := CreateTestInput(t)
+	keeper := testInput.OrderKeeper
+	ctx := testInput.Ctx.WithBlockHeight(10)
+	granter, grantee := testInput.TestAddrs[0], testInput.TestAddrs[1]
+
+	keeper.SetTradingGrant(ctx, types.NewTradingGrant(granter, grantee, nil, sdk.NewDec(100), 0))
+	require.Nil(t, keeper.ConsumeTradingGrant(ctx, granter, grantee, types.TestTokenPair, sdk.NewDec(30)))
+
+	// the unfilled part of the order is given back, the filled part stays used
+	order := types.MockOrder("", types.TestTokenPair, types.BuyOrder, "10.0", "3.0")
+	order.Sender, order.Grantee = granter, grantee
+	order.RemainQuantity = sdk.NewDec(2)
+	keeper.releaseTradingGrant(ctx, order)
+	require.EqualValues(t, sdk.NewDec(10), keeper.GetTradingGrant(ctx, granter, grantee).UsedNotional)
+
+	// the grant replaced after the order was placed is never negative
+	keeper.SetTradingGrant(ctx, types.NewTradingGrant(granter, grantee, nil, sdk.NewDec(100), 0))
+	keeper.releaseTradingGrant(ctx, order)
+	require.EqualValues(t, sdk.ZeroDec(), keeper.GetTradingGrant(ctx, granter, grantee).UsedNotional)
+}
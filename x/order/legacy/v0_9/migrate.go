@@ -2,6 +2,7 @@
 package v0_9
 
 import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
 	v08order "github.com/okex/okchain/x/order/legacy/v0_8"
 	"github.com/okex/okchain/x/order/types"
 )
@@ -13,6 +14,16 @@ func Migrate(oldGenState v08order.GenesisState) GenesisState {
 		MaxDealsPerBlock:  oldGenState.Params.MaxDealsPerBlock,
 		FeePerBlock:       types.DefaultFeePerBlock,
 		TradeFeeRate:      oldGenState.Params.TradeFeeRate,
+
+		MaxMarketOrderSlippage: sdk.MustNewDecFromStr(types.DefaultMaxMarketOrderSlippage),
+		AllocationRule:         types.DefaultAllocationRule,
+
+		PriceBand:            sdk.MustNewDecFromStr(types.DefaultPriceBand),
+		CircuitBreakerBlocks: types.DefaultCircuitBreakerBlocks,
+		MarketPressureRate:   sdk.MustNewDecFromStr(types.DefaultMarketPressureRate),
+
+		DealBudgetPolicy:   types.DefaultDealBudgetPolicy,
+		MinDealsPerProduct: types.DefaultMinDealsPerProduct,
 	}
 
 	orders := make([]*types.Order, 0, len(oldGenState.OpenOrders))
@@ -0,0 +1,220 @@
+package match
+
+import (
+	"fmt"
+	"sort"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/okex/okchain/x/order/keeper"
+	"github.com/okex/okchain/x/order/types"
+)
+
+func expireOrdersInExpiredBlock(ctx sdk.Context, k keeper.Keeper, expiredBlockHeight int64) {
+	logger := ctx.Logger().With("module", "order")
+	orderNum := k.GetBlockOrderNum(ctx, expiredBlockHeight)
+	var index int64
+	for ; index < orderNum; index++ {
+		orderID := types.FormatOrderID(expiredBlockHeight, index+1)
+		order := k.GetOrder(ctx, orderID)
+		if order != nil && (order.Status == types.OrderStatusOpen || order.IsUntriggered()) &&
+			!k.IsProductLocked(order.Product) {
+			k.ExpireOrder(ctx, order, logger)
+			logger.Info(fmt.Sprintf("order (%s) expired", order.OrderID))
+		}
+	}
+}
+
+func markCurBlockToFeatureExpireBlockList(ctx sdk.Context, keeper keeper.Keeper) {
+	curBlockHeight := ctx.BlockHeight()
+	feeParams := keeper.GetParams(ctx)
+
+	// Add current blockHeight to future Height
+	// which will solve expire orders in current block.
+	futureHeight := curBlockHeight + feeParams.OrderExpireBlocks
+
+	// the feeParams.OrderExpireBlocks param can be change during the blockchain running,
+	// so we use an array to record the expire blocks in the feature block height
+	futureExpireHeightList := keeper.GetExpireBlockHeight(ctx, futureHeight)
+	futureExpireHeightList = append(futureExpireHeightList, curBlockHeight)
+	keeper.SetExpireBlockHeight(ctx, futureHeight, futureExpireHeightList)
+}
+
+func cleanLastBlockClosedOrders(ctx sdk.Context, keeper keeper.Keeper) {
+	// drop expired data
+	lastClosedOrderIDs := keeper.GetLastClosedOrderIDs(ctx)
+	for _, orderID := range lastClosedOrderIDs {
+		keeper.DropOrder(ctx, orderID)
+	}
+
+	keeper.GetDiskCache().DecreaseStoreOrderNum(int64(len(lastClosedOrderIDs)))
+}
+
+// Deal the block from create to current height which is Expired
+func cacheExpiredBlockToCurrentHeight(ctx sdk.Context, keeper keeper.Keeper) {
+	logger := ctx.Logger().With("module", "order")
+	curBlockHeight := ctx.BlockHeight()
+
+	lastExpiredBlockHeight := keeper.GetLastExpiredBlockHeight(ctx)
+	if lastExpiredBlockHeight == 0 {
+		lastExpiredBlockHeight = curBlockHeight - 1
+	}
+
+	// check orders in expired blocks, remove expired orders by order id
+	for height := lastExpiredBlockHeight + 1; height <= curBlockHeight; height++ {
+		var expiredHeight int64
+		expiredBlocks := keeper.GetExpireBlockHeight(ctx, height)
+		for _, expiredHeight = range expiredBlocks {
+			expireOrdersInExpiredBlock(ctx, keeper, expiredHeight)
+			logger.Info(fmt.Sprintf("currentHeight(%d), expire orders at blockHeight(%d)",
+				curBlockHeight, expiredHeight))
+		}
+	}
+
+	if !keeper.AnyProductLocked() {
+		height := lastExpiredBlockHeight
+		if curBlockHeight > 1 {
+			for ; height < curBlockHeight; height++ {
+				var expiredHeight int64
+				for _, expiredHeight = range keeper.GetExpireBlockHeight(ctx, height) {
+					keeper.DropBlockOrderNum(ctx, expiredHeight)
+					logger.Info(fmt.Sprintf("currentHeight(%d), drop Data at blockHeight(%d)",
+						curBlockHeight, expiredHeight))
+				}
+				keeper.DropExpireBlockHeight(ctx, height)
+			}
+		}
+		keeper.SetLastExpiredBlockHeight(ctx, height)
+	}
+}
+
+func cleanupOrdersWhoseTokenPairHaveBeenDelisted(ctx sdk.Context, keeper keeper.Keeper) {
+	products := keeper.GetProductsFromDepthBookMap()
+	for _, product := range products {
+		tokenPair := keeper.GetDexKeeper().GetTokenPair(ctx, product)
+		if tokenPair == nil {
+			cleanupOrdersByProduct(ctx, keeper, product)
+		}
+	}
+
+	// untriggered orders are kept out of depth book
+	logger := ctx.Logger()
+	for _, orderID := range keeper.GetTriggerOrderIDs(ctx) {
+		order := keeper.GetOrder(ctx, orderID)
+		if order != nil && order.IsUntriggered() && keeper.GetDexKeeper().GetTokenPair(ctx, order.Product) == nil {
+			keeper.CancelOrder(ctx, order, logger)
+		}
+	}
+}
+
+func cleanupOrdersByProduct(ctx sdk.Context, keeper keeper.Keeper, product string) {
+	depthBook := keeper.GetDepthBookCopy(product)
+	for _, item := range depthBook.Items {
+		buyKey := types.FormatOrderIDsKey(product, item.Price, types.BuyOrder)
+		orderIDList := keeper.GetProductPriceOrderIDs(buyKey)
+		sellKey := types.FormatOrderIDsKey(product, item.Price, types.SellOrder)
+		orderIDList = append(orderIDList, keeper.GetProductPriceOrderIDs(sellKey)...)
+		cleanOrdersByOrderIDList(ctx, keeper, orderIDList)
+	}
+}
+
+func cleanOrdersByOrderIDList(ctx sdk.Context, keeper keeper.Keeper, orderIDList []string) {
+	logger := ctx.Logger()
+	for _, orderID := range orderIDList {
+		order := keeper.GetOrder(ctx, orderID)
+		keeper.CancelOrder(ctx, order, logger)
+	}
+}
+
+func cleanupExpiredOrders(ctx sdk.Context, keeper keeper.Keeper) {
+
+	// Look forward to see what height will this block expired
+	markCurBlockToFeatureExpireBlockList(ctx, keeper)
+
+	// Clean the expired orders which is collected by the last block
+	cleanLastBlockClosedOrders(ctx, keeper)
+
+	// Look backward to see who is expired and cache the expired orders
+	cacheExpiredBlockToCurrentHeight(ctx, keeper)
+}
+
+// quitImmediateOrders quits the remainder of market, IOC and FOK orders after they have been matched, and refunds
+// the locked coins. The orders of locked products are still being filled, and the orders of continuous auction left
+// unmatched by the deal budget are matched in the next block, they are handled after matched
+func quitImmediateOrders(ctx sdk.Context, keeper keeper.Keeper) {
+	logger := ctx.Logger().With("module", "order")
+	pendingOrderIDs := make(map[string]struct{})
+	for _, orderID := range keeper.GetPendingIncomingOrderIDs(ctx) {
+		pendingOrderIDs[orderID] = struct{}{}
+	}
+	for _, orderID := range keeper.GetImmediateOrderIDs(ctx) {
+		order := keeper.GetOrder(ctx, orderID)
+		if order != nil && order.Status == types.OrderStatusOpen {
+			if _, pending := pendingOrderIDs[orderID]; pending || keeper.IsProductLocked(order.Product) {
+				continue
+			}
+			keeper.QuitImmediateOrder(ctx, order, logger)
+			logger.Info(fmt.Sprintf("order (%s) quit after its first match, status: %s, remainQuantity: %v",
+				order.OrderID, types.OrderStatus(order.Status), order.RemainQuantity))
+		}
+		keeper.DropImmediateOrderID(ctx, orderID)
+	}
+}
+
+// expireGTBOrders expires the GTB orders whose expire height has been reached after the match of current block.
+// The orders of locked products are still being filled, and are expired in the next block
+func expireGTBOrders(ctx sdk.Context, keeper keeper.Keeper) {
+	logger := ctx.Logger().With("module", "order")
+	blockHeight := ctx.BlockHeight()
+	orderIDs := keeper.GetGTBOrderIDs(ctx, blockHeight)
+	keeper.DropGTBOrderIDs(ctx, blockHeight)
+	for _, orderID := range orderIDs {
+		order := keeper.GetOrder(ctx, orderID)
+		if order == nil || order.Status != types.OrderStatusOpen {
+			continue
+		}
+		if keeper.IsProductLocked(order.Product) {
+			keeper.SetGTBOrderID(ctx, blockHeight+1, orderID)
+			continue
+		}
+		keeper.GTBExpireOrder(ctx, order, logger)
+		logger.Info(fmt.Sprintf("GTB order (%s) expired at height %d", order.OrderID, order.ExpireHeight))
+	}
+}
+
+// cancelOrdersAfterDeadline cancels the open orders of the accounts whose cancel-after reaches its deadline without
+// being refreshed. The orders of locked products are still being filled, and are cancelled in the next block
+func cancelOrdersAfterDeadline(ctx sdk.Context, keeper keeper.Keeper) {
+	cancelAfters := keeper.GetExpiredCancelAfters(ctx, ctx.BlockHeight())
+	if len(cancelAfters) == 0 {
+		return
+	}
+
+	logger := ctx.Logger().With("module", "order")
+	for _, cancelAfter := range cancelAfters {
+		keeper.DropCancelAfter(ctx, cancelAfter.Address)
+
+		// the open orders of the account, including the untriggered ones kept out of depth book
+		orderIDs := keeper.GetAccountOrderIDs(ctx, cancelAfter.Address)
+		sort.Strings(orderIDs)
+		for _, orderID := range orderIDs {
+			order := keeper.GetOrder(ctx, orderID)
+			if order == nil || (order.Status != types.OrderStatusOpen && !order.IsUntriggered()) {
+				continue
+			}
+			if !cancelAfter.IsCoveredOrder(order) {
+				continue
+			}
+			if keeper.IsProductLocked(order.Product) {
+				if keeper.GetCancelAfter(ctx, order.Sender) == nil {
+					keeper.SetCancelAfter(ctx, types.NewCancelAfter(order.Sender, cancelAfter.Product,
+						ctx.BlockHeight()+1))
+				}
+				continue
+			}
+			keeper.CancelOrder(ctx, order, logger)
+			logger.Info(fmt.Sprintf("order (%s) cancelled after the deadline %d of its sender %s",
+				order.OrderID, cancelAfter.Deadline, order.Sender))
+		}
+	}
+}
@@ -0,0 +1,356 @@
+package match
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/okex/okchain/x/dex"
+	orderkeeper "github.com/okex/okchain/x/order/keeper"
+	"github.com/okex/okchain/x/order/types"
+)
+
+var mockOrder = types.MockOrder
+
+func TestExpireOrdersInExpiredBlock(t *testing.T) {
+	testInput := orderkeeper.CreateTestInput(t)
+	keeper := testInput.OrderKeeper
+	ctx := testInput.Ctx
+	tokenPair := dex.GetBuiltInTokenPair()
+	err := testInput.DexKeeper.SaveTokenPair(ctx, tokenPair)
+	require.Nil(t, err)
+
+	orders := []*types.Order{
+		mockOrder("", types.TestTokenPair, types.BuyOrder, "10.1", "1.0"),
+		mockOrder("", types.TestTokenPair, types.BuyOrder, "10.1", "2.0"),
+		mockOrder("", types.TestTokenPair, types.SellOrder, "9.9", "3.0"),
+		mockOrder("", types.TestTokenPair, types.SellOrder, "10.2", "1.0"),
+	}
+	orders[0].Sender = testInput.TestAddrs[0]
+	orders[1].Sender = testInput.TestAddrs[0]
+	orders[2].Sender = testInput.TestAddrs[1]
+	orders[3].Sender = testInput.TestAddrs[1]
+
+	for i := 0; i < 4; i++ {
+		err := keeper.PlaceOrder(ctx, orders[i])
+		require.EqualValues(t, nil, err)
+	}
+
+	expireOrdersInExpiredBlock(ctx, keeper, ctx.BlockHeight())
+
+	order := keeper.GetOrder(ctx, "ID0000000000-1")
+	require.NotEqual(t, nil, order)
+	require.EqualValues(t, int64(types.OrderStatusExpired), order.Status)
+}
+
+func TestMarkCurBlockToFeatureExpireBlockList(t *testing.T) {
+	testInput := orderkeeper.CreateTestInput(t)
+	keeper := testInput.OrderKeeper
+	ctx := testInput.Ctx
+	feeParams := types.DefaultParams()
+
+	markCurBlockToFeatureExpireBlockList(ctx, keeper)
+	expiredBlocks := keeper.GetExpireBlockHeight(ctx, ctx.BlockHeight()+feeParams.OrderExpireBlocks)
+	require.EqualValues(t, 0, expiredBlocks[0])
+}
+
+func TestCleanLastBlockClosedOrders(t *testing.T) {
+	testInput := orderkeeper.CreateTestInput(t)
+	keeper := testInput.OrderKeeper
+	ctx := testInput.Ctx
+
+	orders := []*types.Order{
+		mockOrder("", types.TestTokenPair, types.BuyOrder, "10.1", "1.0"),
+		mockOrder("", types.TestTokenPair, types.BuyOrder, "10.1", "2.0"),
+		mockOrder("", types.TestTokenPair, types.SellOrder, "9.9", "3.0"),
+		mockOrder("", types.TestTokenPair, types.SellOrder, "10.2", "1.0"),
+	}
+	orders[0].Sender = testInput.TestAddrs[0]
+	orders[1].Sender = testInput.TestAddrs[0]
+	orders[2].Sender = testInput.TestAddrs[1]
+	orders[3].Sender = testInput.TestAddrs[1]
+
+	for i := 0; i < 4; i++ {
+		err := keeper.PlaceOrder(ctx, orders[i])
+		require.EqualValues(t, nil, err)
+	}
+
+	keeper.SetLastClosedOrderIDs(ctx, []string{orders[0].OrderID})
+
+	cleanLastBlockClosedOrders(ctx, keeper)
+
+	order := keeper.GetOrder(ctx, orders[0].OrderID)
+	require.EqualValues(t, (*types.Order)(nil), order)
+}
+
+func TestCacheExpiredBlockToCurrentHeight(t *testing.T) {
+	testInput := orderkeeper.CreateTestInput(t)
+	keeper := testInput.OrderKeeper
+	ctx := testInput.Ctx
+
+	orders := []*types.Order{
+		mockOrder("", types.TestTokenPair, types.BuyOrder, "10.1", "1.0"),
+		mockOrder("", types.TestTokenPair, types.BuyOrder, "10.1", "2.0"),
+		mockOrder("", types.TestTokenPair, types.SellOrder, "9.9", "3.0"),
+		mockOrder("", types.TestTokenPair, types.SellOrder, "10.2", "1.0"),
+	}
+	orders[0].Sender = testInput.TestAddrs[0]
+	orders[1].Sender = testInput.TestAddrs[0]
+	orders[2].Sender = testInput.TestAddrs[1]
+	orders[3].Sender = testInput.TestAddrs[1]
+
+	for i := 0; i < 4; i++ {
+		err := keeper.PlaceOrder(ctx, orders[i])
+		require.EqualValues(t, nil, err)
+	}
+
+	expireOrdersInExpiredBlock(ctx, keeper, ctx.BlockHeight())
+	keeper.SetExpireBlockHeight(ctx, ctx.BlockHeight(), []int64{ctx.BlockHeight()})
+
+	cacheExpiredBlockToCurrentHeight(ctx, keeper)
+
+	num := keeper.GetCache().GetExpireNum()
+	require.EqualValues(t, len(orders), int(num))
+}
+
+func TestCleanupExpiredOrders(t *testing.T) {
+	testInput := orderkeeper.CreateTestInput(t)
+	keeper := testInput.OrderKeeper
+	ctx := testInput.Ctx
+	feeParams := types.DefaultParams()
+
+	orders := []*types.Order{
+		mockOrder("", types.TestTokenPair, types.BuyOrder, "10.1", "1.0"),
+		mockOrder("", types.TestTokenPair, types.BuyOrder, "10.1", "2.0"),
+		mockOrder("", types.TestTokenPair, types.SellOrder, "9.9", "3.0"),
+		mockOrder("", types.TestTokenPair, types.SellOrder, "10.2", "1.0"),
+	}
+	orders[0].Sender = testInput.TestAddrs[0]
+	orders[1].Sender = testInput.TestAddrs[0]
+	orders[2].Sender = testInput.TestAddrs[1]
+	orders[3].Sender = testInput.TestAddrs[1]
+
+	for i := 0; i < 4; i++ {
+		err := keeper.PlaceOrder(ctx, orders[i])
+		require.EqualValues(t, nil, err)
+	}
+
+	keeper.SetLastClosedOrderIDs(ctx, []string{orders[0].OrderID})
+	keeper.ExpireOrder(ctx, orders[1], ctx.Logger())
+
+	cleanupExpiredOrders(ctx, keeper)
+
+	expiredBlocks := keeper.GetExpireBlockHeight(ctx, ctx.BlockHeight()+
+		feeParams.OrderExpireBlocks)
+	require.EqualValues(t, true, expiredBlocks[0] == ctx.BlockHeight())
+
+	lastClosedOrderIDs := keeper.GetLastClosedOrderIDs(ctx)
+	require.EqualValues(t, true, lastClosedOrderIDs[0] == orders[0].OrderID)
+
+	num := keeper.GetCache().GetExpireNum()
+	require.EqualValues(t, 1, int(num))
+}
+
+func TestCleanupOrdersWhoseTokenPairHaveBeenDelisted(t *testing.T) {
+	testInput := orderkeeper.CreateTestInput(t)
+	keeper := testInput.OrderKeeper
+	ctx := testInput.Ctx
+
+	// mock orders, DepthBook, and orderIDsMap
+	orders := []*types.Order{
+		mockOrder("", types.TestTokenPair, types.BuyOrder, "10.1", "1.0"),
+		mockOrder("", types.TestTokenPair, types.BuyOrder, "10.1", "2.0"),
+		mockOrder("", types.TestTokenPair, types.SellOrder, "9.9", "3.0"),
+		mockOrder("", types.TestTokenPair, types.SellOrder, "10.2", "1.0"),
+	}
+	orders[0].Sender = testInput.TestAddrs[0]
+	orders[1].Sender = testInput.TestAddrs[0]
+	orders[2].Sender = testInput.TestAddrs[1]
+	orders[3].Sender = testInput.TestAddrs[1]
+	depthBook := &types.DepthBook{}
+
+	for i := 0; i < 4; i++ {
+		err := keeper.PlaceOrder(ctx, orders[i])
+		require.EqualValues(t, nil, err)
+		depthBook.InsertOrder(orders[i])
+	}
+
+	cleanupOrdersWhoseTokenPairHaveBeenDelisted(ctx, keeper)
+
+	depthBook = keeper.GetDepthBookCopy(types.TestTokenPair)
+	require.EqualValues(t, 0, len(depthBook.Items))
+
+}
+
+func TestQuitImmediateOrders(t *testing.T) {
+	testInput := orderkeeper.CreateTestInput(t)
+	keeper := testInput.OrderKeeper
+	ctx := testInput.Ctx
+	tokenPair := dex.GetBuiltInTokenPair()
+	err := testInput.DexKeeper.SaveTokenPair(ctx, tokenPair)
+	require.Nil(t, err)
+
+	orders := []*types.Order{
+		mockOrder("", types.TestTokenPair, types.BuyOrder, "10.5", "1.0"),
+		mockOrder("", types.TestTokenPair, types.SellOrder, "9.5", "1.0"),
+		mockOrder("", types.TestTokenPair, types.BuyOrder, "9.0", "1.0"),
+		mockOrder("", types.TestTokenPair, types.SellOrder, "11.0", "1.0"),
+		mockOrder("", types.TestTokenPair, types.SellOrder, "12.0", "1.0"),
+	}
+	orders[0].Type = types.OrderTypeMarket
+	orders[1].Type = types.OrderTypeMarket
+	orders[2].TimeInForce = types.TimeInForceIOC
+	orders[3].TimeInForce = types.TimeInForceFOK
+	for i, order := range orders {
+		order.Sender = testInput.TestAddrs[i%2]
+		err := keeper.PlaceOrder(ctx, order)
+		require.NoError(t, err)
+	}
+	// the GTC order is not quit after match
+	require.EqualValues(t, 4, len(keeper.GetImmediateOrderIDs(ctx)))
+
+	// the immediate orders of locked product are still being filled
+	keeper.SetProductLock(ctx, types.TestTokenPair, &types.ProductLock{})
+	quitImmediateOrders(ctx, keeper)
+	require.EqualValues(t, 4, len(keeper.GetImmediateOrderIDs(ctx)))
+	for _, order := range orders {
+		require.EqualValues(t, types.OrderStatusOpen, keeper.GetOrder(ctx, order.OrderID).Status)
+	}
+
+	keeper.UnlockProduct(ctx, types.TestTokenPair)
+	quitImmediateOrders(ctx, keeper)
+	require.EqualValues(t, 0, len(keeper.GetImmediateOrderIDs(ctx)))
+	expectedStatus := []int64{types.OrderStatusCancelled, types.OrderStatusCancelled,
+		types.OrderStatusIOCCancelled, types.OrderStatusFOKKilled, types.OrderStatusOpen}
+	for i, order := range orders {
+		order = keeper.GetOrder(ctx, order.OrderID)
+		require.EqualValues(t, expectedStatus[i], order.Status)
+		if order.Status != types.OrderStatusOpen {
+			require.True(t, order.RemainLocked.IsZero())
+		}
+	}
+	require.EqualValues(t, 1, len(keeper.GetDepthBookCopy(types.TestTokenPair).Items))
+}
+
+func TestExpireGTBOrders(t *testing.T) {
+	testInput := orderkeeper.CreateTestInput(t)
+	keeper := testInput.OrderKeeper
+	ctx := testInput.Ctx.WithBlockHeight(10)
+	tokenPair := dex.GetBuiltInTokenPair()
+	err := testInput.DexKeeper.SaveTokenPair(ctx, tokenPair)
+	require.Nil(t, err)
+
+	orders := []*types.Order{
+		mockOrder("", types.TestTokenPair, types.BuyOrder, "9.0", "1.0"),
+		mockOrder("", types.TestTokenPair, types.SellOrder, "11.0", "1.0"),
+	}
+	for i, order := range orders {
+		order.Sender = testInput.TestAddrs[i]
+		order.TimeInForce = types.TimeInForceGTB
+		order.ExpireHeight = int64(11 + i)
+		err := keeper.PlaceOrder(ctx, order)
+		require.NoError(t, err)
+	}
+
+	// no GTB order reaches the expire height
+	expireGTBOrders(ctx, keeper)
+	require.EqualValues(t, 2, len(keeper.GetGTBOrderIDs(ctx, 12)))
+
+	// the GTB order of locked product is expired after the product is unlocked
+	ctx = ctx.WithBlockHeight(11)
+	keeper.SetProductLock(ctx, types.TestTokenPair, &types.ProductLock{})
+	expireGTBOrders(ctx, keeper)
+	require.EqualValues(t, types.OrderStatusOpen, keeper.GetOrder(ctx, orders[0].OrderID).Status)
+	require.EqualValues(t, []string{orders[0].OrderID, orders[1].OrderID}, keeper.GetGTBOrderIDs(ctx, 12))
+	keeper.UnlockProduct(ctx, types.TestTokenPair)
+
+	ctx = ctx.WithBlockHeight(12)
+	expireGTBOrders(ctx, keeper)
+	require.Nil(t, keeper.GetGTBOrderIDs(ctx, 12))
+	for _, order := range orders {
+		order = keeper.GetOrder(ctx, order.OrderID)
+		require.EqualValues(t, types.OrderStatusGTBExpired, order.Status)
+		require.True(t, order.RemainLocked.IsZero())
+	}
+	require.EqualValues(t, 0, len(keeper.GetDepthBookCopy(types.TestTokenPair).Items))
+}
+
+func TestCancelOrdersAfterDeadline(t *testing.T) {
+	testInput := orderkeeper.CreateTestInput(t)
+	keeper := testInput.OrderKeeper
+	ctx := testInput.Ctx.WithBlockHeight(10)
+	tokenPair := dex.GetBuiltInTokenPair()
+	err := testInput.DexKeeper.SaveTokenPair(ctx, tokenPair)
+	require.Nil(t, err)
+
+	orders := []*types.Order{
+		mockOrder("", types.TestTokenPair, types.BuyOrder, "9.0", "1.0"),
+		mockOrder("", types.TestTokenPair, types.SellOrder, "11.0", "1.0"),
+		mockOrder("", types.TestTokenPair, types.BuyOrder, "9.0", "1.0"),
+	}
+	orders[0].Sender = testInput.TestAddrs[0]
+	orders[1].Sender = testInput.TestAddrs[0]
+	orders[2].Sender = testInput.TestAddrs[1]
+	for _, order := range orders {
+		err := keeper.PlaceOrder(ctx, order)
+		require.NoError(t, err)
+	}
+	keeper.SetCancelAfter(ctx, types.NewCancelAfter(testInput.TestAddrs[0], "", 11))
+
+	// the deadline is not reached
+	cancelOrdersAfterDeadline(ctx, keeper)
+	require.EqualValues(t, types.OrderStatusOpen, keeper.GetOrder(ctx, orders[0].OrderID).Status)
+
+	// the orders of locked product are cancelled in the next block
+	ctx = ctx.WithBlockHeight(11)
+	keeper.SetProductLock(ctx, types.TestTokenPair, &types.ProductLock{})
+	cancelOrdersAfterDeadline(ctx, keeper)
+	require.EqualValues(t, types.OrderStatusOpen, keeper.GetOrder(ctx, orders[0].OrderID).Status)
+	require.EqualValues(t, 12, keeper.GetCancelAfter(ctx, testInput.TestAddrs[0]).Deadline)
+	keeper.UnlockProduct(ctx, types.TestTokenPair)
+
+	// only the orders of the account are cancelled
+	ctx = ctx.WithBlockHeight(12)
+	cancelOrdersAfterDeadline(ctx, keeper)
+	require.EqualValues(t, types.OrderStatusCancelled, keeper.GetOrder(ctx, orders[0].OrderID).Status)
+	require.EqualValues(t, types.OrderStatusCancelled, keeper.GetOrder(ctx, orders[1].OrderID).Status)
+	require.EqualValues(t, types.OrderStatusOpen, keeper.GetOrder(ctx, orders[2].OrderID).Status)
+	require.Nil(t, keeper.GetCancelAfter(ctx, testInput.TestAddrs[0]))
+
+	// the cancel-after of other product does not cancel the orders
+	keeper.SetCancelAfter(ctx, types.NewCancelAfter(testInput.TestAddrs[1], "btc-000_okt", 13))
+	ctx = ctx.WithBlockHeight(13)
+	cancelOrdersAfterDeadline(ctx, keeper)
+	require.EqualValues(t, types.OrderStatusOpen, keeper.GetOrder(ctx, orders[2].OrderID).Status)
+	require.Nil(t, keeper.GetCancelAfter(ctx, testInput.TestAddrs[1]))
+}
+
+func TestQuitMarketOrderAfterProductLock(t *testing.T) {
+	testInput := orderkeeper.CreateTestInput(t)
+	keeper := testInput.OrderKeeper
+	ctx := testInput.Ctx.WithBlockHeight(10)
+	tokenPair := dex.GetBuiltInTokenPair()
+	err := testInput.DexKeeper.SaveTokenPair(ctx, tokenPair)
+	require.Nil(t, err)
+
+	coins := testInput.AccountKeeper.GetAccount(ctx, testInput.TestAddrs[0]).GetCoins()
+	order := mockOrder("", types.TestTokenPair, types.BuyOrder, "10.5", "1.0")
+	order.Type = types.OrderTypeMarket
+	order.Sender = testInput.TestAddrs[0]
+	err = keeper.PlaceOrder(ctx, order)
+	require.NoError(t, err)
+
+	// the market order waits for its first match while the product is locked
+	keeper.SetProductLock(ctx, types.TestTokenPair, &types.ProductLock{})
+	quitImmediateOrders(ctx, keeper)
+	ctx = ctx.WithBlockHeight(15)
+	keeper.UnlockProduct(ctx, types.TestTokenPair)
+	quitImmediateOrders(ctx, keeper)
+
+	// the remainder is refunded without the fee of the blocks waited
+	order = keeper.GetOrder(ctx, order.OrderID)
+	require.EqualValues(t, types.OrderStatusCancelled, order.Status)
+	require.True(t, order.RemainLocked.IsZero())
+	require.EqualValues(t, coins.String(),
+		testInput.AccountKeeper.GetAccount(ctx, testInput.TestAddrs[0]).GetCoins().String())
+}
@@ -3,14 +3,17 @@ package continuousauction
 import (
 	sdk "github.com/cosmos/cosmos-sdk/types"
 
+	"github.com/okex/okchain/x/dex"
 	"github.com/okex/okchain/x/order/keeper"
 )
 
-// nolint
+// CaEngine is the continuous auction match engine
 type CaEngine struct {
 }
 
-// nolint
+// Run triggers the stop-loss and take-profit orders whose trigger price has been crossed by the last price,
+// then matches them and the orders placed in current block of continuous auction products
 func (e *CaEngine) Run(ctx sdk.Context, keeper keeper.Keeper) {
-	// TODO
+	triggeredOrders := keeper.TriggerOrders(ctx, dex.MatchModeContinuousAuction)
+	matchOrders(ctx, keeper, triggeredOrders)
 }
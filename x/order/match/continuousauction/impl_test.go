@@ -0,0 +1,72 @@
+package continuousauction
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/okex/okchain/x/dex"
+	orderkeeper "github.com/okex/okchain/x/order/keeper"
+	"github.com/okex/okchain/x/order/types"
+)
+
+func TestCaEngine_Run(t *testing.T) {
+	testInput := orderkeeper.CreateTestInput(t)
+	keeper := testInput.OrderKeeper
+	ctx := testInput.Ctx
+	tokenPair := dex.GetBuiltInTokenPair()
+	tokenPair.MatchMode = dex.MatchModeContinuousAuction
+	err := testInput.DexKeeper.SaveTokenPair(ctx, tokenPair)
+	require.Nil(t, err)
+
+	var startHeight int64 = 10
+	ctx = ctx.WithBlockHeight(startHeight)
+
+	// mock orders
+	orders := []*types.Order{
+		types.MockOrder("", types.TestTokenPair, types.SellOrder, "10.0", "0.5"),
+		types.MockOrder("", types.TestTokenPair, types.SellOrder, "10.1", "1.0"),
+		types.MockOrder("", types.TestTokenPair, types.BuyOrder, "10.1", "1.0"),
+	}
+	orders[0].Sender = testInput.TestAddrs[1]
+	orders[1].Sender = testInput.TestAddrs[1]
+	orders[2].Sender = testInput.TestAddrs[0]
+	for i := 0; i < 3; i++ {
+		err := keeper.PlaceOrder(ctx, orders[i])
+		require.NoError(t, err)
+	}
+
+	engine := &CaEngine{}
+	engine.Run(ctx, keeper)
+
+	// the buy order takes the lowest ask first, then the next price level
+	order0 := keeper.GetOrder(ctx, orders[0].OrderID)
+	order1 := keeper.GetOrder(ctx, orders[1].OrderID)
+	order2 := keeper.GetOrder(ctx, orders[2].OrderID)
+	require.EqualValues(t, types.OrderStatusFilled, order0.Status)
+	require.EqualValues(t, types.OrderStatusOpen, order1.Status)
+	require.EqualValues(t, sdk.MustNewDecFromStr("0.5"), order1.RemainQuantity)
+	require.EqualValues(t, types.OrderStatusFilled, order2.Status)
+	require.EqualValues(t, sdk.MustNewDecFromStr("10.05"), order2.FilledAvgPrice)
+	require.EqualValues(t, sdk.MustNewDecFromStr("10.1"), keeper.GetLastPrice(ctx, types.TestTokenPair))
+
+	// check depth book and orderIDsMap
+	depthBook := keeper.GetDepthBookCopy(types.TestTokenPair)
+	require.EqualValues(t, 1, len(depthBook.Items))
+	require.EqualValues(t, sdk.MustNewDecFromStr("10.1"), depthBook.Items[0].Price)
+	require.True(t, depthBook.Items[0].BuyQuantity.IsZero())
+	require.EqualValues(t, sdk.MustNewDecFromStr("0.5"), depthBook.Items[0].SellQuantity)
+	sellKey := types.FormatOrderIDsKey(types.TestTokenPair, sdk.MustNewDecFromStr("10.1"), types.SellOrder)
+	require.EqualValues(t, []string{orders[1].OrderID}, keeper.GetProductPriceOrderIDs(sellKey))
+	buyKey := types.FormatOrderIDsKey(types.TestTokenPair, sdk.MustNewDecFromStr("10.1"), types.BuyOrder)
+	require.EqualValues(t, 0, len(keeper.GetProductPriceOrderIDs(buyKey)))
+
+	// check match result
+	matchResult := keeper.GetBlockMatchResult().ResultMap[types.TestTokenPair]
+	require.EqualValues(t, sdk.MustNewDecFromStr("10.1"), matchResult.Price)
+	require.EqualValues(t, sdk.MustNewDecFromStr("1.0"), matchResult.Quantity)
+	require.EqualValues(t, 4, len(matchResult.Deals))
+	require.EqualValues(t, sdk.MustNewDecFromStr("10.0"), matchResult.Deals[0].Price)
+	require.EqualValues(t, sdk.MustNewDecFromStr("10.1"), matchResult.Deals[3].Price)
+}
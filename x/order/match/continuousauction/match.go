@@ -0,0 +1,387 @@
+package continuousauction
+
+import (
+	"fmt"
+	"sort"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/tendermint/tendermint/libs/log"
+
+	"github.com/okex/okchain/x/dex"
+	"github.com/okex/okchain/x/order/keeper"
+	"github.com/okex/okchain/x/order/types"
+)
+
+// dealsPerFill is the number of deals a fill makes, one of the incoming order and one of the resting order
+const dealsPerFill = 2
+
+// matchOrders replays the orders triggered at the start of the match, the orders placed in current block and the
+// orders replaced to a new place of the queue in current block in their arrival sequence. Every incoming order is matched against the resting orders of the opposite side with
+// price-time priority:
+// rule1: Best price first. A buy order takes the lowest ask first, a sell order takes the highest bid first.
+// rule2: Earliest order first. Resting orders at the same price are filled in the sequence they arrived.
+// rule3: Maker price. Every deal is executed at the price of the resting order.
+// The orders placed later in current block have not arrived yet, so they are never filled as makers
+// by an earlier incoming order.
+// A resting order of the same sender is handled by the self-trade prevention mode of the incoming order before
+// it is taken.
+// A post-only order is rejected if it would take any resting order, a FOK order is killed if the resting
+// orders can not fill it completely, and the remainder of market, IOC and FOK orders is quit right after
+// they are matched as the incoming order.
+// Every fill makes a deal of both the incoming order and the resting order, which take up two of the
+// MaxDealsPerBlock budget. The budget is shared with periodic auction, which runs first in the block and leaves
+// the deals it has not spent. Once the budget is used up, the incoming orders left are kept open in the book and
+// matched first in the next block, before the orders arriving in it.
+func matchOrders(ctx sdk.Context, k keeper.Keeper, triggeredOrders []*types.Order) {
+	blockHeight := ctx.BlockHeight()
+	orderNum := k.GetBlockOrderNum(ctx, blockHeight)
+	replacedOrderIDs := k.GetReplacedOrderIDs()
+	carriedOrderIDs := k.GetPendingIncomingOrderIDs(ctx)
+	// no new, triggered, replaced or carried over orders in this block, the resting book is never crossed,
+	// skip match
+	if orderNum == 0 && len(triggeredOrders) == 0 && len(replacedOrderIDs) == 0 && len(carriedOrderIDs) == 0 {
+		return
+	}
+	k.SetPendingIncomingOrderIDs(ctx, nil)
+
+	incomingOrderIDs := make([]string, 0,
+		int64(len(carriedOrderIDs)+len(triggeredOrders)+len(replacedOrderIDs))+orderNum)
+	pendingOrderIDs := make(map[string]struct{}, cap(incomingOrderIDs))
+	// the orders carried over from the previous block arrived before any order of current block
+	carried := make(map[string]struct{}, len(carriedOrderIDs))
+	for _, orderID := range carriedOrderIDs {
+		incomingOrderIDs = append(incomingOrderIDs, orderID)
+		pendingOrderIDs[orderID] = struct{}{}
+		carried[orderID] = struct{}{}
+	}
+	for _, order := range triggeredOrders {
+		if _, ok := pendingOrderIDs[order.OrderID]; !ok {
+			incomingOrderIDs = append(incomingOrderIDs, order.OrderID)
+			pendingOrderIDs[order.OrderID] = struct{}{}
+		}
+	}
+	var index int64
+	for index = 1; index <= orderNum; index++ {
+		orderID := types.FormatOrderID(blockHeight, index)
+		// the order placed and triggered in current block arrives when triggered
+		if _, ok := pendingOrderIDs[orderID]; !ok {
+			incomingOrderIDs = append(incomingOrderIDs, orderID)
+			pendingOrderIDs[orderID] = struct{}{}
+		}
+	}
+	// the order replaced in current block arrives after the new orders
+	for _, orderID := range replacedOrderIDs {
+		if _, ok := pendingOrderIDs[orderID]; !ok {
+			incomingOrderIDs = append(incomingOrderIDs, orderID)
+			pendingOrderIDs[orderID] = struct{}{}
+		}
+	}
+
+	logger := ctx.Logger().With("module", "order")
+	feeParams := k.GetParams(ctx)
+	resultMap := make(map[string]types.MatchResult)
+	startDeals := k.GetBlockRemainDeals(ctx)
+	blockRemainDeals := startDeals
+	for i, orderID := range incomingOrderIDs {
+		if blockRemainDeals < dealsPerFill {
+			carryOverIncomingOrders(ctx, k, incomingOrderIDs[i:], logger)
+			break
+		}
+		delete(pendingOrderIDs, orderID)
+
+		order := k.GetOrder(ctx, orderID)
+		if order == nil || order.Status != types.OrderStatusOpen || k.IsProductLocked(order.Product) ||
+			!k.IsMatchedBy(ctx, order.Product, dex.MatchModeContinuousAuction) {
+			continue
+		}
+
+		// the FOK order partially filled before the budget was used up has passed the check
+		_, partiallyFilled := carried[orderID]
+		partiallyFilled = partiallyFilled && order.RemainQuantity.LT(order.Quantity)
+		if preventSelfTrades(ctx, k, order, pendingOrderIDs, logger) ||
+			(!partiallyFilled && rejectByTimeInForce(ctx, k, order, pendingOrderIDs, logger)) {
+			continue
+		}
+		var deals []types.Deal
+		deals, blockRemainDeals = matchIncomingOrder(ctx, k, order, pendingOrderIDs, feeParams, blockRemainDeals)
+		if blockRemainDeals < dealsPerFill && order.Status == types.OrderStatusOpen {
+			// the order may still cross the book, it's matched again in the next block
+			carryOverIncomingOrders(ctx, k, incomingOrderIDs[i:], logger)
+		} else if order.IsImmediateOrder() && order.Status == types.OrderStatusOpen {
+			k.QuitImmediateOrder(ctx, order, logger)
+		}
+		if len(deals) == 0 {
+			continue
+		}
+
+		matchResult, ok := resultMap[order.Product]
+		if !ok {
+			matchResult = types.MatchResult{BlockHeight: blockHeight, Quantity: sdk.ZeroDec(), Deals: []types.Deal{}}
+		}
+		for _, deal := range deals {
+			if deal.OrderID == order.OrderID {
+				matchResult.Quantity = matchResult.Quantity.Add(deal.Quantity)
+			}
+		}
+		matchResult.Price = deals[len(deals)-1].Price
+		matchResult.Deals = append(matchResult.Deals, deals...)
+		resultMap[order.Product] = matchResult
+
+		logger.Info(fmt.Sprintf("matchResult(%d-%s): order(%s) remainQuantity: %v, lastPrice: %v, dealsNum: %d",
+			blockHeight, order.Product, order.OrderID, order.RemainQuantity, matchResult.Price, len(deals)))
+		if blockRemainDeals < dealsPerFill {
+			// the order done with the last deals of the budget leaves the orders after it to the next block
+			if order.Status != types.OrderStatusOpen && i+1 < len(incomingOrderIDs) {
+				carryOverIncomingOrders(ctx, k, incomingOrderIDs[i+1:], logger)
+			}
+			break
+		}
+	}
+	k.AddBlockDealNum(ctx, startDeals-blockRemainDeals)
+
+	// save match results for querying, merge them into the results of periodic auction in current block
+	if len(resultMap) > 0 {
+		blockMatchResult := k.GetBlockMatchResult()
+		if blockMatchResult != nil && blockMatchResult.BlockHeight == blockHeight && blockMatchResult.ResultMap != nil {
+			for product, matchResult := range resultMap {
+				blockMatchResult.ResultMap[product] = matchResult
+			}
+			return
+		}
+		blockMatchResult = &types.BlockMatchResult{
+			BlockHeight: blockHeight,
+			ResultMap:   resultMap,
+			TimeStamp:   ctx.BlockHeader().Time.Unix(),
+		}
+		k.SetBlockMatchResult(blockMatchResult)
+	}
+}
+
+// carryOverIncomingOrders keeps the incoming orders left unmatched when the deal budget of the block is used up,
+// they are matched first in the next block
+func carryOverIncomingOrders(ctx sdk.Context, k keeper.Keeper, orderIDs []string, logger log.Logger) {
+	k.SetPendingIncomingOrderIDs(ctx, orderIDs)
+	logger.Info(fmt.Sprintf("deal budget of block %d used up, %d incoming orders are matched in the next block",
+		ctx.BlockHeight(), len(orderIDs)))
+}
+
+// rejectByTimeInForce rejects the post-only order which would take resting orders, and kills the FOK order
+// which can not be filled completely. It returns true if the order is quit
+func rejectByTimeInForce(ctx sdk.Context, k keeper.Keeper, order *types.Order,
+	pendingOrderIDs map[string]struct{}, logger log.Logger) bool {
+
+	switch order.TimeInForce {
+	case types.TimeInForcePostOnly:
+		if availableQuantity(ctx, k, order, pendingOrderIDs, sdk.SmallestDec()).IsPositive() {
+			k.PostOnlyRejectOrder(ctx, order, logger)
+			return true
+		}
+	case types.TimeInForceFOK:
+		if availableQuantity(ctx, k, order, pendingOrderIDs, order.RemainQuantity).LT(order.RemainQuantity) {
+			k.FOKKillOrder(ctx, order, logger)
+			return true
+		}
+	}
+	return false
+}
+
+// preventSelfTrades applies the self-trade prevention mode of the incoming order to the resting orders of the same
+// sender which it would take, in price-time priority. It returns true if the incoming order is quit
+func preventSelfTrades(ctx sdk.Context, k keeper.Keeper, order *types.Order,
+	pendingOrderIDs map[string]struct{}, logger log.Logger) bool {
+
+	book := k.GetDepthBookCopy(order.Product)
+	makerSide, index, step := types.BuyOrder, 0, 1
+	if order.Side == types.BuyOrder {
+		makerSide, index, step = types.SellOrder, len(book.Items)-1, -1
+	}
+
+	// the quantity of the resting orders of other senders taken before
+	aheadQuantity := sdk.ZeroDec()
+	for ; index >= 0 && index < len(book.Items) && aheadQuantity.LT(order.RemainQuantity); index += step {
+		price := book.Items[index].Price
+		if (order.Side == types.BuyOrder && price.GT(order.Price)) ||
+			(order.Side == types.SellOrder && price.LT(order.Price)) {
+			break
+		}
+		key := types.FormatOrderIDsKey(order.Product, price, makerSide)
+		for _, orderID := range k.GetProductPriceOrderIDs(key) {
+			if aheadQuantity.GTE(order.RemainQuantity) {
+				break
+			}
+			if _, pending := pendingOrderIDs[orderID]; pending {
+				continue
+			}
+			maker := k.GetOrder(ctx, orderID)
+			if maker == nil {
+				continue
+			}
+			if !maker.Sender.Equals(order.Sender) {
+				aheadQuantity = aheadQuantity.Add(maker.RemainQuantity)
+				continue
+			}
+			quantity := sdk.MinDec(maker.RemainQuantity, order.RemainQuantity.Sub(aheadQuantity))
+			if k.PreventSelfTrade(ctx, order, maker, quantity, logger) == "" {
+				aheadQuantity = aheadQuantity.Add(maker.RemainQuantity)
+				continue
+			}
+			if order.Status != types.OrderStatusOpen {
+				return true
+			}
+			if maker.Status == types.OrderStatusOpen {
+				aheadQuantity = aheadQuantity.Add(maker.RemainQuantity)
+			}
+		}
+	}
+	return false
+}
+
+// availableQuantity returns the quantity of the resting orders which the incoming order is able to take,
+// it stops counting once the quantity reaches the limit
+func availableQuantity(ctx sdk.Context, k keeper.Keeper, order *types.Order,
+	pendingOrderIDs map[string]struct{}, limit sdk.Dec) sdk.Dec {
+
+	book := k.GetDepthBookCopy(order.Product)
+	makerSide, index, step := types.BuyOrder, 0, 1
+	if order.Side == types.BuyOrder {
+		makerSide, index, step = types.SellOrder, len(book.Items)-1, -1
+	}
+
+	available := sdk.ZeroDec()
+	for ; index >= 0 && index < len(book.Items) && available.LT(limit); index += step {
+		price := book.Items[index].Price
+		if (order.Side == types.BuyOrder && price.GT(order.Price)) ||
+			(order.Side == types.SellOrder && price.LT(order.Price)) {
+			break
+		}
+		key := types.FormatOrderIDsKey(order.Product, price, makerSide)
+		for _, orderID := range k.GetProductPriceOrderIDs(key) {
+			if _, pending := pendingOrderIDs[orderID]; pending {
+				continue
+			}
+			if maker := k.GetOrder(ctx, orderID); maker != nil {
+				available = available.Add(maker.RemainQuantity)
+			}
+		}
+	}
+	return available
+}
+
+// matchIncomingOrder fills the incoming order against the opposite side of depth book, from the best price
+// to the limit price of the incoming order, until the deal budget is used up. It updates depth book and
+// orderIDsMap, and returns all deals and the budget left.
+func matchIncomingOrder(ctx sdk.Context, k keeper.Keeper, order *types.Order,
+	pendingOrderIDs map[string]struct{}, feeParams *types.Params, remainDeals int64) ([]types.Deal, int64) {
+
+	var deals []types.Deal
+	book := k.GetDepthBookCopy(order.Product)
+
+	// items in depth book are sorted by price desc, buy orders take asks from low to high,
+	// sell orders take bids from high to low
+	makerSide, index, step := types.BuyOrder, 0, 1
+	if order.Side == types.BuyOrder {
+		makerSide, index, step = types.SellOrder, len(book.Items)-1, -1
+	}
+
+	filledQuantity := sdk.ZeroDec()
+	hiddenQuantity := order.HiddenQuantity()
+	for ; index >= 0 && index < len(book.Items) && order.RemainQuantity.IsPositive() &&
+		remainDeals >= dealsPerFill; index += step {
+		price := book.Items[index].Price
+		if (order.Side == types.BuyOrder && price.GT(order.Price)) ||
+			(order.Side == types.SellOrder && price.LT(order.Price)) {
+			break
+		}
+
+		var levelDeals []types.Deal
+		var levelFilled, levelHidden sdk.Dec
+		levelDeals, levelFilled, levelHidden, remainDeals = fillPriceLevel(ctx, k, order, price, makerSide,
+			pendingOrderIDs, feeParams, remainDeals)
+		if levelFilled.IsZero() {
+			continue
+		}
+		book.Sub(index, levelFilled, levelHidden, makerSide)
+		filledQuantity = filledQuantity.Add(levelFilled)
+		deals = append(deals, levelDeals...)
+	}
+
+	if filledQuantity.IsZero() {
+		return deals, remainDeals
+	}
+
+	// the incoming order has been inserted into depth book when it was placed
+	bookLength := len(book.Items)
+	orderIndex := sort.Search(bookLength, func(i int) bool {
+		return order.Price.GTE(book.Items[i].Price)
+	})
+	if orderIndex < bookLength && book.Items[orderIndex].Price.Equal(order.Price) {
+		book.Sub(orderIndex, filledQuantity, hiddenQuantity.Sub(order.HiddenQuantity()), order.Side)
+	}
+	for i := len(book.Items) - 1; i >= 0; i-- {
+		book.RemoveIfEmpty(i)
+	}
+	k.SetDepthBook(order.Product, book)
+
+	if order.Status == types.OrderStatusFilled {
+		key := types.FormatOrderIDsKey(order.Product, order.Price, order.Side)
+		orderIDs := k.GetProductPriceOrderIDs(key)
+		unFilledOrderIDs := make([]string, 0, len(orderIDs))
+		for _, orderID := range orderIDs {
+			if orderID != order.OrderID {
+				unFilledOrderIDs = append(unFilledOrderIDs, orderID)
+			}
+		}
+		k.SetOrderIDs(key, unFilledOrderIDs)
+	}
+
+	return deals, remainDeals
+}
+
+// fillPriceLevel fills the incoming order against the resting orders at the specific price, in the sequence
+// they arrived, until the deal budget is used up. It returns the deals of both sides, the filled quantity of the
+// incoming order, the part of it drawn from the hidden reserves of the resting iceberg orders, and the budget left.
+func fillPriceLevel(ctx sdk.Context, k keeper.Keeper, order *types.Order, price sdk.Dec, makerSide string,
+	pendingOrderIDs map[string]struct{}, feeParams *types.Params,
+	remainDeals int64) ([]types.Deal, sdk.Dec, sdk.Dec, int64) {
+
+	var deals []types.Deal
+	filledQuantity := sdk.ZeroDec()
+	filledHidden := sdk.ZeroDec()
+	key := types.FormatOrderIDsKey(order.Product, price, makerSide)
+	orderIDs := k.GetProductPriceOrderIDs(key)
+	if len(orderIDs) == 0 {
+		return deals, filledQuantity, filledHidden, remainDeals
+	}
+
+	unFilledOrderIDs := make([]string, 0, len(orderIDs))
+	for _, orderID := range orderIDs {
+		_, pending := pendingOrderIDs[orderID]
+		if pending || !order.RemainQuantity.IsPositive() || remainDeals < dealsPerFill {
+			unFilledOrderIDs = append(unFilledOrderIDs, orderID)
+			continue
+		}
+
+		maker := k.GetOrder(ctx, orderID)
+		if maker == nil {
+			continue
+		}
+		fillQuantity := sdk.MinDec(maker.RemainQuantity, order.RemainQuantity)
+
+		// deal fee of sell orders is calculated by the last price
+		k.SetLastPrice(ctx, order.Product, price)
+		makerHidden := maker.HiddenQuantity()
+		makerDeal := k.FillOrder(ctx, maker, price, fillQuantity, feeParams, true)
+		takerDeal := k.FillOrder(ctx, order, price, fillQuantity, feeParams, false)
+		deals = append(deals, *makerDeal, *takerDeal)
+		remainDeals -= dealsPerFill
+		filledQuantity = filledQuantity.Add(fillQuantity)
+		filledHidden = filledHidden.Add(makerHidden.Sub(maker.HiddenQuantity()))
+
+		if maker.RemainQuantity.IsPositive() {
+			unFilledOrderIDs = append(unFilledOrderIDs, orderID)
+		}
+	}
+	k.SetOrderIDs(key, unFilledOrderIDs) // update orderIDsMap on filled
+
+	return deals, filledQuantity, filledHidden, remainDeals
+}
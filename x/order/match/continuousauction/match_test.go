@@ -0,0 +1,459 @@
+package continuousauction
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/okex/okchain/x/dex"
+	orderkeeper "github.com/okex/okchain/x/order/keeper"
+	"github.com/okex/okchain/x/order/types"
+)
+
+var mockOrder = types.MockOrder
+
+func TestMatchOrdersInArrivalSequence(t *testing.T) {
+	testInput := orderkeeper.CreateTestInput(t)
+	keeper := testInput.OrderKeeper
+	ctx := testInput.Ctx
+	tokenPair := dex.GetBuiltInTokenPair()
+	tokenPair.MatchMode = dex.MatchModeContinuousAuction
+	err := testInput.DexKeeper.SaveTokenPair(ctx, tokenPair)
+	require.Nil(t, err)
+
+	// the sell order arrives later, it takes the resting bid at the price of the bid
+	orders := []*types.Order{
+		mockOrder("", types.TestTokenPair, types.BuyOrder, "10.2", "1.0"),
+		mockOrder("", types.TestTokenPair, types.SellOrder, "9.9", "3.0"),
+		mockOrder("", types.TestTokenPair, types.BuyOrder, "10.0", "1.0"),
+	}
+	orders[0].Sender = testInput.TestAddrs[0]
+	orders[1].Sender = testInput.TestAddrs[1]
+	orders[2].Sender = testInput.TestAddrs[0]
+	for _, order := range orders {
+		err := keeper.PlaceOrder(ctx, order)
+		require.NoError(t, err)
+	}
+
+	matchOrders(ctx, keeper, nil)
+
+	order0 := keeper.GetOrder(ctx, orders[0].OrderID)
+	order1 := keeper.GetOrder(ctx, orders[1].OrderID)
+	order2 := keeper.GetOrder(ctx, orders[2].OrderID)
+	require.EqualValues(t, types.OrderStatusFilled, order0.Status)
+	require.EqualValues(t, sdk.MustNewDecFromStr("10.2"), order0.FilledAvgPrice)
+	require.EqualValues(t, types.OrderStatusFilled, order2.Status)
+	require.EqualValues(t, sdk.MustNewDecFromStr("9.9"), order2.FilledAvgPrice)
+	require.EqualValues(t, types.OrderStatusOpen, order1.Status)
+	require.EqualValues(t, sdk.MustNewDecFromStr("1"), order1.RemainQuantity)
+
+	depthBook := keeper.GetDepthBookCopy(types.TestTokenPair)
+	require.EqualValues(t, 1, len(depthBook.Items))
+	require.EqualValues(t, sdk.MustNewDecFromStr("9.9"), depthBook.Items[0].Price)
+	require.EqualValues(t, sdk.MustNewDecFromStr("1"), depthBook.Items[0].SellQuantity)
+	require.EqualValues(t, sdk.MustNewDecFromStr("9.9"), keeper.GetLastPrice(ctx, types.TestTokenPair))
+}
+
+func TestMatchOrdersByNoCrossedBook(t *testing.T) {
+	testInput := orderkeeper.CreateTestInput(t)
+	keeper := testInput.OrderKeeper
+	ctx := testInput.Ctx
+	tokenPair := dex.GetBuiltInTokenPair()
+	tokenPair.MatchMode = dex.MatchModeContinuousAuction
+	err := testInput.DexKeeper.SaveTokenPair(ctx, tokenPair)
+	require.Nil(t, err)
+
+	orders := []*types.Order{
+		mockOrder("", types.TestTokenPair, types.BuyOrder, "9.9", "1.0"),
+		mockOrder("", types.TestTokenPair, types.SellOrder, "10.0", "1.0"),
+	}
+	orders[0].Sender = testInput.TestAddrs[0]
+	orders[1].Sender = testInput.TestAddrs[1]
+	for _, order := range orders {
+		err := keeper.PlaceOrder(ctx, order)
+		require.NoError(t, err)
+	}
+
+	matchOrders(ctx, keeper, nil)
+
+	depthBook := keeper.GetDepthBookCopy(types.TestTokenPair)
+	require.EqualValues(t, 2, len(depthBook.Items))
+	require.Nil(t, keeper.GetBlockMatchResult())
+}
+
+func TestMatchOrdersSkipPeriodicAuctionPair(t *testing.T) {
+	testInput := orderkeeper.CreateTestInput(t)
+	keeper := testInput.OrderKeeper
+	ctx := testInput.Ctx
+	tokenPair := dex.GetBuiltInTokenPair()
+	err := testInput.DexKeeper.SaveTokenPair(ctx, tokenPair)
+	require.Nil(t, err)
+
+	orders := []*types.Order{
+		mockOrder("", types.TestTokenPair, types.SellOrder, "10.0", "1.0"),
+		mockOrder("", types.TestTokenPair, types.BuyOrder, "10.1", "1.0"),
+	}
+	orders[0].Sender = testInput.TestAddrs[1]
+	orders[1].Sender = testInput.TestAddrs[0]
+	for _, order := range orders {
+		err := keeper.PlaceOrder(ctx, order)
+		require.NoError(t, err)
+	}
+
+	// the token pair is matched by periodic auction, continuous auction leaves the crossed book alone
+	matchOrders(ctx, keeper, nil)
+
+	for _, order := range orders {
+		require.EqualValues(t, types.OrderStatusOpen, keeper.GetOrder(ctx, order.OrderID).Status)
+	}
+	require.Nil(t, keeper.GetBlockMatchResult())
+}
+
+func TestMatchOrdersByTimeInForce(t *testing.T) {
+	testInput := orderkeeper.CreateTestInput(t)
+	keeper := testInput.OrderKeeper
+	ctx := testInput.Ctx.WithBlockHeight(1)
+	tokenPair := dex.GetBuiltInTokenPair()
+	tokenPair.MatchMode = dex.MatchModeContinuousAuction
+	err := testInput.DexKeeper.SaveTokenPair(ctx, tokenPair)
+	require.Nil(t, err)
+
+	restingOrders := []*types.Order{
+		mockOrder("", types.TestTokenPair, types.SellOrder, "10.0", "1.0"),
+		mockOrder("", types.TestTokenPair, types.SellOrder, "10.1", "1.0"),
+	}
+	for _, order := range restingOrders {
+		order.Sender = testInput.TestAddrs[1]
+		err := keeper.PlaceOrder(ctx, order)
+		require.NoError(t, err)
+	}
+	matchOrders(ctx, keeper, nil)
+
+	ctx = ctx.WithBlockHeight(2)
+	orders := []*types.Order{
+		mockOrder("", types.TestTokenPair, types.BuyOrder, "10.0", "1.0"),
+		mockOrder("", types.TestTokenPair, types.BuyOrder, "9.9", "1.0"),
+		mockOrder("", types.TestTokenPair, types.BuyOrder, "10.1", "3.0"),
+		mockOrder("", types.TestTokenPair, types.BuyOrder, "10.0", "1.5"),
+		mockOrder("", types.TestTokenPair, types.BuyOrder, "10.1", "1.0"),
+	}
+	timeInForces := []string{types.TimeInForcePostOnly, types.TimeInForcePostOnly, types.TimeInForceFOK,
+		types.TimeInForceIOC, types.TimeInForceFOK}
+	for i, order := range orders {
+		order.Sender = testInput.TestAddrs[0]
+		order.TimeInForce = timeInForces[i]
+		err := keeper.PlaceOrder(ctx, order)
+		require.NoError(t, err)
+	}
+	matchOrders(ctx, keeper, nil)
+
+	// the post-only order taking the ask is rejected, the other one rests in depth book
+	// the FOK order exceeding the asks is killed, the IOC order is partially filled and cancelled
+	expectedStatus := []int64{types.OrderStatusPostOnlyRejected, types.OrderStatusOpen, types.OrderStatusFOKKilled,
+		types.OrderStatusPartialFilledIOCCancelled, types.OrderStatusFilled}
+	for i, order := range orders {
+		order = keeper.GetOrder(ctx, order.OrderID)
+		require.EqualValues(t, expectedStatus[i], order.Status, i)
+	}
+	require.EqualValues(t, sdk.MustNewDecFromStr("0.5"), keeper.GetOrder(ctx, orders[3].OrderID).RemainQuantity)
+	for _, order := range restingOrders {
+		require.EqualValues(t, types.OrderStatusFilled, keeper.GetOrder(ctx, order.OrderID).Status)
+	}
+
+	depthBook := keeper.GetDepthBookCopy(types.TestTokenPair)
+	require.EqualValues(t, 1, len(depthBook.Items))
+	require.EqualValues(t, sdk.MustNewDecFromStr("9.9"), depthBook.Items[0].Price)
+	require.EqualValues(t, sdk.MustNewDecFromStr("1"), depthBook.Items[0].BuyQuantity)
+
+	// only the resting post-only order still locks coins: 9.9 okt
+	lockedCoins := testInput.TokenKeeper.GetLockedCoins(ctx, testInput.TestAddrs[0])
+	require.EqualValues(t, sdk.MustNewDecFromStr("9.9"), lockedCoins.AmountOf(sdk.DefaultBondDenom))
+}
+
+func TestMatchOrdersTriggeredFirst(t *testing.T) {
+	testInput := orderkeeper.CreateTestInput(t)
+	keeper := testInput.OrderKeeper
+	ctx := testInput.Ctx
+	tokenPair := dex.GetBuiltInTokenPair()
+	tokenPair.MatchMode = dex.MatchModeContinuousAuction
+	err := testInput.DexKeeper.SaveTokenPair(ctx, tokenPair)
+	require.Nil(t, err)
+	keeper.SetLastPrice(ctx, types.TestTokenPair, sdk.MustNewDecFromStr("10.0"))
+
+	// the bid rests in depth book since last block
+	orders := []*types.Order{
+		mockOrder("", types.TestTokenPair, types.BuyOrder, "10.0", "1.0"),
+		mockOrder("", types.TestTokenPair, types.SellOrder, "9.9", "1.0"),
+		mockOrder("", types.TestTokenPair, types.SellOrder, "10.0", "1.0"),
+	}
+	orders[0].Sender = testInput.TestAddrs[0]
+	err = keeper.PlaceOrder(ctx.WithBlockHeight(ctx.BlockHeight()-1), orders[0])
+	require.NoError(t, err)
+
+	// the stop-loss order is placed before the sell order, but arrives first when it is triggered
+	orders[1].Sender = testInput.TestAddrs[1]
+	orders[1].Status = types.OrderStatusUntriggered
+	orders[1].Trigger = &types.OrderTrigger{Type: types.TriggerTypeStopLoss, Price: sdk.MustNewDecFromStr("10.0")}
+	orders[2].Sender = testInput.TestAddrs[1]
+	for _, order := range orders[1:] {
+		err := keeper.PlaceOrder(ctx, order)
+		require.NoError(t, err)
+	}
+	require.EqualValues(t, types.OrderStatusUntriggered, keeper.GetOrder(ctx, orders[1].OrderID).Status)
+
+	triggeredOrders := keeper.TriggerOrders(ctx, dex.MatchModeContinuousAuction)
+	require.EqualValues(t, 1, len(triggeredOrders))
+	matchOrders(ctx, keeper, triggeredOrders)
+
+	order1 := keeper.GetOrder(ctx, orders[1].OrderID)
+	order2 := keeper.GetOrder(ctx, orders[2].OrderID)
+	require.EqualValues(t, types.OrderStatusFilled, order1.Status)
+	require.EqualValues(t, sdk.MustNewDecFromStr("10"), order1.FilledAvgPrice)
+	require.EqualValues(t, ctx.BlockHeight(), order1.Trigger.TriggeredHeight)
+	require.EqualValues(t, types.OrderStatusOpen, order2.Status)
+
+	depthBook := keeper.GetDepthBookCopy(types.TestTokenPair)
+	require.EqualValues(t, 1, len(depthBook.Items))
+	require.EqualValues(t, sdk.MustNewDecFromStr("1"), depthBook.Items[0].SellQuantity)
+}
+
+func TestMatchOrdersReplaced(t *testing.T) {
+	testInput := orderkeeper.CreateTestInput(t)
+	keeper := testInput.OrderKeeper
+	ctx := testInput.Ctx
+	tokenPair := dex.GetBuiltInTokenPair()
+	tokenPair.MatchMode = dex.MatchModeContinuousAuction
+	err := testInput.DexKeeper.SaveTokenPair(ctx, tokenPair)
+	require.Nil(t, err)
+
+	// the book rests since last block
+	orders := []*types.Order{
+		mockOrder("", types.TestTokenPair, types.BuyOrder, "9.9", "1.0"),
+		mockOrder("", types.TestTokenPair, types.SellOrder, "10.0", "1.0"),
+	}
+	orders[0].Sender = testInput.TestAddrs[0]
+	orders[1].Sender = testInput.TestAddrs[1]
+	for _, order := range orders {
+		err := keeper.PlaceOrder(ctx.WithBlockHeight(ctx.BlockHeight()-1), order)
+		require.NoError(t, err)
+	}
+
+	// the bid replaced to cross the book arrives in current block
+	err = keeper.ReplaceOrder(ctx, orders[0], sdk.MustNewDecFromStr("10.0"), sdk.MustNewDecFromStr("1.0"))
+	require.NoError(t, err)
+	matchOrders(ctx, keeper, nil)
+
+	for _, order := range orders {
+		order = keeper.GetOrder(ctx, order.OrderID)
+		require.EqualValues(t, types.OrderStatusFilled, order.Status)
+		require.EqualValues(t, sdk.MustNewDecFromStr("10"), order.FilledAvgPrice)
+	}
+	require.EqualValues(t, 0, len(keeper.GetDepthBookCopy(types.TestTokenPair).Items))
+}
+
+func TestMatchOrdersMakerTakerFee(t *testing.T) {
+	testInput := orderkeeper.CreateTestInput(t)
+	keeper := testInput.OrderKeeper
+	ctx := testInput.Ctx
+	tokenPair := dex.GetBuiltInTokenPair()
+	tokenPair.MatchMode = dex.MatchModeContinuousAuction
+	err := testInput.DexKeeper.SaveTokenPair(ctx, tokenPair)
+	require.Nil(t, err)
+
+	params := types.DefaultParams()
+	params.FeeSchedules = []types.FeeSchedule{{
+		Product:      types.TestTokenPair,
+		MakerFeeRate: sdk.MustNewDecFromStr("0.0005"),
+		TakerFeeRate: sdk.MustNewDecFromStr("0.002"),
+	}}
+	keeper.SetParams(ctx, &params)
+
+	// the ask rests since last block, the bid takes it
+	orders := []*types.Order{
+		mockOrder("", types.TestTokenPair, types.SellOrder, "10.0", "1.0"),
+		mockOrder("", types.TestTokenPair, types.BuyOrder, "10.0", "1.0"),
+	}
+	orders[0].Sender = testInput.TestAddrs[1]
+	orders[1].Sender = testInput.TestAddrs[0]
+	err = keeper.PlaceOrder(ctx.WithBlockHeight(ctx.BlockHeight()-1), orders[0])
+	require.NoError(t, err)
+	err = keeper.PlaceOrder(ctx, orders[1])
+	require.NoError(t, err)
+
+	matchOrders(ctx, keeper, nil)
+
+	deals := keeper.GetBlockMatchResult().ResultMap[types.TestTokenPair].Deals
+	require.EqualValues(t, 2, len(deals))
+	// maker sells 1 at 10.0: 10 * 0.0005, taker buys 1: 1 * 0.002
+	require.EqualValues(t, orders[0].OrderID, deals[0].OrderID)
+	require.EqualValues(t, "0.00500000"+sdk.DefaultBondDenom, deals[0].Fee)
+	require.EqualValues(t, orders[1].OrderID, deals[1].OrderID)
+	require.EqualValues(t, "0.00200000"+types.TestTokenPair[:3], deals[1].Fee)
+
+	// the trade volume of both sides is recorded in quote token
+	for _, addr := range testInput.TestAddrs[:2] {
+		require.EqualValues(t, sdk.MustNewDecFromStr("10"), keeper.GetTrailingTradeVolume(ctx, addr, types.TestTokenPair))
+	}
+}
+
+func TestMatchOrdersSelfTradePrevention(t *testing.T) {
+	tests := []struct {
+		mode             string
+		byAccount        bool
+		statuses         []int64
+		remainQuantities []string
+		selfTrades       int
+	}{
+		// the incoming bid is cancelled, the resting asks are untouched
+		{types.SelfTradePreventionCancelNewest, false,
+			[]int64{types.OrderStatusOpen, types.OrderStatusOpen, types.OrderStatusCancelled},
+			[]string{"2", "1", "3"}, 1},
+		// the own ask is cancelled, the incoming bid takes the ask of the other sender
+		{types.SelfTradePreventionCancelOldest, false,
+			[]int64{types.OrderStatusCancelled, types.OrderStatusFilled, types.OrderStatusOpen},
+			[]string{"2", "0", "2"}, 1},
+		// both are decremented by 2, the own ask is closed and the incoming bid is left 1 to fill
+		{types.SelfTradePreventionDecrementBoth, true,
+			[]int64{types.OrderStatusCancelled, types.OrderStatusFilled, types.OrderStatusFilled},
+			[]string{"2", "0", "0"}, 2},
+	}
+
+	for _, test := range tests {
+		testInput := orderkeeper.CreateTestInput(t)
+		keeper := testInput.OrderKeeper
+		ctx := testInput.Ctx
+		tokenPair := dex.GetBuiltInTokenPair()
+		tokenPair.MatchMode = dex.MatchModeContinuousAuction
+		err := testInput.DexKeeper.SaveTokenPair(ctx, tokenPair)
+		require.Nil(t, err)
+
+		// the asks rest since last block, the bid of the same sender as the best ask arrives in current block
+		orders := []*types.Order{
+			mockOrder("", types.TestTokenPair, types.SellOrder, "10.0", "2.0"),
+			mockOrder("", types.TestTokenPair, types.SellOrder, "10.1", "1.0"),
+			mockOrder("", types.TestTokenPair, types.BuyOrder, "10.1", "3.0"),
+		}
+		orders[0].Sender = testInput.TestAddrs[0]
+		orders[1].Sender = testInput.TestAddrs[1]
+		orders[2].Sender = testInput.TestAddrs[0]
+		if test.byAccount {
+			keeper.SetAccountSelfTradePrevention(ctx, testInput.TestAddrs[0], test.mode)
+		} else {
+			orders[2].SelfTradePrevention = test.mode
+		}
+		for _, order := range orders[:2] {
+			err := keeper.PlaceOrder(ctx.WithBlockHeight(ctx.BlockHeight()-1), order)
+			require.NoError(t, err)
+		}
+		err = keeper.PlaceOrder(ctx, orders[2])
+		require.NoError(t, err)
+
+		matchOrders(ctx, keeper, nil)
+
+		for i, order := range orders {
+			order = keeper.GetOrder(ctx, order.OrderID)
+			require.EqualValues(t, test.statuses[i], order.Status, test.mode)
+			require.EqualValues(t, sdk.MustNewDecFromStr(test.remainQuantities[i]), order.RemainQuantity, test.mode)
+		}
+		require.EqualValues(t, test.selfTrades, len(keeper.GetBlockMatchResult().SelfTrades), test.mode)
+		// no deal between the orders of the same sender
+		for _, matchResult := range keeper.GetBlockMatchResult().ResultMap {
+			for _, deal := range matchResult.Deals {
+				require.NotEqual(t, orders[0].OrderID, deal.OrderID)
+			}
+		}
+	}
+}
+
+func TestMatchOrdersIceberg(t *testing.T) {
+	testInput := orderkeeper.CreateTestInput(t)
+	keeper := testInput.OrderKeeper
+	ctx := testInput.Ctx
+	tokenPair := dex.GetBuiltInTokenPair()
+	tokenPair.MatchMode = dex.MatchModeContinuousAuction
+	err := testInput.DexKeeper.SaveTokenPair(ctx, tokenPair)
+	require.Nil(t, err)
+
+	// the incoming buy order is filled against the full quantity of the resting iceberg order
+	orders := []*types.Order{
+		mockOrder("", types.TestTokenPair, types.SellOrder, "9.9", "5.0"),
+		mockOrder("", types.TestTokenPair, types.BuyOrder, "10.0", "4.0"),
+	}
+	orders[0].Sender = testInput.TestAddrs[1]
+	display := sdk.MustNewDecFromStr("1.0")
+	orders[0].DisplayQuantity = &display
+	orders[1].Sender = testInput.TestAddrs[0]
+	orders[1].DisplayQuantity = &display
+	for _, order := range orders {
+		err := keeper.PlaceOrder(ctx, order)
+		require.NoError(t, err)
+	}
+
+	matchOrders(ctx, keeper, nil)
+
+	order0 := keeper.GetOrder(ctx, orders[0].OrderID)
+	order1 := keeper.GetOrder(ctx, orders[1].OrderID)
+	require.EqualValues(t, types.OrderStatusFilled, order1.Status)
+	require.EqualValues(t, sdk.MustNewDecFromStr("1"), order0.RemainQuantity)
+	require.EqualValues(t, sdk.MustNewDecFromStr("1"), order0.VisibleQuantity())
+
+	depthBook := keeper.GetDepthBookCopy(types.TestTokenPair)
+	require.EqualValues(t, 1, len(depthBook.Items))
+	require.EqualValues(t, sdk.MustNewDecFromStr("1"), depthBook.Items[0].SellQuantity)
+	require.True(t, depthBook.Items[0].HiddenQuantity(types.SellOrder).IsZero())
+}
+
+func TestMatchOrdersByDealBudget(t *testing.T) {
+	testInput := orderkeeper.CreateTestInput(t)
+	keeper := testInput.OrderKeeper
+	ctx := testInput.Ctx.WithBlockHeight(1)
+	tokenPair := dex.GetBuiltInTokenPair()
+	tokenPair.MatchMode = dex.MatchModeContinuousAuction
+	err := testInput.DexKeeper.SaveTokenPair(ctx, tokenPair)
+	require.Nil(t, err)
+
+	asks := []*types.Order{
+		mockOrder("", types.TestTokenPair, types.SellOrder, "10.0", "1.0"),
+		mockOrder("", types.TestTokenPair, types.SellOrder, "10.0", "1.0"),
+		mockOrder("", types.TestTokenPair, types.SellOrder, "10.0", "1.0"),
+	}
+	for _, order := range asks {
+		order.Sender = testInput.TestAddrs[1]
+		err := keeper.PlaceOrder(ctx, order)
+		require.NoError(t, err)
+	}
+	matchOrders(ctx, keeper, nil)
+
+	// the budget of one fill is used up by the first buy order, both buy orders are left for the next block
+	params := keeper.GetParams(ctx)
+	params.MaxDealsPerBlock = 2
+	keeper.SetParams(ctx, params)
+	ctx = ctx.WithBlockHeight(2)
+	bids := []*types.Order{
+		mockOrder("", types.TestTokenPair, types.BuyOrder, "10.0", "2.0"),
+		mockOrder("", types.TestTokenPair, types.BuyOrder, "10.0", "1.0"),
+	}
+	for _, order := range bids {
+		order.Sender = testInput.TestAddrs[0]
+		err := keeper.PlaceOrder(ctx, order)
+		require.NoError(t, err)
+	}
+	matchOrders(ctx, keeper, nil)
+
+	require.EqualValues(t, 2, len(keeper.GetBlockMatchResult().ResultMap[types.TestTokenPair].Deals))
+	require.EqualValues(t, sdk.MustNewDecFromStr("1"), keeper.GetOrder(ctx, bids[0].OrderID).RemainQuantity)
+	require.EqualValues(t, sdk.MustNewDecFromStr("1"), keeper.GetOrder(ctx, bids[1].OrderID).RemainQuantity)
+	require.EqualValues(t, []string{bids[0].OrderID, bids[1].OrderID}, keeper.GetPendingIncomingOrderIDs(ctx))
+
+	// the orders left are matched first in the next block
+	params.MaxDealsPerBlock = 4
+	keeper.SetParams(ctx, params)
+	ctx = ctx.WithBlockHeight(3)
+	matchOrders(ctx, keeper, nil)
+
+	require.EqualValues(t, types.OrderStatusFilled, keeper.GetOrder(ctx, bids[0].OrderID).Status)
+	require.EqualValues(t, types.OrderStatusFilled, keeper.GetOrder(ctx, bids[1].OrderID).Status)
+	require.EqualValues(t, 0, len(keeper.GetPendingIncomingOrderIDs(ctx)))
+	require.EqualValues(t, 0, len(keeper.GetDepthBookCopy(types.TestTokenPair).Items))
+}
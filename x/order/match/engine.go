@@ -1,35 +1,49 @@
 package match
 
 import (
-	"sync"
-
 	sdk "github.com/cosmos/cosmos-sdk/types"
 
+	"github.com/okex/okchain/x/dex"
 	"github.com/okex/okchain/x/order/keeper"
 	"github.com/okex/okchain/x/order/match/continuousauction"
 	"github.com/okex/okchain/x/order/match/periodicauction"
 )
 
 // nolint
-const DefaultAuctionType = "periodicauction"
+const (
+	PeriodicAuction   = dex.MatchModePeriodicAuction
+	ContinuousAuction = dex.MatchModeContinuousAuction
+)
 
-// nolint
+// matchModes defines the sequence in which the match engines run in EndBlocker. Periodic auction runs first,
+// because it saves the match results of the block and continuous auction merges its results into them.
 var (
-	once        sync.Once
-	engine      Engine
-	auctionType = DefaultAuctionType
+	matchModes = []string{PeriodicAuction, ContinuousAuction}
+	engines    = map[string]Engine{
+		PeriodicAuction:   &periodicauction.PaEngine{},
+		ContinuousAuction: &continuousauction.CaEngine{},
+	}
 )
 
-// GetEngine : periodic auction only today
-func GetEngine() Engine {
-	once.Do(func() {
-		if auctionType == DefaultAuctionType {
-			engine = &periodicauction.PaEngine{}
-		} else {
-			engine = &continuousauction.CaEngine{}
-		}
-	})
-	return engine
+// GetEngine returns the match engine of the match mode, nil if the match mode is not supported
+func GetEngine(matchMode string) Engine {
+	return engines[matchMode]
+}
+
+// Run cleans up the expired orders, the orders of delisted token pairs and the orders of the accounts missing the
+// deadline of their cancel-after, then runs every match engine.
+// Each engine only matches the products whose token pair is configured with its match mode.
+// The market, IOC and FOK orders are matched only once, their remainder is quit after match. The GTB orders
+// expire after the match of their expire height.
+func Run(ctx sdk.Context, keeper keeper.Keeper) {
+	cleanupExpiredOrders(ctx, keeper)
+	cleanupOrdersWhoseTokenPairHaveBeenDelisted(ctx, keeper)
+	cancelOrdersAfterDeadline(ctx, keeper)
+	for _, matchMode := range matchModes {
+		GetEngine(matchMode).Run(ctx, keeper)
+	}
+	quitImmediateOrders(ctx, keeper)
+	expireGTBOrders(ctx, keeper)
 }
 
 // nolint
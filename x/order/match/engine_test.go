@@ -0,0 +1,110 @@
+package match
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/okex/okchain/x/dex"
+	orderkeeper "github.com/okex/okchain/x/order/keeper"
+	"github.com/okex/okchain/x/order/types"
+)
+
+func TestGetEngine(t *testing.T) {
+	require.NotNil(t, GetEngine(PeriodicAuction))
+	require.NotNil(t, GetEngine(ContinuousAuction))
+	require.Nil(t, GetEngine("callauction"))
+}
+
+func TestRunByMatchMode(t *testing.T) {
+	tests := []struct {
+		matchMode string
+		price     string
+	}{
+		// the deal price is the best price next to the last price
+		{PeriodicAuction, "10.2"},
+		// the deal price is the price of the resting buy order
+		{ContinuousAuction, "10.5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.matchMode, func(t *testing.T) {
+			testInput := orderkeeper.CreateTestInput(t)
+			keeper := testInput.OrderKeeper
+			ctx := testInput.Ctx.WithBlockHeight(10)
+			tokenPair := dex.GetBuiltInTokenPair()
+			tokenPair.MatchMode = tt.matchMode
+			err := testInput.DexKeeper.SaveTokenPair(ctx, tokenPair)
+			require.Nil(t, err)
+
+			orders := []*types.Order{
+				mockOrder("", types.TestTokenPair, types.BuyOrder, "10.5", "1.0"),
+				mockOrder("", types.TestTokenPair, types.SellOrder, "10.2", "1.0"),
+			}
+			orders[0].Sender = testInput.TestAddrs[0]
+			orders[1].Sender = testInput.TestAddrs[1]
+			for _, order := range orders {
+				err := keeper.PlaceOrder(ctx, order)
+				require.NoError(t, err)
+			}
+
+			Run(ctx, keeper)
+
+			for _, order := range orders {
+				order = keeper.GetOrder(ctx, order.OrderID)
+				require.EqualValues(t, types.OrderStatusFilled, order.Status)
+				require.EqualValues(t, sdk.MustNewDecFromStr(tt.price), order.FilledAvgPrice)
+			}
+			require.EqualValues(t, sdk.MustNewDecFromStr(tt.price), keeper.GetLastPrice(ctx, types.TestTokenPair))
+		})
+	}
+}
+
+func TestRunSharesDealBudget(t *testing.T) {
+	testInput := orderkeeper.CreateTestInput(t)
+	keeper := testInput.OrderKeeper
+	ctx := testInput.Ctx.WithBlockHeight(10)
+	tokenPair := dex.GetBuiltInTokenPair()
+	err := testInput.DexKeeper.SaveTokenPair(ctx, tokenPair)
+	require.Nil(t, err)
+	tokenPair = dex.GetBuiltInTokenPair()
+	tokenPair.BaseAssetSymbol, tokenPair.QuoteAssetSymbol = tokenPair.QuoteAssetSymbol, tokenPair.BaseAssetSymbol
+	tokenPair.MatchMode = ContinuousAuction
+	err = testInput.DexKeeper.SaveTokenPair(ctx, tokenPair)
+	require.Nil(t, err)
+	continuousPair := tokenPair.Name()
+
+	params := types.DefaultParams()
+	params.MaxDealsPerBlock = 4
+	keeper.SetParams(ctx, &params)
+
+	// periodic auction makes 2 deals, which leaves continuous auction the budget of one fill
+	orders := []*types.Order{
+		mockOrder("", types.TestTokenPair, types.BuyOrder, "10.5", "1.0"),
+		mockOrder("", types.TestTokenPair, types.SellOrder, "10.2", "1.0"),
+		mockOrder("", continuousPair, types.SellOrder, "0.1", "1.0"),
+		mockOrder("", continuousPair, types.SellOrder, "0.1", "1.0"),
+		mockOrder("", continuousPair, types.BuyOrder, "0.1", "1.0"),
+		mockOrder("", continuousPair, types.BuyOrder, "0.1", "1.0"),
+	}
+	for i, order := range orders {
+		order.Sender = testInput.TestAddrs[i%2]
+		if order.Product == continuousPair {
+			order.Sender = testInput.TestAddrs[i/4]
+		}
+		err := keeper.PlaceOrder(ctx, order)
+		require.NoError(t, err)
+	}
+
+	Run(ctx, keeper)
+
+	var deals int
+	for _, matchResult := range keeper.GetBlockMatchResult().ResultMap {
+		deals += len(matchResult.Deals)
+	}
+	require.EqualValues(t, params.MaxDealsPerBlock, deals)
+	require.EqualValues(t, 2, len(keeper.GetBlockMatchResult().ResultMap[types.TestTokenPair].Deals))
+	require.EqualValues(t, 2, len(keeper.GetBlockMatchResult().ResultMap[continuousPair].Deals))
+	require.EqualValues(t, []string{orders[5].OrderID}, keeper.GetPendingIncomingOrderIDs(ctx))
+}
@@ -0,0 +1,90 @@
+package periodicauction
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/okex/okchain/x/order/types"
+)
+
+// allocator allocates the fill amount at a price level among the orders queued at the price. It returns the
+// fill amount of every order in the sequence of the queue, every amount is rounded at the quantity precision
+type allocator interface {
+	allocate(orders []*types.Order, fillAmount sdk.Dec, quantityPrecision int64, blockHeight int64) []sdk.Dec
+}
+
+// allocators of the pro-rata rules. There is no allocator of FIFO, the orders are filled in the sequence they are
+// queued by fillOrderByKeyInSequence
+var allocators = map[string]allocator{
+	types.AllocationRuleProRata:  proRataAllocator{weight: remainQuantityWeight},
+	types.AllocationRuleSizeTime: proRataAllocator{weight: sizeTimeWeight},
+}
+
+// getAllocator returns the allocator of the allocation rule, false for FIFO. The rules unknown are refused by the
+// validation of params, see types.Params.ValidateAllocationRule
+func getAllocator(allocationRule string) (allocator, bool) {
+	a, ok := allocators[allocationRule]
+	return a, ok
+}
+
+// proRataAllocator shares the fill amount in proportion to the weight of every order. The share is truncated at
+// the quantity precision, and the remainder by truncation is filled in the sequence the orders are queued
+type proRataAllocator struct {
+	weight func(order *types.Order, blockHeight int64) sdk.Dec
+}
+
+func (a proRataAllocator) allocate(orders []*types.Order, fillAmount sdk.Dec, quantityPrecision int64,
+	blockHeight int64) []sdk.Dec {
+
+	weights := make([]sdk.Dec, len(orders))
+	totalWeight := sdk.ZeroDec()
+	for i, order := range orders {
+		weights[i] = a.weight(order, blockHeight)
+		totalWeight = totalWeight.Add(weights[i])
+	}
+
+	amounts := make([]sdk.Dec, len(orders))
+	allocated := sdk.ZeroDec()
+	for i, order := range orders {
+		amounts[i] = sdk.ZeroDec()
+		if totalWeight.IsPositive() {
+			share := truncateDecimal(fillAmount.Mul(weights[i]).Quo(totalWeight), quantityPrecision)
+			amounts[i] = sdk.MinDec(share, order.RemainQuantity)
+		}
+		allocated = allocated.Add(amounts[i])
+	}
+	fillInSequence(orders, amounts, fillAmount.Sub(allocated))
+	return amounts
+}
+
+// remainQuantityWeight weights the order by its remaining quantity
+func remainQuantityWeight(order *types.Order, blockHeight int64) sdk.Dec {
+	return order.RemainQuantity
+}
+
+// sizeTimeWeight weights the order by its remaining quantity times the blocks it has been queued, including
+// current block
+func sizeTimeWeight(order *types.Order, blockHeight int64) sdk.Dec {
+	queuedBlocks := blockHeight - types.GetBlockHeightFromOrderID(order.OrderID) + 1
+	if queuedBlocks < 1 {
+		queuedBlocks = 1
+	}
+	return order.RemainQuantity.MulInt64(queuedBlocks)
+}
+
+// fillInSequence adds the left amount to the orders in the sequence they are queued, until the orders are filled
+func fillInSequence(orders []*types.Order, amounts []sdk.Dec, leftAmount sdk.Dec) {
+	for i, order := range orders {
+		if !leftAmount.IsPositive() {
+			return
+		}
+		fillAmount := sdk.MinDec(leftAmount, order.RemainQuantity.Sub(amounts[i]))
+		amounts[i] = amounts[i].Add(fillAmount)
+		leftAmount = leftAmount.Sub(fillAmount)
+	}
+}
+
+// truncateDecimal truncates the decimal at the precision
+func truncateDecimal(d sdk.Dec, precision int64) sdk.Dec {
+	precisionMul := sdk.NewIntWithDecimal(1, int(precision))
+	return d.MulInt(precisionMul).TruncateDec().QuoInt(precisionMul)
+}
@@ -0,0 +1,104 @@
+package periodicauction
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/okex/okchain/x/dex"
+	orderkeeper "github.com/okex/okchain/x/order/keeper"
+	"github.com/okex/okchain/x/order/types"
+)
+
+func mockQueuedOrders() []*types.Order {
+	return []*types.Order{
+		mockOrder(types.FormatOrderID(10, 1), types.TestTokenPair, types.BuyOrder, "10.0", "1.0"),
+		mockOrder(types.FormatOrderID(10, 2), types.TestTokenPair, types.BuyOrder, "10.0", "2.0"),
+		mockOrder(types.FormatOrderID(12, 1), types.TestTokenPair, types.BuyOrder, "10.0", "3.0"),
+	}
+}
+
+func requireAmounts(t *testing.T, expected []string, amounts []sdk.Dec) {
+	require.EqualValues(t, len(expected), len(amounts))
+	for i, amount := range amounts {
+		require.EqualValues(t, sdk.MustNewDecFromStr(expected[i]), amount, i)
+	}
+}
+
+func TestGetAllocator(t *testing.T) {
+	// FIFO and the unknown rule falling back to it have no allocator, the orders are filled in sequence
+	_, ok := getAllocator(types.AllocationRuleFIFO)
+	require.False(t, ok)
+	_, ok = getAllocator("unknown")
+	require.False(t, ok)
+}
+
+func TestAllocateProRata(t *testing.T) {
+	allocator, ok := getAllocator(types.AllocationRuleProRata)
+	require.True(t, ok)
+	requireAmounts(t, []string{"0.5", "1", "1.5"}, allocator.allocate(mockQueuedOrders(),
+		sdk.MustNewDecFromStr("3"), 4, 12))
+
+	// 1/6, 2/6 and 3/6 are truncated at 4 digits, the remainder 0.0001 is filled in sequence
+	requireAmounts(t, []string{"0.1667", "0.3333", "0.5"}, allocator.allocate(mockQueuedOrders(),
+		sdk.MustNewDecFromStr("1"), 4, 12))
+	requireAmounts(t, []string{"0.2", "0.3", "0.5"}, allocator.allocate(mockQueuedOrders(),
+		sdk.MustNewDecFromStr("1"), 1, 12))
+
+	// all the orders are filled if the fill amount is enough
+	requireAmounts(t, []string{"1", "2", "3"}, allocator.allocate(mockQueuedOrders(),
+		sdk.MustNewDecFromStr("6"), 4, 12))
+}
+
+func TestAllocateSizeTime(t *testing.T) {
+	// the weights are 1*3, 2*3 and 3*1
+	allocator, ok := getAllocator(types.AllocationRuleSizeTime)
+	require.True(t, ok)
+	requireAmounts(t, []string{"0.5", "1", "0.5"}, allocator.allocate(mockQueuedOrders(),
+		sdk.MustNewDecFromStr("2"), 4, 12))
+}
+
+func TestFillOrderByKeyProRata(t *testing.T) {
+	testInput := orderkeeper.CreateTestInput(t)
+	keeper := testInput.OrderKeeper
+	ctx := testInput.Ctx
+	tokenPair := dex.GetBuiltInTokenPair()
+	err := testInput.DexKeeper.SaveTokenPair(ctx, tokenPair)
+	require.Nil(t, err)
+
+	orders := []*types.Order{
+		mockOrder("", types.TestTokenPair, types.BuyOrder, "10.0", "1.0"),
+		mockOrder("", types.TestTokenPair, types.BuyOrder, "10.0", "3.0"),
+		mockOrder("", types.TestTokenPair, types.BuyOrder, "10.0", "1.0"),
+	}
+	for _, order := range orders {
+		order.Sender = testInput.TestAddrs[0]
+		err := keeper.PlaceOrder(ctx, order)
+		require.Nil(t, err)
+	}
+
+	feeParams := types.DefaultParams()
+	feeParams.AllocationRule = types.AllocationRuleProRata
+	key := types.FormatOrderIDsKey(types.TestTokenPair, orders[0].Price, types.BuyOrder)
+	deals, filledAmount, _, filledDealsCnt := fillOrderByKey(ctx, keeper, key, sdk.MustNewDecFromStr("2.5"),
+		sdk.MustNewDecFromStr("10.0"), &feeParams, 1000)
+	require.EqualValues(t, 3, len(deals))
+	require.EqualValues(t, 3, filledDealsCnt)
+	require.EqualValues(t, sdk.MustNewDecFromStr("2.5"), filledAmount)
+
+	expectedRemains := []string{"0.5", "1.5", "0.5"}
+	for i, order := range orders {
+		order = keeper.GetOrder(ctx, order.OrderID)
+		require.EqualValues(t, sdk.MustNewDecFromStr(expectedRemains[i]), order.RemainQuantity, i)
+	}
+	require.EqualValues(t, 3, len(keeper.GetProductPriceOrderIDs(key)))
+
+	// only the first remainDeals orders take part in the allocation
+	deals, filledAmount, _, filledDealsCnt = fillOrderByKey(ctx, keeper, key, sdk.MustNewDecFromStr("2.5"),
+		sdk.MustNewDecFromStr("10.0"), &feeParams, 2)
+	require.EqualValues(t, 2, len(deals))
+	require.EqualValues(t, 2, filledDealsCnt)
+	require.EqualValues(t, sdk.MustNewDecFromStr("2"), filledAmount)
+	require.EqualValues(t, []string{orders[2].OrderID}, keeper.GetProductPriceOrderIDs(key))
+}
@@ -0,0 +1,98 @@
+package periodicauction
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/okex/okchain/x/order/keeper"
+	"github.com/okex/okchain/x/order/types"
+)
+
+// scheduleDealBudget shares the deal budget left in the block among the products to be executed, in the sequence of
+// products. The SEQUENTIAL policy schedules nothing, every product spends the budget left by the products before
+// it. The FAIR policy guarantees every product the minimum deals, then shares the rest in proportion to the deals
+// every product still needs. It returns the budget of every product and the budget not scheduled
+func scheduleDealBudget(ctx sdk.Context, k keeper.Keeper, products []string,
+	updatedProductsBasePrice map[string]types.MatchResult, lockMap *types.ProductLockMap,
+	feeParams *types.Params) (map[string]int64, int64) {
+
+	maxDeals := k.GetBlockRemainDeals(ctx)
+	if feeParams.DealBudgetPolicy != types.DealBudgetPolicyFair {
+		return map[string]int64{}, maxDeals
+	}
+
+	var scheduledProducts []string
+	demands := make(map[string]int64, len(products))
+	for _, product := range products {
+		if _, ok := demands[product]; ok {
+			continue
+		}
+		if matchResult, ok := updatedProductsBasePrice[product]; ok {
+			demands[product] = estimateDeals(k, product, matchResult.Price)
+		} else if lock, ok := lockMap.Data[product]; ok {
+			demands[product] = estimateDeals(k, product, lock.Price)
+		} else {
+			continue
+		}
+		scheduledProducts = append(scheduledProducts, product)
+	}
+	return allocateDealBudget(scheduledProducts, demands, maxDeals, feeParams.MinDealsPerProduct)
+}
+
+// allocateDealBudget allocates the deals among the products by their demands. Every product gets the minimum deals
+// in sequence first, then the rest is shared in proportion to the unmet demands, and the remainder by rounding is
+// filled in sequence. It returns the budget of every product and the deals left
+func allocateDealBudget(products []string, demands map[string]int64, maxDeals,
+	minDeals int64) (map[string]int64, int64) {
+
+	budgets := make(map[string]int64, len(products))
+	leftDeals := maxDeals
+	for _, product := range products {
+		budgets[product] = minInt64(minInt64(demands[product], minDeals), leftDeals)
+		leftDeals -= budgets[product]
+	}
+
+	var unmetDemand int64
+	for _, product := range products {
+		unmetDemand += demands[product] - budgets[product]
+	}
+	if leftDeals <= 0 || unmetDemand <= 0 {
+		return budgets, leftDeals
+	}
+
+	sharedDeals := leftDeals
+	for _, product := range products {
+		share := sdk.NewInt(sharedDeals).MulRaw(demands[product] - budgets[product]).QuoRaw(unmetDemand).Int64()
+		budgets[product] += share
+		leftDeals -= share
+	}
+	for _, product := range products {
+		extra := minInt64(demands[product]-budgets[product], leftDeals)
+		budgets[product] += extra
+		leftDeals -= extra
+	}
+	return budgets, leftDeals
+}
+
+// estimateDeals returns the most deals the product needs to be executed at the price, which is the number of the
+// orders at the executable prices of both sides
+func estimateDeals(k keeper.Keeper, product string, price sdk.Dec) int64 {
+	var deals int64
+	for _, item := range k.GetDepthBookCopy(product).Items {
+		if item.BuyQuantity.IsPositive() && item.Price.GTE(price) {
+			deals += int64(len(k.GetProductPriceOrderIDs(types.FormatOrderIDsKey(product, item.Price,
+				types.BuyOrder))))
+		}
+		if item.SellQuantity.IsPositive() && item.Price.LTE(price) {
+			deals += int64(len(k.GetProductPriceOrderIDs(types.FormatOrderIDsKey(product, item.Price,
+				types.SellOrder))))
+		}
+	}
+	return deals
+}
+
+func minInt64(a, b int64) int64 {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,90 @@
+package periodicauction
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/okex/okchain/x/common"
+	"github.com/okex/okchain/x/dex"
+	orderkeeper "github.com/okex/okchain/x/order/keeper"
+	"github.com/okex/okchain/x/order/types"
+)
+
+func TestAllocateDealBudget(t *testing.T) {
+	products := []string{"a", "b", "c"}
+
+	// every product gets the minimum deals, and the rest by the unmet demands
+	budgets, left := allocateDealBudget(products, map[string]int64{"a": 100, "b": 5, "c": 30}, 60, 10)
+	require.EqualValues(t, map[string]int64{"a": 39, "b": 5, "c": 16}, budgets)
+	require.EqualValues(t, 0, left)
+
+	// the minimum deals are guaranteed in sequence if the block budget is not enough
+	budgets, left = allocateDealBudget(products, map[string]int64{"a": 100, "b": 5, "c": 30}, 12, 10)
+	require.EqualValues(t, map[string]int64{"a": 10, "b": 2, "c": 0}, budgets)
+	require.EqualValues(t, 0, left)
+
+	// the remainder by rounding is filled in sequence
+	budgets, left = allocateDealBudget(products, map[string]int64{"a": 10, "b": 10, "c": 10}, 10, 1)
+	require.EqualValues(t, map[string]int64{"a": 4, "b": 3, "c": 3}, budgets)
+	require.EqualValues(t, 0, left)
+
+	// the deals more than the demands are left
+	budgets, left = allocateDealBudget(products, map[string]int64{"a": 1, "b": 2, "c": 3}, 100, 10)
+	require.EqualValues(t, map[string]int64{"a": 1, "b": 2, "c": 3}, budgets)
+	require.EqualValues(t, 94, left)
+}
+
+func TestExecuteMatchByDealBudgetPolicy(t *testing.T) {
+	secondPair := "okt_" + common.TestToken
+	for _, policy := range []string{types.DealBudgetPolicySequential, types.DealBudgetPolicyFair} {
+		testInput := orderkeeper.CreateTestInput(t)
+		keeper := testInput.OrderKeeper
+		ctx := testInput.Ctx
+		tokenPair := dex.GetBuiltInTokenPair()
+		err := testInput.DexKeeper.SaveTokenPair(ctx, tokenPair)
+		require.Nil(t, err)
+		tokenPair = dex.GetBuiltInTokenPair()
+		tokenPair.BaseAssetSymbol, tokenPair.QuoteAssetSymbol = tokenPair.QuoteAssetSymbol, tokenPair.BaseAssetSymbol
+		err = testInput.DexKeeper.SaveTokenPair(ctx, tokenPair)
+		require.Nil(t, err)
+
+		params := types.DefaultParams()
+		params.DealBudgetPolicy = policy
+		params.MaxDealsPerBlock = 4
+		params.MinDealsPerProduct = 2
+		keeper.SetParams(ctx, &params)
+
+		// the first product needs 6 deals, the second needs 2 deals
+		var orders []*types.Order
+		for i := 0; i < 3; i++ {
+			orders = append(orders,
+				mockOrder("", types.TestTokenPair, types.BuyOrder, "10.1", "1.0"),
+				mockOrder("", types.TestTokenPair, types.SellOrder, "9.9", "1.0"))
+		}
+		orders = append(orders,
+			mockOrder("", secondPair, types.BuyOrder, "0.1", "1.0"),
+			mockOrder("", secondPair, types.SellOrder, "0.1", "1.0"))
+		for i, order := range orders {
+			order.Sender = testInput.TestAddrs[i%2]
+			err := keeper.PlaceOrder(ctx, order)
+			require.Nil(t, err)
+		}
+
+		products := []string{types.TestTokenPair, secondPair}
+		updatedProductsBasePrice := calcMatchPriceAndExecution(ctx, keeper, products, nil)
+		executeMatch(ctx, keeper, products, updatedProductsBasePrice, keeper.GetDexKeeper().GetLockedProductsCopy())
+
+		require.True(t, keeper.IsProductLocked(types.TestTokenPair))
+		if policy == types.DealBudgetPolicySequential {
+			// the first product spends the whole budget, the second product starves
+			require.True(t, keeper.IsProductLocked(secondPair))
+			require.EqualValues(t, types.OrderStatusOpen, keeper.GetOrder(ctx, orders[6].OrderID).Status)
+		} else {
+			// the second product is executed by its minimum deals
+			require.False(t, keeper.IsProductLocked(secondPair))
+			require.EqualValues(t, types.OrderStatusFilled, keeper.GetOrder(ctx, orders[6].OrderID).Status)
+			require.EqualValues(t, types.OrderStatusFilled, keeper.GetOrder(ctx, orders[7].OrderID).Status)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package periodicauction
+
+import (
+	"runtime"
+	"sync"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/okex/okchain/x/order/types"
+)
+
+// priceDiscoveryWorkers is the number of workers discovering the clearing prices of products concurrently
+var priceDiscoveryWorkers = runtime.NumCPU()
+
+// priceDiscovery is the price discovery of a product. The inputs are read from the store in advance, so the
+// discovery is free of the store and can run concurrently
+type priceDiscovery struct {
+	product        string
+	book           *types.DepthBook
+	pricePrecision int64
+	refPrice       sdk.Dec
+
+	bestPrice    sdk.Dec
+	maxExecution sdk.Dec
+}
+
+// discoverMatchPrices calculates the best price and max execution of every price discovery in a pool of workers.
+// Each discovery only reads the depth book copy of its own product and writes its own result, so the results are
+// identical to the sequential calculation whatever the number of workers is
+func discoverMatchPrices(discoveries []*priceDiscovery, pressureRate sdk.Dec, workers int) {
+	if workers > len(discoveries) {
+		workers = len(discoveries)
+	}
+	if workers <= 1 {
+		for _, discovery := range discoveries {
+			discovery.discover(pressureRate)
+		}
+		return
+	}
+
+	jobs := make(chan *priceDiscovery, len(discoveries))
+	for _, discovery := range discoveries {
+		jobs <- discovery
+	}
+	close(jobs)
+
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+			for discovery := range jobs {
+				discovery.discover(pressureRate)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func (d *priceDiscovery) discover(pressureRate sdk.Dec) {
+	d.bestPrice, d.maxExecution = periodicAuctionMatchPrice(d.book, d.pricePrecision, d.refPrice, pressureRate)
+}
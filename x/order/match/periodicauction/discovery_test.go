@@ -0,0 +1,106 @@
+package periodicauction
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/okex/okchain/x/dex"
+	orderkeeper "github.com/okex/okchain/x/order/keeper"
+	"github.com/okex/okchain/x/order/types"
+)
+
+// mockRandomDepthBook returns a depth book with the levels in descending price, about half of which are empty
+func mockRandomDepthBook(r *rand.Rand, levels int) *types.DepthBook {
+	book := &types.DepthBook{}
+	for i := 0; i < levels; i++ {
+		book.Items = append(book.Items, types.DepthBookItem{
+			Price:        sdk.NewDec(int64(100 + levels - i)),
+			BuyQuantity:  sdk.NewDec(int64(r.Intn(2) * r.Intn(10))),
+			SellQuantity: sdk.NewDec(int64(r.Intn(2) * r.Intn(10))),
+		})
+	}
+	return book
+}
+
+func mockPriceDiscoveries(products, levels int) []*priceDiscovery {
+	r := rand.New(rand.NewSource(1))
+	discoveries := make([]*priceDiscovery, products)
+	for i := range discoveries {
+		discoveries[i] = &priceDiscovery{
+			product:        fmt.Sprintf("xxb%d_okt", i),
+			book:           mockRandomDepthBook(r, levels),
+			pricePrecision: 1,
+			refPrice:       sdk.NewDec(int64(r.Intn(levels) + 100)),
+		}
+	}
+	return discoveries
+}
+
+func TestDiscoverMatchPrices(t *testing.T) {
+	pressureRate := sdk.MustNewDecFromStr(types.DefaultMarketPressureRate)
+	sequential := mockPriceDiscoveries(500, 100)
+	discoverMatchPrices(sequential, pressureRate, 1)
+
+	for _, workers := range []int{0, 2, 8, 1000} {
+		parallel := mockPriceDiscoveries(500, 100)
+		discoverMatchPrices(parallel, pressureRate, workers)
+		for i := range sequential {
+			require.Equal(t, sequential[i].product, parallel[i].product)
+			require.True(t, sequential[i].bestPrice.Equal(parallel[i].bestPrice), parallel[i].product)
+			require.True(t, sequential[i].maxExecution.Equal(parallel[i].maxExecution), parallel[i].product)
+		}
+	}
+	discoverMatchPrices(nil, pressureRate, 8)
+}
+
+func TestCalcMatchPriceAndExecutionConcurrently(t *testing.T) {
+	defer func(workers int) { priceDiscoveryWorkers = workers }(priceDiscoveryWorkers)
+
+	calc := func(workers int) (map[string]types.MatchResult, []*types.ProductHalt) {
+		priceDiscoveryWorkers = workers
+		testInput := orderkeeper.CreateTestInput(t)
+		keeper := testInput.OrderKeeper
+		ctx := testInput.Ctx.WithBlockHeight(10)
+
+		r := rand.New(rand.NewSource(1))
+		var products []string
+		for i := 0; i < 100; i++ {
+			tokenPair := dex.GetBuiltInTokenPair()
+			tokenPair.BaseAssetSymbol = fmt.Sprintf("xxb%d", i)
+			tokenPair.InitPrice = sdk.NewDec(int64(r.Intn(50) + 100))
+			require.Nil(t, testInput.DexKeeper.SaveTokenPair(ctx, tokenPair))
+			product := fmt.Sprintf("%s_%s", tokenPair.BaseAssetSymbol, tokenPair.QuoteAssetSymbol)
+			keeper.SetDepthBook(product, mockRandomDepthBook(r, 50))
+			products = append(products, product)
+		}
+		keeper.GetDexKeeper().SortProducts(ctx, products)
+		return calcMatchPriceAndExecution(ctx, keeper, products, nil), keeper.GetProductHalts(ctx)
+	}
+
+	sequentialResults, sequentialHalts := calc(1)
+	require.NotEmpty(t, sequentialResults)
+	parallelResults, parallelHalts := calc(8)
+	require.Equal(t, sequentialResults, parallelResults)
+	require.Equal(t, sequentialHalts, parallelHalts)
+}
+
+func benchmarkDiscoverMatchPrices(b *testing.B, workers int) {
+	pressureRate := sdk.MustNewDecFromStr(types.DefaultMarketPressureRate)
+	discoveries := mockPriceDiscoveries(500, 200)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		discoverMatchPrices(discoveries, pressureRate, workers)
+	}
+}
+
+func BenchmarkDiscoverMatchPricesSequential(b *testing.B) {
+	benchmarkDiscoverMatchPrices(b, 1)
+}
+
+func BenchmarkDiscoverMatchPricesConcurrent(b *testing.B) {
+	benchmarkDiscoverMatchPrices(b, priceDiscoveryWorkers)
+}
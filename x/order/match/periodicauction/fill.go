@@ -1,13 +1,9 @@
 package periodicauction
 
 import (
-	"fmt"
-	"strings"
-
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	orderkeeper "github.com/okex/okchain/x/order/keeper"
 	"github.com/okex/okchain/x/order/types"
-	token "github.com/okex/okchain/x/token/types"
 )
 
 func fillBuyOrders(ctx sdk.Context, keeper orderkeeper.Keeper, product string,
@@ -32,14 +28,14 @@ func fillBuyOrders(ctx sdk.Context, keeper orderkeeper.Keeper, product string,
 
 		// Fill buy orders at this price
 		key := types.FormatOrderIDsKey(product, book.Items[index].Price, types.BuyOrder)
-		filledBuyDeals, filledBuyAmount, filledDealsCnt := fillOrderByKey(ctx, keeper, key,
+		filledBuyDeals, filledBuyAmount, filledBuyHidden, filledDealsCnt := fillOrderByKey(ctx, keeper, key,
 			fillAmount, bestPrice, feeParams, blockRemainDeals)
 		blockRemainDeals -= filledDealsCnt
 
 		buyDeals = append(buyDeals, filledBuyDeals...)
 		*buyExecuted = buyExecuted.Add(filledBuyAmount)
 
-		book.Sub(index, filledBuyAmount, types.BuyOrder)
+		book.Sub(index, filledBuyAmount, filledBuyHidden, types.BuyOrder)
 
 		res := book.RemoveIfEmpty(index)
 		if !res {
@@ -80,14 +76,14 @@ func fillSellOrders(ctx sdk.Context, keeper orderkeeper.Keeper, product string,
 		// Fill sell orders at this price
 		key := types.FormatOrderIDsKey(product, book.Items[index].Price, types.SellOrder)
 
-		filledSellDeals, filledSellAmount, filledDealsCnt := fillOrderByKey(ctx, keeper,
+		filledSellDeals, filledSellAmount, filledSellHidden, filledDealsCnt := fillOrderByKey(ctx, keeper,
 			key, fillAmount, bestPrice, feeParams, blockRemainDeals)
 
 		blockRemainDeals -= filledDealsCnt
 		sellDeals = append(sellDeals, filledSellDeals...)
 		*sellExecuted = sellExecuted.Add(filledSellAmount)
 
-		book.Sub(index, filledSellAmount, types.SellOrder)
+		book.Sub(index, filledSellAmount, filledSellHidden, types.SellOrder)
 		book.RemoveIfEmpty(index)
 
 		if blockRemainDeals <= 0 {
@@ -132,114 +128,121 @@ func fillDepthBook(ctx sdk.Context,
 	return deals, blockRemainDeals
 }
 
-// Fill orders in orderIDsMap at specific key
+// isRestingOrder returns true if the order has rested in depth book before current block. The resting orders
+// provided liquidity to the auction as makers, the orders placed or triggered in current block took it as takers
+func isRestingOrder(ctx sdk.Context, order *types.Order) bool {
+	if order.Trigger != nil && order.Trigger.TriggeredHeight == ctx.BlockHeight() {
+		return false
+	}
+	return types.GetBlockHeightFromOrderID(order.OrderID) < ctx.BlockHeight()
+}
+
+// Fill orders in orderIDsMap at specific key. Under FIFO the orders are filled in the sequence they are queued, and
+// under a pro-rata rule the fill amount is allocated among the first remainDeals orders in the queue.
+// Besides the deals and the filled amount, it returns the part of the filled amount drawn from the hidden reserves
+// of iceberg orders, whose visible slices are refreshed by the fills
 func fillOrderByKey(ctx sdk.Context, keeper orderkeeper.Keeper, key string,
 	needFillAmount sdk.Dec, fillPrice sdk.Dec, feeParams *types.Params,
-	remainDeals int64) ([]types.Deal, sdk.Dec, int64) {
+	remainDeals int64) ([]types.Deal, sdk.Dec, sdk.Dec, int64) {
 
-	deals := []types.Deal{}
-	filledAmount := sdk.ZeroDec()
 	orderIDsMap := keeper.GetDiskCache().GetOrderIDsMapCopy()
-	filledDealsCnt := int64(0)
-
 	orderIDs, ok := orderIDsMap.Data[key]
 	// if key not found in orderIDsMap, return
+	if !ok || remainDeals <= 0 {
+		return []types.Deal{}, sdk.ZeroDec(), sdk.ZeroDec(), 0
+	}
+
+	allocator, ok := getAllocator(feeParams.AllocationRule)
 	if !ok {
-		return deals, filledAmount, filledDealsCnt
+		return fillOrderByKeyInSequence(ctx, keeper, key, orderIDs, needFillAmount, fillPrice, feeParams, remainDeals)
 	}
+	return fillOrderByKeyByAllocator(ctx, keeper, key, orderIDs, allocator, needFillAmount, fillPrice, feeParams,
+		remainDeals)
+}
 
-	index := 0
-	for filledDealsCnt < remainDeals && filledAmount.LT(needFillAmount) {
-		order := keeper.GetOrder(ctx, orderIDs[index])
-		if filledAmount.Add(order.RemainQuantity).LTE(needFillAmount) {
-			filledAmount = filledAmount.Add(order.RemainQuantity)
-			deal := fillOrder(order, ctx, keeper, fillPrice, order.RemainQuantity, feeParams)
-			deals = append(deals, *deal)
+// fillOrderByKeyInSequence fills the orders one by one in the sequence they are queued. Only the orders fully
+// filled take up the deal budget, the last order partially filled stays at the head of the queue
+func fillOrderByKeyInSequence(ctx sdk.Context, keeper orderkeeper.Keeper, key string, orderIDs []string,
+	needFillAmount sdk.Dec, fillPrice sdk.Dec, feeParams *types.Params,
+	remainDeals int64) ([]types.Deal, sdk.Dec, sdk.Dec, int64) {
 
-			filledDealsCnt++
-			index++
-		} else {
-			deal := fillOrder(order, ctx, keeper, fillPrice, needFillAmount.Sub(filledAmount), feeParams)
-			deals = append(deals, *deal)
-			filledAmount = needFillAmount
+	deals := []types.Deal{}
+	filledAmount := sdk.ZeroDec()
+	filledHidden := sdk.ZeroDec()
+	filledDealsCnt := int64(0)
 
+	index := 0
+	for filledDealsCnt < remainDeals && filledAmount.LT(needFillAmount) && index < len(orderIDs) {
+		order := keeper.GetOrder(ctx, orderIDs[index])
+		fillAmount := sdk.MinDec(order.RemainQuantity, needFillAmount.Sub(filledAmount))
+		hidden := order.HiddenQuantity()
+		deal := keeper.FillOrder(ctx, order, fillPrice, fillAmount, feeParams, isRestingOrder(ctx, order))
+		deals = append(deals, *deal)
+		filledAmount = filledAmount.Add(fillAmount)
+		filledHidden = filledHidden.Add(hidden.Sub(order.HiddenQuantity()))
+		if order.RemainQuantity.IsPositive() {
 			break
 		}
-
+		filledDealsCnt++
+		index++
 	}
 
-	unFilledOrderIDs := orderIDs[index:] // update orderIDs, remove filled orderIDs
-	// Note: orderIDs cannot be nil, we will use empty slice to remove Data on keeper
+	// update orderIDs, remove filled orderIDs
+	unFilledOrderIDs := orderIDs[index:]
 	if len(unFilledOrderIDs) == 0 {
 		unFilledOrderIDs = []string{}
 	}
 	keeper.SetOrderIDs(key, unFilledOrderIDs) // update orderIDsMap on filled
 
-	return deals, filledAmount, filledDealsCnt
+	return deals, filledAmount, filledHidden, filledDealsCnt
 }
 
-func balanceAccount(order *types.Order, ctx sdk.Context, keeper orderkeeper.Keeper,
-	fillPrice, fillQuantity sdk.Dec) {
-
-	symbols := strings.Split(order.Product, "_")
-	// transfer tokens
-	var outputCoins, inputCoins sdk.DecCoins
-	if order.Side == types.BuyOrder {
-		outputCoins = sdk.DecCoins{{Denom: symbols[1], Amount: fillPrice.Mul(fillQuantity)}}
-		inputCoins = sdk.DecCoins{{Denom: symbols[0], Amount: fillQuantity}}
-	} else {
-		outputCoins = sdk.DecCoins{{Denom: symbols[0], Amount: fillQuantity}}
-		inputCoins = sdk.DecCoins{{Denom: symbols[1], Amount: fillPrice.Mul(fillQuantity)}}
-	}
-	keeper.BalanceAccount(ctx, order.Sender, outputCoins, inputCoins)
-}
+// fillOrderByKeyByAllocator fills the orders by the amounts the allocator allocates. Every order filled takes up
+// the deal budget, so only the first remainDeals orders in the queue take part in the allocation
+func fillOrderByKeyByAllocator(ctx sdk.Context, keeper orderkeeper.Keeper, key string, orderIDs []string,
+	allocator allocator, needFillAmount sdk.Dec, fillPrice sdk.Dec, feeParams *types.Params,
+	remainDeals int64) ([]types.Deal, sdk.Dec, sdk.Dec, int64) {
 
-func chargeFee(order *types.Order, ctx sdk.Context, keeper orderkeeper.Keeper, fillQuantity sdk.Dec,
-	feeParams *types.Params) sdk.DecCoins {
-	// charge fee
-	fee := orderkeeper.GetZeroFee()
-	if order.Status == types.OrderStatusFilled {
-		lockedFee := orderkeeper.GetOrderNewFee(order)
-		fee = orderkeeper.GetOrderCostFee(order, ctx)
-		receiveFee := lockedFee.Sub(fee)
-
-		keeper.UnlockCoins(ctx, order.Sender, lockedFee, token.LockCoinsTypeFee)
-		keeper.AddFeeDetail(ctx, order.Sender, receiveFee, types.FeeTypeOrderReceive)
-		order.RecordOrderReceiveFee(receiveFee)
-
-		err := keeper.AddCollectedFees(ctx, fee, order.Sender, types.FeeTypeOrderNew, false)
-		if err != nil {
-			ctx.Logger().Error(fmt.Sprintf("Send fee failed:%s\n", err.Error()))
-		}
+	deals := []types.Deal{}
+	filledAmount := sdk.ZeroDec()
+	filledHidden := sdk.ZeroDec()
+	filledDealsCnt := int64(0)
+
+	queueLen := len(orderIDs)
+	if int64(queueLen) > remainDeals {
+		queueLen = int(remainDeals)
 	}
-	dealFee := orderkeeper.GetDealFee(order, fillQuantity, ctx, keeper, feeParams)
-	err := keeper.SendFeesToProductOwner(ctx, dealFee, order.Sender, types.FeeTypeOrderDeal, order.Product)
-	if err == nil {
-		order.RecordOrderDealFee(fee)
+	if queueLen == 0 {
+		return deals, filledAmount, filledHidden, filledDealsCnt
 	}
-
-	return dealFee
-}
-
-// Fill an order. Update order, charge fee and transfer tokens. Return a deal.
-// If an order is fully filled but still lock some coins, unlock it.
-func fillOrder(order *types.Order, ctx sdk.Context, keeper orderkeeper.Keeper,
-	fillPrice, fillQuantity sdk.Dec, feeParams *types.Params) *types.Deal {
-
-	// update order
-	order.Fill(fillPrice, fillQuantity)
-
-	balanceAccount(order, ctx, keeper, fillPrice, fillQuantity)
-	// if fully filled and still need unlock coins
-	if order.Status == types.OrderStatusFilled && order.RemainLocked.IsPositive() {
-		needUnlockCoins := order.NeedUnlockCoins()
-		keeper.UnlockCoins(ctx, order.Sender, needUnlockCoins, token.LockCoinsTypeQuantity)
-		order.Unlock()
+	orders := make([]*types.Order, 0, queueLen)
+	for _, orderID := range orderIDs[:queueLen] {
+		orders = append(orders, keeper.GetOrder(ctx, orderID))
 	}
 
-	dealFee := chargeFee(order, ctx, keeper, fillQuantity, feeParams)
+	quantityPrecision := int64(sdk.Precision)
+	if tokenPair := keeper.GetDexKeeper().GetTokenPair(ctx, orders[0].Product); tokenPair != nil {
+		quantityPrecision = tokenPair.MaxQuantityDigit
+	}
+	fillAmounts := allocator.allocate(orders, needFillAmount, quantityPrecision, ctx.BlockHeight())
 
-	keeper.UpdateOrder(order, ctx) // update order info on filled
+	unFilledOrderIDs := make([]string, 0, len(orderIDs))
+	for i, order := range orders {
+		if fillAmounts[i].IsPositive() {
+			hidden := order.HiddenQuantity()
+			deal := keeper.FillOrder(ctx, order, fillPrice, fillAmounts[i], feeParams, isRestingOrder(ctx, order))
+			deals = append(deals, *deal)
+			filledAmount = filledAmount.Add(fillAmounts[i])
+			filledHidden = filledHidden.Add(hidden.Sub(order.HiddenQuantity()))
+			filledDealsCnt++
+		}
+		if order.RemainQuantity.IsPositive() {
+			unFilledOrderIDs = append(unFilledOrderIDs, order.OrderID)
+		}
+	}
+	// update orderIDs, remove filled orderIDs
+	unFilledOrderIDs = append(unFilledOrderIDs, orderIDs[queueLen:]...)
+	keeper.SetOrderIDs(key, unFilledOrderIDs) // update orderIDsMap on filled
 
-	return &types.Deal{OrderID: order.OrderID, Side: order.Side, Quantity: fillQuantity, Fee: dealFee.String()}
+	return deals, filledAmount, filledHidden, filledDealsCnt
 }
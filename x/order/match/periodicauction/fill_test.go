@@ -418,6 +418,45 @@ func TestFillBuyOrders(t *testing.T) {
 	require.EqualValues(t, int64(998), blockRemainDeals)
 }
 
+func TestFillIcebergOrders(t *testing.T) {
+	testInput := orderkeeper.CreateTestInput(t)
+	keeper := testInput.OrderKeeper
+	ctx := testInput.Ctx
+	tokenPair := dex.GetBuiltInTokenPair()
+	err := testInput.DexKeeper.SaveTokenPair(ctx, tokenPair)
+	require.Nil(t, err)
+
+	orders := []*types.Order{
+		mockOrder("", types.TestTokenPair, types.BuyOrder, "10.1", "1.0"),
+		mockOrder("", types.TestTokenPair, types.BuyOrder, "10.1", "4.0"),
+	}
+	display := sdk.MustNewDecFromStr("1.0")
+	orders[1].DisplayQuantity = &display
+	for _, order := range orders {
+		order.Sender = testInput.TestAddrs[0]
+		err := keeper.PlaceOrder(ctx, order)
+		require.NoError(t, err)
+	}
+	depthBook := keeper.GetDepthBookCopy(types.TestTokenPair)
+	require.EqualValues(t, sdk.MustNewDecFromStr("5.0"), depthBook.Items[0].BuyQuantity)
+	require.EqualValues(t, sdk.MustNewDecFromStr("3.0"), depthBook.Items[0].HiddenQuantity(types.BuyOrder))
+
+	// the iceberg order is filled beyond its visible slice, which is refreshed from the hidden reserve
+	buyExecution := sdk.ZeroDec()
+	feeParams := types.DefaultParams()
+	buyDeals, _ := fillBuyOrders(ctx, keeper, types.TestTokenPair, sdk.NewDec(10), sdk.NewDec(3), &buyExecution,
+		1000, &feeParams)
+	require.EqualValues(t, 2, len(buyDeals))
+	require.EqualValues(t, sdk.NewDec(3), buyExecution)
+
+	order := keeper.GetOrder(ctx, orders[1].OrderID)
+	require.EqualValues(t, sdk.MustNewDecFromStr("2.0"), order.RemainQuantity)
+	require.EqualValues(t, sdk.MustNewDecFromStr("1.0"), order.VisibleQuantity())
+	depthBook = keeper.GetDepthBookCopy(types.TestTokenPair)
+	require.EqualValues(t, sdk.MustNewDecFromStr("2.0"), depthBook.Items[0].BuyQuantity)
+	require.EqualValues(t, sdk.MustNewDecFromStr("1.0"), depthBook.Items[0].HiddenQuantity(types.BuyOrder))
+}
+
 func TestFillSellOrders(t *testing.T) {
 	testInput := orderkeeper.CreateTestInput(t)
 	keeper := testInput.OrderKeeper
@@ -528,7 +567,7 @@ func TestFillOrderByKey(t *testing.T) {
 	remainDeals := int64(1000)
 	key := types.FormatOrderIDsKey(types.TestTokenPair, orders[0].Price, types.BuyOrder)
 
-	deals, filledAmount, filledDealsCnt := fillOrderByKey(ctx, keeper, key, needFillAmount, fillPrice, &feeParams,
+	deals, filledAmount, _, filledDealsCnt := fillOrderByKey(ctx, keeper, key, needFillAmount, fillPrice, &feeParams,
 		remainDeals)
 
 	require.EqualValues(t, 2, len(deals))
@@ -536,7 +575,7 @@ func TestFillOrderByKey(t *testing.T) {
 	require.EqualValues(t, 2, filledDealsCnt)
 }
 
-func TestFillOrderByKeyByNotExistKey(t *testing.T) {
+func TestFillOrderByKeyFIFODealBudget(t *testing.T) {
 	testInput := orderkeeper.CreateTestInput(t)
 	keeper := testInput.OrderKeeper
 	ctx := testInput.Ctx
@@ -544,72 +583,48 @@ func TestFillOrderByKeyByNotExistKey(t *testing.T) {
 	err := testInput.DexKeeper.SaveTokenPair(ctx, tokenPair)
 	require.Nil(t, err)
 
-	// mock orders, DepthBook, and orderIDsMap
 	orders := []*types.Order{
 		mockOrder("", types.TestTokenPair, types.BuyOrder, "10.1", "1.0"),
 		mockOrder("", types.TestTokenPair, types.BuyOrder, "10.1", "2.0"),
-		mockOrder("", types.TestTokenPair, types.SellOrder, "9.9", "3.0"),
-		mockOrder("", types.TestTokenPair, types.SellOrder, "10.2", "1.0"),
+		mockOrder("", types.TestTokenPair, types.BuyOrder, "10.1", "3.0"),
 	}
-	orders[0].Sender = testInput.TestAddrs[0]
-	orders[1].Sender = testInput.TestAddrs[0]
-	orders[2].Sender = testInput.TestAddrs[1]
-	orders[3].Sender = testInput.TestAddrs[1]
-
-	for i := 0; i < 4; i++ {
-		err := keeper.PlaceOrder(ctx, orders[i])
-		require.EqualValues(t, nil, err)
+	for _, order := range orders {
+		order.Sender = testInput.TestAddrs[0]
+		err := keeper.PlaceOrder(ctx, order)
+		require.Nil(t, err)
 	}
 
 	fillPrice := sdk.NewDec(10.0)
-	needFillAmount := sdk.NewDec(3.0)
 	feeParams := types.DefaultParams()
-	remainDeals := int64(1000)
-	key := types.FormatOrderIDsKey(types.TestTokenPair+"_test", orders[0].Price, types.BuyOrder)
-
-	deals, filledAmount, filledDealsCnt := fillOrderByKey(ctx, keeper, key, needFillAmount, fillPrice, &feeParams,
-		remainDeals)
-	require.EqualValues(t, 0, len(deals))
-	require.EqualValues(t, filledAmount, sdk.ZeroDec())
-	require.EqualValues(t, filledDealsCnt, int64(0))
-}
-
-func TestFillOrder(t *testing.T) {
-	testInput := orderkeeper.CreateTestInput(t)
-	keeper := testInput.OrderKeeper
-	ctx := testInput.Ctx
-	tokenPair := dex.GetBuiltInTokenPair()
-	err := testInput.DexKeeper.SaveTokenPair(ctx, tokenPair)
-	require.Nil(t, err)
-
-	// mock orders, DepthBook, and orderIDsMap
-	orders := []*types.Order{
-		mockOrder("", types.TestTokenPair, types.BuyOrder, "10.1", "1.0"),
-		mockOrder("", types.TestTokenPair, types.BuyOrder, "10.1", "2.0"),
-		mockOrder("", types.TestTokenPair, types.SellOrder, "9.9", "3.0"),
-		mockOrder("", types.TestTokenPair, types.SellOrder, "10.2", "1.0"),
-	}
-	orders[0].Sender = testInput.TestAddrs[0]
-	orders[1].Sender = testInput.TestAddrs[0]
-	orders[2].Sender = testInput.TestAddrs[1]
-	orders[3].Sender = testInput.TestAddrs[1]
-
-	for i := 0; i < 4; i++ {
-		err := keeper.PlaceOrder(ctx, orders[i])
-		require.EqualValues(t, nil, err)
-	}
+	key := types.FormatOrderIDsKey(types.TestTokenPair, orders[0].Price, types.BuyOrder)
 
-	fillPrice := sdk.NewDec(10.0)
-	fillQuantity := sdk.NewDec(1.0)
-	feeParams := types.DefaultParams()
+	// the order partially filled doesn't take up the deal budget
+	deals, filledAmount, _, filledDealsCnt := fillOrderByKey(ctx, keeper, key, sdk.MustNewDecFromStr("2.5"),
+		fillPrice, &feeParams, 2)
+	require.EqualValues(t, 2, len(deals))
+	require.EqualValues(t, 1, filledDealsCnt)
+	require.EqualValues(t, sdk.MustNewDecFromStr("2.5"), filledAmount)
+	require.EqualValues(t, []string{orders[1].OrderID, orders[2].OrderID}, keeper.GetProductPriceOrderIDs(key))
+	require.EqualValues(t, sdk.MustNewDecFromStr("0.5"), keeper.GetOrder(ctx, orders[1].OrderID).RemainQuantity)
+
+	// the orders fully filled take up the deal budget, and the filling stops once it's used up
+	deals, filledAmount, _, filledDealsCnt = fillOrderByKey(ctx, keeper, key, sdk.MustNewDecFromStr("3.5"),
+		fillPrice, &feeParams, 1)
+	require.EqualValues(t, 1, len(deals))
+	require.EqualValues(t, 1, filledDealsCnt)
+	require.EqualValues(t, sdk.MustNewDecFromStr("0.5"), filledAmount)
+	require.EqualValues(t, []string{orders[2].OrderID}, keeper.GetProductPriceOrderIDs(key))
 
-	for _, order := range orders {
-		retDeals := fillOrder(order, ctx, keeper, fillPrice, fillQuantity, &feeParams)
-		require.NotEmpty(t, retDeals)
-	}
+	// a budget of one deal still reaches the order partially filled
+	deals, filledAmount, _, filledDealsCnt = fillOrderByKey(ctx, keeper, key, sdk.MustNewDecFromStr("1.0"),
+		fillPrice, &feeParams, 1)
+	require.EqualValues(t, 1, len(deals))
+	require.EqualValues(t, 0, filledDealsCnt)
+	require.EqualValues(t, sdk.MustNewDecFromStr("1.0"), filledAmount)
+	require.EqualValues(t, []string{orders[2].OrderID}, keeper.GetProductPriceOrderIDs(key))
 }
 
-func TestTransferTokens(t *testing.T) {
+func TestFillOrderByKeyByNotExistKey(t *testing.T) {
 	testInput := orderkeeper.CreateTestInput(t)
 	keeper := testInput.OrderKeeper
 	ctx := testInput.Ctx
@@ -635,39 +650,14 @@ func TestTransferTokens(t *testing.T) {
 	}
 
 	fillPrice := sdk.NewDec(10.0)
-	fillQuantity := sdk.NewDec(1.0)
-	for _, order := range orders {
-		balanceAccount(order, ctx, keeper, fillPrice, fillQuantity)
-	}
-}
-
-func TestChargeFee(t *testing.T) {
-	testInput := orderkeeper.CreateTestInput(t)
-	keeper := testInput.OrderKeeper
-	ctx := testInput.Ctx
-	tokenPair := dex.GetBuiltInTokenPair()
-	err := testInput.DexKeeper.SaveTokenPair(ctx, tokenPair)
-	require.Nil(t, err)
-
-	keeper.ResetCache(ctx)
-	orders := []*types.Order{
-		mockOrder("", types.TestTokenPair, types.BuyOrder, "9.9", "1.0"),
-		mockOrder("", types.TestTokenPair, types.BuyOrder, "10.0", "1.0"),
-		mockOrder("", types.TestTokenPair, types.SellOrder, "10.0", "1.1"),
-		mockOrder("", types.TestTokenPair, types.SellOrder, "10.1", "1.1"),
-	}
-	orders[0].Sender = testInput.TestAddrs[0]
-	orders[1].Sender = testInput.TestAddrs[0]
-	orders[1].Status = types.OrderStatusFilled
-	orders[2].Sender = testInput.TestAddrs[1]
-	orders[2].Status = types.OrderStatusFilled
-	orders[3].Sender = testInput.TestAddrs[1]
-
-	fillQuantity := sdk.NewDec(1.0)
+	needFillAmount := sdk.NewDec(3.0)
 	feeParams := types.DefaultParams()
+	remainDeals := int64(1000)
+	key := types.FormatOrderIDsKey(types.TestTokenPair+"_test", orders[0].Price, types.BuyOrder)
 
-	for _, order := range orders {
-		retFee := chargeFee(order, ctx, keeper, fillQuantity, &feeParams)
-		require.NotEmpty(t, retFee)
-	}
+	deals, filledAmount, _, filledDealsCnt := fillOrderByKey(ctx, keeper, key, needFillAmount, fillPrice, &feeParams,
+		remainDeals)
+	require.EqualValues(t, 0, len(deals))
+	require.EqualValues(t, filledAmount, sdk.ZeroDec())
+	require.EqualValues(t, filledDealsCnt, int64(0))
 }
@@ -3,6 +3,7 @@ package periodicauction
 import (
 	sdk "github.com/cosmos/cosmos-sdk/types"
 
+	"github.com/okex/okchain/x/dex"
 	"github.com/okex/okchain/x/order/keeper"
 )
 
@@ -10,9 +11,9 @@ import (
 type PaEngine struct {
 }
 
-// nolint
+// Run triggers the stop-loss and take-profit orders whose trigger price has been crossed by the last price,
+// then matches the orders of periodic auction products
 func (e *PaEngine) Run(ctx sdk.Context, keeper keeper.Keeper) {
-	cleanupExpiredOrders(ctx, keeper)
-	cleanupOrdersWhoseTokenPairHaveBeenDelisted(ctx, keeper)
+	keeper.TriggerOrders(ctx, dex.MatchModePeriodicAuction)
 	matchOrders(ctx, keeper)
 }
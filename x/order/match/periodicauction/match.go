@@ -2,296 +2,50 @@ package periodicauction
 
 import (
 	"fmt"
+	"strconv"
 
 	"github.com/tendermint/tendermint/libs/log"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
 
+	"github.com/okex/okchain/x/dex"
 	"github.com/okex/okchain/x/order/keeper"
 	"github.com/okex/okchain/x/order/types"
 )
 
-func preMatchProcessing(book *types.DepthBook) (buyAmountSum, sellAmountSum []sdk.Dec) {
-	bookLength := len(book.Items)
-	if bookLength == 0 {
-		return
-	}
-
-	buyAmountSum = make([]sdk.Dec, bookLength)
-	sellAmountSum = make([]sdk.Dec, bookLength)
-
-	buyAmountSum[0] = book.Items[0].BuyQuantity
-	for i := 1; i < bookLength; i++ {
-		buyAmountSum[i] = buyAmountSum[i-1].Add(book.Items[i].BuyQuantity)
-	}
-
-	sellAmountSum[bookLength-1] = book.Items[bookLength-1].SellQuantity
-	for i := bookLength - 2; i >= 0; i-- {
-		sellAmountSum[i] = sellAmountSum[i+1].Add(book.Items[i].SellQuantity)
-	}
-
-	return
-}
-
-func execRule0(buyAmountSum, sellAmountSum []sdk.Dec) (maxExecution sdk.Dec, execution []sdk.Dec) {
-	maxExecution = sdk.ZeroDec()
-	bookLength := len(buyAmountSum)
-	execution = make([]sdk.Dec, bookLength)
-	for i := 0; i < bookLength; i++ {
-		execution[i] = sdk.MinDec(buyAmountSum[i], sellAmountSum[i])
-		maxExecution = sdk.MaxDec(execution[i], maxExecution)
-	}
-
-	return
-}
-
-func execRule1(maxExecution sdk.Dec, execution []sdk.Dec) (indexesRule1 []int) {
-	bookLength := len(execution)
-	for i := 0; i < bookLength; i++ {
-		if execution[i].Equal(maxExecution) {
-			indexesRule1 = append(indexesRule1, i)
-		}
-	}
-
-	return
-}
-
-func execRule2(buyAmountSum, sellAmountSum []sdk.Dec, indexesRule1 []int) (indexesRule2 []int, imbalance []sdk.Dec) {
-	indexLen1 := len(indexesRule1)
-	imbalance = make([]sdk.Dec, indexLen1)
-	for i := 0; i < indexLen1; i++ {
-		imbalance[i] = buyAmountSum[indexesRule1[i]].Sub(sellAmountSum[indexesRule1[i]])
-	}
-	minAbsImbalance := imbalance[0].Abs()
-	for i := 1; i < indexLen1; i++ {
-		minAbsImbalance = sdk.MinDec(minAbsImbalance, imbalance[i].Abs())
-	}
-	for i := 0; i < indexLen1; i++ {
-		if imbalance[i].Abs().Equal(minAbsImbalance) {
-			indexesRule2 = append(indexesRule2, indexesRule1[i])
-		}
-	}
-
-	return
-}
-
-func execRule3(book *types.DepthBook, offset int, refPrice sdk.Dec, pricePrecision int64,
-	indexesRule2 []int, imbalance []sdk.Dec) (bestPrice sdk.Dec) {
-	indexLen2 := len(indexesRule2)
-	if imbalance[indexesRule2[0]-offset].GT(sdk.ZeroDec()) {
-		// rule3a: all imbalances are positive, buy side pressure
-		newRefPrice := refPrice.Mul(sdk.MustNewDecFromStr("1.05"))
-		newRefPrice = newRefPrice.RoundDecimal(pricePrecision)
-		bestPrice = bestPriceFromRefPrice(book.Items[indexesRule2[0]].Price,
-			book.Items[indexesRule2[indexLen2-1]].Price, newRefPrice)
-	} else if imbalance[indexesRule2[indexLen2-1]-offset].LT(sdk.ZeroDec()) {
-		// rule3b: all imbalances are negative, sell side pressure
-		newRefPrice := refPrice.Mul(sdk.MustNewDecFromStr("0.95"))
-		newRefPrice = newRefPrice.RoundDecimal(pricePrecision)
-		bestPrice = bestPriceFromRefPrice(book.Items[indexesRule2[0]].Price,
-			book.Items[indexesRule2[indexLen2-1]].Price, newRefPrice)
-	} else {
-		// rule3c: some imbalance > 0, and some imbalance < 0, no buyer pressure or seller pressure
-		newRefPrice := refPrice.RoundDecimal(pricePrecision)
-		bestPrice = bestPriceFromRefPrice(book.Items[indexesRule2[0]].Price,
-			book.Items[indexesRule2[indexLen2-1]].Price, newRefPrice)
-	}
-
-	return
-}
-
-// Calculate periodic auction match price, return the best price and execution amount
-// The best price is found according following rules:
-// rule0: No match, bestPrice = 0, maxExecution=0
-// rule1: Maximum execution volume.
-//        If there are more than one price with the same max execution, following rule2
-// rule2: Minimum imbalance. We should select the price with minimum absolute value of imbalance.
-//        If more than one price satisfy rule2, following rule3
-// rule3: Market Pressure. There are 3 cases:
-// rule3a: All imbalances are positive. It indicates buy side pressure. Set reference price with
-//         last execute price plus a upper limit percentage(e.g. 5%). Then choose the price
-//         which is closest to reference price.
-// rule3b: All imbalances are negative. It indicates sell side pressure. Set reference price with
-//         last execute price minus a lower limit percentage(e.g. 5%). Then choose the price
-//         which is closest to reference price.
-// rule3c: Otherwise, it indicates no one side pressure. Set reference price with last execute
-//         price. Then choose the price which is closest to reference price.
+// periodicAuctionMatchPrice returns the best price and execution amount of periodic auction, see
+// types.CalcAuctionPrice for the rules
 func periodicAuctionMatchPrice(book *types.DepthBook, pricePrecision int64,
-	refPrice sdk.Dec) (bestPrice sdk.Dec, maxExecution sdk.Dec) {
-
-	buyAmountSum, sellAmountSum := preMatchProcessing(book)
-	if len(buyAmountSum) == 0 {
-		return sdk.ZeroDec(), sdk.ZeroDec()
-	}
-
-	maxExecution, execution := execRule0(buyAmountSum, sellAmountSum)
-	if maxExecution.IsZero() {
-		return refPrice, maxExecution
-	}
-
-	indexesRule1 := execRule1(maxExecution, execution)
-	if len(indexesRule1) == 1 {
-		bestPrice = book.Items[indexesRule1[0]].Price
-		return
-	}
-
-	indexesRule2, imbalance := execRule2(buyAmountSum, sellAmountSum, indexesRule1)
-	if len(indexesRule2) == 1 {
-		bestPrice = book.Items[indexesRule2[0]].Price
-		return
-	}
-
-	bestPrice = execRule3(book, indexesRule1[0], refPrice, pricePrecision, indexesRule2, imbalance)
-
-	return
-}
-
-// get best price from reference price
-// if min < ref < max, choose ref; else choose the closest price to ref price
-func bestPriceFromRefPrice(minPrice, maxPrice, refPrice sdk.Dec) sdk.Dec {
-	if minPrice.LTE(refPrice) {
-		return minPrice
-	}
-	if maxPrice.GTE(refPrice) {
-		return maxPrice
-	}
-	return refPrice
-}
-
-func expireOrdersInExpiredBlock(ctx sdk.Context, k keeper.Keeper, expiredBlockHeight int64) {
-	logger := ctx.Logger().With("module", "order")
-	orderNum := k.GetBlockOrderNum(ctx, expiredBlockHeight)
-	var index int64
-	for ; index < orderNum; index++ {
-		orderID := types.FormatOrderID(expiredBlockHeight, index+1)
-		order := k.GetOrder(ctx, orderID)
-		if order != nil && order.Status == types.OrderStatusOpen && !k.IsProductLocked(order.Product) {
-			k.ExpireOrder(ctx, order, logger)
-			logger.Info(fmt.Sprintf("order (%s) expired", order.OrderID))
-		}
-	}
-}
-
-func markCurBlockToFeatureExpireBlockList(ctx sdk.Context, keeper keeper.Keeper) {
-	curBlockHeight := ctx.BlockHeight()
-	feeParams := keeper.GetParams(ctx)
+	refPrice, pressureRate sdk.Dec) (bestPrice sdk.Dec, maxExecution sdk.Dec) {
 
-	// Add current blockHeight to future Height
-	// which will solve expire orders in current block.
-	futureHeight := curBlockHeight + feeParams.OrderExpireBlocks
-
-	// the feeParams.OrderExpireBlocks param can be change during the blockchain running,
-	// so we use an array to record the expire blocks in the feature block height
-	futureExpireHeightList := keeper.GetExpireBlockHeight(ctx, futureHeight)
-	futureExpireHeightList = append(futureExpireHeightList, curBlockHeight)
-	keeper.SetExpireBlockHeight(ctx, futureHeight, futureExpireHeightList)
-}
-
-func cleanLastBlockClosedOrders(ctx sdk.Context, keeper keeper.Keeper) {
-	// drop expired data
-	lastClosedOrderIDs := keeper.GetLastClosedOrderIDs(ctx)
-	for _, orderID := range lastClosedOrderIDs {
-		keeper.DropOrder(ctx, orderID)
-	}
-
-	keeper.GetDiskCache().DecreaseStoreOrderNum(int64(len(lastClosedOrderIDs)))
-}
-
-// Deal the block from create to current height which is Expired
-func cacheExpiredBlockToCurrentHeight(ctx sdk.Context, keeper keeper.Keeper) {
-	logger := ctx.Logger().With("module", "order")
-	curBlockHeight := ctx.BlockHeight()
-
-	lastExpiredBlockHeight := keeper.GetLastExpiredBlockHeight(ctx)
-	if lastExpiredBlockHeight == 0 {
-		lastExpiredBlockHeight = curBlockHeight - 1
-	}
-
-	// check orders in expired blocks, remove expired orders by order id
-	for height := lastExpiredBlockHeight + 1; height <= curBlockHeight; height++ {
-		var expiredHeight int64
-		expiredBlocks := keeper.GetExpireBlockHeight(ctx, height)
-		for _, expiredHeight = range expiredBlocks {
-			expireOrdersInExpiredBlock(ctx, keeper, expiredHeight)
-			logger.Info(fmt.Sprintf("currentHeight(%d), expire orders at blockHeight(%d)",
-				curBlockHeight, expiredHeight))
-		}
-	}
-
-	if !keeper.AnyProductLocked() {
-		height := lastExpiredBlockHeight
-		if curBlockHeight > 1 {
-			for ; height < curBlockHeight; height++ {
-				var expiredHeight int64
-				for _, expiredHeight = range keeper.GetExpireBlockHeight(ctx, height) {
-					keeper.DropBlockOrderNum(ctx, expiredHeight)
-					logger.Info(fmt.Sprintf("currentHeight(%d), drop Data at blockHeight(%d)",
-						curBlockHeight, expiredHeight))
-				}
-				keeper.DropExpireBlockHeight(ctx, height)
-			}
-		}
-		keeper.SetLastExpiredBlockHeight(ctx, height)
-	}
-}
-
-func cleanupOrdersWhoseTokenPairHaveBeenDelisted(ctx sdk.Context, keeper keeper.Keeper) {
-	products := keeper.GetProductsFromDepthBookMap()
-	for _, product := range products {
-		tokenPair := keeper.GetDexKeeper().GetTokenPair(ctx, product)
-		if tokenPair == nil {
-			cleanupOrdersByProduct(ctx, keeper, product)
-		}
-	}
-}
-
-func cleanupOrdersByProduct(ctx sdk.Context, keeper keeper.Keeper, product string) {
-	depthBook := keeper.GetDepthBookCopy(product)
-	for _, item := range depthBook.Items {
-		buyKey := types.FormatOrderIDsKey(product, item.Price, types.BuyOrder)
-		orderIDList := keeper.GetProductPriceOrderIDs(buyKey)
-		sellKey := types.FormatOrderIDsKey(product, item.Price, types.SellOrder)
-		orderIDList = append(orderIDList, keeper.GetProductPriceOrderIDs(sellKey)...)
-		cleanOrdersByOrderIDList(ctx, keeper, orderIDList)
-	}
-}
-
-func cleanOrdersByOrderIDList(ctx sdk.Context, keeper keeper.Keeper, orderIDList []string) {
-	logger := ctx.Logger()
-	for _, orderID := range orderIDList {
-		order := keeper.GetOrder(ctx, orderID)
-		keeper.CancelOrder(ctx, order, logger)
-	}
-}
-
-func cleanupExpiredOrders(ctx sdk.Context, keeper keeper.Keeper) {
-
-	// Look forward to see what height will this block expired
-	markCurBlockToFeatureExpireBlockList(ctx, keeper)
-
-	// Clean the expired orders which is collected by the last block
-	cleanLastBlockClosedOrders(ctx, keeper)
-
-	// Look backward to see who is expired and cache the expired orders
-	cacheExpiredBlockToCurrentHeight(ctx, keeper)
+	auctionPrice := types.CalcAuctionPrice(book, pricePrecision, refPrice, pressureRate)
+	return auctionPrice.Price, auctionPrice.Quantity
 }
 
 func matchOrders(ctx sdk.Context, keeper keeper.Keeper) {
 	blockHeight := ctx.BlockHeight()
 	orderNum := keeper.GetBlockOrderNum(ctx, blockHeight)
-	// no new orders in this block & no product lock in previous blocks, skip match
-	if orderNum == 0 && !keeper.AnyProductLocked() {
+	resumedProducts := resumeHaltedProducts(ctx, keeper)
+	// no new or triggered orders in this block & no product lock in previous blocks & no product resumed, skip match
+	if orderNum == 0 && len(keeper.GetDiskCache().GetNewDepthbookKeys()) == 0 && !keeper.AnyProductLocked() &&
+		len(resumedProducts) == 0 {
 		return
 	}
 
-	// step0: get active products
+	// step0: get active products, the resumed products are matched even if their depth book is not updated
 	products := keeper.GetDiskCache().GetNewDepthbookKeys()
+	for product := range resumedProducts {
+		if !containsProduct(products, product) {
+			products = append(products, product)
+		}
+	}
 	products = keeper.FilterDelistedProducts(ctx, products)
+	products = keeper.FilterProductsByMatchMode(ctx, products, dex.MatchModePeriodicAuction)
 	keeper.GetDexKeeper().SortProducts(ctx, products) // sort products
 
 	// step1: calc best price and max execution for every active product, save latest price
 	//updatedProductsBaseprice := make(map[string]types.MatchResult)
-	updatedProductsBasePrice := calcMatchPriceAndExecution(ctx, keeper, products)
+	updatedProductsBasePrice := calcMatchPriceAndExecution(ctx, keeper, products, resumedProducts)
 
 	// step1.1: recover locked depth book
 	lockMap := keeper.GetDexKeeper().GetLockedProductsCopy()
@@ -303,9 +57,16 @@ func matchOrders(ctx sdk.Context, keeper keeper.Keeper) {
 	// step2: execute match results, fill orders in match results, transfer tokens and collect fees
 	executeMatch(ctx, keeper, products, updatedProductsBasePrice, lockMap)
 
-	// step3: save match results for querying
+	// step3: save match results for querying, merge them into the self trades prevented in current block
 	if len(updatedProductsBasePrice) > 0 {
-		blockMatchResult := &types.BlockMatchResult{
+		blockMatchResult := keeper.GetBlockMatchResult()
+		if blockMatchResult != nil && blockMatchResult.BlockHeight == blockHeight && blockMatchResult.ResultMap != nil {
+			for product, matchResult := range updatedProductsBasePrice {
+				blockMatchResult.ResultMap[product] = matchResult
+			}
+			return
+		}
+		blockMatchResult = &types.BlockMatchResult{
 			BlockHeight: blockHeight,
 			ResultMap:   updatedProductsBasePrice,
 			TimeStamp:   ctx.BlockHeader().Time.Unix(),
@@ -314,14 +75,54 @@ func matchOrders(ctx sdk.Context, keeper keeper.Keeper) {
 	}
 }
 
-func calcMatchPriceAndExecution(ctx sdk.Context, k keeper.Keeper, products []string) map[string]types.MatchResult {
+// calcMatchPriceAndExecution calculates the clearing price and execution of every product. The clearing prices are
+// discovered concurrently, then the products are processed in sequence. The product whose clearing price breaks the
+// price band around the last price is halted for the circuit breaker blocks instead of being matched, and the first
+// auction of a resumed product is not limited by the price band
+func calcMatchPriceAndExecution(ctx sdk.Context, k keeper.Keeper, products []string,
+	resumedProducts map[string]struct{}) map[string]types.MatchResult {
+
 	resultMap := make(map[string]types.MatchResult)
+	timeInForceOrders := getTimeInForceOrders(ctx, k)
+	params := k.GetParams(ctx)
 
+	discoveries := make([]*priceDiscovery, 0, len(products))
 	for _, product := range products {
+		if k.IsProductHalted(ctx, product) {
+			continue
+		}
 		tokenPair := k.GetDexKeeper().GetTokenPair(ctx, product)
-		book := k.GetDepthBookCopy(product)
-		bestPrice, maxExecution := periodicAuctionMatchPrice(book, tokenPair.MaxPriceDigit,
-			k.GetLastPrice(ctx, product))
+		discoveries = append(discoveries, &priceDiscovery{product: product, book: k.GetDepthBookCopy(product),
+			pricePrecision: tokenPair.MaxPriceDigit, refPrice: k.GetLastPrice(ctx, product)})
+	}
+	discoverMatchPrices(discoveries, params.MarketPressureRate, priceDiscoveryWorkers)
+
+	for _, discovery := range discoveries {
+		product, book := discovery.product, discovery.book
+		bestPrice, maxExecution := discovery.bestPrice, discovery.maxExecution
+
+		// quit the FOK and post-only orders violating their time in force and prevent the self trades one by one,
+		// then recalculate
+		orders := timeInForceOrders[product]
+		for quit := true; quit && maxExecution.IsPositive(); {
+			quit = false
+			if len(orders) > 0 {
+				orders, quit = screenTimeInForceOrders(ctx, k, orders, book, bestPrice, maxExecution)
+			}
+			if !quit {
+				quit = preventSelfTrades(ctx, k, product, book, bestPrice, maxExecution)
+			}
+			if quit {
+				book = k.GetDepthBookCopy(product)
+				bestPrice, maxExecution = periodicAuctionMatchPrice(book, discovery.pricePrecision,
+					k.GetLastPrice(ctx, product), params.MarketPressureRate)
+			}
+		}
+		if _, resumed := resumedProducts[product]; !resumed && maxExecution.IsPositive() &&
+			!types.IsPriceInBand(bestPrice, k.GetLastPrice(ctx, product), params.PriceBand) {
+			haltProduct(ctx, k, product, bestPrice, params.CircuitBreakerBlocks)
+			continue
+		}
 		if maxExecution.IsPositive() {
 			k.SetLastPrice(ctx, product, bestPrice)
 			resultMap[product] = types.MatchResult{BlockHeight: ctx.BlockHeight(), Price: bestPrice,
@@ -332,6 +133,60 @@ func calcMatchPriceAndExecution(ctx sdk.Context, k keeper.Keeper, products []str
 	return resultMap
 }
 
+// haltProduct halts the periodic auction of the product for the circuit breaker blocks
+func haltProduct(ctx sdk.Context, k keeper.Keeper, product string, haltPrice sdk.Dec, haltBlocks int64) {
+	if haltBlocks < 1 {
+		haltBlocks = 1
+	}
+	halt := &types.ProductHalt{
+		Product:      product,
+		HaltHeight:   ctx.BlockHeight(),
+		ResumeHeight: ctx.BlockHeight() + haltBlocks,
+		LastPrice:    k.GetLastPrice(ctx, product),
+		HaltPrice:    haltPrice,
+	}
+	k.SetProductHalt(ctx, halt)
+	ctx.EventManager().EmitEvent(sdk.NewEvent(types.EventTypeProductHalt,
+		sdk.NewAttribute(types.TagKeyProduct, product),
+		sdk.NewAttribute(types.TagKeyHaltHeight, strconv.FormatInt(halt.HaltHeight, 10)),
+		sdk.NewAttribute(types.TagKeyResumeHeight, strconv.FormatInt(halt.ResumeHeight, 10)),
+		sdk.NewAttribute(types.TagKeyLastPrice, halt.LastPrice.String()),
+		sdk.NewAttribute(types.TagKeyHaltPrice, haltPrice.String()),
+	))
+	ctx.Logger().With("module", "order").Info(fmt.Sprintf("BlockHeight<%d> halt product(%s) until "+
+		"BlockHeight<%d>: lastPrice: %v, haltPrice: %v", halt.HaltHeight, product, halt.ResumeHeight,
+		halt.LastPrice, haltPrice))
+}
+
+// resumeHaltedProducts drops the halts which end at current block, and returns the resumed products
+func resumeHaltedProducts(ctx sdk.Context, k keeper.Keeper) map[string]struct{} {
+	resumedProducts := make(map[string]struct{})
+	for _, halt := range k.GetProductHalts(ctx) {
+		if halt.ResumeHeight > ctx.BlockHeight() {
+			continue
+		}
+		k.DropProductHalt(ctx, halt.Product)
+		resumedProducts[halt.Product] = struct{}{}
+		ctx.EventManager().EmitEvent(sdk.NewEvent(types.EventTypeProductResume,
+			sdk.NewAttribute(types.TagKeyProduct, halt.Product),
+			sdk.NewAttribute(types.TagKeyHaltHeight, strconv.FormatInt(halt.HaltHeight, 10)),
+			sdk.NewAttribute(types.TagKeyResumeHeight, strconv.FormatInt(halt.ResumeHeight, 10)),
+		))
+		ctx.Logger().With("module", "order").Info(fmt.Sprintf("BlockHeight<%d> resume product(%s)",
+			ctx.BlockHeight(), halt.Product))
+	}
+	return resumedProducts
+}
+
+func containsProduct(products []string, product string) bool {
+	for _, p := range products {
+		if p == product {
+			return true
+		}
+	}
+	return false
+}
+
 func lockProduct(ctx sdk.Context, k keeper.Keeper, logger log.Logger, product string, matchResult types.MatchResult,
 	buyExecutedCnt, sellExecutedCnt sdk.Dec) {
 	blockHeight := ctx.BlockHeight()
@@ -363,6 +218,7 @@ func executeMatchedUpdatedProduct(ctx sdk.Context, k keeper.Keeper,
 
 	deals, blockRemainDeals := fillDepthBook(ctx, k, product,
 		matchResult.Price, matchResult.Quantity, &buyExecutedCnt, &sellExecutedCnt, blockRemainDeals, feeParams)
+	k.AddBlockDealNum(ctx, int64(len(deals)))
 	matchResult.Deals = deals
 	updatedProductsBasePrice[product] = matchResult
 
@@ -395,6 +251,7 @@ func executeLockedProduct(ctx sdk.Context, k keeper.Keeper,
 	sellExecuted := lock.SellExecuted
 	deals, blockRemainDeals := fillDepthBook(ctx, k, product,
 		lock.Price, lock.Quantity, &buyExecuted, &sellExecuted, blockRemainDeals, feeParams)
+	k.AddBlockDealNum(ctx, int64(len(deals)))
 
 	// if deals not empty, add match result
 	if len(deals) > 0 {
@@ -430,15 +287,21 @@ func executeMatch(ctx sdk.Context, k keeper.Keeper, products []string,
 	updatedProductsBasePrice map[string]types.MatchResult, lockMap *types.ProductLockMap) {
 	logger := ctx.Logger().With("module", "order")
 	feeParams := k.GetParams(ctx)
-	blockRemainDeals := feeParams.MaxDealsPerBlock
+	// the deals not scheduled or not spent by the products before are passed on to the next product
+	budgets, blockRemainDeals := scheduleDealBudget(ctx, k, products, updatedProductsBasePrice, lockMap, feeParams)
 
 	for _, product := range products {
+		budget := budgets[product]
+		delete(budgets, product)
 		if _, ok := updatedProductsBasePrice[product]; ok {
 			blockRemainDeals = executeMatchedUpdatedProduct(ctx, k, updatedProductsBasePrice, feeParams,
-				blockRemainDeals, product, logger)
+				blockRemainDeals+budget, product, logger)
 		} else if _, ok := lockMap.Data[product]; ok {
 			blockRemainDeals = executeLockedProduct(ctx, k, updatedProductsBasePrice, lockMap, feeParams,
-				blockRemainDeals, product, logger)
+				blockRemainDeals+budget, product, logger)
+		}
+		if blockRemainDeals < 0 {
+			blockRemainDeals = 0
 		}
 	}
 }
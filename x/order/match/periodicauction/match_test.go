@@ -46,7 +46,8 @@ func runPeriodicAuctionMatchPriceTest(t *testing.T, testData *MatchTestData, che
 	require.Nil(t, err)
 	needres, err := sdk.NewDecFromStr(testData.output)
 	require.Nil(t, err)
-	bestPrice, _ := periodicAuctionMatchPrice(&book, testData.pricePrecision, refPrice)
+	bestPrice, _ := periodicAuctionMatchPrice(&book, testData.pricePrecision, refPrice,
+		sdk.MustNewDecFromStr(types.DefaultMarketPressureRate))
 	if check {
 		if !needres.Equal(bestPrice) {
 			t.Fatalf("need:%s calc:%s\n", needres.String(), bestPrice.String())
@@ -207,251 +208,30 @@ func TestPeriodicAuctionMatchPriceRule3C(t *testing.T) {
 	runPeriodicAuctionMatchPriceTest(t, &data, true)
 }
 
-func TestPeriodicAuctionMatchPriceByEmptyDepthBook(t *testing.T) {
-	depthBook := &types.DepthBook{}
-	bestPrice, maxExecution := periodicAuctionMatchPrice(depthBook, 10, sdk.MustNewDecFromStr("10.0"))
+func TestPeriodicAuctionMatchPriceByPressureRate(t *testing.T) {
+	book := &types.DepthBook{Items: []types.DepthBookItem{
+		{Price: sdk.MustNewDecFromStr("102"), BuyQuantity: sdk.NewDec(60), SellQuantity: sdk.ZeroDec()},
+		{Price: sdk.MustNewDecFromStr("100"), BuyQuantity: sdk.ZeroDec(), SellQuantity: sdk.NewDec(20)},
+		{Price: sdk.MustNewDecFromStr("95"), BuyQuantity: sdk.ZeroDec(), SellQuantity: sdk.NewDec(30)},
+	}}
 
-	require.EqualValues(t, sdk.ZeroDec(), bestPrice)
-	require.EqualValues(t, sdk.ZeroDec(), maxExecution)
-}
-
-func TestPreMatchProcessing(t *testing.T) {
-	testInput := orderkeeper.CreateTestInput(t)
-
-	// mock orders, DepthBook, and orderIDsMap
-	orders := []*types.Order{
-		mockOrder("", types.TestTokenPair, types.BuyOrder, "10.1", "1.0"),
-		mockOrder("", types.TestTokenPair, types.BuyOrder, "10.1", "2.0"),
-		mockOrder("", types.TestTokenPair, types.SellOrder, "9.9", "3.0"),
-		mockOrder("", types.TestTokenPair, types.SellOrder, "10.2", "1.0"),
-	}
-	orders[0].Sender = testInput.TestAddrs[0]
-	orders[1].Sender = testInput.TestAddrs[0]
-	orders[2].Sender = testInput.TestAddrs[1]
-	orders[3].Sender = testInput.TestAddrs[1]
-
-	depthBook := &types.DepthBook{}
-	for i := 0; i < 4; i++ {
-		depthBook.InsertOrder(orders[i])
+	// rule3a: the reference price 100 is raised by the pressure rate, and the price closest to it is chosen
+	expectedPrices := map[string]string{"0": "100", "0.01": "101", "0.05": "102"}
+	for rate, expectedPrice := range expectedPrices {
+		bestPrice, maxExecution := periodicAuctionMatchPrice(book, 1, sdk.MustNewDecFromStr("100"),
+			sdk.MustNewDecFromStr(rate))
+		require.EqualValues(t, sdk.MustNewDecFromStr(expectedPrice), bestPrice, rate)
+		require.EqualValues(t, sdk.NewDec(50), maxExecution, rate)
 	}
-
-	buyAmountSum, sellAmountSum := preMatchProcessing(depthBook)
-	require.Equal(t, sdk.NewDec(3), buyAmountSum[1])
-	require.Equal(t, sdk.NewDec(4), sellAmountSum[0])
 }
 
-func TestExecRule0(t *testing.T) {
-	buyAmountSum := []sdk.Dec{sdk.NewDec(0.0), sdk.NewDec(3.0), sdk.NewDec(3.0)}
-	sellAmountSum := []sdk.Dec{sdk.NewDec(4.0), sdk.NewDec(3.0), sdk.NewDec(3.0)}
-
-	maxExecution, execution := execRule0(buyAmountSum, sellAmountSum)
-	require.EqualValues(t, sdk.NewDec(3), maxExecution)
-	require.EqualValues(t, sdk.NewDec(3), execution[1])
-}
-
-func TestExecRule1(t *testing.T) {
-	maxExecution := sdk.NewDec(3.0)
-	execution := []sdk.Dec{sdk.NewDec(0.0), sdk.NewDec(3.0), sdk.NewDec(3.0)}
-
-	indexesRule1 := execRule1(maxExecution, execution)
-	require.EqualValues(t, []int{1, 2}, indexesRule1)
-}
-
-func TestExecRule2(t *testing.T) {
-	buyAmountSum := []sdk.Dec{sdk.NewDec(0.0), sdk.NewDec(3.0), sdk.NewDec(3.0)}
-	sellAmountSum := []sdk.Dec{sdk.NewDec(4.0), sdk.NewDec(3.0), sdk.NewDec(3.0)}
-	indexesRule1 := []int{1, 2}
-
-	indexesRule2, _ := execRule2(buyAmountSum, sellAmountSum, indexesRule1)
-	require.EqualValues(t, []int{1, 2}, indexesRule2)
-}
-
-func TestExecRule3(t *testing.T) {
-	testInput := orderkeeper.CreateTestInput(t)
-	keeper := testInput.OrderKeeper
-	ctx := testInput.Ctx
-	tokenPair := dex.GetBuiltInTokenPair()
-	err := testInput.DexKeeper.SaveTokenPair(ctx, tokenPair)
-	require.Nil(t, err)
-
-	// mock orders, DepthBook, and orderIDsMap
-	orders := []*types.Order{
-		mockOrder("", types.TestTokenPair, types.BuyOrder, "10.1", "1.0"),
-		mockOrder("", types.TestTokenPair, types.BuyOrder, "10.1", "2.0"),
-		mockOrder("", types.TestTokenPair, types.SellOrder, "9.9", "3.0"),
-		mockOrder("", types.TestTokenPair, types.SellOrder, "10.2", "1.0"),
-	}
-	orders[0].Sender = testInput.TestAddrs[0]
-	orders[1].Sender = testInput.TestAddrs[0]
-	orders[2].Sender = testInput.TestAddrs[1]
-	orders[3].Sender = testInput.TestAddrs[1]
+func TestPeriodicAuctionMatchPriceByEmptyDepthBook(t *testing.T) {
 	depthBook := &types.DepthBook{}
+	bestPrice, maxExecution := periodicAuctionMatchPrice(depthBook, 10, sdk.MustNewDecFromStr("10.0"),
+		sdk.MustNewDecFromStr(types.DefaultMarketPressureRate))
 
-	for i := 0; i < 4; i++ {
-		err := keeper.PlaceOrder(ctx, orders[i])
-		require.NoError(t, err)
-		depthBook.InsertOrder(orders[i])
-	}
-
-	indexesRule1 := []int{1, 2}
-	refPrice := sdk.NewDec(10.0)
-	pricePrecision := tokenPair.MaxPriceDigit
-	indexesRule2 := []int{1, 2}
-	imbalance := []sdk.Dec{sdk.NewDec(0.0), sdk.NewDec(0.0)}
-
-	bestPrice := execRule3(depthBook, indexesRule1[0], refPrice, pricePrecision, indexesRule2, imbalance)
-	require.EqualValues(t, sdk.NewDec(10), bestPrice)
-}
-
-func TestBestPriceFromRefPrice(t *testing.T) {
-	minPrice, err := sdk.NewDecFromStr("10.1")
-	require.EqualValues(t, nil, err)
-	maxPrice, err := sdk.NewDecFromStr("9.9")
-	require.EqualValues(t, nil, err)
-	refPrice, err := sdk.NewDecFromStr("10.0")
-	require.EqualValues(t, nil, err)
-
-	bestPrice := bestPriceFromRefPrice(minPrice, maxPrice, refPrice)
-	require.EqualValues(t, sdk.NewDec(10), bestPrice)
-}
-
-func TestExpireOrdersInExpiredBlock(t *testing.T) {
-	testInput := orderkeeper.CreateTestInput(t)
-	keeper := testInput.OrderKeeper
-	ctx := testInput.Ctx
-	tokenPair := dex.GetBuiltInTokenPair()
-	err := testInput.DexKeeper.SaveTokenPair(ctx, tokenPair)
-	require.Nil(t, err)
-
-	orders := []*types.Order{
-		mockOrder("", types.TestTokenPair, types.BuyOrder, "10.1", "1.0"),
-		mockOrder("", types.TestTokenPair, types.BuyOrder, "10.1", "2.0"),
-		mockOrder("", types.TestTokenPair, types.SellOrder, "9.9", "3.0"),
-		mockOrder("", types.TestTokenPair, types.SellOrder, "10.2", "1.0"),
-	}
-	orders[0].Sender = testInput.TestAddrs[0]
-	orders[1].Sender = testInput.TestAddrs[0]
-	orders[2].Sender = testInput.TestAddrs[1]
-	orders[3].Sender = testInput.TestAddrs[1]
-
-	for i := 0; i < 4; i++ {
-		err := keeper.PlaceOrder(ctx, orders[i])
-		require.EqualValues(t, nil, err)
-	}
-
-	expireOrdersInExpiredBlock(ctx, keeper, ctx.BlockHeight())
-
-	order := keeper.GetOrder(ctx, "ID0000000000-1")
-	require.NotEqual(t, nil, order)
-	require.EqualValues(t, int64(types.OrderStatusExpired), order.Status)
-}
-
-func TestMarkCurBlockToFeatureExpireBlockList(t *testing.T) {
-	testInput := orderkeeper.CreateTestInput(t)
-	keeper := testInput.OrderKeeper
-	ctx := testInput.Ctx
-	feeParams := types.DefaultParams()
-
-	markCurBlockToFeatureExpireBlockList(ctx, keeper)
-	expiredBlocks := keeper.GetExpireBlockHeight(ctx, ctx.BlockHeight()+feeParams.OrderExpireBlocks)
-	require.EqualValues(t, 0, expiredBlocks[0])
-}
-
-func TestCleanLastBlockClosedOrders(t *testing.T) {
-	testInput := orderkeeper.CreateTestInput(t)
-	keeper := testInput.OrderKeeper
-	ctx := testInput.Ctx
-
-	orders := []*types.Order{
-		mockOrder("", types.TestTokenPair, types.BuyOrder, "10.1", "1.0"),
-		mockOrder("", types.TestTokenPair, types.BuyOrder, "10.1", "2.0"),
-		mockOrder("", types.TestTokenPair, types.SellOrder, "9.9", "3.0"),
-		mockOrder("", types.TestTokenPair, types.SellOrder, "10.2", "1.0"),
-	}
-	orders[0].Sender = testInput.TestAddrs[0]
-	orders[1].Sender = testInput.TestAddrs[0]
-	orders[2].Sender = testInput.TestAddrs[1]
-	orders[3].Sender = testInput.TestAddrs[1]
-
-	for i := 0; i < 4; i++ {
-		err := keeper.PlaceOrder(ctx, orders[i])
-		require.EqualValues(t, nil, err)
-	}
-
-	keeper.SetLastClosedOrderIDs(ctx, []string{orders[0].OrderID})
-
-	cleanLastBlockClosedOrders(ctx, keeper)
-
-	order := keeper.GetOrder(ctx, orders[0].OrderID)
-	require.EqualValues(t, (*types.Order)(nil), order)
-}
-
-func TestCacheExpiredBlockToCurrentHeight(t *testing.T) {
-	testInput := orderkeeper.CreateTestInput(t)
-	keeper := testInput.OrderKeeper
-	ctx := testInput.Ctx
-
-	orders := []*types.Order{
-		mockOrder("", types.TestTokenPair, types.BuyOrder, "10.1", "1.0"),
-		mockOrder("", types.TestTokenPair, types.BuyOrder, "10.1", "2.0"),
-		mockOrder("", types.TestTokenPair, types.SellOrder, "9.9", "3.0"),
-		mockOrder("", types.TestTokenPair, types.SellOrder, "10.2", "1.0"),
-	}
-	orders[0].Sender = testInput.TestAddrs[0]
-	orders[1].Sender = testInput.TestAddrs[0]
-	orders[2].Sender = testInput.TestAddrs[1]
-	orders[3].Sender = testInput.TestAddrs[1]
-
-	for i := 0; i < 4; i++ {
-		err := keeper.PlaceOrder(ctx, orders[i])
-		require.EqualValues(t, nil, err)
-	}
-
-	expireOrdersInExpiredBlock(ctx, keeper, ctx.BlockHeight())
-	keeper.SetExpireBlockHeight(ctx, ctx.BlockHeight(), []int64{ctx.BlockHeight()})
-
-	cacheExpiredBlockToCurrentHeight(ctx, keeper)
-
-	num := keeper.GetCache().GetExpireNum()
-	require.EqualValues(t, len(orders), int(num))
-}
-
-func TestCleanupExpiredOrders(t *testing.T) {
-	testInput := orderkeeper.CreateTestInput(t)
-	keeper := testInput.OrderKeeper
-	ctx := testInput.Ctx
-	feeParams := types.DefaultParams()
-
-	orders := []*types.Order{
-		mockOrder("", types.TestTokenPair, types.BuyOrder, "10.1", "1.0"),
-		mockOrder("", types.TestTokenPair, types.BuyOrder, "10.1", "2.0"),
-		mockOrder("", types.TestTokenPair, types.SellOrder, "9.9", "3.0"),
-		mockOrder("", types.TestTokenPair, types.SellOrder, "10.2", "1.0"),
-	}
-	orders[0].Sender = testInput.TestAddrs[0]
-	orders[1].Sender = testInput.TestAddrs[0]
-	orders[2].Sender = testInput.TestAddrs[1]
-	orders[3].Sender = testInput.TestAddrs[1]
-
-	for i := 0; i < 4; i++ {
-		err := keeper.PlaceOrder(ctx, orders[i])
-		require.EqualValues(t, nil, err)
-	}
-
-	keeper.SetLastClosedOrderIDs(ctx, []string{orders[0].OrderID})
-	keeper.ExpireOrder(ctx, orders[1], ctx.Logger())
-
-	cleanupExpiredOrders(ctx, keeper)
-
-	expiredBlocks := keeper.GetExpireBlockHeight(ctx, ctx.BlockHeight()+
-		feeParams.OrderExpireBlocks)
-	require.EqualValues(t, true, expiredBlocks[0] == ctx.BlockHeight())
-
-	lastClosedOrderIDs := keeper.GetLastClosedOrderIDs(ctx)
-	require.EqualValues(t, true, lastClosedOrderIDs[0] == orders[0].OrderID)
-
-	num := keeper.GetCache().GetExpireNum()
-	require.EqualValues(t, 1, int(num))
+	require.EqualValues(t, sdk.ZeroDec(), bestPrice)
+	require.EqualValues(t, sdk.ZeroDec(), maxExecution)
 }
 
 func TestMatchOrders(t *testing.T) {
@@ -505,7 +285,7 @@ func TestMatchOrders(t *testing.T) {
 		require.EqualValues(t, nil, err)
 		depthBook.InsertOrder(orders[i])
 	}
-	updatedProductsBasePrice := calcMatchPriceAndExecution(ctx, keeper, []string{types.TestTokenPair})
+	updatedProductsBasePrice := calcMatchPriceAndExecution(ctx, keeper, []string{types.TestTokenPair}, nil)
 	lockProduct(ctx, keeper, ctx.Logger(), types.TestTokenPair, updatedProductsBasePrice[types.TestTokenPair],
 		sdk.ZeroDec(), sdk.ZeroDec())
 
@@ -561,7 +341,7 @@ func TestCalcMatchPriceAndExecution(t *testing.T) {
 	products := keeper.GetDiskCache().GetUpdatedDepthbookKeys()
 	keeper.GetDexKeeper().SortProducts(ctx, products) // sort products
 
-	updatedProductsBasePrice := calcMatchPriceAndExecution(ctx, keeper, products)
+	updatedProductsBasePrice := calcMatchPriceAndExecution(ctx, keeper, products, nil)
 	matchResult, ok := updatedProductsBasePrice[types.TestTokenPair]
 	require.EqualValues(t, ok, true)
 	require.EqualValues(t, matchResult.BlockHeight, ctx.BlockHeight())
@@ -596,7 +376,7 @@ func TestLockProduct(t *testing.T) {
 		depthBook.InsertOrder(orders[i])
 	}
 
-	updatedProductsBasePrice := calcMatchPriceAndExecution(ctx, keeper, []string{types.TestTokenPair})
+	updatedProductsBasePrice := calcMatchPriceAndExecution(ctx, keeper, []string{types.TestTokenPair}, nil)
 
 	lockProduct(ctx, keeper, ctx.Logger(), types.TestTokenPair, updatedProductsBasePrice[types.TestTokenPair],
 		sdk.ZeroDec(), sdk.ZeroDec())
@@ -632,7 +412,7 @@ func TestExecuteMatchedUpdatedProduct(t *testing.T) {
 		depthBook.InsertOrder(orders[i])
 	}
 
-	updatedProductsBasePrice := calcMatchPriceAndExecution(ctx, keeper, []string{types.TestTokenPair})
+	updatedProductsBasePrice := calcMatchPriceAndExecution(ctx, keeper, []string{types.TestTokenPair}, nil)
 
 	blockRemainDeals := executeMatchedUpdatedProduct(ctx, keeper, updatedProductsBasePrice, &feeParams,
 		1000, types.TestTokenPair, ctx.Logger())
@@ -668,7 +448,7 @@ func TestExecuteMatchedUpdatedProductByLimitedBlockDeals(t *testing.T) {
 		depthBook.InsertOrder(orders[i])
 	}
 
-	updatedProductsBasePrice := calcMatchPriceAndExecution(ctx, keeper, []string{types.TestTokenPair})
+	updatedProductsBasePrice := calcMatchPriceAndExecution(ctx, keeper, []string{types.TestTokenPair}, nil)
 
 	blockRemainDeals := executeMatchedUpdatedProduct(ctx, keeper, updatedProductsBasePrice, &feeParams,
 		0, types.TestTokenPair, ctx.Logger())
@@ -705,7 +485,7 @@ func TestExecuteLockedProduct(t *testing.T) {
 		depthBook.InsertOrder(orders[i])
 	}
 
-	updatedProductsBasePrice := calcMatchPriceAndExecution(ctx, keeper, []string{types.TestTokenPair})
+	updatedProductsBasePrice := calcMatchPriceAndExecution(ctx, keeper, []string{types.TestTokenPair}, nil)
 	lockProduct(ctx, keeper, ctx.Logger(), types.TestTokenPair, updatedProductsBasePrice[types.TestTokenPair],
 		sdk.ZeroDec(), sdk.ZeroDec())
 
@@ -745,7 +525,7 @@ func TestExecuteLockedProductByLimitedBlockDeals(t *testing.T) {
 		depthBook.InsertOrder(orders[i])
 	}
 
-	updatedProductsBasePrice := calcMatchPriceAndExecution(ctx, keeper, []string{types.TestTokenPair})
+	updatedProductsBasePrice := calcMatchPriceAndExecution(ctx, keeper, []string{types.TestTokenPair}, nil)
 	lockProduct(ctx, keeper, ctx.Logger(), types.TestTokenPair, updatedProductsBasePrice[types.TestTokenPair],
 		sdk.ZeroDec(), sdk.ZeroDec())
 
@@ -785,7 +565,7 @@ func TestExecuteLockedProductByLargeLockQuantity(t *testing.T) {
 		depthBook.InsertOrder(orders[i])
 	}
 
-	updatedProductsBasePrice := calcMatchPriceAndExecution(ctx, keeper, []string{types.TestTokenPair})
+	updatedProductsBasePrice := calcMatchPriceAndExecution(ctx, keeper, []string{types.TestTokenPair}, nil)
 	lockProduct(ctx, keeper, ctx.Logger(), types.TestTokenPair, updatedProductsBasePrice[types.TestTokenPair],
 		sdk.ZeroDec(), sdk.ZeroDec())
 
@@ -828,7 +608,7 @@ func TestExecuteMatch(t *testing.T) {
 
 	products := keeper.GetDiskCache().GetUpdatedDepthbookKeys()
 	keeper.GetDexKeeper().SortProducts(ctx, products) // sort products
-	updatedProductsBasePrice := calcMatchPriceAndExecution(ctx, keeper, products)
+	updatedProductsBasePrice := calcMatchPriceAndExecution(ctx, keeper, products, nil)
 	lockMap := keeper.GetDexKeeper().GetLockedProductsCopy()
 	for product := range lockMap.Data {
 		products = append(products, product)
@@ -844,33 +624,58 @@ func TestExecuteMatch(t *testing.T) {
 	require.EqualValues(t, sdk.MustNewDecFromStr("1"), depthBook.Items[0].SellQuantity)
 }
 
-func TestCleanupOrdersWhoseTokenPairHaveBeenDelisted(t *testing.T) {
+func TestMatchOrdersCircuitBreaker(t *testing.T) {
 	testInput := orderkeeper.CreateTestInput(t)
 	keeper := testInput.OrderKeeper
-	ctx := testInput.Ctx
+	ctx := testInput.Ctx.WithBlockHeight(10)
+	tokenPair := dex.GetBuiltInTokenPair()
+	err := testInput.DexKeeper.SaveTokenPair(ctx, tokenPair)
+	require.Nil(t, err)
+
+	params := types.DefaultParams()
+	params.PriceBand = sdk.MustNewDecFromStr("0.1")
+	params.CircuitBreakerBlocks = 2
+	keeper.SetParams(ctx, &params)
+	keeper.SetLastPrice(ctx, types.TestTokenPair, sdk.MustNewDecFromStr("10.0"))
 
-	// mock orders, DepthBook, and orderIDsMap
 	orders := []*types.Order{
-		mockOrder("", types.TestTokenPair, types.BuyOrder, "10.1", "1.0"),
-		mockOrder("", types.TestTokenPair, types.BuyOrder, "10.1", "2.0"),
-		mockOrder("", types.TestTokenPair, types.SellOrder, "9.9", "3.0"),
-		mockOrder("", types.TestTokenPair, types.SellOrder, "10.2", "1.0"),
+		mockOrder("", types.TestTokenPair, types.BuyOrder, "12.0", "1.0"),
+		mockOrder("", types.TestTokenPair, types.SellOrder, "12.0", "1.0"),
 	}
 	orders[0].Sender = testInput.TestAddrs[0]
-	orders[1].Sender = testInput.TestAddrs[0]
-	orders[2].Sender = testInput.TestAddrs[1]
-	orders[3].Sender = testInput.TestAddrs[1]
-	depthBook := &types.DepthBook{}
-
-	for i := 0; i < 4; i++ {
-		err := keeper.PlaceOrder(ctx, orders[i])
-		require.EqualValues(t, nil, err)
-		depthBook.InsertOrder(orders[i])
+	orders[1].Sender = testInput.TestAddrs[1]
+	for _, order := range orders {
+		err := keeper.PlaceOrder(ctx, order)
+		require.Nil(t, err)
 	}
 
-	cleanupOrdersWhoseTokenPairHaveBeenDelisted(ctx, keeper)
-
-	depthBook = keeper.GetDepthBookCopy(types.TestTokenPair)
-	require.EqualValues(t, 0, len(depthBook.Items))
+	// the clearing price 12 is 20% away from the last price, halt the product
+	ctx = ctx.WithEventManager(sdk.NewEventManager())
+	matchOrders(ctx, keeper)
+	require.Nil(t, keeper.GetBlockMatchResult())
+	require.EqualValues(t, types.OrderStatusOpen, keeper.GetOrder(ctx, orders[0].OrderID).Status)
+	require.EqualValues(t, sdk.MustNewDecFromStr("10.0"), keeper.GetLastPrice(ctx, types.TestTokenPair))
+	halt := keeper.GetProductHalt(ctx, types.TestTokenPair)
+	require.NotNil(t, halt)
+	require.EqualValues(t, 10, halt.HaltHeight)
+	require.EqualValues(t, 12, halt.ResumeHeight)
+	require.EqualValues(t, sdk.MustNewDecFromStr("12.0"), halt.HaltPrice)
+	events := ctx.EventManager().Events()
+	require.EqualValues(t, 1, len(events))
+	require.EqualValues(t, types.EventTypeProductHalt, events[0].Type)
+
+	// still halted in the next block
+	ctx = ctx.WithBlockHeight(11)
+	matchOrders(ctx, keeper)
+	require.True(t, keeper.IsProductHalted(ctx, types.TestTokenPair))
+	require.EqualValues(t, types.OrderStatusOpen, keeper.GetOrder(ctx, orders[0].OrderID).Status)
 
+	// resume at the resume height, the reopening auction is not limited by the price band
+	ctx = ctx.WithBlockHeight(12).WithEventManager(sdk.NewEventManager())
+	matchOrders(ctx, keeper)
+	require.Nil(t, keeper.GetProductHalt(ctx, types.TestTokenPair))
+	require.EqualValues(t, types.OrderStatusFilled, keeper.GetOrder(ctx, orders[0].OrderID).Status)
+	require.EqualValues(t, sdk.MustNewDecFromStr("12.0"), keeper.GetLastPrice(ctx, types.TestTokenPair))
+	events = ctx.EventManager().Events()
+	require.EqualValues(t, types.EventTypeProductResume, events[0].Type)
 }
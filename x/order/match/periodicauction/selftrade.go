@@ -0,0 +1,81 @@
+package periodicauction
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/okex/okchain/x/order/keeper"
+	"github.com/okex/okchain/x/order/types"
+)
+
+// preventSelfTrades finds the first pair of buy and sell orders of the same sender which would both be filled at
+// the match price, and applies the self-trade prevention mode of the newer one. It returns true if an order is
+// cancelled or decremented, the match price must be recalculated if so.
+func preventSelfTrades(ctx sdk.Context, k keeper.Keeper, product string, book *types.DepthBook,
+	bestPrice, maxExecution sdk.Dec) bool {
+
+	// the orders at prices executable by the match price, best price first, grouped by sender
+	buyOrders, sellOrders := make(map[string][]*types.Order), make(map[string][]*types.Order)
+	var buySenders []string
+	for _, item := range book.Items {
+		if item.BuyQuantity.IsPositive() && item.Price.GTE(bestPrice) {
+			for _, order := range getPriceOrders(ctx, k, product, item.Price, types.BuyOrder) {
+				sender := order.Sender.String()
+				if _, ok := buyOrders[sender]; !ok {
+					buySenders = append(buySenders, sender)
+				}
+				buyOrders[sender] = append(buyOrders[sender], order)
+			}
+		}
+	}
+	for i := len(book.Items) - 1; i >= 0; i-- {
+		item := book.Items[i]
+		if item.SellQuantity.IsPositive() && item.Price.LTE(bestPrice) {
+			for _, order := range getPriceOrders(ctx, k, product, item.Price, types.SellOrder) {
+				if _, ok := buyOrders[order.Sender.String()]; ok {
+					sellOrders[order.Sender.String()] = append(sellOrders[order.Sender.String()], order)
+				}
+			}
+		}
+	}
+
+	logger := ctx.Logger().With("module", "order")
+	for _, sender := range buySenders {
+		if len(sellOrders[sender]) == 0 {
+			continue
+		}
+		for _, buyOrder := range buyOrders[sender] {
+			buyFilled := simulateFilledQuantity(ctx, k, buyOrder, book, bestPrice, maxExecution)
+			if !buyFilled.IsPositive() {
+				break
+			}
+			for _, sellOrder := range sellOrders[sender] {
+				sellFilled := simulateFilledQuantity(ctx, k, sellOrder, book, bestPrice, maxExecution)
+				if !sellFilled.IsPositive() {
+					break
+				}
+				newer, older := buyOrder, sellOrder
+				if types.IsOrderArrivedBefore(buyOrder.OrderID, sellOrder.OrderID) {
+					newer, older = sellOrder, buyOrder
+				}
+				if k.PreventSelfTrade(ctx, newer, older, sdk.MinDec(buyFilled, sellFilled), logger) != "" {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// getPriceOrders returns the orders queued at the price of the side, in the sequence they arrived
+func getPriceOrders(ctx sdk.Context, k keeper.Keeper, product string, price sdk.Dec,
+	side string) []*types.Order {
+
+	orderIDs := k.GetProductPriceOrderIDs(types.FormatOrderIDsKey(product, price, side))
+	orders := make([]*types.Order, 0, len(orderIDs))
+	for _, orderID := range orderIDs {
+		if order := k.GetOrder(ctx, orderID); order != nil {
+			orders = append(orders, order)
+		}
+	}
+	return orders
+}
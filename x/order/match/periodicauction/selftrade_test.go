@@ -0,0 +1,79 @@
+package periodicauction
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/okex/okchain/x/dex"
+	orderkeeper "github.com/okex/okchain/x/order/keeper"
+	"github.com/okex/okchain/x/order/types"
+)
+
+func TestMatchOrdersSelfTradePrevention(t *testing.T) {
+	tests := []struct {
+		mode             string
+		byAccount        bool
+		statuses         []int64
+		remainQuantities []string
+		selfTrades       int
+	}{
+		// the newer bid is cancelled, nothing is matched
+		{types.SelfTradePreventionCancelNewest, false,
+			[]int64{types.OrderStatusOpen, types.OrderStatusOpen, types.OrderStatusCancelled},
+			[]string{"2", "1", "3"}, 1},
+		// the older ask is cancelled, the bid is matched with the ask of the other sender
+		{types.SelfTradePreventionCancelOldest, false,
+			[]int64{types.OrderStatusCancelled, types.OrderStatusFilled, types.OrderStatusOpen},
+			[]string{"2", "0", "2"}, 1},
+		// both are decremented by 2, the own ask is closed and the bid is left 1 to match
+		{types.SelfTradePreventionDecrementBoth, true,
+			[]int64{types.OrderStatusCancelled, types.OrderStatusFilled, types.OrderStatusFilled},
+			[]string{"2", "0", "0"}, 2},
+		// self trades are allowed by default
+		{"", false,
+			[]int64{types.OrderStatusFilled, types.OrderStatusFilled, types.OrderStatusFilled},
+			[]string{"0", "0", "0"}, 0},
+	}
+
+	for _, test := range tests {
+		testInput := orderkeeper.CreateTestInput(t)
+		keeper := testInput.OrderKeeper
+		ctx := testInput.Ctx
+		err := testInput.DexKeeper.SaveTokenPair(ctx, dex.GetBuiltInTokenPair())
+		require.Nil(t, err)
+
+		orders := []*types.Order{
+			mockOrder("", types.TestTokenPair, types.SellOrder, "10.0", "2.0"),
+			mockOrder("", types.TestTokenPair, types.SellOrder, "10.0", "1.0"),
+			mockOrder("", types.TestTokenPair, types.BuyOrder, "10.0", "3.0"),
+		}
+		orders[0].Sender = testInput.TestAddrs[0]
+		orders[1].Sender = testInput.TestAddrs[1]
+		orders[2].Sender = testInput.TestAddrs[0]
+		if test.byAccount {
+			keeper.SetAccountSelfTradePrevention(ctx, testInput.TestAddrs[0], test.mode)
+		} else {
+			orders[2].SelfTradePrevention = test.mode
+		}
+		for _, order := range orders {
+			err := keeper.PlaceOrder(ctx, order)
+			require.NoError(t, err)
+		}
+
+		matchOrders(ctx, keeper)
+
+		for i, order := range orders {
+			order = keeper.GetOrder(ctx, order.OrderID)
+			require.EqualValues(t, test.statuses[i], order.Status, test.mode)
+			require.EqualValues(t, sdk.MustNewDecFromStr(test.remainQuantities[i]), order.RemainQuantity, test.mode)
+		}
+		result := keeper.GetBlockMatchResult()
+		require.EqualValues(t, test.selfTrades, len(result.SelfTrades), test.mode)
+		if test.selfTrades > 0 {
+			require.EqualValues(t, test.mode, result.SelfTrades[0].Mode)
+			require.EqualValues(t, types.TestTokenPair, result.SelfTrades[0].Product)
+		}
+	}
+}
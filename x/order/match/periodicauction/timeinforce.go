@@ -0,0 +1,103 @@
+package periodicauction
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/okex/okchain/x/order/keeper"
+	"github.com/okex/okchain/x/order/types"
+)
+
+// getTimeInForceOrders returns the open FOK and post-only orders placed in current block, and the FOK orders
+// triggered in current block, grouped by product. They must be screened before match, because they are not
+// allowed to be partially filled or filled at all
+func getTimeInForceOrders(ctx sdk.Context, k keeper.Keeper) map[string][]*types.Order {
+	blockHeight := ctx.BlockHeight()
+	orderNum := k.GetBlockOrderNum(ctx, blockHeight)
+	ordersMap := make(map[string][]*types.Order)
+	var index int64
+	for index = 1; index <= orderNum; index++ {
+		order := k.GetOrder(ctx, types.FormatOrderID(blockHeight, index))
+		if order == nil || order.Status != types.OrderStatusOpen {
+			continue
+		}
+		if order.TimeInForce == types.TimeInForceFOK || order.TimeInForce == types.TimeInForcePostOnly {
+			ordersMap[order.Product] = append(ordersMap[order.Product], order)
+		}
+	}
+
+	for _, orderID := range k.GetImmediateOrderIDs(ctx) {
+		order := k.GetOrder(ctx, orderID)
+		if order == nil || order.Status != types.OrderStatusOpen || order.TimeInForce != types.TimeInForceFOK ||
+			order.Trigger == nil || order.Trigger.TriggeredHeight != blockHeight ||
+			types.GetBlockHeightFromOrderID(orderID) == blockHeight {
+			continue
+		}
+		ordersMap[order.Product] = append(ordersMap[order.Product], order)
+	}
+	return ordersMap
+}
+
+// screenTimeInForceOrders quits the first order which violates its time in force at the match price:
+// a FOK order which would not be fully filled is killed, and a post-only order which would be filled is rejected.
+// It returns the orders left and whether an order is quit, the match price must be recalculated if so.
+func screenTimeInForceOrders(ctx sdk.Context, k keeper.Keeper, orders []*types.Order, book *types.DepthBook,
+	bestPrice, maxExecution sdk.Dec) ([]*types.Order, bool) {
+
+	logger := ctx.Logger().With("module", "order")
+	for i, order := range orders {
+		// the order may have been closed or decremented by self-trade prevention
+		if order = k.GetOrder(ctx, order.OrderID); order == nil || order.Status != types.OrderStatusOpen {
+			continue
+		}
+		filledQuantity := simulateFilledQuantity(ctx, k, order, book, bestPrice, maxExecution)
+		switch {
+		case order.TimeInForce == types.TimeInForcePostOnly && filledQuantity.IsPositive():
+			k.PostOnlyRejectOrder(ctx, order, logger)
+		case order.TimeInForce == types.TimeInForceFOK && filledQuantity.LT(order.RemainQuantity):
+			k.FOKKillOrder(ctx, order, logger)
+		default:
+			continue
+		}
+		logger.Info(fmt.Sprintf("order (%s) quit before match, status: %s, would be filled: %v",
+			order.OrderID, types.OrderStatus(order.Status), filledQuantity))
+
+		leftOrders := make([]*types.Order, 0, len(orders)-1)
+		leftOrders = append(leftOrders, orders[:i]...)
+		return append(leftOrders, orders[i+1:]...), true
+	}
+	return orders, false
+}
+
+// simulateFilledQuantity returns the quantity of the order which would be filled at the match price. The orders
+// at better prices are filled first, then the orders at the same price in the sequence they arrived.
+func simulateFilledQuantity(ctx sdk.Context, k keeper.Keeper, order *types.Order, book *types.DepthBook,
+	bestPrice, maxExecution sdk.Dec) sdk.Dec {
+
+	if (order.Side == types.BuyOrder && order.Price.LT(bestPrice)) ||
+		(order.Side == types.SellOrder && order.Price.GT(bestPrice)) {
+		return sdk.ZeroDec()
+	}
+
+	aheadQuantity := sdk.ZeroDec()
+	for _, item := range book.Items {
+		if order.Side == types.BuyOrder && item.Price.GT(order.Price) {
+			aheadQuantity = aheadQuantity.Add(item.BuyQuantity)
+		} else if order.Side == types.SellOrder && item.Price.LT(order.Price) {
+			aheadQuantity = aheadQuantity.Add(item.SellQuantity)
+		}
+	}
+	key := types.FormatOrderIDsKey(order.Product, order.Price, order.Side)
+	for _, orderID := range k.GetProductPriceOrderIDs(key) {
+		if orderID == order.OrderID {
+			break
+		}
+		if aheadOrder := k.GetOrder(ctx, orderID); aheadOrder != nil {
+			aheadQuantity = aheadQuantity.Add(aheadOrder.RemainQuantity)
+		}
+	}
+
+	filledQuantity := sdk.MaxDec(maxExecution.Sub(aheadQuantity), sdk.ZeroDec())
+	return sdk.MinDec(filledQuantity, order.RemainQuantity)
+}
@@ -0,0 +1,200 @@
+package types
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// nolint
+const (
+	AuctionRuleNoMatch           = "0"  // no order can be matched
+	AuctionRuleMaxExecution      = "1"  // the only price with the maximum execution volume
+	AuctionRuleMinImbalance      = "2"  // the only price with the minimum imbalance
+	AuctionRuleBuyPressure       = "3a" // the price closest to the reference price raised by the market pressure
+	AuctionRuleSellPressure      = "3b" // the price closest to the reference price lowered by the market pressure
+	AuctionRuleNoPressure        = "3c" // the price closest to the reference price
+	AuctionImbalanceSideBalanced = ""
+)
+
+// AuctionPrice is the clearing price of the periodic auction on a depth book, and how it is decided
+type AuctionPrice struct {
+	Price         sdk.Dec `json:"price"`
+	Quantity      sdk.Dec `json:"quantity"`       // the volume matchable at the price
+	Imbalance     sdk.Dec `json:"imbalance"`      // the volume left unmatched at the price
+	ImbalanceSide string  `json:"imbalance_side"` // BUY/SELL, the side of the imbalance, empty if balanced
+	Rule          string  `json:"rule"`           // 0/1/2/3a/3b/3c, the rule deciding the price
+}
+
+func preMatchProcessing(book *DepthBook) (buyAmountSum, sellAmountSum []sdk.Dec) {
+	bookLength := len(book.Items)
+	if bookLength == 0 {
+		return
+	}
+
+	buyAmountSum = make([]sdk.Dec, bookLength)
+	sellAmountSum = make([]sdk.Dec, bookLength)
+
+	buyAmountSum[0] = book.Items[0].BuyQuantity
+	for i := 1; i < bookLength; i++ {
+		buyAmountSum[i] = buyAmountSum[i-1].Add(book.Items[i].BuyQuantity)
+	}
+
+	sellAmountSum[bookLength-1] = book.Items[bookLength-1].SellQuantity
+	for i := bookLength - 2; i >= 0; i-- {
+		sellAmountSum[i] = sellAmountSum[i+1].Add(book.Items[i].SellQuantity)
+	}
+
+	return
+}
+
+func execRule0(buyAmountSum, sellAmountSum []sdk.Dec) (maxExecution sdk.Dec, execution []sdk.Dec) {
+	maxExecution = sdk.ZeroDec()
+	bookLength := len(buyAmountSum)
+	execution = make([]sdk.Dec, bookLength)
+	for i := 0; i < bookLength; i++ {
+		execution[i] = sdk.MinDec(buyAmountSum[i], sellAmountSum[i])
+		maxExecution = sdk.MaxDec(execution[i], maxExecution)
+	}
+
+	return
+}
+
+func execRule1(maxExecution sdk.Dec, execution []sdk.Dec) (indexesRule1 []int) {
+	bookLength := len(execution)
+	for i := 0; i < bookLength; i++ {
+		if execution[i].Equal(maxExecution) {
+			indexesRule1 = append(indexesRule1, i)
+		}
+	}
+
+	return
+}
+
+func execRule2(buyAmountSum, sellAmountSum []sdk.Dec, indexesRule1 []int) (indexesRule2 []int, imbalance []sdk.Dec) {
+	indexLen1 := len(indexesRule1)
+	imbalance = make([]sdk.Dec, indexLen1)
+	for i := 0; i < indexLen1; i++ {
+		imbalance[i] = buyAmountSum[indexesRule1[i]].Sub(sellAmountSum[indexesRule1[i]])
+	}
+	minAbsImbalance := imbalance[0].Abs()
+	for i := 1; i < indexLen1; i++ {
+		minAbsImbalance = sdk.MinDec(minAbsImbalance, imbalance[i].Abs())
+	}
+	for i := 0; i < indexLen1; i++ {
+		if imbalance[i].Abs().Equal(minAbsImbalance) {
+			indexesRule2 = append(indexesRule2, indexesRule1[i])
+		}
+	}
+
+	return
+}
+
+func execRule3(book *DepthBook, offset int, refPrice, pressureRate sdk.Dec, pricePrecision int64,
+	indexesRule2 []int, imbalance []sdk.Dec) (bestPrice sdk.Dec, rule string) {
+	indexLen2 := len(indexesRule2)
+	if imbalance[indexesRule2[0]-offset].GT(sdk.ZeroDec()) {
+		// rule3a: all imbalances are positive, buy side pressure
+		newRefPrice := refPrice.Mul(sdk.OneDec().Add(pressureRate))
+		newRefPrice = newRefPrice.RoundDecimal(pricePrecision)
+		bestPrice = bestPriceFromRefPrice(book.Items[indexesRule2[0]].Price,
+			book.Items[indexesRule2[indexLen2-1]].Price, newRefPrice)
+		rule = AuctionRuleBuyPressure
+	} else if imbalance[indexesRule2[indexLen2-1]-offset].LT(sdk.ZeroDec()) {
+		// rule3b: all imbalances are negative, sell side pressure
+		newRefPrice := refPrice.Mul(sdk.OneDec().Sub(pressureRate))
+		newRefPrice = newRefPrice.RoundDecimal(pricePrecision)
+		bestPrice = bestPriceFromRefPrice(book.Items[indexesRule2[0]].Price,
+			book.Items[indexesRule2[indexLen2-1]].Price, newRefPrice)
+		rule = AuctionRuleSellPressure
+	} else {
+		// rule3c: some imbalance > 0, and some imbalance < 0, no buyer pressure or seller pressure
+		newRefPrice := refPrice.RoundDecimal(pricePrecision)
+		bestPrice = bestPriceFromRefPrice(book.Items[indexesRule2[0]].Price,
+			book.Items[indexesRule2[indexLen2-1]].Price, newRefPrice)
+		rule = AuctionRuleNoPressure
+	}
+
+	return
+}
+
+// CalcAuctionPrice calculates periodic auction match price, the execution amount and the imbalance at the price
+// The best price is found according following rules:
+// rule0: No match, bestPrice = 0, maxExecution=0
+// rule1: Maximum execution volume.
+//        If there are more than one price with the same max execution, following rule2
+// rule2: Minimum imbalance. We should select the price with minimum absolute value of imbalance.
+//        If more than one price satisfy rule2, following rule3
+// rule3: Market Pressure. There are 3 cases:
+// rule3a: All imbalances are positive. It indicates buy side pressure. Set reference price with
+//         last execute price plus the market pressure rate(e.g. 5%). Then choose the price
+//         which is closest to reference price.
+// rule3b: All imbalances are negative. It indicates sell side pressure. Set reference price with
+//         last execute price minus the market pressure rate(e.g. 5%). Then choose the price
+//         which is closest to reference price.
+// rule3c: Otherwise, it indicates no one side pressure. Set reference price with last execute
+//         price. Then choose the price which is closest to reference price.
+func CalcAuctionPrice(book *DepthBook, pricePrecision int64, refPrice, pressureRate sdk.Dec) AuctionPrice {
+	buyAmountSum, sellAmountSum := preMatchProcessing(book)
+	if len(buyAmountSum) == 0 {
+		return AuctionPrice{Price: sdk.ZeroDec(), Quantity: sdk.ZeroDec(), Imbalance: sdk.ZeroDec(),
+			Rule: AuctionRuleNoMatch}
+	}
+
+	maxExecution, execution := execRule0(buyAmountSum, sellAmountSum)
+	if maxExecution.IsZero() {
+		return newAuctionPrice(book, refPrice, maxExecution, AuctionRuleNoMatch)
+	}
+
+	indexesRule1 := execRule1(maxExecution, execution)
+	if len(indexesRule1) == 1 {
+		return newAuctionPrice(book, book.Items[indexesRule1[0]].Price, maxExecution, AuctionRuleMaxExecution)
+	}
+
+	indexesRule2, imbalance := execRule2(buyAmountSum, sellAmountSum, indexesRule1)
+	if len(indexesRule2) == 1 {
+		return newAuctionPrice(book, book.Items[indexesRule2[0]].Price, maxExecution, AuctionRuleMinImbalance)
+	}
+
+	bestPrice, rule := execRule3(book, indexesRule1[0], refPrice, pressureRate, pricePrecision, indexesRule2,
+		imbalance)
+	return newAuctionPrice(book, bestPrice, maxExecution, rule)
+}
+
+// newAuctionPrice sums the buy quantity at or above the price and the sell quantity at or below the price for the
+// imbalance, the price might be the reference price between the levels of the depth book
+func newAuctionPrice(book *DepthBook, price, maxExecution sdk.Dec, rule string) AuctionPrice {
+	buyQuantity, sellQuantity := sdk.ZeroDec(), sdk.ZeroDec()
+	for _, item := range book.Items {
+		if item.Price.GTE(price) {
+			buyQuantity = buyQuantity.Add(item.BuyQuantity)
+		}
+		if item.Price.LTE(price) {
+			sellQuantity = sellQuantity.Add(item.SellQuantity)
+		}
+	}
+
+	auctionPrice := AuctionPrice{
+		Price:         price,
+		Quantity:      maxExecution,
+		Imbalance:     buyQuantity.Sub(sellQuantity).Abs(),
+		ImbalanceSide: AuctionImbalanceSideBalanced,
+		Rule:          rule,
+	}
+	if buyQuantity.GT(sellQuantity) {
+		auctionPrice.ImbalanceSide = BuyOrder
+	} else if buyQuantity.LT(sellQuantity) {
+		auctionPrice.ImbalanceSide = SellOrder
+	}
+	return auctionPrice
+}
+
+// get best price from reference price
+// if min < ref < max, choose ref; else choose the closest price to ref price
+func bestPriceFromRefPrice(minPrice, maxPrice, refPrice sdk.Dec) sdk.Dec {
+	if minPrice.LTE(refPrice) {
+		return minPrice
+	}
+	if maxPrice.GTE(refPrice) {
+		return maxPrice
+	}
+	return refPrice
+}
@@ -0,0 +1,134 @@
+package types
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+)
+
+func mockDepthBook(orders ...*Order) *DepthBook {
+	depthBook := &DepthBook{}
+	for _, order := range orders {
+		depthBook.InsertOrder(order)
+	}
+	return depthBook
+}
+
+func TestPreMatchProcessing(t *testing.T) {
+	depthBook := mockDepthBook(
+		MockOrder("", TestTokenPair, BuyOrder, "10.1", "1.0"),
+		MockOrder("", TestTokenPair, BuyOrder, "10.1", "2.0"),
+		MockOrder("", TestTokenPair, SellOrder, "9.9", "3.0"),
+		MockOrder("", TestTokenPair, SellOrder, "10.2", "1.0"),
+	)
+
+	buyAmountSum, sellAmountSum := preMatchProcessing(depthBook)
+	require.Equal(t, sdk.NewDec(3), buyAmountSum[1])
+	require.Equal(t, sdk.NewDec(4), sellAmountSum[0])
+}
+
+func TestExecRule0(t *testing.T) {
+	buyAmountSum := []sdk.Dec{sdk.NewDec(0.0), sdk.NewDec(3.0), sdk.NewDec(3.0)}
+	sellAmountSum := []sdk.Dec{sdk.NewDec(4.0), sdk.NewDec(3.0), sdk.NewDec(3.0)}
+
+	maxExecution, execution := execRule0(buyAmountSum, sellAmountSum)
+	require.EqualValues(t, sdk.NewDec(3), maxExecution)
+	require.EqualValues(t, sdk.NewDec(3), execution[1])
+}
+
+func TestExecRule1(t *testing.T) {
+	maxExecution := sdk.NewDec(3.0)
+	execution := []sdk.Dec{sdk.NewDec(0.0), sdk.NewDec(3.0), sdk.NewDec(3.0)}
+
+	indexesRule1 := execRule1(maxExecution, execution)
+	require.EqualValues(t, []int{1, 2}, indexesRule1)
+}
+
+func TestExecRule2(t *testing.T) {
+	buyAmountSum := []sdk.Dec{sdk.NewDec(0.0), sdk.NewDec(3.0), sdk.NewDec(3.0)}
+	sellAmountSum := []sdk.Dec{sdk.NewDec(4.0), sdk.NewDec(3.0), sdk.NewDec(3.0)}
+	indexesRule1 := []int{1, 2}
+
+	indexesRule2, _ := execRule2(buyAmountSum, sellAmountSum, indexesRule1)
+	require.EqualValues(t, []int{1, 2}, indexesRule2)
+}
+
+func TestExecRule3(t *testing.T) {
+	depthBook := mockDepthBook(
+		MockOrder("", TestTokenPair, BuyOrder, "10.1", "1.0"),
+		MockOrder("", TestTokenPair, BuyOrder, "10.1", "2.0"),
+		MockOrder("", TestTokenPair, SellOrder, "9.9", "3.0"),
+		MockOrder("", TestTokenPair, SellOrder, "10.2", "1.0"),
+	)
+
+	indexesRule1 := []int{1, 2}
+	refPrice := sdk.NewDec(10.0)
+	indexesRule2 := []int{1, 2}
+	imbalance := []sdk.Dec{sdk.NewDec(0.0), sdk.NewDec(0.0)}
+
+	bestPrice, rule := execRule3(depthBook, indexesRule1[0], refPrice,
+		sdk.MustNewDecFromStr(DefaultMarketPressureRate), 8, indexesRule2, imbalance)
+	require.EqualValues(t, sdk.NewDec(10), bestPrice)
+	require.EqualValues(t, AuctionRuleNoPressure, rule)
+}
+
+func TestBestPriceFromRefPrice(t *testing.T) {
+	minPrice, err := sdk.NewDecFromStr("10.1")
+	require.EqualValues(t, nil, err)
+	maxPrice, err := sdk.NewDecFromStr("9.9")
+	require.EqualValues(t, nil, err)
+	refPrice, err := sdk.NewDecFromStr("10.0")
+	require.EqualValues(t, nil, err)
+
+	bestPrice := bestPriceFromRefPrice(minPrice, maxPrice, refPrice)
+	require.EqualValues(t, sdk.NewDec(10), bestPrice)
+}
+
+func TestCalcAuctionPrice(t *testing.T) {
+	testCases := []struct {
+		book          *DepthBook
+		price         string
+		quantity      string
+		imbalance     string
+		imbalanceSide string
+		rule          string
+	}{
+		{&DepthBook{}, "0", "0", "0", AuctionImbalanceSideBalanced, AuctionRuleNoMatch},
+		{mockDepthBook(
+			MockOrder("", TestTokenPair, BuyOrder, "9.9", "1.0"),
+			MockOrder("", TestTokenPair, SellOrder, "10.1", "2.0"),
+		), "10", "0", "0", AuctionImbalanceSideBalanced, AuctionRuleNoMatch},
+		{mockDepthBook(
+			MockOrder("", TestTokenPair, BuyOrder, "10.0", "2.0"),
+			MockOrder("", TestTokenPair, SellOrder, "10.0", "1.0"),
+		), "10", "1", "1", BuyOrder, AuctionRuleMaxExecution},
+		{mockDepthBook(
+			MockOrder("", TestTokenPair, BuyOrder, "10.2", "2.0"),
+			MockOrder("", TestTokenPair, BuyOrder, "10.1", "1.0"),
+			MockOrder("", TestTokenPair, SellOrder, "10.0", "2.0"),
+		), "10.2", "2", "0", AuctionImbalanceSideBalanced, AuctionRuleMinImbalance},
+		{mockDepthBook(
+			MockOrder("", TestTokenPair, BuyOrder, "10.1", "3.0"),
+			MockOrder("", TestTokenPair, SellOrder, "9.9", "2.0"),
+		), "10.1", "2", "1", BuyOrder, AuctionRuleBuyPressure},
+		{mockDepthBook(
+			MockOrder("", TestTokenPair, BuyOrder, "10.1", "2.0"),
+			MockOrder("", TestTokenPair, SellOrder, "9.9", "3.0"),
+		), "9.9", "2", "1", SellOrder, AuctionRuleSellPressure},
+		{mockDepthBook(
+			MockOrder("", TestTokenPair, BuyOrder, "10.1", "3.0"),
+			MockOrder("", TestTokenPair, SellOrder, "9.9", "3.0"),
+			MockOrder("", TestTokenPair, SellOrder, "10.2", "1.0"),
+		), "10", "3", "0", AuctionImbalanceSideBalanced, AuctionRuleNoPressure},
+	}
+
+	for i, tc := range testCases {
+		auctionPrice := CalcAuctionPrice(tc.book, 8, sdk.NewDec(10), sdk.MustNewDecFromStr(DefaultMarketPressureRate))
+		require.True(t, sdk.MustNewDecFromStr(tc.price).Equal(auctionPrice.Price), "case %d", i)
+		require.True(t, sdk.MustNewDecFromStr(tc.quantity).Equal(auctionPrice.Quantity), "case %d", i)
+		require.True(t, sdk.MustNewDecFromStr(tc.imbalance).Equal(auctionPrice.Imbalance), "case %d", i)
+		require.EqualValues(t, tc.imbalanceSide, auctionPrice.ImbalanceSide, "case %d", i)
+		require.EqualValues(t, tc.rule, auctionPrice.Rule, "case %d", i)
+	}
+}
@@ -0,0 +1,48 @@
+package types
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// CancelAfter is the dead man's switch of an account. All the open orders of the account, or only the orders of the
+// product if specified, are cancelled at the deadline unless the account refreshes it before
+type CancelAfter struct {
+	Address  sdk.AccAddress `json:"address"`
+	Product  string         `json:"product"`  // empty for all the products
+	Deadline int64          `json:"deadline"` // the block height at which the orders are cancelled
+}
+
+// NewCancelAfter creates a new instance of CancelAfter
+func NewCancelAfter(addr sdk.AccAddress, product string, deadline int64) *CancelAfter {
+	return &CancelAfter{
+		Address:  addr,
+		Product:  product,
+		Deadline: deadline,
+	}
+}
+
+// IsCoveredOrder returns true if the order is cancelled by the cancel-after of its sender
+func (c CancelAfter) IsCoveredOrder(order *Order) bool {
+	return order.Sender.Equals(c.Address) && (c.Product == "" || c.Product == order.Product)
+}
+
+// ValidateCancelAfters checks the cancel-afters of genesis, every account has at most one cancel-after
+func ValidateCancelAfters(cancelAfters []*CancelAfter) error {
+	addrs := make(map[string]struct{}, len(cancelAfters))
+	for _, cancelAfter := range cancelAfters {
+		if cancelAfter.Address.Empty() {
+			return fmt.Errorf("the address of cancel-after is empty")
+		}
+		if cancelAfter.Deadline <= 0 {
+			return fmt.Errorf("the deadline of the cancel-after of %s should be positive, but got %d",
+				cancelAfter.Address, cancelAfter.Deadline)
+		}
+		if _, ok := addrs[cancelAfter.Address.String()]; ok {
+			return fmt.Errorf("duplicate cancel-after of %s", cancelAfter.Address)
+		}
+		addrs[cancelAfter.Address.String()] = struct{}{}
+	}
+	return nil
+}
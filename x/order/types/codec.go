@@ -8,6 +8,11 @@ import (
 func RegisterCodec(cdc *codec.Codec) {
 	cdc.RegisterConcrete(MsgNewOrders{}, "okchain/order/MsgNew", nil)
 	cdc.RegisterConcrete(MsgCancelOrders{}, "okchain/order/MsgCancel", nil)
+	cdc.RegisterConcrete(MsgReplaceOrders{}, "okchain/order/MsgReplace", nil)
+	cdc.RegisterConcrete(MsgSetSelfTradePrevention{}, "okchain/order/MsgSetSelfTradePrevention", nil)
+	cdc.RegisterConcrete(MsgSetCancelAfter{}, "okchain/order/MsgSetCancelAfter", nil)
+	cdc.RegisterConcrete(MsgGrantTrading{}, "okchain/order/MsgGrantTrading", nil)
+	cdc.RegisterConcrete(MsgRevokeTrading{}, "okchain/order/MsgRevokeTrading", nil)
 }
 
 // ModuleCdc generic sealed codec to be used throughout this module
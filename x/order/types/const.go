@@ -7,12 +7,17 @@ import (
 
 // nolint
 const (
-	FeeTypeOrderNew     = "new"
-	FeeTypeOrderCancel  = "cancel"
-	FeeTypeOrderExpire  = "expire"
-	FeeTypeOrderDeal    = "deal"
-	FeeTypeOrderReceive = "receive"
-	TestTokenPair       = common.TestToken + "_" + sdk.DefaultBondDenom
-	BuyOrder            = "BUY"
-	SellOrder           = "SELL"
+	FeeTypeOrderNew            = "new"
+	FeeTypeOrderCancel         = "cancel"
+	FeeTypeOrderExpire         = "expire"
+	FeeTypeOrderDeal           = "deal"
+	FeeTypeOrderReceive        = "receive"
+	FeeTypeOrderIOCCancel      = "ioc_cancel"
+	FeeTypeOrderFOKKill        = "fok_kill"
+	FeeTypeOrderPostOnlyReject = "post_only_reject"
+	FeeTypeOrderGTBExpire      = "gtb_expire"
+	FeeTypeOrderSelfTrade      = "self_trade_cancel"
+	TestTokenPair              = common.TestToken + "_" + sdk.DefaultBondDenom
+	BuyOrder                   = "BUY"
+	SellOrder                  = "SELL"
 )
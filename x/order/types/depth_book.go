@@ -10,6 +10,7 @@ import (
 type Deal struct {
 	OrderID  string  `json:"order_id"`
 	Side     string  `json:"side"`
+	Price    sdk.Dec `json:"price"`
 	Quantity sdk.Dec `json:"quantity"`
 	Fee      string  `json:"fee"`
 }
@@ -27,6 +28,7 @@ type BlockMatchResult struct {
 	BlockHeight int64                  `json:"block_height"`
 	ResultMap   map[string]MatchResult `json:"result_map"`
 	TimeStamp   int64                  `json:"timestamp"`
+	SelfTrades  []SelfTrade            `json:"self_trades"` // orders closed or reduced by self-trade prevention
 }
 
 // nolint
@@ -34,6 +36,37 @@ type DepthBookItem struct {
 	Price        sdk.Dec `json:"price"`
 	BuyQuantity  sdk.Dec `json:"buy_quantity"`
 	SellQuantity sdk.Dec `json:"sell_quantity"`
+	// the hidden reserves of iceberg orders, which are included in the quantities above and matched as usual,
+	// but left out of the depth book queries. nil means no hidden reserve
+	BuyHiddenQuantity  *sdk.Dec `json:"buy_hidden_quantity,omitempty"`
+	SellHiddenQuantity *sdk.Dec `json:"sell_hidden_quantity,omitempty"`
+}
+
+// HiddenQuantity returns the hidden quantity of the side at the price
+func (item DepthBookItem) HiddenQuantity(side string) sdk.Dec {
+	hidden := item.SellHiddenQuantity
+	if side == BuyOrder {
+		hidden = item.BuyHiddenQuantity
+	}
+	if hidden == nil {
+		return sdk.ZeroDec()
+	}
+	return *hidden
+}
+
+func (item *DepthBookItem) addHiddenQuantity(side string, num sdk.Dec) {
+	if num.IsZero() {
+		return
+	}
+	var hidden *sdk.Dec
+	if sum := item.HiddenQuantity(side).Add(num); !sum.IsZero() {
+		hidden = &sum
+	}
+	if side == BuyOrder {
+		item.BuyHiddenQuantity = hidden
+	} else if side == SellOrder {
+		item.SellHiddenQuantity = hidden
+	}
 }
 
 // nolint
@@ -55,6 +88,7 @@ func (depthBook *DepthBook) InsertOrder(order *Order) {
 	} else {
 		newItem.SellQuantity = order.RemainQuantity
 	}
+	newItem.addHiddenQuantity(order.Side, order.HiddenQuantity())
 	if bookLength == 0 || order.Price.LT(depthBook.Items[bookLength-1].Price) {
 		depthBook.Items = append(depthBook.Items, newItem)
 		return
@@ -73,6 +107,7 @@ func (depthBook *DepthBook) InsertOrder(order *Order) {
 			depthBook.Items[index].SellQuantity =
 				depthBook.Items[index].SellQuantity.Add(order.RemainQuantity)
 		}
+		depthBook.Items[index].addHiddenQuantity(order.Side, order.HiddenQuantity())
 	} else { // order.InitPrice > depthBook[index].InitPrice
 		rear := append([]DepthBookItem{newItem}, depthBook.Items[index:]...)
 		depthBook.Items = append(depthBook.Items[:index], rear...)
@@ -96,18 +131,20 @@ func (depthBook *DepthBook) RemoveOrder(order *Order) {
 			depthBook.Items[index].SellQuantity =
 				depthBook.Items[index].SellQuantity.Sub(order.RemainQuantity)
 		}
+		depthBook.Items[index].addHiddenQuantity(order.Side, order.HiddenQuantity().Neg())
 
 		depthBook.RemoveIfEmpty(index)
 	}
 }
 
-// Sub : subtract the buy or sell quantity
-func (depthBook *DepthBook) Sub(index int, num sdk.Dec, side string) {
+// Sub : subtract the buy or sell quantity, and the part of it drawn from the hidden reserves of iceberg orders
+func (depthBook *DepthBook) Sub(index int, num, hiddenNum sdk.Dec, side string) {
 	if side == BuyOrder {
 		depthBook.Items[index].BuyQuantity = depthBook.Items[index].BuyQuantity.Sub(num)
 	} else if side == SellOrder {
 		depthBook.Items[index].SellQuantity = depthBook.Items[index].SellQuantity.Sub(num)
 	}
+	depthBook.Items[index].addHiddenQuantity(side, hiddenNum.Neg())
 }
 
 // RemoveIfEmpty : remove the filled or empty item
@@ -119,6 +156,20 @@ func (depthBook *DepthBook) RemoveIfEmpty(index int) bool {
 	return res
 }
 
+// Visible returns a copy of depth book with the visible quantities only, the hidden reserves of iceberg orders
+// are left out
+func (depthBook *DepthBook) Visible() *DepthBook {
+	itemList := make([]DepthBookItem, 0, len(depthBook.Items))
+	for _, item := range depthBook.Items {
+		itemList = append(itemList, DepthBookItem{
+			Price:        item.Price,
+			BuyQuantity:  item.BuyQuantity.Sub(item.HiddenQuantity(BuyOrder)),
+			SellQuantity: item.SellQuantity.Sub(item.HiddenQuantity(SellOrder)),
+		})
+	}
+	return &DepthBook{Items: itemList}
+}
+
 // Copy : depth copy of depth book
 func (depthBook *DepthBook) Copy() *DepthBook {
 	itemList := make([]DepthBookItem, 0, len(depthBook.Items))
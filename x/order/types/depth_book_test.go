@@ -64,10 +64,10 @@ func TestSub(t *testing.T) {
 	order1 := MockOrder("", TestTokenPair, BuyOrder, "0.5", "1.1")
 	depthBook.InsertOrder(order1)
 
-	depthBook.Sub(0, sdk.MustNewDecFromStr("1.0"), BuyOrder)
+	depthBook.Sub(0, sdk.MustNewDecFromStr("1.0"), sdk.ZeroDec(), BuyOrder)
 	require.EqualValues(t, sdk.MustNewDecFromStr("0.1"), depthBook.Items[0].BuyQuantity)
 
-	depthBook.Sub(0, sdk.MustNewDecFromStr("0.5"), SellOrder)
+	depthBook.Sub(0, sdk.MustNewDecFromStr("0.5"), sdk.ZeroDec(), SellOrder)
 	require.EqualValues(t, sdk.MustNewDecFromStr("-0.5"), depthBook.Items[0].SellQuantity)
 }
 
@@ -77,7 +77,7 @@ func TestRemoveIfEmpty(t *testing.T) {
 	order1 := MockOrder("", TestTokenPair, BuyOrder, "0.5", "1.1")
 	depthBook.InsertOrder(order1)
 
-	depthBook.Sub(0, sdk.MustNewDecFromStr("1.1"), BuyOrder)
+	depthBook.Sub(0, sdk.MustNewDecFromStr("1.1"), sdk.ZeroDec(), BuyOrder)
 	depthBook.RemoveIfEmpty(0)
 
 	require.EqualValues(t, 0, len(depthBook.Items))
@@ -90,9 +90,47 @@ func TestCopy(t *testing.T) {
 	depthBook.InsertOrder(order1)
 
 	bookCopy := depthBook.Copy()
-	bookCopy.Sub(0, sdk.MustNewDecFromStr("1.1"), BuyOrder)
+	bookCopy.Sub(0, sdk.MustNewDecFromStr("1.1"), sdk.ZeroDec(), BuyOrder)
 	bookCopy.RemoveIfEmpty(0)
 
 	require.EqualValues(t, 1, len(depthBook.Items))
 	require.EqualValues(t, sdk.MustNewDecFromStr("0.5"), depthBook.Items[0].Price)
 }
+
+func TestDepthBookIceberg(t *testing.T) {
+	depthBook := &DepthBook{}
+
+	order1 := MockOrder("", TestTokenPair, BuyOrder, "0.5", "5.0")
+	display1, display3 := sdk.MustNewDecFromStr("1.0"), sdk.MustNewDecFromStr("0.5")
+	order1.DisplayQuantity = &display1
+	order2 := MockOrder("", TestTokenPair, BuyOrder, "0.5", "1.1")
+	order3 := MockOrder("", TestTokenPair, SellOrder, "0.6", "3.0")
+	order3.DisplayQuantity = &display3
+	depthBook.InsertOrder(order1)
+	depthBook.InsertOrder(order2)
+	depthBook.InsertOrder(order3)
+
+	// the full quantities are kept for matching
+	require.EqualValues(t, sdk.MustNewDecFromStr("6.1"), depthBook.Items[1].BuyQuantity)
+	require.EqualValues(t, sdk.MustNewDecFromStr("4.0"), depthBook.Items[1].HiddenQuantity(BuyOrder))
+	require.EqualValues(t, sdk.MustNewDecFromStr("2.5"), depthBook.Items[0].HiddenQuantity(SellOrder))
+	require.True(t, depthBook.Items[0].HiddenQuantity(BuyOrder).IsZero())
+
+	// only the visible slices are shown
+	visible := depthBook.Visible()
+	require.EqualValues(t, 2, len(visible.Items))
+	require.EqualValues(t, sdk.MustNewDecFromStr("0.5"), visible.Items[0].SellQuantity)
+	require.EqualValues(t, sdk.MustNewDecFromStr("2.1"), visible.Items[1].BuyQuantity)
+	require.EqualValues(t, sdk.MustNewDecFromStr("6.1"), depthBook.Items[1].BuyQuantity)
+
+	depthBook.Sub(1, sdk.MustNewDecFromStr("2.0"), sdk.MustNewDecFromStr("2.0"), BuyOrder)
+	require.EqualValues(t, sdk.MustNewDecFromStr("4.1"), depthBook.Items[1].BuyQuantity)
+	require.EqualValues(t, sdk.MustNewDecFromStr("2.0"), depthBook.Items[1].HiddenQuantity(BuyOrder))
+
+	depthBook.RemoveOrder(order3)
+	require.EqualValues(t, 1, len(depthBook.Items))
+	order1.Fill(order1.Price, sdk.MustNewDecFromStr("2.0"))
+	depthBook.RemoveOrder(order1)
+	require.EqualValues(t, sdk.MustNewDecFromStr("1.1"), depthBook.Items[0].BuyQuantity)
+	require.True(t, depthBook.Items[0].HiddenQuantity(BuyOrder).IsZero())
+}
@@ -0,0 +1,63 @@
+package types
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// NewOrderEvent returns an order lifecycle event of the specified type, which carries the current state of the order
+func NewOrderEvent(eventType string, order *Order) sdk.Event {
+	orderType := order.Type
+	if orderType == "" {
+		orderType = OrderTypeLimit
+	}
+	timeInForce := order.TimeInForce
+	if timeInForce == "" {
+		timeInForce = TimeInForceGTC
+	}
+	return sdk.NewEvent(eventType,
+		sdk.NewAttribute(TagKeyOrderID, order.OrderID),
+		sdk.NewAttribute(TagKeySender, order.Sender.String()),
+		sdk.NewAttribute(TagKeyProduct, order.Product),
+		sdk.NewAttribute(TagKeySide, order.Side),
+		sdk.NewAttribute(TagKeyOrderType, orderType),
+		sdk.NewAttribute(TagKeyTimeInForce, timeInForce),
+		sdk.NewAttribute(TagKeyPrice, order.Price.String()),
+		sdk.NewAttribute(TagKeyQuantity, order.Quantity.String()),
+		sdk.NewAttribute(TagKeyRemainQuantity, order.RemainQuantity.String()),
+		sdk.NewAttribute(TagKeyFilledAvgPrice, order.FilledAvgPrice.String()),
+		sdk.NewAttribute(TagKeyStatus, OrderStatus(order.Status).String()),
+	)
+}
+
+// NewOrderFillEvent returns the filled or partially filled event of an order for a single deal. The counter side is
+// the side of the orders on the other side of the deal
+func NewOrderFillEvent(order *Order, deal *Deal) sdk.Event {
+	eventType := EventTypeOrderPartiallyFilled
+	if order.Status == OrderStatusFilled {
+		eventType = EventTypeOrderFilled
+	}
+	return NewOrderEvent(eventType, order).AppendAttributes(
+		sdk.NewAttribute(TagKeyCounterSide, counterSide(deal.Side)),
+		sdk.NewAttribute(TagKeyFillPrice, deal.Price.String()),
+		sdk.NewAttribute(TagKeyFillQuantity, deal.Quantity.String()),
+		sdk.NewAttribute(TagKeyFee, deal.Fee),
+	)
+}
+
+// NewOrderQuitEvent returns the cancelled or expired event of an order quit with the specified fee charged
+func NewOrderQuitEvent(order *Order, fee sdk.DecCoins) sdk.Event {
+	eventType := EventTypeOrderCancelled
+	switch order.Status {
+	case OrderStatusExpired, OrderStatusPartialFilledExpired, OrderStatusGTBExpired,
+		OrderStatusPartialFilledGTBExpired:
+		eventType = EventTypeOrderExpired
+	}
+	return NewOrderEvent(eventType, order).AppendAttributes(sdk.NewAttribute(TagKeyFee, fee.String()))
+}
+
+func counterSide(side string) string {
+	if side == BuyOrder {
+		return SellOrder
+	}
+	return BuyOrder
+}
@@ -0,0 +1,102 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// nolint
+const (
+	// TradeVolumeDays is the number of days of the trailing trade volume which the volume tiers are based on
+	TradeVolumeDays = 30
+	SecondsInADay   = 24 * 60 * 60
+)
+
+// VolumeTier discounts the deal fee rate of the address whose trailing trade volume of the token pair, in quote
+// token, reaches the minimum volume
+type VolumeTier struct {
+	MinVolume sdk.Dec `json:"min_volume"`
+	Discount  sdk.Dec `json:"discount"` // e.g. 0.2 means 20% off the fee rate
+}
+
+// FeeSchedule is the deal fee schedule of a token pair. The maker fee rate is applied to the order which provided
+// liquidity, and the taker fee rate is applied to the order which took liquidity
+type FeeSchedule struct {
+	Product      string       `json:"product"`
+	MakerFeeRate sdk.Dec      `json:"maker_fee_rate"`
+	TakerFeeRate sdk.Dec      `json:"taker_fee_rate"`
+	VolumeTiers  []VolumeTier `json:"volume_tiers"`
+}
+
+// String implements the stringer interface
+func (s FeeSchedule) String() string {
+	tiers := make([]string, 0, len(s.VolumeTiers))
+	for _, tier := range s.VolumeTiers {
+		tiers = append(tiers, fmt.Sprintf("%s:%s", tier.MinVolume, tier.Discount))
+	}
+	return fmt.Sprintf("%s(maker: %s, taker: %s, tiers: [%s])", s.Product, s.MakerFeeRate, s.TakerFeeRate,
+		strings.Join(tiers, ", "))
+}
+
+// FeeRate returns the deal fee rate of the maker or taker, discounted by the highest volume tier reached
+func (s FeeSchedule) FeeRate(isMaker bool, trailingVolume sdk.Dec) sdk.Dec {
+	feeRate := s.TakerFeeRate
+	if isMaker {
+		feeRate = s.MakerFeeRate
+	}
+	discount := sdk.ZeroDec()
+	for _, tier := range s.VolumeTiers {
+		if trailingVolume.GTE(tier.MinVolume) {
+			discount = tier.Discount
+		}
+	}
+	return feeRate.Mul(sdk.OneDec().Sub(discount))
+}
+
+// Validate checks the fee rates and the volume tiers, the tiers must be sorted by minimum volume ascending
+func (s FeeSchedule) Validate() error {
+	if s.Product == "" {
+		return fmt.Errorf("product of fee schedule is empty")
+	}
+	if s.MakerFeeRate.IsNegative() || s.MakerFeeRate.GTE(sdk.OneDec()) ||
+		s.TakerFeeRate.IsNegative() || s.TakerFeeRate.GTE(sdk.OneDec()) {
+		return fmt.Errorf("fee rates of %s should be in [0, 1)", s.Product)
+	}
+	for i, tier := range s.VolumeTiers {
+		if tier.MinVolume.IsNegative() || tier.Discount.IsNegative() || tier.Discount.GT(sdk.OneDec()) {
+			return fmt.Errorf("volume tier %d of %s is invalid", i, s.Product)
+		}
+		if i > 0 && !tier.MinVolume.GT(s.VolumeTiers[i-1].MinVolume) {
+			return fmt.Errorf("volume tiers of %s should be sorted by min volume ascending", s.Product)
+		}
+	}
+	return nil
+}
+
+// ValidateFeeSchedules checks every fee schedule, and a token pair has at most one fee schedule
+func ValidateFeeSchedules(schedules []FeeSchedule) error {
+	products := make(map[string]struct{}, len(schedules))
+	for _, schedule := range schedules {
+		if err := schedule.Validate(); err != nil {
+			return err
+		}
+		if _, ok := products[schedule.Product]; ok {
+			return fmt.Errorf("duplicated fee schedule of %s", schedule.Product)
+		}
+		products[schedule.Product] = struct{}{}
+	}
+	return nil
+}
+
+// GetFeeSchedule returns the fee schedule of the token pair. The token pair without a fee schedule is charged
+// with the trade fee rate, whether it is the maker or taker
+func (p Params) GetFeeSchedule(product string) FeeSchedule {
+	for _, schedule := range p.FeeSchedules {
+		if schedule.Product == product {
+			return schedule
+		}
+	}
+	return FeeSchedule{Product: product, MakerFeeRate: p.TradeFeeRate, TakerFeeRate: p.TradeFeeRate}
+}
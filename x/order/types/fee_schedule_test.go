@@ -0,0 +1,55 @@
+package types
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+)
+
+func mockFeeSchedule() FeeSchedule {
+	return FeeSchedule{
+		Product:      TestTokenPair,
+		MakerFeeRate: sdk.MustNewDecFromStr("0.001"),
+		TakerFeeRate: sdk.MustNewDecFromStr("0.002"),
+		VolumeTiers: []VolumeTier{
+			{MinVolume: sdk.NewDec(100), Discount: sdk.MustNewDecFromStr("0.2")},
+			{MinVolume: sdk.NewDec(1000), Discount: sdk.MustNewDecFromStr("0.5")},
+		},
+	}
+}
+
+func TestFeeScheduleFeeRate(t *testing.T) {
+	schedule := mockFeeSchedule()
+	require.EqualValues(t, sdk.MustNewDecFromStr("0.001"), schedule.FeeRate(true, sdk.NewDec(99)))
+	require.EqualValues(t, sdk.MustNewDecFromStr("0.002"), schedule.FeeRate(false, sdk.NewDec(99)))
+	require.EqualValues(t, sdk.MustNewDecFromStr("0.0008"), schedule.FeeRate(true, sdk.NewDec(100)))
+	require.EqualValues(t, sdk.MustNewDecFromStr("0.001"), schedule.FeeRate(false, sdk.NewDec(5000)))
+
+	params := DefaultParams()
+	require.EqualValues(t, FeeSchedule{Product: "xxb_yyb", MakerFeeRate: params.TradeFeeRate,
+		TakerFeeRate: params.TradeFeeRate}, params.GetFeeSchedule("xxb_yyb"))
+	params.FeeSchedules = []FeeSchedule{schedule}
+	require.EqualValues(t, schedule, params.GetFeeSchedule(TestTokenPair))
+}
+
+func TestValidateFeeSchedules(t *testing.T) {
+	require.Nil(t, ValidateFeeSchedules(nil))
+	require.Nil(t, ValidateFeeSchedules([]FeeSchedule{mockFeeSchedule()}))
+
+	// duplicated token pair
+	require.NotNil(t, ValidateFeeSchedules([]FeeSchedule{mockFeeSchedule(), mockFeeSchedule()}))
+
+	invalidSchedules := []func(s *FeeSchedule){
+		func(s *FeeSchedule) { s.Product = "" },
+		func(s *FeeSchedule) { s.MakerFeeRate = sdk.MustNewDecFromStr("-0.001") },
+		func(s *FeeSchedule) { s.TakerFeeRate = sdk.OneDec() },
+		func(s *FeeSchedule) { s.VolumeTiers[0].Discount = sdk.MustNewDecFromStr("1.1") },
+		func(s *FeeSchedule) { s.VolumeTiers[1].MinVolume = sdk.NewDec(100) },
+	}
+	for i, invalidate := range invalidSchedules {
+		schedule := mockFeeSchedule()
+		invalidate(&schedule)
+		require.NotNil(t, ValidateFeeSchedules([]FeeSchedule{schedule}), i)
+	}
+}
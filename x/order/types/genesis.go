@@ -0,0 +1,214 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// LastPrice is the last executed price of a product, which is the reference price of its next auction
+type LastPrice struct {
+	Product string  `json:"product"`
+	Price   sdk.Dec `json:"price"`
+}
+
+// BlockOrderNum is the number of orders placed at a block height, the orders of the block are expired by it
+type BlockOrderNum struct {
+	BlockHeight int64 `json:"block_height"`
+	OrderNum    int64 `json:"order_num"`
+}
+
+// ExpireBlockHeight is the block heights whose orders expire at a block height
+type ExpireBlockHeight struct {
+	BlockHeight        int64   `json:"block_height"`
+	ExpireBlockHeights []int64 `json:"expire_block_heights"`
+}
+
+// SelfTradeMode is the self-trade prevention mode of an account
+type SelfTradeMode struct {
+	Address sdk.AccAddress `json:"address"`
+	Mode    string         `json:"mode"`
+}
+
+// TradeVolume is the trade volume of an address on a product in a day
+type TradeVolume struct {
+	Address sdk.AccAddress `json:"address"`
+	Product string         `json:"product"`
+	Day     int64          `json:"day"`
+	Volume  sdk.Dec        `json:"volume"`
+}
+
+// ParseOrderID returns the block height and the sequence in the block of the order ID
+func ParseOrderID(orderID string) (blockHeight, orderNum int64, err error) {
+	if _, err = fmt.Sscanf(orderID, "ID%d-%d", &blockHeight, &orderNum); err != nil {
+		return 0, 0, fmt.Errorf("invalid order id %s: %v", orderID, err)
+	}
+	if blockHeight <= 0 || orderNum <= 0 || FormatOrderID(blockHeight, orderNum) != orderID {
+		return 0, 0, fmt.Errorf("invalid order id %s", orderID)
+	}
+	return blockHeight, orderNum, nil
+}
+
+// ValidateOpenOrders checks the open orders of genesis. The order IDs are unique, and the coins locked by every
+// order match its remain quantity: a sell order locks the remain quantity, and a buy order locks between the amount
+// of the remain quantity and the amount of the whole quantity at the order price, since it might be filled at lower
+// prices
+func ValidateOpenOrders(orders []*Order) error {
+	orderIDs := make(map[string]struct{}, len(orders))
+	for _, order := range orders {
+		if order == nil {
+			return fmt.Errorf("the open order is empty")
+		}
+		if _, _, err := ParseOrderID(order.OrderID); err != nil {
+			return err
+		}
+		if _, ok := orderIDs[order.OrderID]; ok {
+			return fmt.Errorf("duplicate order %s", order.OrderID)
+		}
+		orderIDs[order.OrderID] = struct{}{}
+
+		if order.Status != OrderStatusOpen && order.Status != OrderStatusUntriggered {
+			return fmt.Errorf("the order %s is not open, status: %d", order.OrderID, order.Status)
+		}
+		if order.Sender.Empty() {
+			return fmt.Errorf("the sender of order %s is empty", order.OrderID)
+		}
+		if len(strings.Split(order.Product, "_")) != 2 {
+			return fmt.Errorf("invalid product %s of order %s", order.Product, order.OrderID)
+		}
+		if order.Side != BuyOrder && order.Side != SellOrder {
+			return fmt.Errorf("invalid side %s of order %s", order.Side, order.OrderID)
+		}
+		if !order.Price.IsPositive() || !order.Quantity.IsPositive() || !order.RemainQuantity.IsPositive() ||
+			order.RemainQuantity.GT(order.Quantity) {
+			return fmt.Errorf("invalid price %s, quantity %s or remain quantity %s of order %s", order.Price,
+				order.Quantity, order.RemainQuantity, order.OrderID)
+		}
+
+		minLocked, maxLocked := order.RemainQuantity, order.RemainQuantity
+		if order.Side == BuyOrder {
+			minLocked, maxLocked = order.Price.Mul(order.RemainQuantity), order.Price.Mul(order.Quantity)
+		}
+		if order.RemainLocked.IsNil() || order.RemainLocked.LT(minLocked) || order.RemainLocked.GT(maxLocked) {
+			return fmt.Errorf("the locked amount %s of order %s does not match its remain quantity %s",
+				order.RemainLocked, order.OrderID, order.RemainQuantity)
+		}
+	}
+	return nil
+}
+
+// ValidateLastPrices checks the last prices of genesis, every product has at most one positive last price
+func ValidateLastPrices(prices []*LastPrice) error {
+	products := make(map[string]struct{}, len(prices))
+	for _, price := range prices {
+		if price.Product == "" {
+			return fmt.Errorf("the product of last price is empty")
+		}
+		if price.Price.IsNil() || !price.Price.IsPositive() {
+			return fmt.Errorf("the last price of %s should be positive", price.Product)
+		}
+		if _, ok := products[price.Product]; ok {
+			return fmt.Errorf("duplicate last price of %s", price.Product)
+		}
+		products[price.Product] = struct{}{}
+	}
+	return nil
+}
+
+// ValidateBlockOrderNums checks the order numbers of blocks of genesis, every block height has at most one record
+func ValidateBlockOrderNums(nums []*BlockOrderNum) error {
+	heights := make(map[int64]struct{}, len(nums))
+	for _, num := range nums {
+		if num.BlockHeight <= 0 || num.OrderNum <= 0 {
+			return fmt.Errorf("invalid order number %d at block height %d", num.OrderNum, num.BlockHeight)
+		}
+		if _, ok := heights[num.BlockHeight]; ok {
+			return fmt.Errorf("duplicate order number at block height %d", num.BlockHeight)
+		}
+		heights[num.BlockHeight] = struct{}{}
+	}
+	return nil
+}
+
+// ValidateExpireBlockHeights checks the expire block heights of genesis, every block height has at most one record
+func ValidateExpireBlockHeights(expireHeights []*ExpireBlockHeight) error {
+	heights := make(map[int64]struct{}, len(expireHeights))
+	for _, expireHeight := range expireHeights {
+		if expireHeight.BlockHeight <= 0 {
+			return fmt.Errorf("invalid expire block height %d", expireHeight.BlockHeight)
+		}
+		for _, height := range expireHeight.ExpireBlockHeights {
+			if height <= 0 {
+				return fmt.Errorf("invalid block height %d expired at %d", height, expireHeight.BlockHeight)
+			}
+		}
+		if _, ok := heights[expireHeight.BlockHeight]; ok {
+			return fmt.Errorf("duplicate expire block height %d", expireHeight.BlockHeight)
+		}
+		heights[expireHeight.BlockHeight] = struct{}{}
+	}
+	return nil
+}
+
+// ValidateProductHalts checks the product halts of genesis, every product has at most one halt
+func ValidateProductHalts(halts []*ProductHalt) error {
+	products := make(map[string]struct{}, len(halts))
+	for _, halt := range halts {
+		if halt.Product == "" {
+			return fmt.Errorf("the product of halt is empty")
+		}
+		if halt.ResumeHeight <= halt.HaltHeight {
+			return fmt.Errorf("the resume height %d of the halt of %s should be greater than the halt height %d",
+				halt.ResumeHeight, halt.Product, halt.HaltHeight)
+		}
+		if _, ok := products[halt.Product]; ok {
+			return fmt.Errorf("duplicate halt of %s", halt.Product)
+		}
+		products[halt.Product] = struct{}{}
+	}
+	return nil
+}
+
+// ValidateSelfTradeModes checks the self-trade prevention modes of genesis, every account has at most one mode
+func ValidateSelfTradeModes(modes []*SelfTradeMode) error {
+	addrs := make(map[string]struct{}, len(modes))
+	for _, mode := range modes {
+		if mode.Address.Empty() {
+			return fmt.Errorf("the address of self-trade prevention mode is empty")
+		}
+		if mode.Mode == "" {
+			return fmt.Errorf("the self-trade prevention mode of %s is empty", mode.Address)
+		}
+		if err := ValidateSelfTradePrevention(mode.Mode); err != nil {
+			return err
+		}
+		if _, ok := addrs[mode.Address.String()]; ok {
+			return fmt.Errorf("duplicate self-trade prevention mode of %s", mode.Address)
+		}
+		addrs[mode.Address.String()] = struct{}{}
+	}
+	return nil
+}
+
+// ValidateTradeVolumes checks the trade volumes of genesis, every address has at most one volume on a product
+// in a day
+func ValidateTradeVolumes(volumes []*TradeVolume) error {
+	keys := make(map[string]struct{}, len(volumes))
+	for _, volume := range volumes {
+		if volume.Address.Empty() || volume.Product == "" {
+			return fmt.Errorf("the address or product of trade volume is empty")
+		}
+		if volume.Day < 0 || volume.Volume.IsNil() || volume.Volume.IsNegative() {
+			return fmt.Errorf("invalid trade volume %s of %s on %s in day %d", volume.Volume, volume.Address,
+				volume.Product, volume.Day)
+		}
+		key := string(GetTradeVolumeKey(volume.Product, volume.Address, volume.Day))
+		if _, ok := keys[key]; ok {
+			return fmt.Errorf("duplicate trade volume of %s on %s in day %d", volume.Address, volume.Product,
+				volume.Day)
+		}
+		keys[key] = struct{}{}
+	}
+	return nil
+}
@@ -25,6 +25,11 @@ const (
 	QueryParameters  = "params"
 	QueryStore       = "store"
 	QueryDepthBookV2 = "depthbookV2"
+	QueryHalts       = "halts"
+	QueryAuction     = "auction"
+	QueryCancelAfter = "cancelafter"
+	QueryGrants      = "grants"
+	QueryOpenOrders  = "open"
 
 	OrderStoreKey = ModuleName
 )
@@ -46,6 +51,22 @@ var (
 	LastExpiredBlockHeightKey = []byte{0x18}
 	OpenOrderNumKey           = []byte{0x19}
 	StoreOrderNumKey          = []byte{0x20}
+
+	// iterator keys
+	ImmediateOrderKey = []byte{0x21}
+	GTBOrderKey       = []byte{0x22}
+	TriggerOrderKey   = []byte{0x23}
+	ProductHaltKey    = []byte{0x24}
+	TradeVolumeKey    = []byte{0x25}
+	SelfTradeModeKey  = []byte{0x26}
+	CancelAfterKey    = []byte{0x27}
+	DeadlineKey       = []byte{0x28}
+	TradingGrantKey   = []byte{0x29}
+	AccountOrderKey   = []byte{0x2A}
+	GrantExpiryKey    = []byte{0x2C}
+
+	// none iterator keys
+	PendingIncomingOrdersKey = []byte{0x2B}
 )
 
 // nolint
@@ -53,6 +74,92 @@ func GetOrderKey(key string) []byte {
 	return append(OrderKey, []byte(key)...)
 }
 
+// nolint
+func GetImmediateOrderKey(orderID string) []byte {
+	return append(ImmediateOrderKey, []byte(orderID)...)
+}
+
+// nolint
+func GetGTBOrderKey(expireHeight int64, orderID string) []byte {
+	return append(GetGTBOrderHeightPrefix(expireHeight), []byte(orderID)...)
+}
+
+// nolint
+func GetGTBOrderHeightPrefix(expireHeight int64) []byte {
+	return append(GTBOrderKey, sdk.Uint64ToBigEndian(uint64(expireHeight))...)
+}
+
+// nolint
+func GetTriggerOrderKey(orderID string) []byte {
+	return append(TriggerOrderKey, []byte(orderID)...)
+}
+
+// nolint
+func GetProductHaltKey(product string) []byte {
+	return append(ProductHaltKey, []byte(product)...)
+}
+
+// nolint
+func GetTradeVolumePrefix(product string, addr sdk.AccAddress) []byte {
+	key := append(TradeVolumeKey, []byte(product+":")...)
+	return append(key, addr.Bytes()...)
+}
+
+// nolint
+func GetTradeVolumeKey(product string, addr sdk.AccAddress, day int64) []byte {
+	return append(GetTradeVolumePrefix(product, addr), sdk.Uint64ToBigEndian(uint64(day))...)
+}
+
+// nolint
+func GetSelfTradeModeKey(addr sdk.AccAddress) []byte {
+	return append(SelfTradeModeKey, addr.Bytes()...)
+}
+
+// nolint
+func GetCancelAfterKey(addr sdk.AccAddress) []byte {
+	return append(CancelAfterKey, addr.Bytes()...)
+}
+
+// nolint
+func GetDeadlineKey(deadline int64, addr sdk.AccAddress) []byte {
+	return append(GetDeadlinePrefix(deadline), addr.Bytes()...)
+}
+
+// nolint
+func GetDeadlinePrefix(deadline int64) []byte {
+	return append(DeadlineKey, sdk.Uint64ToBigEndian(uint64(deadline))...)
+}
+
+// nolint
+func GetTradingGrantPrefix(granter sdk.AccAddress) []byte {
+	return append(TradingGrantKey, granter.Bytes()...)
+}
+
+// nolint
+func GetTradingGrantKey(granter, grantee sdk.AccAddress) []byte {
+	return append(GetTradingGrantPrefix(granter), grantee.Bytes()...)
+}
+
+// nolint
+func GetGrantExpiryKey(expireHeight int64, granter, grantee sdk.AccAddress) []byte {
+	return append(append(GetGrantExpiryPrefix(expireHeight), granter.Bytes()...), grantee.Bytes()...)
+}
+
+// nolint
+func GetGrantExpiryPrefix(expireHeight int64) []byte {
+	return append(GrantExpiryKey, sdk.Uint64ToBigEndian(uint64(expireHeight))...)
+}
+
+// nolint
+func GetAccountOrderPrefix(addr sdk.AccAddress) []byte {
+	return append(AccountOrderKey, addr.Bytes()...)
+}
+
+// nolint
+func GetAccountOrderKey(addr sdk.AccAddress, orderID string) []byte {
+	return append(GetAccountOrderPrefix(addr), []byte(orderID)...)
+}
+
 // nolint
 func GetDepthBookKey(key string) []byte {
 	return append(DepthBookKey, []byte(key)...)
@@ -17,11 +17,19 @@ const (
 
 // nolint
 type MsgNewOrder struct {
-	Sender   sdk.AccAddress `json:"sender"`   // order maker address
-	Product  string         `json:"product"`  // product for trading pair in full name of the tokens
-	Side     string         `json:"side"`     // BUY/SELL
-	Price    sdk.Dec        `json:"price"`    // price of the order
-	Quantity sdk.Dec        `json:"quantity"` // quantity of the order
+	Sender       sdk.AccAddress `json:"sender"`        // order maker address
+	Product      string         `json:"product"`       // product for trading pair in full name of the tokens
+	Side         string         `json:"side"`          // BUY/SELL
+	Price        sdk.Dec        `json:"price"`         // price of the order
+	Quantity     sdk.Dec        `json:"quantity"`      // quantity of the order
+	Type         string         `json:"type"`          // LIMIT/MARKET
+	TimeInForce  string         `json:"time_in_force"` // GTC/IOC/FOK/POST_ONLY/GTB
+	ExpireHeight int64          `json:"expire_height"` // the last block height of GTB order
+	TriggerType  string         `json:"trigger_type"`  // STOP_LOSS/TAKE_PROFIT, empty means no trigger
+	TriggerPrice sdk.Dec        `json:"trigger_price"` // the order is triggered when the last price crosses it
+	// CANCEL_NEWEST/CANCEL_OLDEST/DECREMENT_BOTH, empty means the mode of the sender account
+	SelfTradePrevention string  `json:"stp"`
+	DisplayQuantity     sdk.Dec `json:"display_quantity"` // the visible slice of iceberg order, empty means no slice
 }
 
 // NewMsgNewOrder is a constructor function for MsgNewOrder
@@ -45,6 +53,7 @@ func NewMsgNewOrder(sender sdk.AccAddress, product string, side string, price st
 type MsgCancelOrder struct {
 	Sender  sdk.AccAddress `json:"sender"`
 	OrderID string         `json:"order_id"`
+	Grantee sdk.AccAddress `json:"grantee,omitempty"`
 }
 
 // NewMsgCancelOrder is a constructor function for MsgCancelOrder
@@ -61,14 +70,25 @@ func NewMsgCancelOrder(sender sdk.AccAddress, orderID string) MsgCancelOrders {
 type MsgNewOrders struct {
 	Sender     sdk.AccAddress `json:"sender"` // order maker address
 	OrderItems []OrderItem    `json:"order_items"`
+	// the address trading on behalf of the sender by its trading grant, empty if the sender signs itself
+	Grantee sdk.AccAddress `json:"grantee,omitempty"`
 }
 
 // nolint
 type OrderItem struct {
-	Product  string  `json:"product"`  // product for trading pair in full name of the tokens
-	Side     string  `json:"side"`     // BUY/SELL
-	Price    sdk.Dec `json:"price"`    // price of the order
-	Quantity sdk.Dec `json:"quantity"` // quantity of the order
+	Product      string  `json:"product"`                 // product for trading pair in full name of the tokens
+	Side         string  `json:"side"`                    // BUY/SELL
+	Price        sdk.Dec `json:"price"`                   // price of the order, zero for market order
+	Quantity     sdk.Dec `json:"quantity"`                // quantity of the order
+	Type         string  `json:"type,omitempty"`          // LIMIT/MARKET, empty means LIMIT
+	TimeInForce  string  `json:"time_in_force,omitempty"` // GTC/IOC/FOK/POST_ONLY/GTB, empty means GTC
+	ExpireHeight int64   `json:"expire_height,omitempty"` // the last block height of GTB order
+	TriggerType  string  `json:"trigger_type,omitempty"`  // STOP_LOSS/TAKE_PROFIT, empty means no trigger
+	TriggerPrice sdk.Dec `json:"trigger_price,omitempty"` // the order is triggered when the last price crosses it
+	// CANCEL_NEWEST/CANCEL_OLDEST/DECREMENT_BOTH, empty means the mode of the sender account
+	SelfTradePrevention string `json:"stp,omitempty"`
+	// the visible slice of iceberg order in depth book, empty means the whole order is visible
+	DisplayQuantity sdk.Dec `json:"display_quantity,omitempty"`
 }
 
 // nolint
@@ -82,6 +102,37 @@ func NewOrderItem(product string, side string, price string,
 	}
 }
 
+// NewMarketOrderItem creates an item of market order, which is filled at the best available prices in the next match
+func NewMarketOrderItem(product string, side string, quantity string) OrderItem {
+	return OrderItem{
+		Product:  product,
+		Side:     side,
+		Price:    sdk.ZeroDec(),
+		Quantity: sdk.MustNewDecFromStr(quantity),
+		Type:     OrderTypeMarket,
+	}
+}
+
+// NewTimeInForceOrderItem creates an item of limit order with the time in force. The expire height is only
+// required by GTB orders
+func NewTimeInForceOrderItem(product string, side string, price string, quantity string,
+	timeInForce string, expireHeight int64) OrderItem {
+	item := NewOrderItem(product, side, price, quantity)
+	item.TimeInForce = timeInForce
+	item.ExpireHeight = expireHeight
+	return item
+}
+
+// NewTriggerOrderItem creates an item of stop-loss or take-profit limit order, which is kept out of depth book until
+// the last price crosses the trigger price
+func NewTriggerOrderItem(product string, side string, price string, quantity string,
+	triggerType string, triggerPrice string) OrderItem {
+	item := NewOrderItem(product, side, price, quantity)
+	item.TriggerType = triggerType
+	item.TriggerPrice = sdk.MustNewDecFromStr(triggerPrice)
+	return item
+}
+
 // NewMsgNewOrders is a constructor function for MsgNewOrder
 func NewMsgNewOrders(sender sdk.AccAddress, orderItems []OrderItem) MsgNewOrders {
 	return MsgNewOrders{
@@ -101,6 +152,9 @@ func (msg MsgNewOrders) ValidateBasic() sdk.Error {
 	if msg.Sender.Empty() {
 		return sdk.ErrInvalidAddress(msg.Sender.String())
 	}
+	if msg.Grantee.Equals(msg.Sender) {
+		return sdk.ErrUnknownRequest("the grantee should not be the sender")
+	}
 	if msg.OrderItems == nil || len(msg.OrderItems) == 0 {
 		return sdk.ErrUnknownRequest("invalid OrderItems")
 	}
@@ -122,11 +176,97 @@ func (msg MsgNewOrders) ValidateBasic() sdk.Error {
 			return sdk.ErrUnknownRequest(
 				fmt.Sprintf("Side is expected to be \"BUY\" or \"SELL\", but got \"%s\"", item.Side))
 		}
-		if !(item.Price.IsPositive() && item.Quantity.IsPositive()) {
-			return sdk.ErrUnknownRequest("Price/Quantity must be positive")
+		switch item.Type {
+		case "", OrderTypeLimit:
+			if !(item.Price.IsPositive() && item.Quantity.IsPositive()) {
+				return sdk.ErrUnknownRequest("Price/Quantity must be positive")
+			}
+		case OrderTypeMarket:
+			if !item.Price.IsNil() && !item.Price.IsZero() {
+				return sdk.ErrUnknownRequest("Price of market order must be zero")
+			}
+			if !item.Quantity.IsPositive() {
+				return sdk.ErrUnknownRequest("Quantity must be positive")
+			}
+		default:
+			return sdk.ErrUnknownRequest(
+				fmt.Sprintf("Type is expected to be \"LIMIT\" or \"MARKET\", but got \"%s\"", item.Type))
+		}
+		if err := validateTimeInForce(item); err != nil {
+			return err
+		}
+		if err := validateTrigger(item); err != nil {
+			return err
+		}
+		if err := ValidateSelfTradePrevention(item.SelfTradePrevention); err != nil {
+			return err
+		}
+		if err := validateIceberg(item); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func validateTimeInForce(item OrderItem) sdk.Error {
+	switch item.TimeInForce {
+	case "", TimeInForceGTC, TimeInForceIOC, TimeInForceFOK:
+	case TimeInForcePostOnly:
+		if item.Type == OrderTypeMarket {
+			return sdk.ErrUnknownRequest("market order can not be post-only")
+		}
+	case TimeInForceGTB:
+		if item.Type == OrderTypeMarket {
+			return sdk.ErrUnknownRequest("market order can not be good till block")
+		}
+		if item.ExpireHeight <= 0 {
+			return sdk.ErrUnknownRequest("ExpireHeight of GTB order must be positive")
 		}
+		return nil
+	default:
+		return sdk.ErrUnknownRequest(fmt.Sprintf(
+			"TimeInForce is expected to be \"GTC\", \"IOC\", \"FOK\", \"POST_ONLY\" or \"GTB\", but got \"%s\"",
+			item.TimeInForce))
+	}
+	if item.ExpireHeight != 0 {
+		return sdk.ErrUnknownRequest("ExpireHeight is only allowed for GTB order")
 	}
+	return nil
+}
 
+func validateTrigger(item OrderItem) sdk.Error {
+	switch item.TriggerType {
+	case "":
+		if !item.TriggerPrice.IsNil() && !item.TriggerPrice.IsZero() {
+			return sdk.ErrUnknownRequest("TriggerPrice is only allowed for stop-loss or take-profit order")
+		}
+		return nil
+	case TriggerTypeStopLoss, TriggerTypeTakeProfit:
+	default:
+		return sdk.ErrUnknownRequest(fmt.Sprintf(
+			"TriggerType is expected to be \"STOP_LOSS\" or \"TAKE_PROFIT\", but got \"%s\"", item.TriggerType))
+	}
+	if item.TriggerPrice.IsNil() || !item.TriggerPrice.IsPositive() {
+		return sdk.ErrUnknownRequest("TriggerPrice must be positive")
+	}
+	if item.TimeInForce == TimeInForcePostOnly || item.TimeInForce == TimeInForceGTB {
+		return sdk.ErrUnknownRequest(
+			fmt.Sprintf("stop-loss or take-profit order can not be %s", item.TimeInForce))
+	}
+	return nil
+}
+
+func validateIceberg(item OrderItem) sdk.Error {
+	if item.DisplayQuantity.IsNil() || item.DisplayQuantity.IsZero() {
+		return nil
+	}
+	if item.Type == OrderTypeMarket || item.TimeInForce == TimeInForceIOC || item.TimeInForce == TimeInForceFOK {
+		return sdk.ErrUnknownRequest("iceberg order can not be market, IOC or FOK order")
+	}
+	if !item.DisplayQuantity.IsPositive() || item.DisplayQuantity.GTE(item.Quantity) {
+		return sdk.ErrUnknownRequest("DisplayQuantity of iceberg order must be positive and less than Quantity")
+	}
 	return nil
 }
 
@@ -136,8 +276,11 @@ func (msg MsgNewOrders) GetSignBytes() []byte {
 	return sdk.MustSortJSON(bz)
 }
 
-// GetSigners defines whose signature is required
+// GetSigners defines whose signature is required, the grantee signs for the sender if specified
 func (msg MsgNewOrders) GetSigners() []sdk.AccAddress {
+	if !msg.Grantee.Empty() {
+		return []sdk.AccAddress{msg.Grantee}
+	}
 	return []sdk.AccAddress{msg.Sender}
 }
 
@@ -145,6 +288,8 @@ func (msg MsgNewOrders) GetSigners() []sdk.AccAddress {
 type MsgCancelOrders struct {
 	Sender   sdk.AccAddress `json:"sender"` // order maker address
 	OrderIDs []string       `json:"order_ids"`
+	// the address trading on behalf of the sender by its trading grant, empty if the sender signs itself
+	Grantee sdk.AccAddress `json:"grantee,omitempty"`
 }
 
 // NewMsgCancelOrders is a constructor function for MsgCancelOrder
@@ -167,6 +312,9 @@ func (msg MsgCancelOrders) ValidateBasic() sdk.Error {
 	if msg.Sender.Empty() {
 		return sdk.ErrInvalidAddress(msg.Sender.String())
 	}
+	if msg.Grantee.Equals(msg.Sender) {
+		return sdk.ErrUnknownRequest("the grantee should not be the sender")
+	}
 	if msg.OrderIDs == nil || len(msg.OrderIDs) == 0 {
 		return sdk.ErrUnknownRequest("invalid OrderIDs")
 	}
@@ -203,11 +351,268 @@ func (msg MsgCancelOrders) GetSignBytes() []byte {
 	return sdk.MustSortJSON(bz)
 }
 
-// GetSigners defines whose signature is required
+// GetSigners defines whose signature is required, the grantee signs for the sender if specified
 func (msg MsgCancelOrders) GetSigners() []sdk.AccAddress {
+	if !msg.Grantee.Empty() {
+		return []sdk.AccAddress{msg.Grantee}
+	}
+	return []sdk.AccAddress{msg.Sender}
+}
+
+// ReplaceOrderItem amends the price and quantity of an open order. Quantity is the new total quantity of the
+// order, including the filled quantity
+type ReplaceOrderItem struct {
+	OrderID  string  `json:"order_id"`
+	Price    sdk.Dec `json:"price"`
+	Quantity sdk.Dec `json:"quantity"`
+}
+
+// NewReplaceOrderItem is a constructor function for ReplaceOrderItem
+func NewReplaceOrderItem(orderID string, price string, quantity string) ReplaceOrderItem {
+	return ReplaceOrderItem{
+		OrderID:  orderID,
+		Price:    sdk.MustNewDecFromStr(price),
+		Quantity: sdk.MustNewDecFromStr(quantity),
+	}
+}
+
+// MsgReplaceOrders atomically amends open orders without cancelling them
+type MsgReplaceOrders struct {
+	Sender       sdk.AccAddress     `json:"sender"` // order maker address
+	ReplaceItems []ReplaceOrderItem `json:"replace_items"`
+}
+
+// NewMsgReplaceOrders is a constructor function for MsgReplaceOrders
+func NewMsgReplaceOrders(sender sdk.AccAddress, replaceItems []ReplaceOrderItem) MsgReplaceOrders {
+	return MsgReplaceOrders{
+		Sender:       sender,
+		ReplaceItems: replaceItems,
+	}
+}
+
+// nolint
+func (msg MsgReplaceOrders) Route() string { return "order" }
+
+// nolint
+func (msg MsgReplaceOrders) Type() string { return "replace" }
+
+// nolint
+func (msg MsgReplaceOrders) ValidateBasic() sdk.Error {
+	if msg.Sender.Empty() {
+		return sdk.ErrInvalidAddress(msg.Sender.String())
+	}
+	if len(msg.ReplaceItems) == 0 {
+		return sdk.ErrUnknownRequest("invalid ReplaceItems")
+	}
+	if len(msg.ReplaceItems) > OrderItemLimit {
+		return sdk.ErrUnknownRequest("Numbers of ReplaceOrderItem should not be more than " + strconv.Itoa(OrderItemLimit))
+	}
+	orderIDs := make([]string, 0, len(msg.ReplaceItems))
+	for _, item := range msg.ReplaceItems {
+		if item.OrderID == "" {
+			return sdk.ErrUnauthorized("orderID cannot be empty")
+		}
+		if item.Price.IsNil() || !item.Price.IsPositive() {
+			return sdk.ErrUnknownRequest("Price must be positive")
+		}
+		if item.Quantity.IsNil() || !item.Quantity.IsPositive() {
+			return sdk.ErrUnknownRequest("Quantity must be positive")
+		}
+		orderIDs = append(orderIDs, item.OrderID)
+	}
+	if hasDuplicatedID(orderIDs) {
+		return sdk.ErrUnknownRequest("Duplicated order ids detected")
+	}
+	return nil
+}
+
+// GetSignBytes encodes the message for signing
+func (msg MsgReplaceOrders) GetSignBytes() []byte {
+	bz := ModuleCdc.MustMarshalJSON(msg)
+	return sdk.MustSortJSON(bz)
+}
+
+// GetSigners defines whose signature is required
+func (msg MsgReplaceOrders) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.Sender}
+}
+
+// MsgSetSelfTradePrevention sets the self-trade prevention mode of the sender account, which is applied to the
+// orders without their own mode. Empty mode allows self trades
+type MsgSetSelfTradePrevention struct {
+	Sender sdk.AccAddress `json:"sender"`
+	Mode   string         `json:"mode"` // CANCEL_NEWEST/CANCEL_OLDEST/DECREMENT_BOTH
+}
+
+// NewMsgSetSelfTradePrevention is a constructor function for MsgSetSelfTradePrevention
+func NewMsgSetSelfTradePrevention(sender sdk.AccAddress, mode string) MsgSetSelfTradePrevention {
+	return MsgSetSelfTradePrevention{
+		Sender: sender,
+		Mode:   mode,
+	}
+}
+
+// nolint
+func (msg MsgSetSelfTradePrevention) Route() string { return "order" }
+
+// nolint
+func (msg MsgSetSelfTradePrevention) Type() string { return "set_stp" }
+
+// nolint
+func (msg MsgSetSelfTradePrevention) ValidateBasic() sdk.Error {
+	if msg.Sender.Empty() {
+		return sdk.ErrInvalidAddress(msg.Sender.String())
+	}
+	return ValidateSelfTradePrevention(msg.Mode)
+}
+
+// GetSignBytes encodes the message for signing
+func (msg MsgSetSelfTradePrevention) GetSignBytes() []byte {
+	bz := ModuleCdc.MustMarshalJSON(msg)
+	return sdk.MustSortJSON(bz)
+}
+
+// GetSigners defines whose signature is required
+func (msg MsgSetSelfTradePrevention) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.Sender}
+}
+
+// MsgSetCancelAfter is the heartbeat of the dead man's switch. The open orders of the sender, or only the orders of
+// the product if specified, are cancelled at the deadline unless refreshed by another heartbeat before. Zero deadline
+// turns the switch off
+type MsgSetCancelAfter struct {
+	Sender   sdk.AccAddress `json:"sender"`
+	Product  string         `json:"product"`
+	Deadline int64          `json:"deadline"`
+}
+
+// NewMsgSetCancelAfter is a constructor function for MsgSetCancelAfter
+func NewMsgSetCancelAfter(sender sdk.AccAddress, product string, deadline int64) MsgSetCancelAfter {
+	return MsgSetCancelAfter{
+		Sender:   sender,
+		Product:  product,
+		Deadline: deadline,
+	}
+}
+
+// nolint
+func (msg MsgSetCancelAfter) Route() string { return "order" }
+
+// nolint
+func (msg MsgSetCancelAfter) Type() string { return "set_cancel_after" }
+
+// nolint
+func (msg MsgSetCancelAfter) ValidateBasic() sdk.Error {
+	if msg.Sender.Empty() {
+		return sdk.ErrInvalidAddress(msg.Sender.String())
+	}
+	if msg.Deadline < 0 {
+		return sdk.ErrUnknownRequest(fmt.Sprintf("Deadline should not be negative, but got %d", msg.Deadline))
+	}
+	return nil
+}
+
+// GetSignBytes encodes the message for signing
+func (msg MsgSetCancelAfter) GetSignBytes() []byte {
+	bz := ModuleCdc.MustMarshalJSON(msg)
+	return sdk.MustSortJSON(bz)
+}
+
+// GetSigners defines whose signature is required
+func (msg MsgSetCancelAfter) GetSigners() []sdk.AccAddress {
 	return []sdk.AccAddress{msg.Sender}
 }
 
+// MsgGrantTrading permits the grantee to place and cancel the orders of the granter. It replaces the previous grant
+// to the grantee, and resets the notional used
+type MsgGrantTrading struct {
+	Granter      sdk.AccAddress `json:"granter"`
+	Grantee      sdk.AccAddress `json:"grantee"`
+	Products     []string       `json:"products"`
+	NotionalCap  sdk.Dec        `json:"notional_cap"`
+	ExpireHeight int64          `json:"expire_height"`
+}
+
+// NewMsgGrantTrading is a constructor function for MsgGrantTrading
+func NewMsgGrantTrading(granter, grantee sdk.AccAddress, products []string, notionalCap sdk.Dec,
+	expireHeight int64) MsgGrantTrading {
+
+	return MsgGrantTrading{
+		Granter:      granter,
+		Grantee:      grantee,
+		Products:     products,
+		NotionalCap:  notionalCap,
+		ExpireHeight: expireHeight,
+	}
+}
+
+// nolint
+func (msg MsgGrantTrading) Route() string { return "order" }
+
+// nolint
+func (msg MsgGrantTrading) Type() string { return "grant_trading" }
+
+// nolint
+func (msg MsgGrantTrading) ValidateBasic() sdk.Error {
+	return validateTradingGrant(msg.Granter, msg.Grantee, msg.Products, msg.NotionalCap, msg.ExpireHeight)
+}
+
+// GetSignBytes encodes the message for signing
+func (msg MsgGrantTrading) GetSignBytes() []byte {
+	bz := ModuleCdc.MustMarshalJSON(msg)
+	return sdk.MustSortJSON(bz)
+}
+
+// GetSigners defines whose signature is required
+func (msg MsgGrantTrading) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.Granter}
+}
+
+// MsgRevokeTrading revokes the trading grant to the grantee
+type MsgRevokeTrading struct {
+	Granter sdk.AccAddress `json:"granter"`
+	Grantee sdk.AccAddress `json:"grantee"`
+}
+
+// NewMsgRevokeTrading is a constructor function for MsgRevokeTrading
+func NewMsgRevokeTrading(granter, grantee sdk.AccAddress) MsgRevokeTrading {
+	return MsgRevokeTrading{
+		Granter: granter,
+		Grantee: grantee,
+	}
+}
+
+// nolint
+func (msg MsgRevokeTrading) Route() string { return "order" }
+
+// nolint
+func (msg MsgRevokeTrading) Type() string { return "revoke_trading" }
+
+// nolint
+func (msg MsgRevokeTrading) ValidateBasic() sdk.Error {
+	if msg.Granter.Empty() {
+		return sdk.ErrInvalidAddress(msg.Granter.String())
+	}
+	if msg.Grantee.Empty() {
+		return sdk.ErrInvalidAddress(msg.Grantee.String())
+	}
+	if msg.Granter.Equals(msg.Grantee) {
+		return sdk.ErrUnknownRequest("the grantee should not be the granter")
+	}
+	return nil
+}
+
+// GetSignBytes encodes the message for signing
+func (msg MsgRevokeTrading) GetSignBytes() []byte {
+	bz := ModuleCdc.MustMarshalJSON(msg)
+	return sdk.MustSortJSON(bz)
+}
+
+// GetSigners defines whose signature is required
+func (msg MsgRevokeTrading) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.Granter}
+}
+
 // nolint
 type OrderResult struct {
 	Code    sdk.CodeType `json:"code"`    // order return code
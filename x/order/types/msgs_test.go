@@ -6,6 +6,7 @@ import (
 	"strconv"
 	"testing"
 
+	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/okex/okchain/x/common"
 
 	"github.com/stretchr/testify/require"
@@ -229,6 +230,276 @@ func TestMsgMultiNewOrderInvalid(t *testing.T) {
 	require.NotNil(t, err)
 }
 
+func TestMsgNewOrdersMarketOrder(t *testing.T) {
+	addr, err := hex.DecodeString("1212121212121212123412121212121212121234")
+	require.Nil(t, err)
+
+	// market order
+	item := NewMarketOrderItem("btc_"+common.NativeToken, BuyOrder, testQuantity)
+	orderMsg := NewMsgNewOrders(addr, []OrderItem{item})
+	require.Nil(t, orderMsg.ValidateBasic())
+	require.Contains(t, string(orderMsg.GetSignBytes()), `"type":"MARKET"`)
+
+	// market order without price
+	item.Price = sdk.Dec{}
+	orderMsg = NewMsgNewOrders(addr, []OrderItem{item})
+	require.Nil(t, orderMsg.ValidateBasic())
+
+	// limit order keeps the sign bytes without type
+	limitItem := NewOrderItem("btc_"+common.NativeToken, BuyOrder, testPrice, testQuantity)
+	orderMsg = NewMsgNewOrders(addr, []OrderItem{limitItem})
+	require.NotContains(t, string(orderMsg.GetSignBytes()), `"type":"LIMIT"`)
+	require.Contains(t, string(orderMsg.GetSignBytes()), `"side":"BUY"}`)
+	limitItem.Type = OrderTypeLimit
+	orderMsg = NewMsgNewOrders(addr, []OrderItem{limitItem})
+	require.Nil(t, orderMsg.ValidateBasic())
+
+	// market order with price
+	item = NewMarketOrderItem("btc_"+common.NativeToken, BuyOrder, testQuantity)
+	item.Price = sdk.MustNewDecFromStr(testPrice)
+	orderMsg = NewMsgNewOrders(addr, []OrderItem{item})
+	require.NotNil(t, orderMsg.ValidateBasic())
+
+	// market order with zero quantity
+	item = NewMarketOrderItem("btc_"+common.NativeToken, SellOrder, "0")
+	orderMsg = NewMsgNewOrders(addr, []OrderItem{item})
+	require.NotNil(t, orderMsg.ValidateBasic())
+
+	// invalid type
+	item = NewOrderItem("btc_"+common.NativeToken, BuyOrder, testPrice, testQuantity)
+	item.Type = "STOP"
+	orderMsg = NewMsgNewOrders(addr, []OrderItem{item})
+	require.NotNil(t, orderMsg.ValidateBasic())
+}
+
+func TestMsgNewOrdersTimeInForce(t *testing.T) {
+	addr, err := hex.DecodeString("1212121212121212123412121212121212121234")
+	require.Nil(t, err)
+	product := "btc_" + common.NativeToken
+
+	validItems := []OrderItem{
+		NewTimeInForceOrderItem(product, BuyOrder, testPrice, testQuantity, TimeInForceGTC, 0),
+		NewTimeInForceOrderItem(product, BuyOrder, testPrice, testQuantity, TimeInForceIOC, 0),
+		NewTimeInForceOrderItem(product, SellOrder, testPrice, testQuantity, TimeInForceFOK, 0),
+		NewTimeInForceOrderItem(product, SellOrder, testPrice, testQuantity, TimeInForcePostOnly, 0),
+		NewTimeInForceOrderItem(product, SellOrder, testPrice, testQuantity, TimeInForceGTB, 100),
+	}
+	for _, item := range validItems {
+		orderMsg := NewMsgNewOrders(addr, []OrderItem{item})
+		require.Nil(t, orderMsg.ValidateBasic(), item.TimeInForce)
+	}
+	orderMsg := NewMsgNewOrders(addr, validItems[4:])
+	require.Contains(t, string(orderMsg.GetSignBytes()), `"expire_height":"100"`)
+
+	// market orders can be IOC or FOK
+	item := NewMarketOrderItem(product, BuyOrder, testQuantity)
+	item.TimeInForce = TimeInForceFOK
+	require.Nil(t, NewMsgNewOrders(addr, []OrderItem{item}).ValidateBasic())
+
+	invalidItems := []OrderItem{
+		// unknown time in force
+		NewTimeInForceOrderItem(product, BuyOrder, testPrice, testQuantity, "GTD", 0),
+		// GTB order without expire height
+		NewTimeInForceOrderItem(product, BuyOrder, testPrice, testQuantity, TimeInForceGTB, 0),
+		// expire height of non-GTB order
+		NewTimeInForceOrderItem(product, BuyOrder, testPrice, testQuantity, TimeInForceIOC, 100),
+	}
+	item.TimeInForce = TimeInForcePostOnly
+	invalidItems = append(invalidItems, item)
+	item.TimeInForce, item.ExpireHeight = TimeInForceGTB, 100
+	invalidItems = append(invalidItems, item)
+	for _, item := range invalidItems {
+		orderMsg := NewMsgNewOrders(addr, []OrderItem{item})
+		require.NotNil(t, orderMsg.ValidateBasic(), item.TimeInForce)
+	}
+}
+
+func TestMsgNewOrdersTrigger(t *testing.T) {
+	addr, err := hex.DecodeString("1212121212121212123412121212121212121234")
+	require.Nil(t, err)
+	product := "btc_" + common.NativeToken
+
+	validItems := []OrderItem{
+		NewTriggerOrderItem(product, SellOrder, testPrice, testQuantity, TriggerTypeStopLoss, "9.0"),
+		NewTriggerOrderItem(product, BuyOrder, testPrice, testQuantity, TriggerTypeTakeProfit, "9.0"),
+	}
+	item := NewTriggerOrderItem(product, SellOrder, testPrice, testQuantity, TriggerTypeStopLoss, "9.0")
+	item.TimeInForce = TimeInForceIOC
+	validItems = append(validItems, item)
+	for _, item := range validItems {
+		orderMsg := NewMsgNewOrders(addr, []OrderItem{item})
+		require.Nil(t, orderMsg.ValidateBasic(), item.TriggerType)
+	}
+	orderMsg := NewMsgNewOrders(addr, validItems[:1])
+	require.Contains(t, string(orderMsg.GetSignBytes()), `"trigger_type":"STOP_LOSS"`)
+
+	invalidItems := []OrderItem{
+		// unknown trigger type
+		NewTriggerOrderItem(product, SellOrder, testPrice, testQuantity, "TRAILING_STOP", "9.0"),
+		// trigger price must be positive
+		NewTriggerOrderItem(product, SellOrder, testPrice, testQuantity, TriggerTypeStopLoss, "0"),
+	}
+	// trigger price without trigger type
+	item = NewTriggerOrderItem(product, SellOrder, testPrice, testQuantity, "", "9.0")
+	invalidItems = append(invalidItems, item)
+	// trigger orders can not be post-only or GTB
+	item = NewTriggerOrderItem(product, SellOrder, testPrice, testQuantity, TriggerTypeStopLoss, "9.0")
+	item.TimeInForce = TimeInForcePostOnly
+	invalidItems = append(invalidItems, item)
+	item.TimeInForce, item.ExpireHeight = TimeInForceGTB, 100
+	invalidItems = append(invalidItems, item)
+	for _, item := range invalidItems {
+		orderMsg := NewMsgNewOrders(addr, []OrderItem{item})
+		require.NotNil(t, orderMsg.ValidateBasic(), item.TriggerType)
+	}
+}
+
+func TestMsgReplaceOrders(t *testing.T) {
+	addr, err := hex.DecodeString("1212121212121212123412121212121212121234")
+	require.Nil(t, err)
+	items := []ReplaceOrderItem{
+		NewReplaceOrderItem(testOrderID, testPrice, testQuantity),
+		NewReplaceOrderItem(testOrderID+"1", testPrice, testQuantity),
+	}
+	orderMsg := NewMsgReplaceOrders(addr, items)
+	require.Nil(t, orderMsg.ValidateBasic())
+	require.Equal(t, "order", orderMsg.Route())
+	require.Equal(t, "replace", orderMsg.Type())
+	require.Contains(t, string(orderMsg.GetSignBytes()), `"replace_items"`)
+	require.EqualValues(t, addr, orderMsg.GetSigners()[0])
+
+	invalidMsgs := []MsgReplaceOrders{
+		// empty sender
+		NewMsgReplaceOrders(nil, items),
+		// empty items
+		NewMsgReplaceOrders(addr, nil),
+		// duplicated order ids
+		NewMsgReplaceOrders(addr, []ReplaceOrderItem{items[0], items[0]}),
+		// empty order id
+		NewMsgReplaceOrders(addr, []ReplaceOrderItem{NewReplaceOrderItem("", testPrice, testQuantity)}),
+		// zero price
+		NewMsgReplaceOrders(addr, []ReplaceOrderItem{NewReplaceOrderItem(testOrderID, "0", testQuantity)}),
+		// negative quantity
+		NewMsgReplaceOrders(addr, []ReplaceOrderItem{NewReplaceOrderItem(testOrderID, testPrice, "-1")}),
+	}
+	for i, msg := range invalidMsgs {
+		require.NotNil(t, msg.ValidateBasic(), i)
+	}
+}
+
+func TestMsgSelfTradePrevention(t *testing.T) {
+	addr, err := hex.DecodeString("1212121212121212123412121212121212121234")
+	require.Nil(t, err)
+	product := "btc_" + common.NativeToken
+
+	item := NewOrderItem(product, SellOrder, testPrice, testQuantity)
+	for _, mode := range []string{"", SelfTradePreventionCancelNewest, SelfTradePreventionCancelOldest,
+		SelfTradePreventionDecrementBoth} {
+		item.SelfTradePrevention = mode
+		require.Nil(t, NewMsgNewOrders(addr, []OrderItem{item}).ValidateBasic(), mode)
+		require.Nil(t, NewMsgSetSelfTradePrevention(addr, mode).ValidateBasic(), mode)
+	}
+	item.SelfTradePrevention = "CANCEL_BOTH"
+	require.NotNil(t, NewMsgNewOrders(addr, []OrderItem{item}).ValidateBasic())
+
+	msg := NewMsgSetSelfTradePrevention(addr, SelfTradePreventionCancelOldest)
+	require.Equal(t, "order", msg.Route())
+	require.Equal(t, "set_stp", msg.Type())
+	require.Contains(t, string(msg.GetSignBytes()), `"mode":"CANCEL_OLDEST"`)
+	require.EqualValues(t, addr, msg.GetSigners()[0])
+	require.NotNil(t, NewMsgSetSelfTradePrevention(nil, SelfTradePreventionCancelOldest).ValidateBasic())
+	require.NotNil(t, NewMsgSetSelfTradePrevention(addr, "cancel_oldest").ValidateBasic())
+}
+
+func TestMsgNewOrdersIceberg(t *testing.T) {
+	addr, err := hex.DecodeString("1212121212121212123412121212121212121234")
+	require.Nil(t, err)
+	product := "btc_" + common.NativeToken
+
+	item := NewOrderItem(product, SellOrder, "10.0", "5.0")
+	require.NotContains(t, string(NewMsgNewOrders(addr, []OrderItem{item}).GetSignBytes()), "display_quantity")
+	item.DisplayQuantity = sdk.MustNewDecFromStr("1.0")
+	require.Nil(t, NewMsgNewOrders(addr, []OrderItem{item}).ValidateBasic())
+	item.TimeInForce = TimeInForcePostOnly
+	require.Nil(t, NewMsgNewOrders(addr, []OrderItem{item}).ValidateBasic())
+
+	// the display quantity should be positive and less than the quantity
+	item.TimeInForce = ""
+	for _, display := range []string{"-1.0", "5.0", "6.0"} {
+		item.DisplayQuantity = sdk.MustNewDecFromStr(display)
+		require.NotNil(t, NewMsgNewOrders(addr, []OrderItem{item}).ValidateBasic(), display)
+	}
+
+	// the iceberg order rests in depth book
+	item.DisplayQuantity = sdk.MustNewDecFromStr("1.0")
+	for _, timeInForce := range []string{TimeInForceIOC, TimeInForceFOK} {
+		item.TimeInForce = timeInForce
+		require.NotNil(t, NewMsgNewOrders(addr, []OrderItem{item}).ValidateBasic(), timeInForce)
+	}
+	item.TimeInForce = ""
+	marketItem := NewMarketOrderItem(product, SellOrder, "5.0")
+	marketItem.DisplayQuantity = item.DisplayQuantity
+	require.NotNil(t, NewMsgNewOrders(addr, []OrderItem{marketItem}).ValidateBasic())
+}
+
+func TestMsgSetCancelAfter(t *testing.T) {
+	addr, err := hex.DecodeString("1212121212121212123412121212121212121234")
+	require.Nil(t, err)
+
+	msg := NewMsgSetCancelAfter(addr, TestTokenPair, 100)
+	require.Nil(t, msg.ValidateBasic())
+	require.Equal(t, "order", msg.Route())
+	require.Equal(t, "set_cancel_after", msg.Type())
+	require.Contains(t, string(msg.GetSignBytes()), `"deadline":"100"`)
+	require.EqualValues(t, addr, msg.GetSigners()[0])
+
+	require.Nil(t, NewMsgSetCancelAfter(addr, "", 0).ValidateBasic())
+	require.NotNil(t, NewMsgSetCancelAfter(addr, "", -1).ValidateBasic())
+	require.NotNil(t, NewMsgSetCancelAfter(nil, "", 100).ValidateBasic())
+}
+
+func TestMsgGrantTrading(t *testing.T) {
+	granter, err := hex.DecodeString("1212121212121212123412121212121212121234")
+	require.Nil(t, err)
+	grantee, err := hex.DecodeString("3434343434343434343434343434343434343434")
+	require.Nil(t, err)
+
+	msg := NewMsgGrantTrading(granter, grantee, []string{TestTokenPair}, sdk.NewDec(100), 20)
+	require.Nil(t, msg.ValidateBasic())
+	require.Equal(t, "order", msg.Route())
+	require.Equal(t, "grant_trading", msg.Type())
+	require.EqualValues(t, granter, msg.GetSigners()[0])
+	require.Nil(t, NewMsgGrantTrading(granter, grantee, nil, sdk.ZeroDec(), 0).ValidateBasic())
+	require.NotNil(t, NewMsgGrantTrading(granter, granter, nil, sdk.ZeroDec(), 0).ValidateBasic())
+	require.NotNil(t, NewMsgGrantTrading(granter, nil, nil, sdk.ZeroDec(), 0).ValidateBasic())
+	require.NotNil(t, NewMsgGrantTrading(granter, grantee, []string{""}, sdk.ZeroDec(), 0).ValidateBasic())
+	require.NotNil(t, NewMsgGrantTrading(granter, grantee, nil, sdk.NewDec(-1), 0).ValidateBasic())
+	require.NotNil(t, NewMsgGrantTrading(granter, grantee, nil, sdk.ZeroDec(), -1).ValidateBasic())
+
+	revokeMsg := NewMsgRevokeTrading(granter, grantee)
+	require.Nil(t, revokeMsg.ValidateBasic())
+	require.Equal(t, "revoke_trading", revokeMsg.Type())
+	require.EqualValues(t, granter, revokeMsg.GetSigners()[0])
+	require.NotNil(t, NewMsgRevokeTrading(granter, granter).ValidateBasic())
+
+	// the grantee signs the order msgs on behalf of the granter
+	newMsg := NewMsgNewOrders(granter, []OrderItem{NewOrderItem(TestTokenPair, BuyOrder, "10.0", "1.0")})
+	require.NotContains(t, string(newMsg.GetSignBytes()), "grantee")
+	newMsg.Grantee = grantee
+	require.Nil(t, newMsg.ValidateBasic())
+	require.EqualValues(t, grantee, newMsg.GetSigners()[0])
+	newMsg.Grantee = granter
+	require.NotNil(t, newMsg.ValidateBasic())
+
+	cancelMsg := NewMsgCancelOrders(granter, []string{testOrderID})
+	require.NotContains(t, string(cancelMsg.GetSignBytes()), "grantee")
+	cancelMsg.Grantee = grantee
+	require.Nil(t, cancelMsg.ValidateBasic())
+	require.EqualValues(t, grantee, cancelMsg.GetSigners()[0])
+	cancelMsg.Grantee = granter
+	require.NotNil(t, cancelMsg.ValidateBasic())
+}
+
 func TestMsgMultiCancelOrder(t *testing.T) {
 	orderID := testOrderID
 	addr, err := hex.DecodeString("1212121212121212123412121212121212121234")
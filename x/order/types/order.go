@@ -20,6 +20,14 @@ const (
 	Expired
 	PartialFilledCancelled
 	PartialFilledExpired
+	_ // reserved for PartialFilled
+	IOCCancelled
+	PartialFilledIOCCancelled
+	FOKKilled
+	PostOnlyRejected
+	GTBExpired
+	PartialFilledGTBExpired
+	Untriggered
 )
 
 func (p OrderStatus) String() string {
@@ -36,6 +44,20 @@ func (p OrderStatus) String() string {
 		return "PartialFilledCancelled"
 	case PartialFilledExpired:
 		return "PartialFilledExpired"
+	case IOCCancelled:
+		return "IOCCancelled"
+	case PartialFilledIOCCancelled:
+		return "PartialFilledIOCCancelled"
+	case FOKKilled:
+		return "FOKKilled"
+	case PostOnlyRejected:
+		return "PostOnlyRejected"
+	case GTBExpired:
+		return "GTBExpired"
+	case PartialFilledGTBExpired:
+		return "PartialFilledGTBExpired"
+	case Untriggered:
+		return "Untriggered"
 	default:
 		return "Unknown"
 	}
@@ -50,6 +72,34 @@ const (
 	OrderStatusPartialFilledCancelled = 4
 	OrderStatusPartialFilledExpired   = 5
 	//OrderStatusPartialFilled          = 6
+	OrderStatusIOCCancelled              = 7
+	OrderStatusPartialFilledIOCCancelled = 8
+	OrderStatusFOKKilled                 = 9
+	OrderStatusPostOnlyRejected          = 10
+	OrderStatusGTBExpired                = 11
+	OrderStatusPartialFilledGTBExpired   = 12
+	OrderStatusUntriggered               = 13
+)
+
+// nolint
+const (
+	OrderTypeLimit  = "LIMIT"
+	OrderTypeMarket = "MARKET"
+)
+
+// nolint
+const (
+	TimeInForceGTC      = "GTC"       // good till cancelled, empty means GTC
+	TimeInForceIOC      = "IOC"       // immediate or cancel, the remainder is cancelled after the first match
+	TimeInForceFOK      = "FOK"       // fill or kill, the order is killed unless fully filled in the first match
+	TimeInForcePostOnly = "POST_ONLY" // the order is rejected if it would be filled in the first match
+	TimeInForceGTB      = "GTB"       // good till block, the order expires after the match of the expire height
+)
+
+// nolint
+const (
+	TriggerTypeStopLoss   = "STOP_LOSS"
+	TriggerTypeTakeProfit = "TAKE_PROFIT"
 )
 
 // nolint
@@ -77,7 +127,35 @@ type Order struct {
 	Timestamp         int64          `json:"timestamp"`        // created timestamp
 	OrderExpireBlocks int64          `json:"order_expire_blocks"`
 	FeePerBlock       sdk.DecCoin    `json:"fee_per_block"`
-	ExtraInfo         string         `json:"extra_info"` // extra info of order in json format
+	ExtraInfo         string         `json:"extra_info"`              // extra info of order in json format
+	Type              string         `json:"type,omitempty"`          // LIMIT/MARKET, empty means LIMIT
+	TimeInForce       string         `json:"time_in_force,omitempty"` // GTC/IOC/FOK/POST_ONLY/GTB, empty means GTC
+	ExpireHeight      int64          `json:"expire_height,omitempty"` // the last block height of GTB order
+	Trigger           *OrderTrigger  `json:"trigger,omitempty"`       // trigger of stop-loss/take-profit order
+	// CANCEL_NEWEST/CANCEL_OLDEST/DECREMENT_BOTH, empty means the mode of the sender account
+	SelfTradePrevention string `json:"stp,omitempty"`
+	// the visible slice of iceberg order in depth book, empty means the whole order is visible
+	DisplayQuantity *sdk.Dec `json:"display_quantity,omitempty"`
+	// the grantee placing the order on behalf of the sender, empty means the sender placed it
+	Grantee sdk.AccAddress `json:"grantee,omitempty"`
+}
+
+// OrderTrigger is the trigger condition of a stop-loss or take-profit order. The order is kept out of depth book
+// until the last price of the product crosses the trigger price
+type OrderTrigger struct {
+	Type            string  `json:"type"`             // STOP_LOSS/TAKE_PROFIT
+	Price           sdk.Dec `json:"price"`            // trigger price
+	TriggeredHeight int64   `json:"triggered_height"` // the block height when triggered, 0 means untriggered
+}
+
+// IsTriggeredBy returns true if the last price crosses the trigger price. A sell stop-loss order and a buy
+// take-profit order are triggered when the last price falls to the trigger price, the others are triggered when
+// the last price rises to the trigger price
+func (trigger OrderTrigger) IsTriggeredBy(side string, lastPrice sdk.Dec) bool {
+	if (side == SellOrder) == (trigger.Type == TriggerTypeStopLoss) {
+		return lastPrice.LTE(trigger.Price)
+	}
+	return lastPrice.GTE(trigger.Price)
 }
 
 // nolint
@@ -104,6 +182,61 @@ func NewOrder(txHash string, sender sdk.AccAddress, product, side string, price,
 	return order
 }
 
+// NewMarketOrder creates a market order. The price of a market order is the worst price it accepts, which is
+// calculated from the best opposite price and the slippage cap, so the buyer locks price*quantity at most
+func NewMarketOrder(txHash string, sender sdk.AccAddress, product, side string, price, quantity sdk.Dec,
+	timestamp int64, orderExpireBlocks int64, feePerBlock sdk.DecCoin) *Order {
+	order := NewOrder(txHash, sender, product, side, price, quantity, timestamp, orderExpireBlocks, feePerBlock)
+	order.Type = OrderTypeMarket
+	return order
+}
+
+// IsMarketOrder returns true if the order is a market order
+func (order *Order) IsMarketOrder() bool {
+	return order.Type == OrderTypeMarket
+}
+
+// IsImmediateOrder returns true if the remainder of the order is quit right after its first match,
+// which includes market orders, IOC orders and FOK orders
+func (order *Order) IsImmediateOrder() bool {
+	return order.IsMarketOrder() || order.TimeInForce == TimeInForceIOC || order.TimeInForce == TimeInForceFOK
+}
+
+// IsUntriggered returns true if the order is a stop-loss or take-profit order waiting for its trigger
+func (order *Order) IsUntriggered() bool {
+	return order.Status == OrderStatusUntriggered
+}
+
+// TriggerAt turns an untriggered order into a normal open order at the block height
+func (order *Order) TriggerAt(blockHeight int64) {
+	order.Status = OrderStatusOpen
+	order.Trigger.TriggeredHeight = blockHeight
+}
+
+// IsIcebergOrder returns true if only a slice of the order is visible in depth book
+func (order *Order) IsIcebergOrder() bool {
+	return order.DisplayQuantity != nil && order.DisplayQuantity.IsPositive()
+}
+
+// VisibleQuantity returns the remaining quantity of the order visible in depth book. The visible slice of an
+// iceberg order is refreshed from its hidden reserve after each fill, until the reserve runs out
+func (order *Order) VisibleQuantity() sdk.Dec {
+	if order.IsIcebergOrder() {
+		return sdk.MinDec(*order.DisplayQuantity, order.RemainQuantity)
+	}
+	return order.RemainQuantity
+}
+
+// HiddenQuantity returns the remaining quantity of the order hidden from depth book
+func (order *Order) HiddenQuantity() sdk.Dec {
+	return order.RemainQuantity.Sub(order.VisibleQuantity())
+}
+
+// IsGTBOrder returns true if the order is good till block
+func (order *Order) IsGTBOrder() bool {
+	return order.TimeInForce == TimeInForceGTB
+}
+
 func (order *Order) String() string {
 	if orderJSON, err := json.Marshal(order); err != nil {
 		panic(err)
@@ -216,6 +349,34 @@ func (order *Order) Expire() {
 	}
 }
 
+// IOCCancel cancels the remainder of an IOC order after its first match
+func (order *Order) IOCCancel() {
+	if order.RemainQuantity.Equal(order.Quantity) {
+		order.Status = OrderStatusIOCCancelled
+	} else {
+		order.Status = OrderStatusPartialFilledIOCCancelled
+	}
+}
+
+// FOKKill kills a FOK order which can not be fully filled in its first match
+func (order *Order) FOKKill() {
+	order.Status = OrderStatusFOKKilled
+}
+
+// PostOnlyReject rejects a post-only order which would be filled in its first match
+func (order *Order) PostOnlyReject() {
+	order.Status = OrderStatusPostOnlyRejected
+}
+
+// GTBExpire expires a GTB order after the match of its expire height
+func (order *Order) GTBExpire() {
+	if order.RemainQuantity.Equal(order.Quantity) {
+		order.Status = OrderStatusGTBExpired
+	} else {
+		order.Status = OrderStatusPartialFilledGTBExpired
+	}
+}
+
 // NeedLockCoins : when place a new order, we should lock the coins of sender
 func (order *Order) NeedLockCoins() sdk.DecCoins {
 	if order.Side == BuyOrder {
@@ -267,6 +428,15 @@ func MockOrder(orderID, product, side, price, quantity string) *Order {
 	return order
 }
 
+// GetMarketOrderPrice returns the worst price which a market order accepts. The reference price moves
+// by the slippage to the unfavorable direction, and is rounded to the price precision of token pair
+func GetMarketOrderPrice(side string, refPrice, slippage sdk.Dec, pricePrecision int64) sdk.Dec {
+	if side == BuyOrder {
+		return refPrice.Mul(sdk.OneDec().Add(slippage)).RoundDecimal(pricePrecision)
+	}
+	return refPrice.Mul(sdk.OneDec().Sub(slippage)).RoundDecimal(pricePrecision)
+}
+
 // nolint
 func FormatOrderID(blockHeight, orderNum int64) string {
 	format := "ID%010d-%d"
@@ -289,3 +459,18 @@ func GetBlockHeightFromOrderID(orderID string) int64 {
 
 	return blockHeight
 }
+
+// IsOrderArrivedBefore returns true if the order of orderID was placed before the order of otherID
+func IsOrderArrivedBefore(orderID, otherID string) bool {
+	var height, otherHeight, num, otherNum int64
+	if _, err := fmt.Sscanf(orderID, "ID%d-%d", &height, &num); err != nil {
+		return orderID < otherID
+	}
+	if _, err := fmt.Sscanf(otherID, "ID%d-%d", &otherHeight, &otherNum); err != nil {
+		return orderID < otherID
+	}
+	if height != otherHeight {
+		return height < otherHeight
+	}
+	return num < otherNum
+}
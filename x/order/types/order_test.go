@@ -31,9 +31,31 @@ func TestNewOrder(t *testing.T) {
 	require.Equal(t, expected, order1.String())
 
 	order1.Status = 10
+	require.Equal(t, "PostOnlyRejected", OrderStatus(order1.Status).String())
+	order1.Status = 100
 	require.Equal(t, "Unknown", OrderStatus(order1.Status).String())
 }
 
+func TestNewMarketOrder(t *testing.T) {
+	params := DefaultParams()
+	price := GetMarketOrderPrice(BuyOrder, sdk.MustNewDecFromStr("10.0"), params.MaxMarketOrderSlippage, 8)
+	require.Equal(t, sdk.MustNewDecFromStr("10.5"), price)
+	order := NewMarketOrder("hash1", nil, TestTokenPair, BuyOrder, price,
+		sdk.MustNewDecFromStr("2.0"), 123, params.OrderExpireBlocks, params.FeePerBlock)
+	require.True(t, order.IsMarketOrder())
+	// buy market order locks the quote token by the worst price it accepts
+	require.Equal(t, sdk.MustNewDecFromStr("21"), order.RemainLocked)
+
+	price = GetMarketOrderPrice(SellOrder, sdk.MustNewDecFromStr("0.3333"), params.MaxMarketOrderSlippage, 4)
+	require.Equal(t, sdk.MustNewDecFromStr("0.3166"), price)
+	order = NewMarketOrder("hash2", nil, TestTokenPair, SellOrder, price,
+		sdk.MustNewDecFromStr("2.0"), 123, params.OrderExpireBlocks, params.FeePerBlock)
+	require.True(t, order.IsMarketOrder())
+	require.Equal(t, sdk.MustNewDecFromStr("2"), order.RemainLocked)
+
+	require.False(t, MockOrder("", TestTokenPair, BuyOrder, "10.0", "1.0").IsMarketOrder())
+}
+
 func TestOrderUpdateExtraInfo(t *testing.T) {
 	order := MockOrder("", "", SellOrder, "0.1", "10.0")
 	order.setExtraInfoWithKeyValue(OrderExtraInfoKeyCancelFee, "0.002"+common.NativeToken)
@@ -128,6 +150,99 @@ func TestOrderExpire(t *testing.T) {
 	require.EqualValues(t, "PartialFilledExpired", OrderStatus(order2.Status).String())
 }
 
+func TestOrderQuitByTimeInForce(t *testing.T) {
+	order := MockOrder("", TestTokenPair, BuyOrder, "0.1", "10.0")
+	order.TimeInForce = TimeInForceIOC
+	require.True(t, order.IsImmediateOrder())
+	order.IOCCancel()
+	require.EqualValues(t, OrderStatusIOCCancelled, order.Status)
+	require.EqualValues(t, "IOCCancelled", OrderStatus(order.Status).String())
+
+	order = MockOrder("", TestTokenPair, BuyOrder, "0.1", "10.0")
+	order.Fill(sdk.MustNewDecFromStr("0.1"), sdk.MustNewDecFromStr("5"))
+	order.IOCCancel()
+	require.EqualValues(t, OrderStatusPartialFilledIOCCancelled, order.Status)
+	require.EqualValues(t, "PartialFilledIOCCancelled", OrderStatus(order.Status).String())
+
+	order = MockOrder("", TestTokenPair, SellOrder, "0.1", "10.0")
+	order.TimeInForce = TimeInForceFOK
+	require.True(t, order.IsImmediateOrder())
+	order.FOKKill()
+	require.EqualValues(t, OrderStatusFOKKilled, order.Status)
+	require.EqualValues(t, "FOKKilled", OrderStatus(order.Status).String())
+
+	order = MockOrder("", TestTokenPair, SellOrder, "0.1", "10.0")
+	order.TimeInForce = TimeInForcePostOnly
+	require.False(t, order.IsImmediateOrder())
+	order.PostOnlyReject()
+	require.EqualValues(t, OrderStatusPostOnlyRejected, order.Status)
+
+	order = MockOrder("", TestTokenPair, SellOrder, "0.1", "10.0")
+	order.TimeInForce = TimeInForceGTB
+	require.True(t, order.IsGTBOrder())
+	require.False(t, order.IsImmediateOrder())
+	order.GTBExpire()
+	require.EqualValues(t, OrderStatusGTBExpired, order.Status)
+	require.EqualValues(t, "GTBExpired", OrderStatus(order.Status).String())
+
+	order = MockOrder("", TestTokenPair, SellOrder, "0.1", "10.0")
+	order.Fill(sdk.MustNewDecFromStr("0.1"), sdk.MustNewDecFromStr("5"))
+	order.GTBExpire()
+	require.EqualValues(t, OrderStatusPartialFilledGTBExpired, order.Status)
+	require.EqualValues(t, "PartialFilledGTBExpired", OrderStatus(order.Status).String())
+}
+
+func TestOrderTrigger(t *testing.T) {
+	stopLoss := OrderTrigger{Type: TriggerTypeStopLoss, Price: sdk.MustNewDecFromStr("10.0")}
+	takeProfit := OrderTrigger{Type: TriggerTypeTakeProfit, Price: sdk.MustNewDecFromStr("10.0")}
+	low, high := sdk.MustNewDecFromStr("9.9"), sdk.MustNewDecFromStr("10.1")
+
+	// sell stop-loss and buy take-profit orders are triggered when the price falls
+	require.True(t, stopLoss.IsTriggeredBy(SellOrder, low))
+	require.True(t, stopLoss.IsTriggeredBy(SellOrder, stopLoss.Price))
+	require.False(t, stopLoss.IsTriggeredBy(SellOrder, high))
+	require.True(t, takeProfit.IsTriggeredBy(BuyOrder, low))
+	require.False(t, takeProfit.IsTriggeredBy(BuyOrder, high))
+
+	// buy stop-loss and sell take-profit orders are triggered when the price rises
+	require.True(t, stopLoss.IsTriggeredBy(BuyOrder, high))
+	require.True(t, stopLoss.IsTriggeredBy(BuyOrder, stopLoss.Price))
+	require.False(t, stopLoss.IsTriggeredBy(BuyOrder, low))
+	require.True(t, takeProfit.IsTriggeredBy(SellOrder, high))
+	require.False(t, takeProfit.IsTriggeredBy(SellOrder, low))
+
+	order := MockOrder("", TestTokenPair, SellOrder, "9.5", "1.0")
+	require.False(t, order.IsUntriggered())
+	order.Status = OrderStatusUntriggered
+	order.Trigger = &stopLoss
+	require.True(t, order.IsUntriggered())
+	require.EqualValues(t, "Untriggered", OrderStatus(order.Status).String())
+	order.TriggerAt(10)
+	require.False(t, order.IsUntriggered())
+	require.EqualValues(t, OrderStatusOpen, order.Status)
+	require.EqualValues(t, 10, order.Trigger.TriggeredHeight)
+}
+
+func TestOrderIceberg(t *testing.T) {
+	order := MockOrder("", TestTokenPair, SellOrder, "10.0", "5.0")
+	require.False(t, order.IsIcebergOrder())
+	require.EqualValues(t, sdk.MustNewDecFromStr("5.0"), order.VisibleQuantity())
+	require.True(t, order.HiddenQuantity().IsZero())
+
+	// the visible slice is refreshed from the hidden reserve after each fill
+	display := sdk.MustNewDecFromStr("2.0")
+	order.DisplayQuantity = &display
+	require.True(t, order.IsIcebergOrder())
+	require.EqualValues(t, sdk.MustNewDecFromStr("2.0"), order.VisibleQuantity())
+	require.EqualValues(t, sdk.MustNewDecFromStr("3.0"), order.HiddenQuantity())
+	order.Fill(sdk.MustNewDecFromStr("10.0"), sdk.MustNewDecFromStr("1.0"))
+	require.EqualValues(t, sdk.MustNewDecFromStr("2.0"), order.VisibleQuantity())
+	require.EqualValues(t, sdk.MustNewDecFromStr("2.0"), order.HiddenQuantity())
+	order.Fill(sdk.MustNewDecFromStr("10.0"), sdk.MustNewDecFromStr("2.5"))
+	require.EqualValues(t, sdk.MustNewDecFromStr("1.5"), order.VisibleQuantity())
+	require.True(t, order.HiddenQuantity().IsZero())
+}
+
 func TestOrderNeedLockCoins(t *testing.T) {
 	order := MockOrder("", TestTokenPair, BuyOrder, "0.1", "10.0")
 	decCoins := order.NeedLockCoins()
@@ -166,3 +281,10 @@ func TestGetBlockHeightFromOrderID(t *testing.T) {
 	num = GetBlockHeightFromOrderID(orderID)
 	require.Equal(t, blockHeight, num)
 }
+
+func TestIsOrderArrivedBefore(t *testing.T) {
+	require.True(t, IsOrderArrivedBefore(FormatOrderID(9, 2), FormatOrderID(10, 1)))
+	require.True(t, IsOrderArrivedBefore(FormatOrderID(10, 2), FormatOrderID(10, 10)))
+	require.False(t, IsOrderArrivedBefore(FormatOrderID(10, 10), FormatOrderID(10, 2)))
+	require.False(t, IsOrderArrivedBefore(FormatOrderID(99999999990, 1), FormatOrderID(10, 1)))
+}
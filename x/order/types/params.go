@@ -18,15 +18,43 @@ const (
 	DefaultFeeAmountPerBlock = "0.000001" // okt
 	DefaultFeeDenomPerBlock  = common.NativeToken
 	DefaultFeeRateTrade      = "0.001" // percentage
+
+	// Market order param
+	DefaultMaxMarketOrderSlippage = "0.05" // market orders are filled at most 5% away from the best opposite price
+
+	// Allocation rules of the orders at the marginal price of periodic auction
+	AllocationRuleFIFO     = "FIFO"      // earliest order first
+	AllocationRuleProRata  = "PRO_RATA"  // in proportion to the remaining quantity
+	AllocationRuleSizeTime = "SIZE_TIME" // in proportion to the remaining quantity times the blocks queued
+	DefaultAllocationRule  = AllocationRuleFIFO
+
+	// Price protection param of periodic auction
+	DefaultPriceBand            = "0"    // no price band, the clearing price is never limited
+	DefaultCircuitBreakerBlocks = 10     // matching halts for 10 blocks once the price band is broken
+	DefaultMarketPressureRate   = "0.05" // reference price moves 5% under one side pressure
+
+	// Policies of sharing the deal budget of a block among the products of periodic auction
+	DealBudgetPolicySequential = "SEQUENTIAL" // products spend the budget in turn, sorted by dex deposits
+	DealBudgetPolicyFair       = "FAIR"       // a minimum for every product, the rest is shared by demand
+	DefaultDealBudgetPolicy    = DealBudgetPolicyFair
+	DefaultMinDealsPerProduct  = 10
 )
 
 // nolint : Parameter keys
 var (
-	KeyOrderExpireBlocks = []byte("OrderExpireBlocks")
-	KeyMaxDealsPerBlock  = []byte("MaxDealsPerBlock")
-	KeyFeePerBlock       = []byte("FeePerBlock")
-	KeyTradeFeeRate      = []byte("TradeFeeRate")
-	DefaultFeePerBlock   = sdk.NewDecCoinFromDec(DefaultFeeDenomPerBlock, sdk.MustNewDecFromStr(DefaultFeeAmountPerBlock))
+	KeyOrderExpireBlocks      = []byte("OrderExpireBlocks")
+	KeyMaxDealsPerBlock       = []byte("MaxDealsPerBlock")
+	KeyFeePerBlock            = []byte("FeePerBlock")
+	KeyTradeFeeRate           = []byte("TradeFeeRate")
+	KeyMaxMarketOrderSlippage = []byte("MaxMarketOrderSlippage")
+	KeyAllocationRule         = []byte("AllocationRule")
+	KeyPriceBand              = []byte("PriceBand")
+	KeyCircuitBreakerBlocks   = []byte("CircuitBreakerBlocks")
+	KeyMarketPressureRate     = []byte("MarketPressureRate")
+	KeyFeeSchedules           = []byte("FeeSchedules")
+	KeyDealBudgetPolicy       = []byte("DealBudgetPolicy")
+	KeyMinDealsPerProduct     = []byte("MinDealsPerProduct")
+	DefaultFeePerBlock        = sdk.NewDecCoinFromDec(DefaultFeeDenomPerBlock, sdk.MustNewDecFromStr(DefaultFeeAmountPerBlock))
 )
 
 // nolint
@@ -38,6 +66,18 @@ type Params struct {
 	MaxDealsPerBlock  int64       `json:"max_deals_per_block"`
 	FeePerBlock       sdk.DecCoin `json:"fee_per_block"`
 	TradeFeeRate      sdk.Dec     `json:"trade_fee_rate"`
+
+	MaxMarketOrderSlippage sdk.Dec `json:"max_market_order_slippage"`
+	AllocationRule         string  `json:"allocation_rule"`
+
+	PriceBand            sdk.Dec `json:"price_band"`
+	CircuitBreakerBlocks int64   `json:"circuit_breaker_blocks"`
+	MarketPressureRate   sdk.Dec `json:"market_pressure_rate"`
+
+	FeeSchedules []FeeSchedule `json:"fee_schedules"`
+
+	DealBudgetPolicy   string `json:"deal_budget_policy"`
+	MinDealsPerProduct int64  `json:"min_deals_per_product"`
 }
 
 // ParamKeyTable for auth module
@@ -54,6 +94,14 @@ func (p *Params) ParamSetPairs() params.ParamSetPairs {
 		{KeyMaxDealsPerBlock, &p.MaxDealsPerBlock},
 		{KeyFeePerBlock, &p.FeePerBlock},
 		{KeyTradeFeeRate, &p.TradeFeeRate},
+		{KeyMaxMarketOrderSlippage, &p.MaxMarketOrderSlippage},
+		{KeyAllocationRule, &p.AllocationRule},
+		{KeyPriceBand, &p.PriceBand},
+		{KeyCircuitBreakerBlocks, &p.CircuitBreakerBlocks},
+		{KeyMarketPressureRate, &p.MarketPressureRate},
+		{KeyFeeSchedules, &p.FeeSchedules},
+		{KeyDealBudgetPolicy, &p.DealBudgetPolicy},
+		{KeyMinDealsPerProduct, &p.MinDealsPerProduct},
 	}
 }
 
@@ -64,6 +112,16 @@ func DefaultParams() Params {
 		MaxDealsPerBlock:  DefaultMaxDealsPerBlock,
 		FeePerBlock:       DefaultFeePerBlock,
 		TradeFeeRate:      sdk.MustNewDecFromStr(DefaultFeeRateTrade),
+
+		MaxMarketOrderSlippage: sdk.MustNewDecFromStr(DefaultMaxMarketOrderSlippage),
+		AllocationRule:         DefaultAllocationRule,
+
+		PriceBand:            sdk.MustNewDecFromStr(DefaultPriceBand),
+		CircuitBreakerBlocks: DefaultCircuitBreakerBlocks,
+		MarketPressureRate:   sdk.MustNewDecFromStr(DefaultMarketPressureRate),
+
+		DealBudgetPolicy:   DefaultDealBudgetPolicy,
+		MinDealsPerProduct: DefaultMinDealsPerProduct,
 	}
 }
 
@@ -73,7 +131,78 @@ func (p Params) String() string {
   OrderExpireBlocks: %d
   MaxDealsPerBlock: %d
   FeePerBlock: %s
-  TradeFeeRate: %s`, p.OrderExpireBlocks,
+  TradeFeeRate: %s
+  MaxMarketOrderSlippage: %s
+  AllocationRule: %s
+  PriceBand: %s
+  CircuitBreakerBlocks: %d
+  MarketPressureRate: %s
+  FeeSchedules: %v
+  DealBudgetPolicy: %s
+  MinDealsPerProduct: %d`, p.OrderExpireBlocks,
 		p.MaxDealsPerBlock, p.FeePerBlock,
-		p.TradeFeeRate)
+		p.TradeFeeRate, p.MaxMarketOrderSlippage, p.AllocationRule,
+		p.PriceBand, p.CircuitBreakerBlocks, p.MarketPressureRate, p.FeeSchedules,
+		p.DealBudgetPolicy, p.MinDealsPerProduct)
+}
+
+// Validate checks the params which are read by the match engines without checking
+func (p Params) Validate() error {
+	if err := p.ValidateAllocationRule(); err != nil {
+		return err
+	}
+	if err := p.ValidatePriceProtection(); err != nil {
+		return err
+	}
+	if err := p.ValidateDealBudget(); err != nil {
+		return err
+	}
+	return ValidateFeeSchedules(p.FeeSchedules)
+}
+
+// ValidateAllocationRule checks the allocation rule is one of the rules known to periodic auction
+func (p Params) ValidateAllocationRule() error {
+	switch p.AllocationRule {
+	case AllocationRuleFIFO, AllocationRuleProRata, AllocationRuleSizeTime:
+		return nil
+	default:
+		return fmt.Errorf("allocation rule is expected to be %q, %q or %q, but got %q",
+			AllocationRuleFIFO, AllocationRuleProRata, AllocationRuleSizeTime, p.AllocationRule)
+	}
+}
+
+// ValidatePriceProtection checks the price band, the circuit breaker blocks and the market pressure rate
+func (p Params) ValidatePriceProtection() error {
+	if p.PriceBand.IsNil() || p.PriceBand.IsNegative() {
+		return fmt.Errorf("price band should not be negative, but got %s", p.PriceBand)
+	}
+	if p.CircuitBreakerBlocks < 1 {
+		return fmt.Errorf("circuit breaker blocks should be at least 1, but got %d", p.CircuitBreakerBlocks)
+	}
+	if p.MarketPressureRate.IsNil() || p.MarketPressureRate.IsNegative() || p.MarketPressureRate.GTE(sdk.OneDec()) {
+		return fmt.Errorf("market pressure rate should be in [0, 1), but got %s", p.MarketPressureRate)
+	}
+	return nil
+}
+
+// ValidateParamsChange validates the params of order module read from the subspace, after a param change proposal
+// changed them
+func ValidateParamsChange(ctx sdk.Context, ss params.Subspace) error {
+	var p Params
+	ss.GetParamSet(ctx, &p)
+	return p.Validate()
+}
+
+// ValidateDealBudget checks the policy and the minimum of the deal budget
+func (p Params) ValidateDealBudget() error {
+	switch p.DealBudgetPolicy {
+	case DealBudgetPolicySequential, DealBudgetPolicyFair:
+	default:
+		return fmt.Errorf("deal budget policy is expected to be %q or %q, but got %q",
+			DealBudgetPolicySequential, DealBudgetPolicyFair, p.DealBudgetPolicy)
+	}
+	if p.MinDealsPerProduct < 0 {
+		return fmt.Errorf("min deals per product should not be negative, but got %d", p.MinDealsPerProduct)
+	}
+	return nil
 }
@@ -14,6 +14,16 @@ func TestParamSetPairs(t *testing.T) {
 			MaxDealsPerBlock:  10000,
 			FeePerBlock:       sdk.NewDecCoinFromDec(DefaultFeeDenomPerBlock, sdk.MustNewDecFromStr("0.000001")),
 			TradeFeeRate:      sdk.MustNewDecFromStr("0.001"),
+
+			MaxMarketOrderSlippage: sdk.MustNewDecFromStr("0.1"),
+			AllocationRule:         AllocationRuleProRata,
+
+			PriceBand:            sdk.MustNewDecFromStr("0.1"),
+			CircuitBreakerBlocks: 5,
+			MarketPressureRate:   sdk.MustNewDecFromStr("0.02"),
+
+			FeeSchedules: []FeeSchedule{{Product: TestTokenPair, MakerFeeRate: sdk.MustNewDecFromStr("0.0005"),
+				TakerFeeRate: sdk.MustNewDecFromStr("0.002")}},
 		},
 	}
 
@@ -33,6 +43,24 @@ func TestParamSetPairs(t *testing.T) {
 				if !v.Value.(*sdk.Dec).Equal(test.TradeFeeRate) {
 					t.Errorf("key(%s) -> %x, want %x", v.Key, test.TradeFeeRate, v.Value)
 				}
+			case string(KeyMaxMarketOrderSlippage):
+				if !v.Value.(*sdk.Dec).Equal(test.MaxMarketOrderSlippage) {
+					t.Errorf("key(%s) -> %x, want %x", v.Key, test.MaxMarketOrderSlippage, v.Value)
+				}
+			case string(KeyAllocationRule):
+				require.EqualValues(t, test.AllocationRule, *(v.Value.(*string)))
+			case string(KeyPriceBand):
+				require.True(t, v.Value.(*sdk.Dec).Equal(test.PriceBand))
+			case string(KeyCircuitBreakerBlocks):
+				require.EqualValues(t, test.CircuitBreakerBlocks, *(v.Value.(*int64)))
+			case string(KeyMarketPressureRate):
+				require.True(t, v.Value.(*sdk.Dec).Equal(test.MarketPressureRate))
+			case string(KeyFeeSchedules):
+				require.EqualValues(t, test.FeeSchedules, *(v.Value.(*[]FeeSchedule)))
+			case string(KeyDealBudgetPolicy):
+				require.EqualValues(t, test.DealBudgetPolicy, *(v.Value.(*string)))
+			case string(KeyMinDealsPerProduct):
+				require.EqualValues(t, test.MinDealsPerProduct, *(v.Value.(*int64)))
 			}
 
 		}
@@ -45,6 +73,58 @@ func TestParamsString(t *testing.T) {
   OrderExpireBlocks: 259200
   MaxDealsPerBlock: 1000
   FeePerBlock: 0.00000100okt
-  TradeFeeRate: 0.00100000`
+  TradeFeeRate: 0.00100000
+  MaxMarketOrderSlippage: 0.05000000
+  AllocationRule: FIFO
+  PriceBand: 0.00000000
+  CircuitBreakerBlocks: 10
+  MarketPressureRate: 0.05000000
+  FeeSchedules: []
+  DealBudgetPolicy: FAIR
+  MinDealsPerProduct: 10`
 	require.EqualValues(t, expectString, param.String())
 }
+
+func TestValidatePriceProtection(t *testing.T) {
+	require.Nil(t, DefaultParams().Validate())
+
+	tests := []struct {
+		priceBand            string
+		circuitBreakerBlocks int64
+		marketPressureRate   string
+		valid                bool
+	}{
+		{"0", 1, "0", true},
+		{"0.1", 10, "0.99", true},
+		{"-0.1", 10, "0.05", false},
+		{"0.1", 0, "0.05", false},
+		{"0.1", 10, "-0.05", false},
+		{"0.1", 10, "1", false},
+	}
+	for i, test := range tests {
+		params := DefaultParams()
+		params.PriceBand = sdk.MustNewDecFromStr(test.priceBand)
+		params.CircuitBreakerBlocks = test.circuitBreakerBlocks
+		params.MarketPressureRate = sdk.MustNewDecFromStr(test.marketPressureRate)
+		require.Equal(t, test.valid, params.ValidatePriceProtection() == nil, i)
+		require.Equal(t, test.valid, params.Validate() == nil, i)
+	}
+
+	// the params missing are invalid
+	require.NotNil(t, Params{CircuitBreakerBlocks: 1}.ValidatePriceProtection())
+}
+
+func TestValidateAllocationRule(t *testing.T) {
+	for _, rule := range []string{AllocationRuleFIFO, AllocationRuleProRata, AllocationRuleSizeTime} {
+		params := DefaultParams()
+		params.AllocationRule = rule
+		require.Nil(t, params.ValidateAllocationRule(), rule)
+		require.Nil(t, params.Validate(), rule)
+	}
+	for _, rule := range []string{"", "fifo", "LIFO"} {
+		params := DefaultParams()
+		params.AllocationRule = rule
+		require.NotNil(t, params.ValidateAllocationRule(), rule)
+		require.NotNil(t, params.Validate(), rule)
+	}
+}
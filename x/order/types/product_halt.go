@@ -0,0 +1,36 @@
+package types
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// ProductHalt records the product whose periodic auction is halted by the circuit breaker, because the clearing
+// price broke the price band around the last price
+type ProductHalt struct {
+	Product      string  `json:"product"`
+	HaltHeight   int64   `json:"halt_height"`
+	ResumeHeight int64   `json:"resume_height"`
+	LastPrice    sdk.Dec `json:"last_price"`
+	HaltPrice    sdk.Dec `json:"halt_price"`
+}
+
+// String implements the stringer interface
+func (h ProductHalt) String() string {
+	return fmt.Sprintf(`ProductHalt:
+  Product: %s
+  HaltHeight: %d
+  ResumeHeight: %d
+  LastPrice: %s
+  HaltPrice: %s`, h.Product, h.HaltHeight, h.ResumeHeight, h.LastPrice, h.HaltPrice)
+}
+
+// IsPriceInBand returns true if the price moves away from the last price no more than the band. The band takes no
+// effect if it is not positive or there is no last price
+func IsPriceInBand(price, lastPrice, band sdk.Dec) bool {
+	if !band.IsPositive() || !lastPrice.IsPositive() {
+		return true
+	}
+	return price.Sub(lastPrice).Abs().Quo(lastPrice).LTE(band)
+}
@@ -0,0 +1,36 @@
+package types
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// nolint
+const (
+	SelfTradePreventionCancelNewest  = "CANCEL_NEWEST"  // the newer order is cancelled, the older order rests
+	SelfTradePreventionCancelOldest  = "CANCEL_OLDEST"  // the older order is cancelled, the newer order goes on
+	SelfTradePreventionDecrementBoth = "DECREMENT_BOTH" // both orders are reduced by the quantity they would trade
+)
+
+// SelfTrade records an order closed or reduced by self-trade prevention in a match
+type SelfTrade struct {
+	Product        string  `json:"product"`
+	OrderID        string  `json:"order_id"`         // the order closed or reduced
+	CounterOrderID string  `json:"counter_order_id"` // the order of the same sender it would trade with
+	Mode           string  `json:"mode"`             // CANCEL_NEWEST/CANCEL_OLDEST/DECREMENT_BOTH
+	Quantity       sdk.Dec `json:"quantity"`         // the quantity removed from the order
+	Cancelled      bool    `json:"cancelled"`        // true if the order is closed
+}
+
+// ValidateSelfTradePrevention checks the self-trade prevention mode, empty means self trades are allowed for an
+// account, or the account mode is applied for an order
+func ValidateSelfTradePrevention(mode string) sdk.Error {
+	switch mode {
+	case "", SelfTradePreventionCancelNewest, SelfTradePreventionCancelOldest, SelfTradePreventionDecrementBoth:
+		return nil
+	default:
+		return sdk.ErrUnknownRequest(fmt.Sprintf("SelfTradePrevention is expected to be \"CANCEL_NEWEST\", "+
+			"\"CANCEL_OLDEST\" or \"DECREMENT_BOTH\", but got \"%s\"", mode))
+	}
+}
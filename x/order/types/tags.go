@@ -3,4 +3,32 @@ package types
 // Tag keys and values
 var (
 	TagKeyOrderID = "orderID"
+
+	EventTypeProductHalt   = "product_halt"
+	EventTypeProductResume = "product_resume"
+	TagKeyProduct          = "product"
+	TagKeyHaltHeight       = "halt_height"
+	TagKeyResumeHeight     = "resume_height"
+	TagKeyLastPrice        = "last_price"
+	TagKeyHaltPrice        = "halt_price"
+
+	// order lifecycle events, the partially filled and filled events are emitted once per deal
+	EventTypeOrderAccepted        = "order_accepted"
+	EventTypeOrderPartiallyFilled = "order_partially_filled"
+	EventTypeOrderFilled          = "order_filled"
+	EventTypeOrderCancelled       = "order_cancelled"
+	EventTypeOrderExpired         = "order_expired"
+	TagKeySender                  = "sender"
+	TagKeySide                    = "side"
+	TagKeyCounterSide             = "counter_side"
+	TagKeyOrderType               = "type"
+	TagKeyTimeInForce             = "time_in_force"
+	TagKeyPrice                   = "price"
+	TagKeyQuantity                = "quantity"
+	TagKeyRemainQuantity          = "remain_quantity"
+	TagKeyFilledAvgPrice          = "filled_avg_price"
+	TagKeyStatus                  = "status"
+	TagKeyFillPrice               = "fill_price"
+	TagKeyFillQuantity            = "fill_quantity"
+	TagKeyFee                     = "fee"
 )
@@ -0,0 +1,116 @@
+package types
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// TradingGrant permits the grantee to place and cancel the orders of the granter on behalf of it. The grantee is
+// not able to send or withdraw the coins of the granter
+type TradingGrant struct {
+	Granter      sdk.AccAddress `json:"granter"`
+	Grantee      sdk.AccAddress `json:"grantee"`
+	Products     []string       `json:"products"`      // the products permitted, empty for all the products
+	NotionalCap  sdk.Dec        `json:"notional_cap"`  // the max notional of the orders placed, zero for no cap
+	UsedNotional sdk.Dec        `json:"used_notional"` // the notional of the orders placed by the grantee
+	ExpireHeight int64          `json:"expire_height"` // the last block height of the grant, zero for no expiry
+}
+
+// NewTradingGrant creates a new instance of TradingGrant
+func NewTradingGrant(granter, grantee sdk.AccAddress, products []string, notionalCap sdk.Dec,
+	expireHeight int64) *TradingGrant {
+
+	return &TradingGrant{
+		Granter:      granter,
+		Grantee:      grantee,
+		Products:     products,
+		NotionalCap:  notionalCap,
+		UsedNotional: sdk.ZeroDec(),
+		ExpireHeight: expireHeight,
+	}
+}
+
+// IsExpired returns true if the grant expires before the block height
+func (g TradingGrant) IsExpired(height int64) bool {
+	return g.ExpireHeight > 0 && g.ExpireHeight < height
+}
+
+// IsProductPermitted returns true if the orders of the product are permitted by the grant
+func (g TradingGrant) IsProductPermitted(product string) bool {
+	if len(g.Products) == 0 {
+		return true
+	}
+	for _, p := range g.Products {
+		if p == product {
+			return true
+		}
+	}
+	return false
+}
+
+// Authorize checks whether the grantee is permitted to trade the product, and to place the order of the notional if
+// it is positive
+func (g TradingGrant) Authorize(height int64, product string, notional sdk.Dec) error {
+	if g.IsExpired(height) {
+		return fmt.Errorf("the trading grant from %s to %s expired at height %d", g.Granter, g.Grantee,
+			g.ExpireHeight)
+	}
+	if !g.IsProductPermitted(product) {
+		return fmt.Errorf("trading pair '%s' is not permitted by the trading grant from %s to %s", product,
+			g.Granter, g.Grantee)
+	}
+	if notional.IsPositive() && g.NotionalCap.IsPositive() && g.UsedNotional.Add(notional).GT(g.NotionalCap) {
+		return fmt.Errorf("notional(%v) exceeds the cap of the trading grant from %s to %s, used: %v, cap: %v",
+			notional, g.Granter, g.Grantee, g.UsedNotional, g.NotionalCap)
+	}
+	return nil
+}
+
+func validateTradingGrant(granter, grantee sdk.AccAddress, products []string, notionalCap sdk.Dec,
+	expireHeight int64) sdk.Error {
+
+	if granter.Empty() {
+		return sdk.ErrInvalidAddress(granter.String())
+	}
+	if grantee.Empty() {
+		return sdk.ErrInvalidAddress(grantee.String())
+	}
+	if granter.Equals(grantee) {
+		return sdk.ErrUnknownRequest("the grantee should not be the granter")
+	}
+	for _, product := range products {
+		if product == "" {
+			return sdk.ErrUnknownRequest("Product cannot be empty")
+		}
+	}
+	if notionalCap.IsNil() || notionalCap.IsNegative() {
+		return sdk.ErrUnknownRequest("NotionalCap should not be negative")
+	}
+	if expireHeight < 0 {
+		return sdk.ErrUnknownRequest(fmt.Sprintf("ExpireHeight should not be negative, but got %d", expireHeight))
+	}
+	return nil
+}
+
+// ValidateTradingGrants checks the trading grants of genesis, every pair of granter and grantee has at most one
+// grant
+func ValidateTradingGrants(grants []*TradingGrant) error {
+	pairs := make(map[string]struct{}, len(grants))
+	for _, grant := range grants {
+		if err := validateTradingGrant(grant.Granter, grant.Grantee, grant.Products, grant.NotionalCap,
+			grant.ExpireHeight); err != nil {
+			return err
+		}
+		if grant.UsedNotional.IsNil() || grant.UsedNotional.IsNegative() {
+			return fmt.Errorf("the used notional of the trading grant from %s to %s should not be negative",
+				grant.Granter, grant.Grantee)
+		}
+		pair := grant.Granter.String() + grant.Grantee.String()
+		if _, ok := pairs[pair]; ok {
+			return fmt.Errorf("duplicate trading grant from %s to %s", grant.Granter, grant.Grantee)
+		}
+		pairs[pair] = struct{}{}
+	}
+	return nil
+}
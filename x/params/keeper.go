@@ -17,13 +17,19 @@ type Keeper struct {
 	ck BankKeeper
 	// the reference to the GovKeeper to insert waiting queue
 	gk GovKeeper
+	// the validators of the params of the subspaces, which are run after the params are changed
+	validators map[string]ParamsValidator
 }
 
+// ParamsValidator validates the params of a subspace read from the store
+type ParamsValidator func(ctx sdk.Context, ss Subspace) error
+
 // NewKeeper creates a new instance of params keeper
 func NewKeeper(cdc *codec.Codec, key *sdk.KVStoreKey, tkey *sdk.TransientStoreKey, codespace sdk.CodespaceType) (
 	k Keeper) {
 	k = Keeper{
-		Keeper: sdkparams.NewKeeper(cdc, key, tkey, codespace),
+		Keeper:     sdkparams.NewKeeper(cdc, key, tkey, codespace),
+		validators: make(map[string]ParamsValidator),
 	}
 	k.paramSpace = k.Subspace(DefaultParamspace).WithKeyTable(ParamKeyTable())
 	return k
@@ -44,6 +50,12 @@ func (keeper *Keeper) SetGovKeeper(gk GovKeeper) {
 	keeper.gk = gk
 }
 
+// RegisterParamsValidator hooks the validator of the params of a subspace into params keeper, a param change
+// proposal leaving the params invalid fails
+func (keeper *Keeper) RegisterParamsValidator(subspace string, validator ParamsValidator) {
+	keeper.validators[subspace] = validator
+}
+
 // SetParams sets the params into the store
 func (keeper *Keeper) SetParams(ctx sdk.Context, params Params) {
 	keeper.paramSpace.Set(ctx, ParamStoreKeyParamsParams, params)
@@ -61,6 +61,11 @@ func changeParams(ctx sdk.Context, k *Keeper, paramProposal ParameterChangePropo
 			err = ss.UpdateWithSubkey(ctx, []byte(c.Key), []byte(c.Subkey), []byte(c.Value))
 		}
 
+		if err == nil {
+			if validator, ok := k.validators[c.Subspace]; ok {
+				err = validator(ctx, ss)
+			}
+		}
 		if err != nil {
 			return sdkparams.ErrSettingParameter(k.Codespace(), c.Key, c.Subkey, c.Value, err.Error())
 		}
@@ -0,0 +1,105 @@
+package stream
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/okex/okchain/x/backend"
+	"github.com/okex/okchain/x/stream/types"
+)
+
+// EndBlocker writes the market data of the block into the journal, from which it's published to the sinks. It's
+// called after the EndBlocker of order module, whose caches keep the orders and match results of the block
+func EndBlocker(ctx sdk.Context, k Keeper) {
+	if !k.AnalysisEnable() {
+		return
+	}
+	defer k.cache.Flush()
+
+	data, err := collectBlockData(ctx, k)
+	if err != nil {
+		k.logger.Error(fmt.Sprintf("failed to collect the stream data of block %d: %s", ctx.BlockHeight(), err.Error()))
+		return
+	}
+	bz, err := json.Marshal(data)
+	if err != nil {
+		k.logger.Error(fmt.Sprintf("failed to encode the stream data of block %d: %s", ctx.BlockHeight(), err.Error()))
+		return
+	}
+
+	k.journal.Put(ctx.BlockHeight(), bz)
+	k.publisher.Notify()
+	k.metrics.CacheSize.Set(float64(k.publisher.Pending()))
+}
+
+func collectBlockData(ctx sdk.Context, k Keeper) (*types.BlockData, error) {
+	newOrders, err := backend.GetNewOrdersAtEndBlock(ctx, k.orderKeeper)
+	if err != nil {
+		return nil, err
+	}
+	updatedOrders := backend.GetUpdatedOrdersAtEndBlock(ctx, k.orderKeeper)
+	deals, results, err := backend.GetNewDealsAndMatchResultsAtEndBlock(ctx, k.orderKeeper)
+	if err != nil {
+		return nil, err
+	}
+
+	// the depth books are only changed by orders, and the balances by txs, orders and fees
+	products := make(map[string]struct{})
+	for _, orders := range [][]*backend.Order{newOrders, updatedOrders} {
+		for _, order := range orders {
+			products[order.Product] = struct{}{}
+			k.cache.AddAddress(order.Sender)
+		}
+	}
+	for _, deal := range deals {
+		products[deal.Product] = struct{}{}
+		k.cache.AddAddress(deal.Sender)
+	}
+	for _, feeDetail := range k.tokenKeeper.GetFeeDetailList() {
+		k.cache.AddAddress(feeDetail.Address)
+	}
+
+	return &types.BlockData{
+		Height:        ctx.BlockHeight(),
+		Timestamp:     ctx.BlockHeader().Time.Unix(),
+		NewOrders:     newOrders,
+		UpdatedOrders: updatedOrders,
+		Deals:         deals,
+		MatchResults:  results,
+		DepthBooks:    getDepthBooks(k, products),
+		Balances:      getBalances(ctx, k, k.cache.GetAddresses()),
+	}, nil
+}
+
+// getDepthBooks returns the snapshots of the visible depth books of products in order
+func getDepthBooks(k Keeper, products map[string]struct{}) []types.DepthBook {
+	productList := make([]string, 0, len(products))
+	for product := range products {
+		productList = append(productList, product)
+	}
+	sort.Strings(productList)
+
+	depthBooks := make([]types.DepthBook, 0, len(productList))
+	for _, product := range productList {
+		depthBooks = append(depthBooks, types.DepthBook{
+			Product: product,
+			Items:   k.orderKeeper.GetDepthBookCopy(product).Visible().Items,
+		})
+	}
+	return depthBooks
+}
+
+func getBalances(ctx sdk.Context, k Keeper, addresses []string) []types.Balance {
+	balances := make([]types.Balance, 0, len(addresses))
+	for _, address := range addresses {
+		addr, err := sdk.AccAddressFromBech32(address)
+		if err != nil {
+			k.logger.Error(fmt.Sprintf("invalid address %s: %s", address, err.Error()))
+			continue
+		}
+		balances = append(balances, types.Balance{Address: address, Coins: k.tokenKeeper.GetCoinsInfo(ctx, addr)})
+	}
+	return balances
+}
@@ -0,0 +1,21 @@
+// nolint
+package stream
+
+import (
+	"github.com/okex/okchain/x/stream/types"
+)
+
+const (
+	// ModuleName is the name of the stream module
+	ModuleName = types.ModuleName
+)
+
+type (
+	Config    = types.Config
+	BlockData = types.BlockData
+)
+
+var (
+	DefaultConfig = types.DefaultConfig
+	ParseConfig   = types.ParseConfig
+)
@@ -0,0 +1,144 @@
+package journal
+
+import (
+	"encoding/binary"
+
+	dbm "github.com/tendermint/tm-db"
+)
+
+var (
+	blockDataPrefix    = []byte{0x01}
+	blockDataPrefixEnd = []byte{0x02}
+	cursorPrefix       = []byte{0x02}
+)
+
+// Journal persists the encoded stream data of blocks together with the cursors of sinks, which are the heights of
+// the last blocks they have accepted. Stream data is written before the block is committed, so the blocks which are
+// not yet accepted by a sink are published again after a restart
+type Journal struct {
+	db dbm.DB
+}
+
+// New opens the journal in dir
+func New(dir string) (*Journal, error) {
+	db, err := dbm.NewGoLevelDB("journal", dir)
+	if err != nil {
+		return nil, err
+	}
+	return NewWithDB(db), nil
+}
+
+// NewWithDB creates a journal on db
+func NewWithDB(db dbm.DB) *Journal {
+	return &Journal{db: db}
+}
+
+func blockDataKey(height int64) []byte {
+	key := make([]byte, len(blockDataPrefix)+8)
+	copy(key, blockDataPrefix)
+	binary.BigEndian.PutUint64(key[len(blockDataPrefix):], uint64(height))
+	return key
+}
+
+func heightFromKey(key []byte) int64 {
+	return int64(binary.BigEndian.Uint64(key[len(blockDataPrefix):]))
+}
+
+func cursorKey(name string) []byte {
+	return append(append([]byte{}, cursorPrefix...), name...)
+}
+
+// Put writes the stream data of the block at height, replacing the one written by an uncommitted execution
+func (j *Journal) Put(height int64, data []byte) {
+	j.db.SetSync(blockDataKey(height), data)
+}
+
+// Get returns the stream data of the block at height, nil if it's not kept
+func (j *Journal) Get(height int64) []byte {
+	return j.db.Get(blockDataKey(height))
+}
+
+// OldestHeight returns the height of the oldest block kept, 0 if the journal is empty
+func (j *Journal) OldestHeight() int64 {
+	iter := j.db.Iterator(blockDataKey(0), blockDataPrefixEnd)
+	defer iter.Close()
+	if !iter.Valid() {
+		return 0
+	}
+	return heightFromKey(iter.Key())
+}
+
+// LatestHeight returns the height of the latest block kept, 0 if the journal is empty
+func (j *Journal) LatestHeight() int64 {
+	iter := j.db.ReverseIterator(blockDataKey(0), blockDataPrefixEnd)
+	defer iter.Close()
+	if !iter.Valid() {
+		return 0
+	}
+	return heightFromKey(iter.Key())
+}
+
+// Iterate calls cb with the stream data of the blocks kept from fromHeight on, in order of height, until cb returns
+// true
+func (j *Journal) Iterate(fromHeight int64, cb func(height int64, data []byte) (stop bool)) {
+	iter := j.db.Iterator(blockDataKey(fromHeight), blockDataPrefixEnd)
+	defer iter.Close()
+	for ; iter.Valid(); iter.Next() {
+		if cb(heightFromKey(iter.Key()), iter.Value()) {
+			return
+		}
+	}
+}
+
+// Prune removes the blocks kept up to toHeight
+func (j *Journal) Prune(toHeight int64) {
+	iter := j.db.Iterator(blockDataKey(0), blockDataKey(toHeight+1))
+	var keys [][]byte
+	for ; iter.Valid(); iter.Next() {
+		keys = append(keys, iter.Key())
+	}
+	iter.Close()
+
+	if len(keys) == 0 {
+		return
+	}
+	batch := j.db.NewBatch()
+	defer batch.Close()
+	for _, key := range keys {
+		batch.Delete(key)
+	}
+	batch.WriteSync()
+}
+
+// Cursor returns the height of the last block accepted by the sink, and whether the sink has accepted any block
+func (j *Journal) Cursor(sinkName string) (int64, bool) {
+	bz := j.db.Get(cursorKey(sinkName))
+	if bz == nil {
+		return 0, false
+	}
+	return int64(binary.BigEndian.Uint64(bz)), true
+}
+
+// SetCursor records height as the last block accepted by the sink
+func (j *Journal) SetCursor(sinkName string, height int64) {
+	bz := make([]byte, 8)
+	binary.BigEndian.PutUint64(bz, uint64(height))
+	j.db.SetSync(cursorKey(sinkName), bz)
+}
+
+// IterateCursors calls cb with the cursors whose names start with prefix, in order of name, until cb returns true
+func (j *Journal) IterateCursors(prefix string, cb func(name string, height int64) (stop bool)) {
+	iter := dbm.IteratePrefix(j.db, cursorKey(prefix))
+	defer iter.Close()
+	for ; iter.Valid(); iter.Next() {
+		name := string(iter.Key()[len(cursorPrefix):])
+		if cb(name, int64(binary.BigEndian.Uint64(iter.Value()))) {
+			return
+		}
+	}
+}
+
+// Close closes the database of the journal
+func (j *Journal) Close() {
+	j.db.Close()
+}
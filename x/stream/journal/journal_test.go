@@ -0,0 +1,60 @@
+package journal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	dbm "github.com/tendermint/tm-db"
+)
+
+func TestJournal(t *testing.T) {
+	j := NewWithDB(dbm.NewMemDB())
+	require.Equal(t, int64(0), j.OldestHeight())
+	require.Equal(t, int64(0), j.LatestHeight())
+
+	for height := int64(1); height <= 5; height++ {
+		j.Put(height, []byte{byte(height)})
+	}
+	// the data of a re-executed block is replaced
+	j.Put(5, []byte{50})
+	require.Equal(t, int64(1), j.OldestHeight())
+	require.Equal(t, int64(5), j.LatestHeight())
+	require.Equal(t, []byte{50}, j.Get(5))
+	require.Nil(t, j.Get(6))
+
+	var heights []int64
+	j.Iterate(3, func(height int64, data []byte) bool {
+		heights = append(heights, height)
+		return height == 4
+	})
+	require.Equal(t, []int64{3, 4}, heights)
+
+	j.Prune(2)
+	require.Equal(t, int64(3), j.OldestHeight())
+	require.Nil(t, j.Get(2))
+
+	_, found := j.Cursor("file")
+	require.False(t, found)
+	j.SetCursor("file", 4)
+	cursor, found := j.Cursor("file")
+	require.True(t, found)
+	require.Equal(t, int64(4), cursor)
+
+	// cursors are not taken as blocks
+	require.Equal(t, int64(5), j.LatestHeight())
+	j.Prune(10)
+	require.Equal(t, int64(0), j.LatestHeight())
+	cursor, _ = j.Cursor("file")
+	require.Equal(t, int64(4), cursor)
+
+	// the cursors are listed by the prefix of names
+	j.SetCursor("websocket", 3)
+	j.SetCursor("websocket/b", 2)
+	j.SetCursor("websocket/a", 1)
+	cursors := make(map[string]int64)
+	j.IterateCursors("websocket/", func(name string, height int64) bool {
+		cursors[name] = height
+		return false
+	})
+	require.Equal(t, map[string]int64{"websocket/a": 1, "websocket/b": 2}, cursors)
+}
@@ -0,0 +1,131 @@
+package stream
+
+import (
+	"fmt"
+	"sort"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/okex/okchain/x/backend"
+	"github.com/okex/okchain/x/common/monitor"
+	"github.com/okex/okchain/x/stream/journal"
+	"github.com/okex/okchain/x/stream/sink"
+	"github.com/okex/okchain/x/stream/types"
+	tokentypes "github.com/okex/okchain/x/token/types"
+	"github.com/tendermint/tendermint/libs/log"
+)
+
+// Keeper collects the market data of each block into the journal, and publishes it to the configured sinks
+type Keeper struct {
+	orderKeeper types.OrderKeeper
+	tokenKeeper types.TokenKeeper
+	logger      log.Logger
+	metrics     *monitor.StreamMetrics
+	Config      *types.Config
+
+	journal   *journal.Journal
+	publisher *sink.Publisher
+
+	// memory cache
+	cache *Cache
+}
+
+// NewKeeper creates the keeper of stream module, which opens the journal and starts publishing to the sinks if
+// stream is enabled
+func NewKeeper(orderKeeper types.OrderKeeper, tokenKeeper types.TokenKeeper, logger log.Logger, cfg *types.Config,
+	metrics *monitor.StreamMetrics) Keeper {
+	k := Keeper{
+		orderKeeper: orderKeeper,
+		tokenKeeper: tokenKeeper,
+		logger:      logger.With("module", ModuleName),
+		metrics:     metrics,
+		Config:      cfg,
+		cache:       NewCache(),
+	}
+
+	if k.Config.Enable {
+		j, err := journal.New(k.Config.JournalDir)
+		if err != nil {
+			panic(fmt.Sprintf("failed to open the journal of stream in %s: %s", k.Config.JournalDir, err.Error()))
+		}
+		sinks, err := sink.NewSinks(k.Config, j, k.logger)
+		if err != nil {
+			j.Close()
+			panic(fmt.Sprintf("failed to create the sinks of stream: %s", err.Error()))
+		}
+
+		k.journal = j
+		k.publisher = sink.NewPublisher(j, sinks, k.Config.JournalKeepBlocks, k.Config.RetryInterval, k.logger)
+		k.publisher.Start()
+	}
+
+	logger.Debug(fmt.Sprintf("%+v", k.Config))
+	return k
+}
+
+// Stop stops publishing and closes the sinks and the journal
+func (k Keeper) Stop() {
+	if k.publisher != nil {
+		k.publisher.Stop()
+	}
+	if k.journal != nil {
+		k.journal.Close()
+	}
+}
+
+// SyncTx records the accounts involved in the tx, whose balances are collected at EndBlock, called at DeliverTx
+func (k Keeper) SyncTx(ctx sdk.Context, tx *auth.StdTx, txHash string, timestamp int64) {
+	if !k.AnalysisEnable() {
+		return
+	}
+
+	for _, msg := range tx.GetMsgs() {
+		for _, signer := range msg.GetSigners() {
+			k.cache.AddAddress(signer.String())
+		}
+		switch msg := msg.(type) {
+		case tokentypes.MsgSend:
+			k.cache.AddAddress(msg.ToAddress.String())
+		case tokentypes.MsgMultiSend:
+			for _, transfer := range msg.Transfers {
+				k.cache.AddAddress(transfer.To.String())
+			}
+		}
+	}
+}
+
+// GetMarketKeeper returns nil, since stream module doesn't serve market data to backend module
+func (k Keeper) GetMarketKeeper() backend.MarketKeeper { return nil }
+
+// AnalysisEnable returns whether stream module collects the data of txs
+func (k Keeper) AnalysisEnable() bool { return k.Config != nil && k.Config.Enable }
+
+// Cache keeps the accounts involved in the txs of current block
+type Cache struct {
+	addresses map[string]struct{}
+}
+
+// NewCache returns a new cache, called at NewKeeper
+func NewCache() *Cache {
+	return &Cache{addresses: make(map[string]struct{})}
+}
+
+// AddAddress records an account involved in current block
+func (c *Cache) AddAddress(address string) {
+	c.addresses[address] = struct{}{}
+}
+
+// GetAddresses returns the accounts involved in current block in order
+func (c *Cache) GetAddresses() []string {
+	addresses := make([]string, 0, len(c.addresses))
+	for address := range c.addresses {
+		addresses = append(addresses, address)
+	}
+	sort.Strings(addresses)
+	return addresses
+}
+
+// Flush clears the cache, called at EndBlock
+func (c *Cache) Flush() {
+	c.addresses = make(map[string]struct{})
+}
@@ -0,0 +1,161 @@
+package stream
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/okex/okchain/x/common/monitor"
+	ordertypes "github.com/okex/okchain/x/order/types"
+	"github.com/okex/okchain/x/stream/types"
+	"github.com/okex/okchain/x/token"
+	tokentypes "github.com/okex/okchain/x/token/types"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/libs/log"
+)
+
+const testProduct = "xxb_okt"
+
+var (
+	testAddrs = []sdk.AccAddress{
+		sdk.AccAddress([]byte("stream_test_addr_0__")),
+		sdk.AccAddress([]byte("stream_test_addr_1__")),
+		sdk.AccAddress([]byte("stream_test_addr_2__")),
+	}
+)
+
+type mockOrderKeeper struct {
+	orders          map[string]*ordertypes.Order
+	blockOrderNum   int64
+	updatedOrderIDs []string
+	matchResult     *ordertypes.BlockMatchResult
+	depthBooks      map[string]*ordertypes.DepthBook
+}
+
+func (k mockOrderKeeper) GetOrder(ctx sdk.Context, orderID string) *ordertypes.Order {
+	return k.orders[orderID]
+}
+func (k mockOrderKeeper) GetUpdatedOrderIDs() []string { return k.updatedOrderIDs }
+func (k mockOrderKeeper) GetBlockOrderNum(ctx sdk.Context, blockHeight int64) int64 {
+	return k.blockOrderNum
+}
+func (k mockOrderKeeper) GetBlockMatchResult() *ordertypes.BlockMatchResult { return k.matchResult }
+func (k mockOrderKeeper) GetLastPrice(ctx sdk.Context, product string) sdk.Dec {
+	return sdk.ZeroDec()
+}
+func (k mockOrderKeeper) GetBestBidAndAsk(ctx sdk.Context, product string) (sdk.Dec, sdk.Dec) {
+	return sdk.ZeroDec(), sdk.ZeroDec()
+}
+func (k mockOrderKeeper) GetDepthBookCopy(product string) *ordertypes.DepthBook {
+	if book, ok := k.depthBooks[product]; ok {
+		return book.Copy()
+	}
+	return &ordertypes.DepthBook{}
+}
+
+type mockTokenKeeper struct {
+	feeDetails []*token.FeeDetail
+}
+
+func (k mockTokenKeeper) GetFeeDetailList() []*token.FeeDetail { return k.feeDetails }
+func (k mockTokenKeeper) GetCoinsInfo(ctx sdk.Context, addr sdk.AccAddress) tokentypes.CoinsInfo {
+	return tokentypes.CoinsInfo{{Symbol: "okt", Available: "100.00000000", Locked: "0"}}
+}
+
+func TestEndBlocker(t *testing.T) {
+	dir, err := ioutil.TempDir("", "stream_end_blocker")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	height := int64(10)
+	newOrder := ordertypes.MockOrder(ordertypes.FormatOrderID(height, 1), testProduct, ordertypes.BuyOrder, "10", "2")
+	newOrder.Sender = testAddrs[0]
+	filledOrder := ordertypes.MockOrder(ordertypes.FormatOrderID(height-1, 1), testProduct, ordertypes.SellOrder, "10", "1")
+	filledOrder.Sender = testAddrs[1]
+	filledOrder.Status = ordertypes.OrderStatusFilled
+
+	depthBook := &ordertypes.DepthBook{}
+	depthBook.InsertOrder(newOrder)
+	orderKeeper := mockOrderKeeper{
+		orders:          map[string]*ordertypes.Order{newOrder.OrderID: newOrder, filledOrder.OrderID: filledOrder},
+		blockOrderNum:   1,
+		updatedOrderIDs: []string{filledOrder.OrderID},
+		matchResult: &ordertypes.BlockMatchResult{
+			BlockHeight: height,
+			ResultMap: map[string]ordertypes.MatchResult{testProduct: {
+				BlockHeight: height, Price: sdk.NewDec(10), Quantity: sdk.OneDec(),
+				Deals: []ordertypes.Deal{{OrderID: filledOrder.OrderID, Side: ordertypes.SellOrder,
+					Price: sdk.NewDec(10), Quantity: sdk.OneDec(), Fee: "0.01okt"}},
+			}},
+		},
+		depthBooks: map[string]*ordertypes.DepthBook{testProduct: depthBook},
+	}
+
+	cfg := types.DefaultConfig()
+	cfg.Enable = true
+	cfg.JournalDir = filepath.Join(dir, "journal")
+	cfg.FilePath = filepath.Join(dir, "blocks.jsonl")
+	require.Nil(t, cfg.Validate())
+	keeper := NewKeeper(orderKeeper, mockTokenKeeper{}, log.NewNopLogger(), cfg, monitor.NopStreamMetrics())
+	defer keeper.Stop()
+	require.True(t, keeper.AnalysisEnable())
+	require.Nil(t, keeper.GetMarketKeeper())
+
+	ctx := sdk.NewContext(nil, abci.Header{Height: height, Time: time.Unix(1000, 0)}, false, log.NewNopLogger())
+	tx := auth.StdTx{Msgs: []sdk.Msg{tokentypes.NewMsgTokenSend(testAddrs[1], testAddrs[2], sdk.NewDecCoins(
+		sdk.NewDecCoinsFromDec("okt", sdk.OneDec())))}}
+	keeper.SyncTx(ctx, &tx, "hash", 1000)
+	NewAppModule(keeper).EndBlock(ctx, abci.RequestEndBlock{Height: height})
+
+	var lines []string
+	require.Eventually(t, func() bool {
+		bz, err := ioutil.ReadFile(cfg.FilePath)
+		lines = strings.Split(strings.TrimSpace(string(bz)), "\n")
+		return err == nil && len(lines) == 1 && lines[0] != ""
+	}, time.Second, 10*time.Millisecond)
+
+	var data types.BlockData
+	require.Nil(t, json.Unmarshal([]byte(lines[0]), &data))
+	require.Equal(t, height, data.Height)
+	require.Equal(t, int64(1000), data.Timestamp)
+	require.Equal(t, 1, len(data.NewOrders))
+	require.Equal(t, newOrder.OrderID, data.NewOrders[0].OrderID)
+	require.Equal(t, 1, len(data.UpdatedOrders))
+	require.EqualValues(t, ordertypes.OrderStatusFilled, data.UpdatedOrders[0].Status)
+	require.Equal(t, 1, len(data.Deals))
+	require.Equal(t, testAddrs[1].String(), data.Deals[0].Sender)
+	require.Equal(t, 1, len(data.MatchResults))
+	require.Equal(t, 1, len(data.DepthBooks))
+	require.Equal(t, testProduct, data.DepthBooks[0].Product)
+	require.Equal(t, "2.00000000", data.DepthBooks[0].Items[0].BuyQuantity.String())
+
+	// the accounts of txs, orders and deals are all collected
+	addresses := make([]string, 0, len(data.Balances))
+	for _, balance := range data.Balances {
+		addresses = append(addresses, balance.Address)
+	}
+	require.ElementsMatch(t, []string{testAddrs[0].String(), testAddrs[1].String(), testAddrs[2].String()}, addresses)
+	require.Equal(t, "100.00000000", data.Balances[0].Coins[0].Available)
+
+	// the accounts are flushed at EndBlock
+	require.Equal(t, 0, len(keeper.cache.GetAddresses()))
+}
+
+func TestDisabledKeeper(t *testing.T) {
+	keeper := NewKeeper(mockOrderKeeper{}, mockTokenKeeper{}, log.NewNopLogger(), DefaultConfig(),
+		monitor.NopStreamMetrics())
+	require.False(t, keeper.AnalysisEnable())
+	require.False(t, Keeper{}.AnalysisEnable())
+
+	ctx := sdk.NewContext(nil, abci.Header{Height: 1}, false, log.NewNopLogger())
+	keeper.SyncTx(ctx, &auth.StdTx{}, "hash", 0)
+	EndBlocker(ctx, keeper)
+	keeper.Stop()
+}
@@ -1,9 +1,8 @@
-//+build !stream
-
 package stream
 
 import (
 	"encoding/json"
+
 	"github.com/cosmos/cosmos-sdk/client/context"
 	"github.com/cosmos/cosmos-sdk/codec"
 	sdk "github.com/cosmos/cosmos-sdk/types"
@@ -13,71 +12,85 @@ import (
 	abci "github.com/tendermint/tendermint/abci/types"
 )
 
-const (
-	ModuleName = "stream"
-)
-
 // type check to ensure the interface is properly implemented
 var (
 	_ module.AppModule      = AppModule{}
 	_ module.AppModuleBasic = AppModuleBasic{}
 )
 
-// app module Basics object
+// AppModuleBasic app module Basics object
 type AppModuleBasic struct{}
 
+// Name returns ModuleName
 func (AppModuleBasic) Name() string { return ModuleName }
 
+// RegisterCodec registers module codec
 func (AppModuleBasic) RegisterCodec(cdc *codec.Codec) {}
 
+// DefaultGenesis returns nil, stream module has no genesis state
 func (AppModuleBasic) DefaultGenesis() json.RawMessage { return nil }
 
-// Validation check of the Genesis
+// ValidateGenesis validation check of the Genesis
 func (AppModuleBasic) ValidateGenesis(bz json.RawMessage) error { return nil }
 
-// Register rest routes
+// RegisterRESTRoutes registers rest routes
 func (AppModuleBasic) RegisterRESTRoutes(ctx context.CLIContext, rtr *mux.Router) {}
 
-// Get the root query command of this module
+// GetQueryCmd returns the root query command of this module
 func (AppModuleBasic) GetQueryCmd(cdc *codec.Codec) *cobra.Command { return nil }
 
-// Get the root tx command of this module
+// GetTxCmd returns the root tx command of this module
 func (AppModuleBasic) GetTxCmd(cdc *codec.Codec) *cobra.Command { return nil }
 
+// AppModule is a struct of app module
 type AppModule struct {
 	AppModuleBasic
+	keeper Keeper
 }
 
 // NewAppModule creates a new AppModule Object
 func NewAppModule(k Keeper) AppModule {
 	return AppModule{
 		AppModuleBasic: AppModuleBasic{},
+		keeper:         k,
 	}
 }
 
+// Name returns ModuleName
 func (AppModule) Name() string { return ModuleName }
 
+// RegisterInvariants registers invariants
 func (am AppModule) RegisterInvariants(ir sdk.InvariantRegistry) {}
 
+// Route returns module message route name
 func (am AppModule) Route() string { return "" }
 
+// NewHandler returns module handler
 func (am AppModule) NewHandler() sdk.Handler { return nil }
-func (am AppModule) QuerierRoute() string    { return "" }
 
+// QuerierRoute returns module querier route name
+func (am AppModule) QuerierRoute() string { return "" }
+
+// NewQuerierHandler returns module querier
 func (am AppModule) NewQuerierHandler() sdk.Querier {
 	return func(ctx sdk.Context, path []string, req abci.RequestQuery) (res []byte, err sdk.Error) {
 		return nil, nil
 	}
 }
 
+// BeginBlock is invoked on the beginning of each block
 func (am AppModule) BeginBlock(_ sdk.Context, _ abci.RequestBeginBlock) {}
 
+// EndBlock is invoked on the end of each block
 func (am AppModule) EndBlock(ctx sdk.Context, _ abci.RequestEndBlock) []abci.ValidatorUpdate {
+	EndBlocker(ctx, am.keeper)
 	return nil
 }
 
+// InitGenesis initializes module genesis
 func (am AppModule) InitGenesis(ctx sdk.Context, data json.RawMessage) []abci.ValidatorUpdate {
 	return nil
 }
 
+// ExportGenesis exports module genesis
 func (am AppModule) ExportGenesis(ctx sdk.Context) json.RawMessage { return nil }
@@ -0,0 +1,48 @@
+package sink
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/okex/okchain/x/stream/types"
+)
+
+var _ Sink = (*FileSink)(nil)
+
+// FileSink appends the stream data of each block as a JSON line to a local file
+type FileSink struct {
+	file *os.File
+}
+
+// NewFileSink opens the file at path for appending, creating it if it doesn't exist
+func NewFileSink(path string) (*FileSink, error) {
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return nil, err
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return &FileSink{file: file}, nil
+}
+
+// Name returns the name of the sink
+func (s *FileSink) Name() string {
+	return types.SinkFile
+}
+
+// Publish appends the stream data as a line and syncs the file
+func (s *FileSink) Publish(height int64, data []byte) error {
+	line := make([]byte, len(data)+1)
+	copy(line, data)
+	line[len(data)] = '\n'
+	if _, err := s.file.Write(line); err != nil {
+		return err
+	}
+	return s.file.Sync()
+}
+
+// Close closes the file
+func (s *FileSink) Close() error {
+	return s.file.Close()
+}
@@ -0,0 +1,161 @@
+package sink
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/okex/okchain/x/stream/journal"
+	"github.com/tendermint/tendermint/libs/log"
+)
+
+// Publisher delivers the blocks written into the journal to each sink at least once, in order of height. Each sink
+// is driven by its own goroutine, so a failing sink only holds back itself. The cursor of a sink is advanced after
+// the sink has accepted a block, and a block is retried until it's accepted. The blocks are pruned from the journal
+// once accepted by all the sinks and passed by the cursors of their consumers, see ConsumerCursors
+type Publisher struct {
+	journal       *journal.Journal
+	sinks         []Sink
+	keepBlocks    int64
+	retryInterval time.Duration
+	logger        log.Logger
+
+	notifyChs []chan struct{}
+	stopCh    chan struct{}
+	wg        sync.WaitGroup
+	pruneMtx  sync.Mutex
+}
+
+// NewPublisher creates a publisher of the journal to sinks, which keeps keepBlocks latest blocks in the journal
+// after they are accepted by all the sinks
+func NewPublisher(j *journal.Journal, sinks []Sink, keepBlocks int64, retryInterval time.Duration,
+	logger log.Logger) *Publisher {
+	notifyChs := make([]chan struct{}, len(sinks))
+	for i := range notifyChs {
+		notifyChs[i] = make(chan struct{}, 1)
+	}
+	return &Publisher{
+		journal:       j,
+		sinks:         sinks,
+		keepBlocks:    keepBlocks,
+		retryInterval: retryInterval,
+		logger:        logger,
+		notifyChs:     notifyChs,
+		stopCh:        make(chan struct{}),
+	}
+}
+
+// Start starts delivering to the sinks, beginning with the blocks left behind by the last run
+func (p *Publisher) Start() {
+	for i := range p.sinks {
+		p.wg.Add(1)
+		go p.run(i)
+	}
+}
+
+// Notify wakes up the sinks to deliver the blocks newly written into the journal
+func (p *Publisher) Notify() {
+	for _, ch := range p.notifyChs {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Stop stops delivering and closes the sinks
+func (p *Publisher) Stop() {
+	close(p.stopCh)
+	p.wg.Wait()
+	for _, s := range p.sinks {
+		if err := s.Close(); err != nil {
+			p.logger.Error(fmt.Sprintf("[stream] failed to close sink %s: %s", s.Name(), err.Error()))
+		}
+	}
+}
+
+// Pending returns the number of blocks in the journal not yet accepted by the slowest sink
+func (p *Publisher) Pending() int64 {
+	latest := p.journal.LatestHeight()
+	return latest - p.minCursor(latest)
+}
+
+func (p *Publisher) run(index int) {
+	defer p.wg.Done()
+	s := p.sinks[index]
+
+	// a sink added to the configuration starts with the oldest block kept
+	cursor, found := p.journal.Cursor(s.Name())
+	if !found {
+		if oldest := p.journal.OldestHeight(); oldest > 0 {
+			cursor = oldest - 1
+		}
+	}
+
+	for {
+		var err error
+		p.journal.Iterate(cursor+1, func(height int64, data []byte) bool {
+			if err = s.Publish(height, data); err != nil {
+				return true
+			}
+			cursor = height
+			p.journal.SetCursor(s.Name(), height)
+			return false
+		})
+
+		wait := p.notifyChs[index]
+		var retryCh <-chan time.Time
+		if err != nil {
+			p.logger.Error(fmt.Sprintf("[stream] failed to publish block %d to sink %s, retry in %s: %s",
+				cursor+1, s.Name(), p.retryInterval, err.Error()))
+			retryCh = time.After(p.retryInterval)
+			wait = nil
+		} else {
+			p.prune()
+		}
+
+		select {
+		case <-wait:
+		case <-retryCh:
+		case <-p.stopCh:
+			return
+		}
+	}
+}
+
+// minCursor returns the lowest cursor of the sinks, latest if there is no sink
+func (p *Publisher) minCursor(latest int64) int64 {
+	minCursor := latest
+	for _, s := range p.sinks {
+		cursor, found := p.journal.Cursor(s.Name())
+		if !found {
+			return 0
+		}
+		if cursor < minCursor {
+			minCursor = cursor
+		}
+	}
+	return minCursor
+}
+
+// prune removes the blocks accepted by all the sinks and their consumers, except for the latest keepBlocks ones
+func (p *Publisher) prune() {
+	p.pruneMtx.Lock()
+	defer p.pruneMtx.Unlock()
+
+	latest := p.journal.LatestHeight()
+	toHeight := p.minCursor(latest)
+	for _, s := range p.sinks {
+		if c, ok := s.(ConsumerCursors); ok {
+			if cursor, found := c.MinConsumerCursor(); found && cursor < toHeight {
+				toHeight = cursor
+			}
+		}
+	}
+	if keptFrom := latest - p.keepBlocks; keptFrom < toHeight {
+		toHeight = keptFrom
+	}
+	if toHeight >= p.journal.OldestHeight() && toHeight > 0 {
+		p.journal.Prune(toHeight)
+	}
+}
@@ -0,0 +1,151 @@
+package sink
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/okex/okchain/x/stream/types"
+)
+
+const (
+	redisTimeout = 5 * time.Second
+	// redisErrIDTooSmall is part of the error replied to XADD with an id not greater than the last one of the stream
+	redisErrIDTooSmall = "equal or smaller than the target stream top item"
+)
+
+// redisReplyError is an error replied by the server
+type redisReplyError string
+
+func (e redisReplyError) Error() string {
+	return string(e)
+}
+
+func isRedisReplyError(err error) bool {
+	_, ok := err.(redisReplyError)
+	return ok
+}
+
+var _ Sink = (*RedisSink)(nil)
+
+// RedisSink appends the stream data of each block to a stream of a redis server, speaking the redis protocol directly.
+// The entry of a block has the id <height>-0 and the data in the field data, so the consumers read the stream by height
+// with XREAD or XRANGE and resume after the last height they handled. The block is accepted once the server replied
+// to XADD, or if the stream already has it, as when the reply to a former XADD was lost
+type RedisSink struct {
+	addr     string
+	password string
+	stream   string
+
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+// NewRedisSink creates a redis sink, which connects to the server when it publishes
+func NewRedisSink(addr, password, stream string) *RedisSink {
+	return &RedisSink{addr: addr, password: password, stream: stream}
+}
+
+// Name returns the name of the sink
+func (s *RedisSink) Name() string {
+	return types.SinkRedis
+}
+
+// Publish appends the stream data to the stream, reconnecting to the server if the last command failed
+func (s *RedisSink) Publish(height int64, data []byte) error {
+	if s.conn == nil {
+		if err := s.connect(); err != nil {
+			return err
+		}
+	}
+
+	id := []byte(strconv.FormatInt(height, 10) + "-0")
+	if _, err := s.do("XADD", []byte(s.stream), id, []byte("data"), data); err != nil {
+		if isRedisReplyError(err) && strings.Contains(err.Error(), redisErrIDTooSmall) {
+			// the stream has the block or a later one already
+			return nil
+		}
+		s.Close()
+		return err
+	}
+	return nil
+}
+
+// Close closes the connection to the server
+func (s *RedisSink) Close() error {
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	s.reader = nil
+	return err
+}
+
+func (s *RedisSink) connect() error {
+	conn, err := net.DialTimeout("tcp", s.addr, redisTimeout)
+	if err != nil {
+		return err
+	}
+	s.conn = conn
+	s.reader = bufio.NewReader(conn)
+
+	if s.password != "" {
+		if _, err := s.do("AUTH", []byte(s.password)); err != nil {
+			s.Close()
+			return err
+		}
+	}
+	return nil
+}
+
+// do sends a command as an array of bulk strings and reads the reply, which is a simple string, an integer or a bulk
+// string
+func (s *RedisSink) do(cmd string, args ...[]byte) (string, error) {
+	if err := s.conn.SetDeadline(time.Now().Add(redisTimeout)); err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("*%d\r\n$%d\r\n%s\r\n", len(args)+1, len(cmd), cmd))
+	for _, arg := range args {
+		b.WriteString("$" + strconv.Itoa(len(arg)) + "\r\n")
+		b.Write(arg)
+		b.WriteString("\r\n")
+	}
+	if _, err := s.conn.Write([]byte(b.String())); err != nil {
+		return "", err
+	}
+
+	line, err := s.reader.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	line = strings.TrimSuffix(line, "\r\n")
+	if len(line) == 0 {
+		return "", fmt.Errorf("empty reply of redis command %s", cmd)
+	}
+
+	switch line[0] {
+	case '+', ':':
+		return line[1:], nil
+	case '$':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil || n < 0 {
+			return "", fmt.Errorf("unexpected reply of redis command %s: %s", cmd, line)
+		}
+		bz := make([]byte, n+2)
+		if _, err := io.ReadFull(s.reader, bz); err != nil {
+			return "", err
+		}
+		return string(bz[:n]), nil
+	case '-':
+		return "", redisReplyError(fmt.Sprintf("redis command %s failed: %s", cmd, line[1:]))
+	default:
+		return "", fmt.Errorf("unexpected reply of redis command %s: %s", cmd, line)
+	}
+}
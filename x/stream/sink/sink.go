@@ -0,0 +1,52 @@
+package sink
+
+import (
+	"fmt"
+
+	"github.com/okex/okchain/x/stream/journal"
+	"github.com/okex/okchain/x/stream/types"
+	"github.com/tendermint/tendermint/libs/log"
+)
+
+// Sink publishes the encoded stream data of blocks, which are passed in order of height. A block is passed again if
+// publishing it failed, or if the process exited before the sink's cursor recorded it
+type Sink interface {
+	Name() string
+	Publish(height int64, data []byte) error
+	Close() error
+}
+
+// ConsumerCursors is implemented by the sink whose consumers acknowledge the blocks by cursors of their own. The
+// blocks are kept in the journal until all the cursors have passed them
+type ConsumerCursors interface {
+	// MinConsumerCursor returns the lowest cursor of the consumers, and whether there is any
+	MinConsumerCursor() (int64, bool)
+}
+
+// NewSinks creates the sinks configured
+func NewSinks(cfg *types.Config, j *journal.Journal, logger log.Logger) ([]Sink, error) {
+	sinks := make([]Sink, 0, len(cfg.Sinks))
+	for _, name := range cfg.Sinks {
+		var s Sink
+		var err error
+		switch name {
+		case types.SinkFile:
+			s, err = NewFileSink(cfg.FilePath)
+		case types.SinkWebSocket:
+			s, err = NewWebSocketSink(cfg.WebSocketAddr, j, logger)
+		case types.SinkRedis:
+			s = NewRedisSink(cfg.RedisAddr, cfg.RedisPassword, cfg.RedisStream)
+		default:
+			err = fmt.Errorf("unknown stream sink %s", name)
+		}
+
+		if err != nil {
+			for _, created := range sinks {
+				created.Close()
+			}
+			return nil, err
+		}
+		sinks = append(sinks, s)
+	}
+	return sinks, nil
+}
@@ -0,0 +1,405 @@
+package sink
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/okex/okchain/x/stream/journal"
+	"github.com/okex/okchain/x/stream/types"
+	"github.com/stretchr/testify/require"
+	"github.com/tendermint/tendermint/libs/log"
+	dbm "github.com/tendermint/tm-db"
+)
+
+type mockSink struct {
+	mtx       sync.Mutex
+	failTimes int
+	heights   []int64
+}
+
+func (s *mockSink) Name() string { return "mock" }
+
+func (s *mockSink) Publish(height int64, data []byte) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if s.failTimes > 0 {
+		s.failTimes--
+		return errors.New("mock failure")
+	}
+	s.heights = append(s.heights, height)
+	return nil
+}
+
+func (s *mockSink) Close() error { return nil }
+
+func (s *mockSink) getHeights() []int64 {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return append([]int64{}, s.heights...)
+}
+
+func TestPublisher(t *testing.T) {
+	j := journal.NewWithDB(dbm.NewMemDB())
+	for height := int64(1); height <= 3; height++ {
+		j.Put(height, []byte(strconv.FormatInt(height, 10)))
+	}
+
+	// the failed block is retried, and the blocks are delivered in order
+	s := &mockSink{failTimes: 2}
+	p := NewPublisher(j, []Sink{s}, 1, 10*time.Millisecond, log.NewNopLogger())
+	p.Start()
+	require.Eventually(t, func() bool { return len(s.getHeights()) == 3 }, time.Second, 5*time.Millisecond)
+	require.Equal(t, []int64{1, 2, 3}, s.getHeights())
+
+	j.Put(4, []byte("4"))
+	p.Notify()
+	require.Eventually(t, func() bool { return len(s.getHeights()) == 4 }, time.Second, 5*time.Millisecond)
+	p.Stop()
+
+	cursor, found := j.Cursor("mock")
+	require.True(t, found)
+	require.Equal(t, int64(4), cursor)
+	require.Equal(t, int64(0), p.Pending())
+	// only the latest block is kept after delivered
+	require.Equal(t, int64(4), j.OldestHeight())
+
+	// a restarted publisher resumes after the cursor
+	j.Put(5, []byte("5"))
+	s2 := &mockSink{}
+	p2 := NewPublisher(j, []Sink{s2}, 1, 10*time.Millisecond, log.NewNopLogger())
+	require.Equal(t, int64(1), p2.Pending())
+	p2.Start()
+	require.Eventually(t, func() bool { return len(s2.getHeights()) == 1 }, time.Second, 5*time.Millisecond)
+	p2.Stop()
+	require.Equal(t, []int64{5}, s2.getHeights())
+}
+
+func TestFileSink(t *testing.T) {
+	dir, err := ioutil.TempDir("", "stream_file_sink")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "sub", "blocks.jsonl")
+	s, err := NewFileSink(path)
+	require.Nil(t, err)
+	require.Nil(t, s.Publish(1, []byte(`{"height":1}`)))
+	require.Nil(t, s.Publish(2, []byte(`{"height":2}`)))
+	require.Nil(t, s.Close())
+
+	// the file is appended to after reopened
+	s, err = NewFileSink(path)
+	require.Nil(t, err)
+	require.Nil(t, s.Publish(3, []byte(`{"height":3}`)))
+	require.Nil(t, s.Close())
+
+	bz, err := ioutil.ReadFile(path)
+	require.Nil(t, err)
+	require.Equal(t, "{\"height\":1}\n{\"height\":2}\n{\"height\":3}\n", string(bz))
+}
+
+// mockRedisServer records the commands it receives, and replies an error to the ones listed in failCmds. The entries
+// added by XADD are kept in streams, and an entry whose id isn't greater than the last one is refused like redis does
+type mockRedisServer struct {
+	listener net.Listener
+	mtx      sync.Mutex
+	commands [][]string
+	failCmds map[string]bool
+	streams  map[string][]string
+}
+
+func newMockRedisServer(t *testing.T) *mockRedisServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.Nil(t, err)
+	server := &mockRedisServer{listener: listener, failCmds: make(map[string]bool), streams: make(map[string][]string)}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go server.serve(conn)
+		}
+	}()
+	return server
+}
+
+func (server *mockRedisServer) serve(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		argNum, _ := strconv.Atoi(strings.TrimSpace(line[1:]))
+		args := make([]string, argNum)
+		for i := range args {
+			if _, err := reader.ReadString('\n'); err != nil {
+				return
+			}
+			arg, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			args[i] = strings.TrimSuffix(arg, "\r\n")
+		}
+
+		server.mtx.Lock()
+		server.commands = append(server.commands, args)
+		reply := ":1\r\n"
+		switch {
+		case server.failCmds[args[0]]:
+			reply = "-ERR mock failure\r\n"
+		case args[0] == "AUTH":
+			reply = "+OK\r\n"
+		case args[0] == "XADD":
+			reply = server.xadd(args[1], args[2], args[4])
+		}
+		server.mtx.Unlock()
+
+		if _, err := conn.Write([]byte(reply)); err != nil {
+			return
+		}
+	}
+}
+
+func (server *mockRedisServer) xadd(stream, id, data string) string {
+	height, _ := strconv.ParseInt(strings.TrimSuffix(id, "-0"), 10, 64)
+	if entries := server.streams[stream]; len(entries) > 0 {
+		var last int64
+		fmt.Sscanf(entries[len(entries)-1], `{"height":%d}`, &last)
+		if height <= last {
+			return "-ERR The ID specified in XADD is equal or smaller than the target stream top item\r\n"
+		}
+	}
+	server.streams[stream] = append(server.streams[stream], data)
+	return fmt.Sprintf("$%d\r\n%s\r\n", len(id), id)
+}
+
+// readStream returns the entries of the stream after the height, as a consumer resuming by XREAD does
+func (server *mockRedisServer) readStream(stream string, after int64) []string {
+	server.mtx.Lock()
+	defer server.mtx.Unlock()
+	var entries []string
+	for _, data := range server.streams[stream] {
+		var height int64
+		fmt.Sscanf(data, `{"height":%d}`, &height)
+		if height > after {
+			entries = append(entries, data)
+		}
+	}
+	return entries
+}
+
+func (server *mockRedisServer) setFail(cmd string, fail bool) {
+	server.mtx.Lock()
+	defer server.mtx.Unlock()
+	server.failCmds[cmd] = fail
+}
+
+func (server *mockRedisServer) getCommands() [][]string {
+	server.mtx.Lock()
+	defer server.mtx.Unlock()
+	return append([][]string{}, server.commands...)
+}
+
+func TestRedisSink(t *testing.T) {
+	server := newMockRedisServer(t)
+	defer server.listener.Close()
+
+	s := NewRedisSink(server.listener.Addr().String(), "pwd", "okchain_stream")
+	require.Nil(t, s.Publish(1, []byte(`{"height":1}`)))
+
+	server.setFail("XADD", true)
+	require.NotNil(t, s.Publish(2, []byte(`{"height":2}`)))
+
+	// the sink reconnects after a failure
+	server.setFail("XADD", false)
+	require.Nil(t, s.Publish(2, []byte(`{"height":2}`)))
+	// a block the stream has already is accepted, as when retried after the reply was lost
+	require.Nil(t, s.Publish(2, []byte(`{"height":2}`)))
+	require.Nil(t, s.Close())
+
+	require.Equal(t, [][]string{
+		{"AUTH", "pwd"},
+		{"XADD", "okchain_stream", "1-0", "data", `{"height":1}`},
+		{"XADD", "okchain_stream", "2-0", "data", `{"height":2}`},
+		{"AUTH", "pwd"},
+		{"XADD", "okchain_stream", "2-0", "data", `{"height":2}`},
+		{"XADD", "okchain_stream", "2-0", "data", `{"height":2}`},
+	}, server.getCommands())
+	require.Equal(t, []string{`{"height":1}`, `{"height":2}`}, server.readStream("okchain_stream", 0))
+
+	// the server is gone
+	server.listener.Close()
+	s = NewRedisSink("127.0.0.1:1", "", "okchain_stream")
+	require.NotNil(t, s.Publish(3, []byte(`{"height":3}`)))
+}
+
+func TestRedisSinkConsumerReconnect(t *testing.T) {
+	server := newMockRedisServer(t)
+	defer server.listener.Close()
+
+	j := journal.NewWithDB(dbm.NewMemDB())
+	s := NewRedisSink(server.listener.Addr().String(), "", "okchain_stream")
+	p := NewPublisher(j, []Sink{s}, 1, 10*time.Millisecond, log.NewNopLogger())
+	p.Start()
+	defer p.Stop()
+
+	put := func(from, to int64) {
+		for height := from; height <= to; height++ {
+			j.Put(height, []byte(fmt.Sprintf(`{"height":%d}`, height)))
+		}
+		p.Notify()
+		require.Eventually(t, func() bool { return p.Pending() == 0 }, time.Second, 5*time.Millisecond)
+	}
+
+	// a consumer reads up to block 2, then goes away while blocks 3 to 5 are published
+	put(1, 2)
+	require.Equal(t, []string{`{"height":1}`, `{"height":2}`}, server.readStream("okchain_stream", 0))
+	put(3, 5)
+
+	// the consumer resumes after the last height it handled and gets every block since
+	require.Equal(t, []string{`{"height":3}`, `{"height":4}`, `{"height":5}`},
+		server.readStream("okchain_stream", 2))
+}
+
+func readHeights(t *testing.T, conn *websocket.Conn, num int) []string {
+	msgs := make([]string, 0, num)
+	for i := 0; i < num; i++ {
+		require.Nil(t, conn.SetReadDeadline(time.Now().Add(time.Second)))
+		_, msg, err := conn.ReadMessage()
+		require.Nil(t, err)
+		msgs = append(msgs, string(msg))
+	}
+	return msgs
+}
+
+func TestWebSocketSink(t *testing.T) {
+	j := journal.NewWithDB(dbm.NewMemDB())
+	s, err := NewWebSocketSink("127.0.0.1:0", j, log.NewNopLogger())
+	require.Nil(t, err)
+	defer s.Close()
+	url := fmt.Sprintf("ws://%s%s", s.Addr(), WebSocketPath)
+
+	for height := int64(1); height <= 3; height++ {
+		j.Put(height, []byte(strconv.FormatInt(height, 10)))
+		require.Nil(t, s.Publish(height, nil))
+	}
+
+	// a live client gets the blocks published after it connected
+	liveConn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	require.Nil(t, err)
+	defer liveConn.Close()
+
+	// a resuming client gets the kept blocks from its cursor on first
+	resumeConn, _, err := websocket.DefaultDialer.Dial(url+"?from_height=2", nil)
+	require.Nil(t, err)
+	defer resumeConn.Close()
+	require.Equal(t, []string{"2", "3"}, readHeights(t, resumeConn, 2))
+
+	j.Put(4, []byte("4"))
+	require.Nil(t, s.Publish(4, nil))
+	require.Equal(t, []string{"4"}, readHeights(t, liveConn, 1))
+	require.Equal(t, []string{"4"}, readHeights(t, resumeConn, 1))
+
+	// the blocks pruned from the journal are not able to be resumed from
+	j.Prune(2)
+	prunedConn, _, err := websocket.DefaultDialer.Dial(url+"?from_height=1", nil)
+	require.Nil(t, err)
+	defer prunedConn.Close()
+	require.Nil(t, prunedConn.SetReadDeadline(time.Now().Add(time.Second)))
+	_, _, err = prunedConn.ReadMessage()
+	require.True(t, websocket.IsCloseError(err, websocket.CloseGoingAway))
+
+	_, _, err = websocket.DefaultDialer.Dial(url+"?from_height=abc", nil)
+	require.NotNil(t, err)
+}
+
+func TestWebSocketSinkClientID(t *testing.T) {
+	j := journal.NewWithDB(dbm.NewMemDB())
+	s, err := NewWebSocketSink("127.0.0.1:0", j, log.NewNopLogger())
+	require.Nil(t, err)
+	p := NewPublisher(j, []Sink{s}, 1, 10*time.Millisecond, log.NewNopLogger())
+	p.Start()
+	defer p.Stop()
+	url := fmt.Sprintf("ws://%s%s?client_id=c1", s.Addr(), WebSocketPath)
+
+	put := func(from, to int64) {
+		for height := from; height <= to; height++ {
+			j.Put(height, []byte(strconv.FormatInt(height, 10)))
+		}
+		p.Notify()
+		require.Eventually(t, func() bool { return p.Pending() == 0 }, time.Second, 5*time.Millisecond)
+	}
+	clientCursor := func() int64 {
+		cursor, _ := j.Cursor(webSocketCursorPrefix + "c1")
+		return cursor
+	}
+
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	require.Nil(t, err)
+	put(1, 3)
+	require.Equal(t, []string{"1", "2", "3"}, readHeights(t, conn, 3))
+
+	// an ack beyond the blocks sent is ignored
+	require.Nil(t, conn.WriteJSON(webSocketAck{Ack: 10}))
+	require.Nil(t, conn.WriteJSON(webSocketAck{Ack: 2}))
+	require.Eventually(t, func() bool { return clientCursor() == 2 }, time.Second, 5*time.Millisecond)
+	require.Nil(t, conn.Close())
+
+	// the blocks not acknowledged are kept while the client is away, beyond keepBlocks
+	put(4, 5)
+	require.Equal(t, int64(3), j.OldestHeight())
+	require.Equal(t, int64(2), clientCursor())
+
+	// the client reconnecting with its id gets every block after its cursor
+	conn, _, err = websocket.DefaultDialer.Dial(url, nil)
+	require.Nil(t, err)
+	defer conn.Close()
+	require.Equal(t, []string{"3", "4", "5"}, readHeights(t, conn, 3))
+	put(6, 6)
+	require.Equal(t, []string{"6"}, readHeights(t, conn, 1))
+
+	// the journal is pruned once the client acknowledges the blocks
+	require.Nil(t, conn.WriteJSON(webSocketAck{Ack: 6}))
+	require.Eventually(t, func() bool { return clientCursor() == 6 }, time.Second, 5*time.Millisecond)
+	put(7, 7)
+	require.Equal(t, int64(7), j.OldestHeight())
+
+	_, _, err = websocket.DefaultDialer.Dial(fmt.Sprintf("ws://%s%s?client_id=a.b", s.Addr(), WebSocketPath), nil)
+	require.NotNil(t, err)
+}
+
+func TestNewSinks(t *testing.T) {
+	dir, err := ioutil.TempDir("", "stream_sinks")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	cfg := types.DefaultConfig()
+	cfg.Sinks = []string{types.SinkFile, types.SinkWebSocket, types.SinkRedis}
+	cfg.FilePath = filepath.Join(dir, "blocks.jsonl")
+	cfg.WebSocketAddr = "127.0.0.1:0"
+	sinks, err := NewSinks(cfg, journal.NewWithDB(dbm.NewMemDB()), log.NewNopLogger())
+	require.Nil(t, err)
+	require.Equal(t, 3, len(sinks))
+	for i, s := range sinks {
+		require.Equal(t, cfg.Sinks[i], s.Name())
+		require.Nil(t, s.Close())
+	}
+
+	cfg.Sinks = []string{types.SinkFile, "kafka"}
+	_, err = NewSinks(cfg, journal.NewWithDB(dbm.NewMemDB()), log.NewNopLogger())
+	require.NotNil(t, err)
+}
@@ -0,0 +1,282 @@
+package sink
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/okex/okchain/x/stream/journal"
+	"github.com/okex/okchain/x/stream/types"
+	"github.com/tendermint/tendermint/libs/log"
+)
+
+const (
+	// WebSocketPath is the path the websocket sink serves at
+	WebSocketPath = "/stream"
+	// FlagFromHeight is the query parameter for a websocket client to resume from a height
+	FlagFromHeight = "from_height"
+	// FlagClientID is the query parameter for a websocket client to be known by the id, whose cursor is kept
+	FlagClientID = "client_id"
+
+	webSocketWriteTimeout = 10 * time.Second
+	webSocketMaxClientID  = 64
+	// webSocketCursorPrefix prefixes the names of the cursors of clients in the journal
+	webSocketCursorPrefix = types.SinkWebSocket + "/"
+)
+
+// webSocketAck is sent by a client known by id to acknowledge the blocks up to the height
+type webSocketAck struct {
+	Ack int64 `json:"ack"`
+}
+
+var _ Sink = (*WebSocketSink)(nil)
+
+// WebSocketSink serves the stream data of blocks to the websocket clients of ws://<addr>/stream as text messages.
+// A client resumes from its cursor by connecting to ws://<addr>/stream?from_height=<height>, then the blocks from
+// the height on are sent from the journal before the new ones, as long as they are still kept. Blocks are read from
+// the journal by each client, so a slow client lags behind without holding the others.
+//
+// A client connecting with client_id=<id> is delivered the blocks at least once. It acknowledges the blocks it has
+// handled by sending {"ack": <height>}, which is kept as its cursor in the journal, and the blocks after the lowest
+// cursor of the clients are kept in the journal until acknowledged. Reconnecting with the same id, the client resumes
+// after its cursor, and the blocks sent but not acknowledged are sent again. The cursor of a client is kept until it
+// is dropped by the operator, so the ids are meant for the long-lived consumers only. The clients without id are
+// served the blocks published while they are connected, and the ones kept in the journal they ask for by from_height
+type WebSocketSink struct {
+	journal  *journal.Journal
+	logger   log.Logger
+	addr     string
+	server   *http.Server
+	upgrader websocket.Upgrader
+
+	mtx       sync.Mutex
+	published int64         // the height of the latest block published
+	notifyCh  chan struct{} // closed when a block is published or the sink is closed
+	closed    bool
+	ackMtx    sync.Mutex
+}
+
+var _ ConsumerCursors = (*WebSocketSink)(nil)
+
+// NewWebSocketSink starts the websocket server listening at addr
+func NewWebSocketSink(addr string, j *journal.Journal, logger log.Logger) (*WebSocketSink, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	published, _ := j.Cursor(types.SinkWebSocket)
+	s := &WebSocketSink{
+		journal: j,
+		logger:  logger,
+		addr:    listener.Addr().String(),
+		upgrader: websocket.Upgrader{
+			// market data is public, it's served to the pages of any origin
+			CheckOrigin: func(r *http.Request) bool { return true },
+		},
+		published: published,
+		notifyCh:  make(chan struct{}),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(WebSocketPath, s.handle)
+	s.server = &http.Server{Handler: mux}
+	go func() {
+		if err := s.server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			logger.Error(fmt.Sprintf("[stream] websocket server stopped: %s", err.Error()))
+		}
+	}()
+	return s, nil
+}
+
+// Addr returns the address the websocket server listens at
+func (s *WebSocketSink) Addr() string {
+	return s.addr
+}
+
+// Name returns the name of the sink
+func (s *WebSocketSink) Name() string {
+	return types.SinkWebSocket
+}
+
+// Publish notifies the clients of the block, which is read from the journal when it's sent. The block is kept in the
+// journal for the clients known by id until they acknowledge it, see MinConsumerCursor
+func (s *WebSocketSink) Publish(height int64, data []byte) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.published = height
+	close(s.notifyCh)
+	s.notifyCh = make(chan struct{})
+	return nil
+}
+
+// MinConsumerCursor returns the lowest cursor of the clients known by id, and whether there is any
+func (s *WebSocketSink) MinConsumerCursor() (minCursor int64, found bool) {
+	s.journal.IterateCursors(webSocketCursorPrefix, func(name string, height int64) bool {
+		if !found || height < minCursor {
+			minCursor, found = height, true
+		}
+		return false
+	})
+	return minCursor, found
+}
+
+// ack moves the cursor of the client forward to height
+func (s *WebSocketSink) ack(clientID string, height int64) {
+	s.ackMtx.Lock()
+	defer s.ackMtx.Unlock()
+	if cursor, found := s.journal.Cursor(webSocketCursorPrefix + clientID); !found || height > cursor {
+		s.journal.SetCursor(webSocketCursorPrefix+clientID, height)
+	}
+}
+
+// Close stops the websocket server and disconnects the clients
+func (s *WebSocketSink) Close() error {
+	s.mtx.Lock()
+	if !s.closed {
+		s.closed = true
+		close(s.notifyCh)
+	}
+	s.mtx.Unlock()
+	return s.server.Close()
+}
+
+func (s *WebSocketSink) state() (published int64, notifyCh chan struct{}, closed bool) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return s.published, s.notifyCh, s.closed
+}
+
+func (s *WebSocketSink) handle(w http.ResponseWriter, r *http.Request) {
+	published, _, _ := s.state()
+	next := published + 1
+
+	clientID := r.URL.Query().Get(FlagClientID)
+	if clientID != "" {
+		if !isValidClientID(clientID) {
+			http.Error(w, fmt.Sprintf("invalid %s: %s", FlagClientID, clientID), http.StatusBadRequest)
+			return
+		}
+		if cursor, found := s.journal.Cursor(webSocketCursorPrefix + clientID); found {
+			next = cursor + 1
+		}
+	}
+	if fromHeight := r.URL.Query().Get(FlagFromHeight); fromHeight != "" {
+		height, err := strconv.ParseInt(fromHeight, 10, 64)
+		if err != nil || height <= 0 {
+			http.Error(w, fmt.Sprintf("invalid %s: %s", FlagFromHeight, fromHeight), http.StatusBadRequest)
+			return
+		}
+		next = height
+	}
+
+	conn, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		s.logger.Debug(fmt.Sprintf("[stream] failed to upgrade websocket connection: %s", err.Error()))
+		return
+	}
+	if clientID != "" {
+		// the blocks from next on are kept until the client acknowledges them
+		s.ackMtx.Lock()
+		s.journal.SetCursor(webSocketCursorPrefix+clientID, next-1)
+		s.ackMtx.Unlock()
+	}
+	go s.serve(conn, next, clientID)
+}
+
+func isValidClientID(clientID string) bool {
+	if len(clientID) > webSocketMaxClientID {
+		return false
+	}
+	for _, c := range clientID {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_') {
+			return false
+		}
+	}
+	return true
+}
+
+// serve sends the blocks from next on to the client until it disconnects or the sink is closed
+func (s *WebSocketSink) serve(conn *websocket.Conn, next int64, clientID string) {
+	defer conn.Close()
+
+	// the acks of the client known by id move its cursor up to the blocks sent, the other messages are discarded.
+	// Reading also notices the disconnection
+	sent := next - 1
+	doneCh := make(chan struct{})
+	go func() {
+		defer close(doneCh)
+		for {
+			_, bz, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			var ack webSocketAck
+			if clientID == "" || json.Unmarshal(bz, &ack) != nil {
+				continue
+			}
+			if ack.Ack > 0 && ack.Ack <= atomic.LoadInt64(&sent) {
+				s.ack(clientID, ack.Ack)
+			}
+		}
+	}()
+
+	for {
+		published, notifyCh, closed := s.state()
+		if closed {
+			return
+		}
+
+		if next <= published {
+			if oldest := s.journal.OldestHeight(); oldest == 0 || next < oldest {
+				msg := fmt.Sprintf("block %d is no longer kept, the oldest one kept is %d", next, oldest)
+				s.writeClose(conn, websocket.CloseGoingAway, msg)
+				return
+			}
+
+			var err error
+			sentFrom := next
+			s.journal.Iterate(next, func(height int64, data []byte) bool {
+				if height > published {
+					return true
+				}
+				if err = conn.SetWriteDeadline(time.Now().Add(webSocketWriteTimeout)); err == nil {
+					err = conn.WriteMessage(websocket.TextMessage, data)
+				}
+				if err != nil {
+					return true
+				}
+				next = height + 1
+				atomic.StoreInt64(&sent, height)
+				return false
+			})
+			if err != nil {
+				s.logger.Debug(fmt.Sprintf("[stream] websocket client %s disconnected: %s",
+					conn.RemoteAddr().String(), err.Error()))
+				return
+			}
+			if next > sentFrom {
+				continue
+			}
+		}
+
+		select {
+		case <-notifyCh:
+		case <-doneCh:
+			return
+		}
+	}
+}
+
+func (s *WebSocketSink) writeClose(conn *websocket.Conn, code int, text string) {
+	msg := websocket.FormatCloseMessage(code, text)
+	if err := conn.WriteControl(websocket.CloseMessage, msg, time.Now().Add(webSocketWriteTimeout)); err != nil {
+		s.logger.Debug(fmt.Sprintf("[stream] failed to close websocket connection: %s", err.Error()))
+	}
+}
@@ -0,0 +1,102 @@
+package types
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/server/config"
+	"github.com/spf13/viper"
+)
+
+// Config is the configuration of stream module, read from the [stream] section of app.toml, e.g.
+//
+//	[stream]
+//	enable = true
+//	sinks = ["file", "websocket", "redis"]
+//	websocket_addr = "127.0.0.1:26661"
+//	redis_addr = "127.0.0.1:6379"
+type Config struct {
+	Enable bool `json:"enable" mapstructure:"enable"`
+	// the sinks to publish stream data to, any of file, websocket and redis
+	Sinks []string `json:"sinks" mapstructure:"sinks"`
+
+	// the directory of the journal which keeps the stream data of blocks and the cursors of sinks
+	JournalDir string `json:"journal_dir" mapstructure:"journal_dir"`
+	// the number of latest blocks kept in the journal after they are published to all the sinks, from which
+	// websocket clients are able to resume. The blocks not yet acknowledged by a websocket client with client_id are
+	// kept as well
+	JournalKeepBlocks int64 `json:"journal_keep_blocks" mapstructure:"journal_keep_blocks"`
+	// the interval to publish a block again after a sink failed to accept it
+	RetryInterval time.Duration `json:"retry_interval" mapstructure:"retry_interval"`
+
+	// the JSON lines file the file sink appends stream data to
+	FilePath string `json:"file_path" mapstructure:"file_path"`
+	// the listen address of the websocket server embedded as websocket sink
+	WebSocketAddr string `json:"websocket_addr" mapstructure:"websocket_addr"`
+	// the address and password of the redis server of redis sink, and the stream it appends the blocks to
+	RedisAddr     string `json:"redis_addr" mapstructure:"redis_addr"`
+	RedisPassword string `json:"redis_password" mapstructure:"redis_password"`
+	RedisStream   string `json:"redis_stream" mapstructure:"redis_stream"`
+}
+
+// DefaultConfig returns the default configuration of stream module, which is disabled
+func DefaultConfig() *Config {
+	streamHome := filepath.Join(config.DefaultBackendNodeDataHome, ModuleName)
+	return &Config{
+		Enable:            false,
+		Sinks:             []string{SinkFile},
+		JournalDir:        streamHome,
+		JournalKeepBlocks: 1000,
+		RetryInterval:     3 * time.Second,
+		FilePath:          filepath.Join(streamHome, "blocks.jsonl"),
+		WebSocketAddr:     "127.0.0.1:26661",
+		RedisAddr:         "127.0.0.1:6379",
+		RedisStream:       "okchain_stream",
+	}
+}
+
+// ParseConfig reads the configuration of stream module from viper over the default one
+func ParseConfig() (*Config, error) {
+	cfg := DefaultConfig()
+	if err := viper.UnmarshalKey(ModuleName, cfg); err != nil {
+		return nil, err
+	}
+	return cfg, cfg.Validate()
+}
+
+// Validate checks the configuration of stream module
+func (cfg *Config) Validate() error {
+	if !cfg.Enable {
+		return nil
+	}
+
+	if len(cfg.Sinks) == 0 {
+		return fmt.Errorf("no sink is configured for stream")
+	}
+	seen := make(map[string]bool, len(cfg.Sinks))
+	for _, name := range cfg.Sinks {
+		switch name {
+		case SinkFile, SinkWebSocket, SinkRedis:
+		default:
+			return fmt.Errorf("unknown stream sink %s, it should be one of %s, %s and %s",
+				name, SinkFile, SinkWebSocket, SinkRedis)
+		}
+		if seen[name] {
+			return fmt.Errorf("duplicated stream sink %s", name)
+		}
+		seen[name] = true
+	}
+
+	if cfg.JournalDir == "" {
+		return fmt.Errorf("journal_dir of stream is required")
+	}
+	if cfg.JournalKeepBlocks < 0 || (seen[SinkWebSocket] && cfg.JournalKeepBlocks == 0) {
+		return fmt.Errorf("journal_keep_blocks of stream should not be negative, " +
+			"and should be positive with websocket sink")
+	}
+	if cfg.RetryInterval <= 0 {
+		return fmt.Errorf("retry_interval of stream should be positive")
+	}
+	return nil
+}
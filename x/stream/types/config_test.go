@@ -0,0 +1,57 @@
+package types
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseConfig(t *testing.T) {
+	defer viper.Reset()
+
+	cfg, err := ParseConfig()
+	require.Nil(t, err)
+	require.Equal(t, DefaultConfig(), cfg)
+	require.False(t, cfg.Enable)
+
+	viper.Set("stream.enable", true)
+	viper.Set("stream.sinks", []string{SinkFile, SinkRedis})
+	viper.Set("stream.retry_interval", "10s")
+	viper.Set("stream.redis_stream", "market")
+	cfg, err = ParseConfig()
+	require.Nil(t, err)
+	require.True(t, cfg.Enable)
+	require.Equal(t, []string{SinkFile, SinkRedis}, cfg.Sinks)
+	require.Equal(t, 10*time.Second, cfg.RetryInterval)
+	require.Equal(t, "market", cfg.RedisStream)
+	require.Equal(t, DefaultConfig().RedisAddr, cfg.RedisAddr)
+
+	viper.Set("stream.sinks", []string{"kafka"})
+	_, err = ParseConfig()
+	require.NotNil(t, err)
+}
+
+func TestConfig_Validate(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Sinks = nil
+	// nothing is checked while disabled
+	require.Nil(t, cfg.Validate())
+
+	cfg.Enable = true
+	require.NotNil(t, cfg.Validate())
+
+	cfg.Sinks = []string{SinkFile, SinkFile}
+	require.NotNil(t, cfg.Validate())
+
+	cfg.Sinks = []string{SinkFile, SinkWebSocket}
+	require.Nil(t, cfg.Validate())
+	cfg.JournalKeepBlocks = 0
+	require.NotNil(t, cfg.Validate())
+	cfg.Sinks = []string{SinkFile}
+	require.Nil(t, cfg.Validate())
+
+	cfg.RetryInterval = 0
+	require.NotNil(t, cfg.Validate())
+}
@@ -0,0 +1,21 @@
+package types
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	backendtypes "github.com/okex/okchain/x/backend/types"
+	ordertypes "github.com/okex/okchain/x/order/types"
+	"github.com/okex/okchain/x/token"
+	tokentypes "github.com/okex/okchain/x/token/types"
+)
+
+// OrderKeeper expected order keeper
+type OrderKeeper interface {
+	backendtypes.OrderKeeper
+	GetDepthBookCopy(product string) *ordertypes.DepthBook
+}
+
+// TokenKeeper expected token keeper
+type TokenKeeper interface {
+	GetFeeDetailList() []*token.FeeDetail
+	GetCoinsInfo(ctx sdk.Context, addr sdk.AccAddress) tokentypes.CoinsInfo
+}
@@ -0,0 +1,11 @@
+package types
+
+const (
+	// ModuleName is the name of the stream module
+	ModuleName = "stream"
+
+	// names of the sinks stream data can be published to
+	SinkFile      = "file"
+	SinkWebSocket = "websocket"
+	SinkRedis     = "redis"
+)
@@ -0,0 +1,32 @@
+package types
+
+import (
+	backendtypes "github.com/okex/okchain/x/backend/types"
+	ordertypes "github.com/okex/okchain/x/order/types"
+	tokentypes "github.com/okex/okchain/x/token/types"
+)
+
+// BlockData is the market data of a block collected at EndBlock and published to the sinks. Sinks deliver each
+// block at least once, so consumers are supposed to skip the heights they have already handled
+type BlockData struct {
+	Height        int64                       `json:"height"`
+	Timestamp     int64                       `json:"timestamp"`
+	NewOrders     []*backendtypes.Order       `json:"new_orders"`
+	UpdatedOrders []*backendtypes.Order       `json:"updated_orders"`
+	Deals         []*backendtypes.Deal        `json:"deals"`
+	MatchResults  []*backendtypes.MatchResult `json:"match_results"`
+	DepthBooks    []DepthBook                 `json:"depth_books"`
+	Balances      []Balance                   `json:"balances"`
+}
+
+// DepthBook is the snapshot of the visible depth book of a product changed in a block
+type DepthBook struct {
+	Product string                     `json:"product"`
+	Items   []ordertypes.DepthBookItem `json:"items"`
+}
+
+// Balance is the balance of an account changed in a block
+type Balance struct {
+	Address string               `json:"address"`
+	Coins   tokentypes.CoinsInfo `json:"coins"`
+}
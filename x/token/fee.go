@@ -6,4 +6,5 @@ type FeeDetail struct {
 	Fee       string `gorm:"type:varchar(40)" json:"fee" v2:"fee"`
 	FeeType   string `gorm:"index;type:varchar(20)" json:"fee_type" v2:"fee_type"` // transfer, deal, etc. see common/const.go
 	Timestamp int64  `gorm:"index;bigint" json:"timestamp" v2:"timestamp"`
+	FeeRate   string `gorm:"type:varchar(40)" json:"fee_rate" v2:"fee_rate"` // the rate applied, empty if not rated
 }
@@ -337,12 +337,18 @@ func (k Keeper) GetFeeDetailList() []*FeeDetail {
 
 // nolint
 func (k Keeper) AddFeeDetail(ctx sdk.Context, from string, fee sdk.DecCoins, feeType string) {
+	k.AddFeeDetailWithRate(ctx, from, fee, feeType, "")
+}
+
+// AddFeeDetailWithRate adds the fee detail with the fee rate applied
+func (k Keeper) AddFeeDetailWithRate(ctx sdk.Context, from string, fee sdk.DecCoins, feeType, feeRate string) {
 	if k.enableBackend {
 		feeDetail := &FeeDetail{
 			Address:   from,
 			Fee:       fee.String(),
 			FeeType:   feeType,
 			Timestamp: ctx.BlockHeader().Time.Unix(),
+			FeeRate:   feeRate,
 		}
 		k.cache.addFeeDetail(feeDetail)
 	}